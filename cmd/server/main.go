@@ -0,0 +1,291 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/labstack/echo/v4/middleware"
+	"github.com/x402stacks/stacks-facilitator/internal/config"
+	"github.com/x402stacks/stacks-facilitator/internal/payment/application/command"
+	"github.com/x402stacks/stacks-facilitator/internal/payment/domain/service"
+	"github.com/x402stacks/stacks-facilitator/internal/payment/domain/valueobject"
+	"github.com/x402stacks/stacks-facilitator/internal/payment/infrastructure/blockchain"
+	paymenthttp "github.com/x402stacks/stacks-facilitator/internal/payment/infrastructure/http"
+	"github.com/x402stacks/stacks-facilitator/internal/payment/infrastructure/persistence"
+	"github.com/x402stacks/stacks-facilitator/internal/payment/infrastructure/webhook"
+	"github.com/x402stacks/stacks-facilitator/internal/stacks"
+	"github.com/x402stacks/stacks-facilitator/internal/stacks/secp256k1"
+)
+
+func main() {
+	cfg, err := config.Load()
+	if err != nil {
+		log.Fatalf("failed to load configuration: %v", err)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	if err := run(ctx, cfg); err != nil {
+		log.Fatalf("server error: %v", err)
+	}
+}
+
+// run wires the application together and serves HTTP until ctx is cancelled
+func run(ctx context.Context, cfg config.Config) error {
+	httpTimeout := time.Duration(cfg.HTTPTimeout)
+	mainnetClient := stacks.NewClientWithTimeout(cfg.Networks.MainnetAPIURL, httpTimeout)
+	testnetClient := stacks.NewClientWithTimeout(cfg.Networks.TestnetAPIURL, httpTimeout)
+	adapter := blockchain.NewStacksClientAdapterWithClients(mainnetClient, testnetClient)
+
+	paymentStore, closeStore, err := openPaymentStore(cfg.PaymentStorePath)
+	if err != nil {
+		return err
+	}
+	defer closeStore()
+
+	tokenRegistry, err := newTokenRegistry(ctx, cfg.Tokens, adapter)
+	if err != nil {
+		return err
+	}
+
+	chainTip := blockchain.NewChainTipTracker(mainnetClient, testnetClient, time.Duration(cfg.Confirmations.TipMaxAge))
+	minConfirmations := command.WithMinConfirmations(cfg.Confirmations.MinConfirmations, cfg.Confirmations.MinBurnConfirmations)
+
+	verificationSvc := service.NewVerificationService()
+	decoder := blockchain.NewTransactionDecoder()
+	verifyHandler := command.NewVerifyPaymentHandler(adapter, verificationSvc,
+		command.WithRetry(cfg.Verify.MaxRetries, time.Duration(cfg.Verify.RetryDelay)),
+		command.WithTokenRegistry(tokenRegistry),
+		command.WithChainTip(chainTip),
+		minConfirmations,
+		command.WithPaymentStore(paymentStore),
+		command.WithSignedTransactions(decoder, adapter))
+	settleOpts := []command.Option{
+		command.WithRetry(cfg.Settle.MaxRetries, time.Duration(cfg.Settle.RetryDelay)),
+		command.WithTokenRegistry(tokenRegistry),
+		command.WithChainTip(chainTip),
+		minConfirmations,
+		command.WithPaymentStore(paymentStore),
+	}
+	var sponsorHandler *paymenthttp.SponsorHandler
+	if cfg.Sponsor.Enabled() {
+		sponsor, policy, err := newSponsor(cfg.Sponsor, mainnetClient, testnetClient)
+		if err != nil {
+			return err
+		}
+		settleOpts = append(settleOpts, command.WithSponsor(sponsor, policy))
+		sponsorHandler = paymenthttp.NewSponsorHandler(sponsor)
+	}
+	// The signing secret is all a settle's callback_url needs; WEBHOOK_URLS is optional
+	if cfg.Webhooks.Enabled() {
+		deliveryLog, closeLog, err := openDeliveryLog(cfg.Webhooks.LogPath)
+		if err != nil {
+			return err
+		}
+		defer closeLog()
+
+		dispatcher := webhook.NewDispatcher(webhook.DispatcherConfig{
+			Secret:         cfg.Webhooks.Secret,
+			URLs:           cfg.Webhooks.URLs,
+			MaxAttempts:    cfg.Webhooks.MaxAttempts,
+			InitialBackoff: time.Duration(cfg.Webhooks.InitialBackoff),
+			MaxBackoff:     time.Duration(cfg.Webhooks.MaxBackoff),
+			Timeout:        time.Duration(cfg.Webhooks.Timeout),
+		}, deliveryLog)
+		tracker := command.NewPaymentTracker(adapter, verificationSvc, dispatcher,
+			command.WithRetry(cfg.PaymentEvents.MaxRetries, time.Duration(cfg.PaymentEvents.RetryDelay)),
+			command.WithTokenRegistry(tokenRegistry),
+			command.WithChainTip(chainTip))
+		dispatchCtx, stopDispatch := context.WithCancel(ctx)
+		dispatched := make(chan struct{})
+		tracked := make(chan struct{})
+		go func() {
+			defer close(dispatched)
+			if err := dispatcher.Run(dispatchCtx); err != nil {
+				log.Printf("webhooks: %v", err)
+			}
+		}()
+		go func() {
+			defer close(tracked)
+			tracker.Run(dispatchCtx)
+		}()
+		// Stop tracking and sending before the delivery log is closed; unsent deliveries resume on restart
+		defer func() {
+			stopDispatch()
+			<-tracked
+			<-dispatched
+		}()
+		settleOpts = append(settleOpts, command.WithTracker(tracker))
+		log.Printf("webhooks: %d global url(s), per-settlement callback_url enabled", len(cfg.Webhooks.URLs))
+	}
+	settleHandler := command.NewSettlePaymentHandler(adapter, decoder, verificationSvc, settleOpts...)
+	paymentWatcher := command.NewPaymentWatcher(adapter,
+		command.WithTokenRegistry(tokenRegistry),
+		command.WithRetry(cfg.PaymentEvents.MaxRetries, time.Duration(cfg.PaymentEvents.RetryDelay)),
+		command.WithChainTip(chainTip),
+		minConfirmations)
+	settlementStore := persistence.NewMemorySettlementStore(time.Duration(cfg.AsyncSettle.Retention))
+	asyncSettleHandler := command.NewAsyncSettlePaymentHandler(settleHandler, settlementStore, cfg.AsyncSettle.Workers, cfg.AsyncSettle.QueueSize)
+	settled := make(chan struct{})
+	go func() {
+		defer close(settled)
+		asyncSettleHandler.Run(ctx)
+	}()
+	// Wait for interrupted settlements to be saved before tracking stops and the stores close
+	defer func() { <-settled }()
+
+	e := echo.New()
+	e.HideBanner = true
+	e.Use(middleware.Recover())
+	e.Use(middleware.Logger())
+
+	paymenthttp.NewHandler(verifyHandler, settleHandler).WithAsyncSettle(asyncSettleHandler).RegisterRoutes(e)
+	paymenthttp.NewX402Handler(verifyHandler, settleHandler, tokenRegistry).RegisterRoutes(e)
+	paymenthttp.NewEventsHandler(paymentWatcher).RegisterRoutes(e)
+	if sponsorHandler != nil {
+		sponsorHandler.RegisterRoutes(e)
+	}
+
+	errCh := make(chan error, 1)
+	go func() {
+		log.Printf("stacks facilitator listening on %s", cfg.Address())
+		if err := e.Start(cfg.Address()); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			errCh <- err
+		}
+		close(errCh)
+	}()
+
+	select {
+	case err := <-errCh:
+		return err
+	case <-ctx.Done():
+	}
+
+	log.Printf("shutting down")
+	// End event streams first; they would otherwise hold the graceful shutdown open
+	paymentWatcher.Close()
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), time.Duration(cfg.ShutdownTimeout))
+	defer cancel()
+
+	return e.Shutdown(shutdownCtx)
+}
+
+// openPaymentStore opens the durable payment store when a path is configured,
+// falling back to an in-memory store otherwise
+func openPaymentStore(path string) (command.PaymentStore, func(), error) {
+	if path == "" {
+		log.Printf("payment store: in-memory (consumed payments are lost on restart)")
+		return persistence.NewMemoryPaymentStore(), func() {}, nil
+	}
+
+	store, err := persistence.OpenFilePaymentStore(path)
+	if err != nil {
+		return nil, nil, err
+	}
+	log.Printf("payment store: %s", path)
+	return store, func() { store.Close() }, nil
+}
+
+// openDeliveryLog opens the durable webhook delivery log when a path is configured,
+// falling back to an in-memory log otherwise
+func openDeliveryLog(path string) (webhook.DeliveryLog, func(), error) {
+	if path == "" {
+		log.Printf("webhook delivery log: in-memory (pending deliveries are lost on restart)")
+		return persistence.NewMemoryDeliveryLog(), func() {}, nil
+	}
+
+	deliveryLog, err := persistence.OpenFileDeliveryLog(path)
+	if err != nil {
+		return nil, nil, err
+	}
+	log.Printf("webhook delivery log: %s", path)
+	return deliveryLog, func() { deliveryLog.Close() }, nil
+}
+
+// newTokenRegistry extends the default registry with the configured tokens, reading a
+// token's symbol or decimals from its contract when the configuration leaves them out
+func newTokenRegistry(ctx context.Context, tokens []config.TokenConfig, adapter *blockchain.StacksClientAdapter) (*service.TokenRegistry, error) {
+	registry := service.DefaultTokenRegistry()
+
+	for _, token := range tokens {
+		tokenType, err := valueobject.NewTokenType(token.Type)
+		if err != nil {
+			return nil, err
+		}
+
+		var lookup service.TokenContract
+		var lookupNetwork valueobject.Network
+		for name, contractCfg := range token.Contracts {
+			network, err := valueobject.NewNetwork(name)
+			if err != nil {
+				return nil, err
+			}
+			contract := service.TokenContract{ContractID: contractCfg.ContractID, AssetName: contractCfg.AssetName}
+			registry.Register(network, tokenType, contract)
+			if lookupNetwork == "" || network.IsMainnet() {
+				lookup, lookupNetwork = contract, network
+			}
+		}
+
+		metadata := service.TokenMetadata{Symbol: token.Symbol}
+		if token.Decimals != nil {
+			metadata.Decimals = *token.Decimals
+		}
+		if token.Symbol == "" || token.Decimals == nil {
+			discovered, err := adapter.FetchTokenMetadata(ctx, lookup, lookupNetwork)
+			if err != nil {
+				return nil, fmt.Errorf("failed to read metadata of token %s: %w", tokenType, err)
+			}
+			if token.Symbol == "" {
+				metadata.Symbol = discovered.Symbol
+			}
+			if token.Decimals == nil {
+				metadata.Decimals = discovered.Decimals
+			}
+		}
+		registry.SetMetadata(tokenType, metadata)
+
+		log.Printf("token: %s (%s, %d decimals)", tokenType, metadata.Symbol, metadata.Decimals)
+	}
+
+	return registry, nil
+}
+
+// newSponsor builds the fee sponsor and its policy from configuration
+func newSponsor(cfg config.SponsorConfig, mainnetClient, testnetClient *stacks.Client) (*blockchain.TransactionSponsor, service.SponsorPolicy, error) {
+	var keys []*secp256k1.PrivateKey
+	for i, hexKey := range cfg.PrivateKeys {
+		key, err := secp256k1.ParsePrivateKey(hexKey)
+		if err != nil {
+			return nil, service.SponsorPolicy{}, fmt.Errorf("invalid sponsor private key %d: %w", i+1, err)
+		}
+		keys = append(keys, key)
+	}
+
+	policy := service.SponsorPolicy{
+		MaxFee:           cfg.MaxFee,
+		AllowedContracts: cfg.AllowedContracts,
+	}
+	for _, token := range cfg.AllowedTokens {
+		tokenType, err := valueobject.NewTokenType(token)
+		if err != nil {
+			return nil, service.SponsorPolicy{}, err
+		}
+		policy.AllowedTokens = append(policy.AllowedTokens, tokenType)
+	}
+
+	sponsor := blockchain.NewTransactionSponsor(keys, mainnetClient, testnetClient)
+	for _, address := range sponsor.Addresses(valueobject.NetworkMainnet) {
+		log.Printf("sponsor: %s, max fee %d", address, cfg.MaxFee)
+	}
+	return sponsor, policy, nil
+}
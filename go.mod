@@ -3,6 +3,8 @@ module github.com/x402stacks/stacks-facilitator
 go 1.24.3
 
 require (
+	github.com/decred/dcrd/crypto/ripemd160 v1.0.2
+	github.com/decred/dcrd/dcrec/secp256k1/v4 v4.4.1
 	github.com/labstack/echo/v4 v4.15.0
 	github.com/stretchr/testify v1.11.1
 )
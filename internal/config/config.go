@@ -0,0 +1,481 @@
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/x402stacks/stacks-facilitator/internal/payment/domain/valueobject"
+	"github.com/x402stacks/stacks-facilitator/internal/stacks/secp256k1"
+)
+
+// Duration is a time.Duration that reads from JSON as a Go duration string ("2s", "500ms")
+type Duration time.Duration
+
+// UnmarshalJSON parses a duration string or a number of nanoseconds
+func (d *Duration) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err == nil {
+		parsed, err := time.ParseDuration(s)
+		if err != nil {
+			return fmt.Errorf("invalid duration %q: %w", s, err)
+		}
+		*d = Duration(parsed)
+		return nil
+	}
+
+	var n int64
+	if err := json.Unmarshal(data, &n); err != nil {
+		return errors.New("duration must be a string like \"2s\" or an integer number of nanoseconds")
+	}
+	*d = Duration(n)
+	return nil
+}
+
+// MarshalJSON writes the duration as a Go duration string
+func (d Duration) MarshalJSON() ([]byte, error) {
+	return json.Marshal(time.Duration(d).String())
+}
+
+// RetryConfig controls how a command handler polls the blockchain
+type RetryConfig struct {
+	MaxRetries int      `json:"max_retries"`
+	RetryDelay Duration `json:"retry_delay"`
+}
+
+// AsyncSettleConfig sizes the worker pool that confirms asynchronous settlements
+type AsyncSettleConfig struct {
+	Workers   int      `json:"workers"`    // Settlements confirmed at once
+	QueueSize int      `json:"queue_size"` // Settlements waiting for a worker before new ones are refused
+	Retention Duration `json:"retention"`  // How long a finished settlement can still be looked up
+}
+
+// ConfirmationConfig controls how deep a transaction must be before a payment counts
+type ConfirmationConfig struct {
+	MinConfirmations     uint64   `json:"min_confirmations"`      // Stacks blocks, including the one holding the transaction
+	MinBurnConfirmations uint64   `json:"min_burn_confirmations"` // Bitcoin burn blocks, including the one anchoring the transaction
+	TipMaxAge            Duration `json:"tip_max_age"`            // How long a fetched chain tip is reused
+}
+
+// NetworkConfig holds the API endpoints for each Stacks network
+type NetworkConfig struct {
+	MainnetAPIURL string `json:"mainnet_api_url"`
+	TestnetAPIURL string `json:"testnet_api_url"`
+}
+
+// SponsorConfig controls paying the fee of sponsored transactions; disabled without private keys
+type SponsorConfig struct {
+	PrivateKeys      []string `json:"private_keys"`      // Hex secp256k1 keys of the fee-paying accounts
+	MaxFee           uint64   `json:"max_fee"`           // Fee cap per transaction in microSTX
+	AllowedTokens    []string `json:"allowed_tokens"`    // Token types that may be sponsored; any when empty
+	AllowedContracts []string `json:"allowed_contracts"` // Contract IDs that may be called; any when empty
+}
+
+// WebhookConfig controls payment notifications; disabled without a secret
+type WebhookConfig struct {
+	Secret         string   `json:"secret"`          // HMAC key deliveries are signed with
+	URLs           []string `json:"urls"`            // Notified of every payment event
+	MaxAttempts    int      `json:"max_attempts"`    // Attempts per delivery before giving up
+	InitialBackoff Duration `json:"initial_backoff"` // Delay before the first retry, doubled after each failure
+	MaxBackoff     Duration `json:"max_backoff"`     // Longest delay between retries
+	Timeout        Duration `json:"timeout"`         // Time allowed for each attempt
+	LogPath        string   `json:"log_path"`        // File recording deliveries; empty keeps them in memory
+}
+
+// TokenContractConfig is the SIP-010 contract backing a token on one network
+type TokenContractConfig struct {
+	ContractID string `json:"contract_id"` // Contract principal, e.g. SP3K8BC0PPEVCV7NZ6QSRWPQ2JE9E5B6N3PA0KBR9.token-alex
+	AssetName  string `json:"asset_name"`  // Fungible token name declared by define-fungible-token
+}
+
+// TokenConfig adds a SIP-010 token to the registry, or replaces a built-in one
+type TokenConfig struct {
+	Type      string                         `json:"type"`      // Token type used in requests, e.g. ALEX
+	Symbol    string                         `json:"symbol"`    // Display symbol; read from get-symbol when empty
+	Decimals  *int                           `json:"decimals"`  // Decimal places; read from get-decimals when omitted
+	Contracts map[string]TokenContractConfig `json:"contracts"` // Contract per network name ("mainnet", "testnet")
+}
+
+// maxTokenDecimals is the most decimal places a u128 balance can meaningfully carry
+const maxTokenDecimals = 38
+
+// builtinTokens are the token types known without configuration
+var builtinTokens = []valueobject.TokenType{valueobject.TokenSTX, valueobject.TokenSBTC, valueobject.TokenUSDCX}
+
+// Enabled returns true when at least one sponsor key is configured
+func (s SponsorConfig) Enabled() bool {
+	return len(s.PrivateKeys) > 0
+}
+
+// Enabled returns true when a webhook secret is configured. Per-settlement callbacks need
+// nothing more; URLs only add global subscribers.
+func (w WebhookConfig) Enabled() bool {
+	return w.Secret != ""
+}
+
+// Config is the complete server configuration
+type Config struct {
+	Port            int           `json:"port"`
+	ShutdownTimeout Duration      `json:"shutdown_timeout"`
+	HTTPTimeout     Duration      `json:"http_timeout"`
+	Networks        NetworkConfig `json:"networks"`
+	Verify          RetryConfig   `json:"verify"`
+	Settle          RetryConfig   `json:"settle"`
+	PaymentEvents   RetryConfig   `json:"payment_events"` // Polling behind each payment event stream
+
+	Confirmations ConfirmationConfig `json:"confirmations"`
+	AsyncSettle   AsyncSettleConfig  `json:"async_settle"`
+
+	// PaymentStorePath is the file used to record consumed payments; empty keeps them in memory
+	PaymentStorePath string `json:"payment_store_path"`
+
+	Sponsor  SponsorConfig `json:"sponsor"`
+	Webhooks WebhookConfig `json:"webhooks"`
+
+	// Tokens lists SIP-010 tokens in addition to the built-in sBTC and USDCx
+	Tokens []TokenConfig `json:"tokens"`
+}
+
+// Default returns the configuration used when nothing is overridden
+func Default() Config {
+	return Config{
+		Port:            8080,
+		ShutdownTimeout: Duration(15 * time.Second),
+		HTTPTimeout:     Duration(30 * time.Second),
+		Networks: NetworkConfig{
+			MainnetAPIURL: valueobject.NetworkMainnet.APIBaseURL(),
+			TestnetAPIURL: valueobject.NetworkTestnet.APIBaseURL(),
+		},
+		Verify: RetryConfig{
+			MaxRetries: 10,
+			RetryDelay: Duration(2 * time.Second),
+		},
+		Settle: RetryConfig{
+			MaxRetries: 15,
+			RetryDelay: Duration(2 * time.Second),
+		},
+		PaymentEvents: RetryConfig{
+			MaxRetries: 900,
+			RetryDelay: Duration(2 * time.Second),
+		},
+		Confirmations: ConfirmationConfig{
+			TipMaxAge: Duration(5 * time.Second),
+		},
+		AsyncSettle: AsyncSettleConfig{
+			Workers:   8,
+			QueueSize: 256,
+			Retention: Duration(24 * time.Hour),
+		},
+		Sponsor: SponsorConfig{
+			MaxFee: 100000,
+		},
+		Webhooks: WebhookConfig{
+			MaxAttempts:    8,
+			InitialBackoff: Duration(5 * time.Second),
+			MaxBackoff:     Duration(10 * time.Minute),
+			Timeout:        Duration(10 * time.Second),
+		},
+	}
+}
+
+// Load builds the configuration from defaults, an optional JSON file and the environment.
+// The file is read from CONFIG_FILE when set; environment variables take precedence over it.
+func Load() (Config, error) {
+	return load(os.LookupEnv)
+}
+
+// load is Load with an injectable environment lookup
+func load(lookup func(string) (string, bool)) (Config, error) {
+	cfg := Default()
+
+	if path, ok := lookup("CONFIG_FILE"); ok && path != "" {
+		if err := cfg.loadFile(path); err != nil {
+			return Config{}, err
+		}
+	}
+
+	if err := cfg.loadEnv(lookup); err != nil {
+		return Config{}, err
+	}
+
+	if err := cfg.Validate(); err != nil {
+		return Config{}, err
+	}
+
+	return cfg, nil
+}
+
+// loadFile overlays values from a JSON config file
+func (c *Config) loadFile(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read config file: %w", err)
+	}
+
+	if err := json.Unmarshal(data, c); err != nil {
+		return fmt.Errorf("failed to parse config file %s: %w", path, err)
+	}
+
+	return nil
+}
+
+// loadEnv overlays values from environment variables
+func (c *Config) loadEnv(lookup func(string) (string, bool)) error {
+	if err := envInt(lookup, "PORT", &c.Port); err != nil {
+		return err
+	}
+	if err := envDuration(lookup, "SHUTDOWN_TIMEOUT", &c.ShutdownTimeout); err != nil {
+		return err
+	}
+	if err := envDuration(lookup, "HTTP_TIMEOUT", &c.HTTPTimeout); err != nil {
+		return err
+	}
+	envString(lookup, "MAINNET_API_URL", &c.Networks.MainnetAPIURL)
+	envString(lookup, "TESTNET_API_URL", &c.Networks.TestnetAPIURL)
+	envString(lookup, "PAYMENT_STORE_PATH", &c.PaymentStorePath)
+	if err := envInt(lookup, "VERIFY_MAX_RETRIES", &c.Verify.MaxRetries); err != nil {
+		return err
+	}
+	if err := envDuration(lookup, "VERIFY_RETRY_DELAY", &c.Verify.RetryDelay); err != nil {
+		return err
+	}
+	if err := envInt(lookup, "SETTLE_MAX_RETRIES", &c.Settle.MaxRetries); err != nil {
+		return err
+	}
+	if err := envDuration(lookup, "SETTLE_RETRY_DELAY", &c.Settle.RetryDelay); err != nil {
+		return err
+	}
+	if err := envInt(lookup, "PAYMENT_EVENTS_MAX_RETRIES", &c.PaymentEvents.MaxRetries); err != nil {
+		return err
+	}
+	if err := envDuration(lookup, "PAYMENT_EVENTS_RETRY_DELAY", &c.PaymentEvents.RetryDelay); err != nil {
+		return err
+	}
+	if err := envUint64(lookup, "MIN_CONFIRMATIONS", &c.Confirmations.MinConfirmations); err != nil {
+		return err
+	}
+	if err := envUint64(lookup, "MIN_BURN_CONFIRMATIONS", &c.Confirmations.MinBurnConfirmations); err != nil {
+		return err
+	}
+	if err := envDuration(lookup, "CHAIN_TIP_MAX_AGE", &c.Confirmations.TipMaxAge); err != nil {
+		return err
+	}
+	if err := envInt(lookup, "ASYNC_SETTLE_WORKERS", &c.AsyncSettle.Workers); err != nil {
+		return err
+	}
+	if err := envInt(lookup, "ASYNC_SETTLE_QUEUE_SIZE", &c.AsyncSettle.QueueSize); err != nil {
+		return err
+	}
+	if err := envDuration(lookup, "SETTLEMENT_RETENTION", &c.AsyncSettle.Retention); err != nil {
+		return err
+	}
+	envList(lookup, "SPONSOR_PRIVATE_KEYS", &c.Sponsor.PrivateKeys)
+	if err := envUint64(lookup, "SPONSOR_MAX_FEE", &c.Sponsor.MaxFee); err != nil {
+		return err
+	}
+	envList(lookup, "SPONSOR_ALLOWED_TOKENS", &c.Sponsor.AllowedTokens)
+	envList(lookup, "SPONSOR_ALLOWED_CONTRACTS", &c.Sponsor.AllowedContracts)
+	envString(lookup, "WEBHOOK_SECRET", &c.Webhooks.Secret)
+	envList(lookup, "WEBHOOK_URLS", &c.Webhooks.URLs)
+	if err := envInt(lookup, "WEBHOOK_MAX_ATTEMPTS", &c.Webhooks.MaxAttempts); err != nil {
+		return err
+	}
+	if err := envDuration(lookup, "WEBHOOK_INITIAL_BACKOFF", &c.Webhooks.InitialBackoff); err != nil {
+		return err
+	}
+	if err := envDuration(lookup, "WEBHOOK_MAX_BACKOFF", &c.Webhooks.MaxBackoff); err != nil {
+		return err
+	}
+	if err := envDuration(lookup, "WEBHOOK_TIMEOUT", &c.Webhooks.Timeout); err != nil {
+		return err
+	}
+	envString(lookup, "WEBHOOK_LOG_PATH", &c.Webhooks.LogPath)
+	return nil
+}
+
+// Validate checks that the configuration is usable
+func (c Config) Validate() error {
+	if c.Port <= 0 || c.Port > 65535 {
+		return fmt.Errorf("invalid port: %d", c.Port)
+	}
+	if c.Networks.MainnetAPIURL == "" || c.Networks.TestnetAPIURL == "" {
+		return errors.New("mainnet and testnet API URLs are required")
+	}
+	if c.HTTPTimeout <= 0 {
+		return errors.New("http timeout must be positive")
+	}
+	if c.Verify.MaxRetries <= 0 || c.Settle.MaxRetries <= 0 || c.PaymentEvents.MaxRetries <= 0 {
+		return errors.New("max retries must be positive")
+	}
+	if c.Verify.RetryDelay <= 0 || c.Settle.RetryDelay <= 0 || c.PaymentEvents.RetryDelay <= 0 {
+		return errors.New("retry delay must be positive")
+	}
+	if c.Confirmations.TipMaxAge < 0 {
+		return errors.New("chain tip max age cannot be negative")
+	}
+	if c.AsyncSettle.Workers <= 0 || c.AsyncSettle.QueueSize < 0 {
+		return errors.New("async settle needs at least one worker and a non-negative queue size")
+	}
+	if c.AsyncSettle.Retention <= 0 {
+		return errors.New("settlement retention must be positive")
+	}
+	if err := c.Webhooks.validate(); err != nil {
+		return err
+	}
+	known := append([]valueobject.TokenType(nil), builtinTokens...)
+	for i, token := range c.Tokens {
+		tokenType, err := token.validate()
+		if err != nil {
+			return fmt.Errorf("invalid token %d: %w", i+1, err)
+		}
+		known = append(known, tokenType)
+	}
+	if c.Sponsor.Enabled() {
+		for i, key := range c.Sponsor.PrivateKeys {
+			if _, err := secp256k1.ParsePrivateKey(key); err != nil {
+				return fmt.Errorf("invalid sponsor private key %d: %w", i+1, err)
+			}
+		}
+		for _, token := range c.Sponsor.AllowedTokens {
+			tokenType, err := valueobject.NewTokenType(token)
+			if err != nil {
+				return fmt.Errorf("invalid sponsor allowed token: %w", err)
+			}
+			if !slices.Contains(known, tokenType) {
+				return fmt.Errorf("invalid sponsor allowed token: %s is not a configured token", token)
+			}
+		}
+	}
+	return nil
+}
+
+// validate checks the webhook settings
+func (w WebhookConfig) validate() error {
+	if !w.Enabled() {
+		if len(w.URLs) > 0 {
+			return errors.New("webhook urls need a webhook secret to sign deliveries")
+		}
+		return nil
+	}
+	for _, raw := range w.URLs {
+		u, err := url.Parse(raw)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return fmt.Errorf("invalid webhook url %q: must be an absolute http or https url", raw)
+		}
+	}
+	if w.MaxAttempts <= 0 {
+		return errors.New("webhook max attempts must be positive")
+	}
+	if w.InitialBackoff <= 0 || w.MaxBackoff < w.InitialBackoff {
+		return errors.New("webhook backoff must be positive, with max backoff at least the initial backoff")
+	}
+	if w.Timeout <= 0 {
+		return errors.New("webhook timeout must be positive")
+	}
+	return nil
+}
+
+// validate checks a token entry and returns its token type
+func (t TokenConfig) validate() (valueobject.TokenType, error) {
+	tokenType, err := valueobject.NewTokenType(t.Type)
+	if err != nil {
+		return "", err
+	}
+	if tokenType.IsNative() {
+		return "", errors.New("STX is native and has no token contract")
+	}
+	if t.Decimals != nil && (*t.Decimals < 0 || *t.Decimals > maxTokenDecimals) {
+		return "", fmt.Errorf("%s decimals must be between 0 and %d", tokenType, maxTokenDecimals)
+	}
+	if len(t.Contracts) == 0 {
+		return "", fmt.Errorf("%s needs a contract on at least one network", tokenType)
+	}
+	for name, contract := range t.Contracts {
+		network, err := valueobject.NewNetwork(name)
+		if err != nil {
+			return "", fmt.Errorf("%s: %w", tokenType, err)
+		}
+		principal, err := valueobject.NewPrincipal(contract.ContractID)
+		if err != nil || !principal.IsContract() {
+			return "", fmt.Errorf("%s: invalid %s contract ID %q", tokenType, network, contract.ContractID)
+		}
+		if principal.Network() != network {
+			return "", fmt.Errorf("%s: contract %s is not a %s contract", tokenType, contract.ContractID, network)
+		}
+		if contract.AssetName == "" {
+			return "", fmt.Errorf("%s: %s asset name is required", tokenType, network)
+		}
+	}
+	return tokenType, nil
+}
+
+// Address returns the listen address for the HTTP server
+func (c Config) Address() string {
+	return ":" + strconv.Itoa(c.Port)
+}
+
+func envString(lookup func(string) (string, bool), key string, dst *string) {
+	if v, ok := lookup(key); ok && v != "" {
+		*dst = v
+	}
+}
+
+func envInt(lookup func(string) (string, bool), key string, dst *int) error {
+	v, ok := lookup(key)
+	if !ok || v == "" {
+		return nil
+	}
+	n, err := strconv.Atoi(v)
+	if err != nil {
+		return fmt.Errorf("invalid %s: %w", key, err)
+	}
+	*dst = n
+	return nil
+}
+
+func envDuration(lookup func(string) (string, bool), key string, dst *Duration) error {
+	v, ok := lookup(key)
+	if !ok || v == "" {
+		return nil
+	}
+	d, err := time.ParseDuration(v)
+	if err != nil {
+		return fmt.Errorf("invalid %s: %w", key, err)
+	}
+	*dst = Duration(d)
+	return nil
+}
+
+func envUint64(lookup func(string) (string, bool), key string, dst *uint64) error {
+	v, ok := lookup(key)
+	if !ok || v == "" {
+		return nil
+	}
+	n, err := strconv.ParseUint(v, 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid %s: %w", key, err)
+	}
+	*dst = n
+	return nil
+}
+
+// envList reads a comma-separated list, ignoring empty entries
+func envList(lookup func(string) (string, bool), key string, dst *[]string) {
+	v, ok := lookup(key)
+	if !ok || v == "" {
+		return
+	}
+	var values []string
+	for _, item := range strings.Split(v, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			values = append(values, item)
+		}
+	}
+	*dst = values
+}
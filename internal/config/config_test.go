@@ -0,0 +1,291 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func envFrom(values map[string]string) func(string) (string, bool) {
+	return func(key string) (string, bool) {
+		v, ok := values[key]
+		return v, ok
+	}
+}
+
+func TestLoad_Defaults(t *testing.T) {
+	cfg, err := load(envFrom(nil))
+
+	require.NoError(t, err)
+	assert.Equal(t, 8080, cfg.Port)
+	assert.Equal(t, ":8080", cfg.Address())
+	assert.Equal(t, "https://api.mainnet.hiro.so", cfg.Networks.MainnetAPIURL)
+	assert.Equal(t, "https://api.testnet.hiro.so", cfg.Networks.TestnetAPIURL)
+	assert.Equal(t, 10, cfg.Verify.MaxRetries)
+	assert.Equal(t, 15, cfg.Settle.MaxRetries)
+	assert.Equal(t, 2*time.Second, time.Duration(cfg.Settle.RetryDelay))
+}
+
+func TestLoad_EnvOverrides(t *testing.T) {
+	cfg, err := load(envFrom(map[string]string{
+		"PORT":               "9090",
+		"TESTNET_API_URL":    "http://localhost:3999",
+		"HTTP_TIMEOUT":       "5s",
+		"VERIFY_MAX_RETRIES": "3",
+		"SETTLE_RETRY_DELAY": "500ms",
+	}))
+
+	require.NoError(t, err)
+	assert.Equal(t, 9090, cfg.Port)
+	assert.Equal(t, "http://localhost:3999", cfg.Networks.TestnetAPIURL)
+	assert.Equal(t, 5*time.Second, time.Duration(cfg.HTTPTimeout))
+	assert.Equal(t, 3, cfg.Verify.MaxRetries)
+	assert.Equal(t, 500*time.Millisecond, time.Duration(cfg.Settle.RetryDelay))
+}
+
+func TestLoad_ConfigFileWithEnvPrecedence(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.json")
+	err := os.WriteFile(path, []byte(`{
+		"port": 7070,
+		"networks": {"mainnet_api_url": "https://mainnet.example.com"},
+		"settle": {"max_retries": 30, "retry_delay": "1s"}
+	}`), 0o600)
+	require.NoError(t, err)
+
+	cfg, err := load(envFrom(map[string]string{
+		"CONFIG_FILE": path,
+		"PORT":        "6060",
+	}))
+
+	require.NoError(t, err)
+	assert.Equal(t, 6060, cfg.Port)
+	assert.Equal(t, "https://mainnet.example.com", cfg.Networks.MainnetAPIURL)
+	assert.Equal(t, "https://api.testnet.hiro.so", cfg.Networks.TestnetAPIURL)
+	assert.Equal(t, 30, cfg.Settle.MaxRetries)
+	assert.Equal(t, time.Second, time.Duration(cfg.Settle.RetryDelay))
+}
+
+func TestLoad_InvalidEnv(t *testing.T) {
+	_, err := load(envFrom(map[string]string{"VERIFY_RETRY_DELAY": "soon"}))
+
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "VERIFY_RETRY_DELAY")
+}
+
+func TestLoad_MissingConfigFile(t *testing.T) {
+	_, err := load(envFrom(map[string]string{"CONFIG_FILE": "/does/not/exist.json"}))
+
+	assert.Error(t, err)
+}
+
+func TestConfig_ValidateRejectsBadPort(t *testing.T) {
+	cfg := Default()
+	cfg.Port = 0
+
+	assert.Error(t, cfg.Validate())
+}
+
+func TestLoad_Sponsor(t *testing.T) {
+	cfg, err := load(envFrom(map[string]string{
+		"SPONSOR_PRIVATE_KEYS":      "4242424242424242424242424242424242424242424242424242424242424242" + "01," + "4343434343434343434343434343434343434343434343434343434343434343",
+		"SPONSOR_MAX_FEE":           "20000",
+		"SPONSOR_ALLOWED_TOKENS":    "sBTC, USDCx",
+		"SPONSOR_ALLOWED_CONTRACTS": "SM3VDXK3WZZSA84XXFKAFAF15NNZX32CTSG82JFQ4.sbtc-token,",
+	}))
+
+	require.NoError(t, err)
+	assert.True(t, cfg.Sponsor.Enabled())
+	assert.Len(t, cfg.Sponsor.PrivateKeys, 2)
+	assert.Equal(t, uint64(20000), cfg.Sponsor.MaxFee)
+	assert.Equal(t, []string{"sBTC", "USDCx"}, cfg.Sponsor.AllowedTokens)
+	assert.Equal(t, []string{"SM3VDXK3WZZSA84XXFKAFAF15NNZX32CTSG82JFQ4.sbtc-token"}, cfg.Sponsor.AllowedContracts)
+}
+
+func TestLoad_SponsorDisabledByDefault(t *testing.T) {
+	cfg, err := load(envFrom(nil))
+
+	require.NoError(t, err)
+	assert.False(t, cfg.Sponsor.Enabled())
+	assert.Equal(t, uint64(100000), cfg.Sponsor.MaxFee)
+}
+
+func TestConfig_ValidateRejectsBadSponsor(t *testing.T) {
+	badKey := Default()
+	badKey.Sponsor.PrivateKeys = []string{"4242424242424242424242424242424242424242424242424242424242424242", "not-a-key"}
+	assert.Error(t, badKey.Validate())
+
+	badToken := Default()
+	badToken.Sponsor.PrivateKeys = []string{"4242424242424242424242424242424242424242424242424242424242424242"}
+	badToken.Sponsor.AllowedTokens = []string{"DOGE"}
+	assert.Error(t, badToken.Validate())
+}
+
+func TestLoad_Confirmations(t *testing.T) {
+	cfg, err := load(envFrom(nil))
+	require.NoError(t, err)
+	assert.Zero(t, cfg.Confirmations.MinConfirmations)
+	assert.Equal(t, 5*time.Second, time.Duration(cfg.Confirmations.TipMaxAge))
+
+	path := filepath.Join(t.TempDir(), "config.json")
+	err = os.WriteFile(path, []byte(`{
+		"confirmations": {"min_confirmations": 3, "min_burn_confirmations": 1, "tip_max_age": "10s"}
+	}`), 0o600)
+	require.NoError(t, err)
+
+	cfg, err = load(envFrom(map[string]string{
+		"CONFIG_FILE":       path,
+		"MIN_CONFIRMATIONS": "6",
+	}))
+
+	require.NoError(t, err)
+	assert.Equal(t, uint64(6), cfg.Confirmations.MinConfirmations)
+	assert.Equal(t, uint64(1), cfg.Confirmations.MinBurnConfirmations)
+	assert.Equal(t, 10*time.Second, time.Duration(cfg.Confirmations.TipMaxAge))
+
+	_, err = load(envFrom(map[string]string{"CHAIN_TIP_MAX_AGE": "-1s"}))
+	assert.Error(t, err)
+}
+
+func TestLoad_AsyncSettle(t *testing.T) {
+	cfg, err := load(envFrom(nil))
+	require.NoError(t, err)
+	assert.Equal(t, 8, cfg.AsyncSettle.Workers)
+	assert.Equal(t, 256, cfg.AsyncSettle.QueueSize)
+	assert.Equal(t, 24*time.Hour, time.Duration(cfg.AsyncSettle.Retention))
+
+	cfg, err = load(envFrom(map[string]string{
+		"ASYNC_SETTLE_WORKERS":    "2",
+		"ASYNC_SETTLE_QUEUE_SIZE": "0",
+		"SETTLEMENT_RETENTION":    "1h",
+	}))
+	require.NoError(t, err)
+	assert.Equal(t, 2, cfg.AsyncSettle.Workers)
+	assert.Equal(t, 0, cfg.AsyncSettle.QueueSize)
+	assert.Equal(t, time.Hour, time.Duration(cfg.AsyncSettle.Retention))
+
+	_, err = load(envFrom(map[string]string{"ASYNC_SETTLE_WORKERS": "0"}))
+	assert.Error(t, err)
+}
+
+func TestLoad_PaymentEvents(t *testing.T) {
+	cfg, err := load(envFrom(nil))
+	require.NoError(t, err)
+	assert.Equal(t, 900, cfg.PaymentEvents.MaxRetries)
+	assert.Equal(t, 2*time.Second, time.Duration(cfg.PaymentEvents.RetryDelay))
+
+	cfg, err = load(envFrom(map[string]string{
+		"PAYMENT_EVENTS_MAX_RETRIES": "60",
+		"PAYMENT_EVENTS_RETRY_DELAY": "5s",
+	}))
+	require.NoError(t, err)
+	assert.Equal(t, 60, cfg.PaymentEvents.MaxRetries)
+	assert.Equal(t, 5*time.Second, time.Duration(cfg.PaymentEvents.RetryDelay))
+
+	_, err = load(envFrom(map[string]string{"PAYMENT_EVENTS_MAX_RETRIES": "0"}))
+	assert.Error(t, err)
+}
+
+func TestLoad_Webhooks(t *testing.T) {
+	cfg, err := load(envFrom(nil))
+	require.NoError(t, err)
+	assert.False(t, cfg.Webhooks.Enabled())
+	assert.Equal(t, 8, cfg.Webhooks.MaxAttempts)
+
+	cfg, err = load(envFrom(map[string]string{
+		"WEBHOOK_SECRET":          "whsec_test",
+		"WEBHOOK_URLS":            "https://a.example/hooks, https://b.example/hooks",
+		"WEBHOOK_MAX_ATTEMPTS":    "3",
+		"WEBHOOK_INITIAL_BACKOFF": "1s",
+		"WEBHOOK_MAX_BACKOFF":     "1m",
+		"WEBHOOK_TIMEOUT":         "5s",
+		"WEBHOOK_LOG_PATH":        "/var/lib/facilitator/webhooks.jsonl",
+	}))
+	require.NoError(t, err)
+	assert.True(t, cfg.Webhooks.Enabled())
+	assert.Equal(t, []string{"https://a.example/hooks", "https://b.example/hooks"}, cfg.Webhooks.URLs)
+	assert.Equal(t, 3, cfg.Webhooks.MaxAttempts)
+	assert.Equal(t, time.Second, time.Duration(cfg.Webhooks.InitialBackoff))
+	assert.Equal(t, time.Minute, time.Duration(cfg.Webhooks.MaxBackoff))
+	assert.Equal(t, 5*time.Second, time.Duration(cfg.Webhooks.Timeout))
+	assert.Equal(t, "/var/lib/facilitator/webhooks.jsonl", cfg.Webhooks.LogPath)
+
+	// A secret alone enables per-settlement callbacks, without global URLs
+	cfg, err = load(envFrom(map[string]string{"WEBHOOK_SECRET": "whsec_test"}))
+	require.NoError(t, err)
+	assert.True(t, cfg.Webhooks.Enabled())
+	assert.Empty(t, cfg.Webhooks.URLs)
+
+	for name, env := range map[string]map[string]string{
+		"urls without secret": {"WEBHOOK_URLS": "https://a.example/hooks"},
+		"relative url":        {"WEBHOOK_SECRET": "s", "WEBHOOK_URLS": "/hooks"},
+		"no attempts":         {"WEBHOOK_SECRET": "s", "WEBHOOK_MAX_ATTEMPTS": "0"},
+		"max below initial":   {"WEBHOOK_SECRET": "s", "WEBHOOK_INITIAL_BACKOFF": "1m", "WEBHOOK_MAX_BACKOFF": "1s"},
+	} {
+		_, err := load(envFrom(env))
+		assert.Error(t, err, name)
+	}
+}
+
+func TestLoad_Tokens(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.json")
+	err := os.WriteFile(path, []byte(`{
+		"tokens": [{
+			"type": "alex",
+			"symbol": "ALEX",
+			"decimals": 8,
+			"contracts": {
+				"mainnet": {"contract_id": "SP3K8BC0PPEVCV7NZ6QSRWPQ2JE9E5B6N3PA0KBR9.token-alex", "asset_name": "alex"}
+			}
+		}],
+		"sponsor": {
+			"private_keys": ["4242424242424242424242424242424242424242424242424242424242424242"],
+			"allowed_tokens": ["ALEX", "sBTC"]
+		}
+	}`), 0o600)
+	require.NoError(t, err)
+
+	cfg, err := load(envFrom(map[string]string{"CONFIG_FILE": path}))
+
+	require.NoError(t, err)
+	require.Len(t, cfg.Tokens, 1)
+	assert.Equal(t, "alex", cfg.Tokens[0].Type)
+	require.NotNil(t, cfg.Tokens[0].Decimals)
+	assert.Equal(t, 8, *cfg.Tokens[0].Decimals)
+	assert.Equal(t, "alex", cfg.Tokens[0].Contracts["mainnet"].AssetName)
+}
+
+func TestConfig_ValidateRejectsBadTokens(t *testing.T) {
+	alex := TokenContractConfig{ContractID: "SP3K8BC0PPEVCV7NZ6QSRWPQ2JE9E5B6N3PA0KBR9.token-alex", AssetName: "alex"}
+	tooMany := 39
+
+	tests := []struct {
+		name  string
+		token TokenConfig
+	}{
+		{"invalid type", TokenConfig{Type: "token alex", Contracts: map[string]TokenContractConfig{"mainnet": alex}}},
+		{"native STX", TokenConfig{Type: "STX", Contracts: map[string]TokenContractConfig{"mainnet": alex}}},
+		{"no contracts", TokenConfig{Type: "ALEX"}},
+		{"unknown network", TokenConfig{Type: "ALEX", Contracts: map[string]TokenContractConfig{"devnet": alex}}},
+		{"wrong network", TokenConfig{Type: "ALEX", Contracts: map[string]TokenContractConfig{"testnet": alex}}},
+		{"standard principal", TokenConfig{Type: "ALEX", Contracts: map[string]TokenContractConfig{
+			"mainnet": {ContractID: "SP3K8BC0PPEVCV7NZ6QSRWPQ2JE9E5B6N3PA0KBR9", AssetName: "alex"},
+		}}},
+		{"missing asset name", TokenConfig{Type: "ALEX", Contracts: map[string]TokenContractConfig{
+			"mainnet": {ContractID: alex.ContractID},
+		}}},
+		{"too many decimals", TokenConfig{Type: "ALEX", Decimals: &tooMany, Contracts: map[string]TokenContractConfig{"mainnet": alex}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := Default()
+			cfg.Tokens = []TokenConfig{tt.token}
+
+			assert.Error(t, cfg.Validate())
+		})
+	}
+}
@@ -0,0 +1,43 @@
+package command
+
+import (
+	"errors"
+	"fmt"
+)
+
+// BroadcastRejectionReason is the node's reason for refusing a transaction at broadcast
+type BroadcastRejectionReason string
+
+// Rejection reasons with their own response codes. Any other reason the node gives is
+// passed through as is.
+const (
+	RejectionBadNonce                  BroadcastRejectionReason = "BadNonce"
+	RejectionNotEnoughFunds            BroadcastRejectionReason = "NotEnoughFunds"
+	RejectionFeeTooLow                 BroadcastRejectionReason = "FeeTooLow"
+	RejectionConflictingNonceInMempool BroadcastRejectionReason = "ConflictingNonceInMempool"
+	RejectionBadTransactionVersion     BroadcastRejectionReason = "BadTransactionVersion"
+	RejectionContractAlreadyExists     BroadcastRejectionReason = "ContractAlreadyExists"
+	RejectionPostConditionFailed       BroadcastRejectionReason = "PostConditionFailed"
+)
+
+// ErrBroadcastRejected is wrapped by every BroadcastRejectedError
+var ErrBroadcastRejected = errors.New("transaction rejected")
+
+// BroadcastRejectedError is returned when the node refuses to accept a transaction
+type BroadcastRejectedError struct {
+	Reason   BroadcastRejectionReason
+	Message  string // Explanation from the node's reason data, such as "expected nonce 6, got 5"
+	Expected string // Amount or nonce the node required, in decimal; empty when the reason data has none
+	Actual   string // Amount or nonce the transaction had, in decimal; empty when the reason data has none
+	Err      error  // Error from the node client
+}
+
+// Error returns the reason and explanation
+func (e *BroadcastRejectedError) Error() string {
+	return fmt.Sprintf("%s: %s: %s", ErrBroadcastRejected, e.Reason, e.Message)
+}
+
+// Unwrap exposes ErrBroadcastRejected and the node client's error
+func (e *BroadcastRejectedError) Unwrap() []error {
+	return []error{ErrBroadcastRejected, e.Err}
+}
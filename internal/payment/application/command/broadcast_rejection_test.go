@@ -0,0 +1,51 @@
+package command
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/x402stacks/stacks-facilitator/internal/payment/domain/service"
+	"github.com/x402stacks/stacks-facilitator/internal/payment/domain/valueobject"
+)
+
+func TestBroadcastRejectedError(t *testing.T) {
+	cause := errors.New(`broadcast failed: {"error":"transaction rejected","reason":"BadNonce"}`)
+	err := &BroadcastRejectedError{Reason: RejectionBadNonce, Message: "expected nonce 6, got 5", Err: cause}
+
+	assert.Equal(t, "transaction rejected: BadNonce: expected nonce 6, got 5", err.Error())
+	assert.ErrorIs(t, err, ErrBroadcastRejected)
+	assert.ErrorIs(t, err, cause)
+}
+
+func TestSettlePaymentHandler_BroadcastRejected(t *testing.T) {
+	sender, _ := valueobject.NewStacksAddress("ST2J6ZY48GV1EZ5V2V5RB9MP66SW86PYKKQYAC0RQ")
+	recipient, _ := valueobject.NewPrincipal("ST1PQHQKV0RJXZFY1DGX8MNSNYVE3VGZJSRTPGZGM")
+	decoded := service.BlockchainTransaction{
+		TokenType: valueobject.TokenSTX,
+		Sender:    sender,
+		Recipient: recipient,
+		Amount:    valueobject.NewAmount(1000000),
+		Status:    "pending",
+	}
+	broadcaster := &MockBroadcaster{
+		BroadcastFn: func(ctx context.Context, signedTx string, network valueobject.Network) (valueobject.TransactionID, error) {
+			return valueobject.TransactionID{}, &BroadcastRejectedError{Reason: RejectionNotEnoughFunds, Message: "needs 1000180 microSTX, balance is 1000"}
+		},
+	}
+	handler := NewSettlePaymentHandler(broadcaster, decoderReturning(decoded, valueobject.NetworkTestnet), service.NewVerificationService())
+
+	_, err := handler.Handle(context.Background(), SettlePaymentCommand{
+		SignedTransaction: "0x00000001deadbeef",
+		TokenType:         "STX",
+		ExpectedRecipient: "ST1PQHQKV0RJXZFY1DGX8MNSNYVE3VGZJSRTPGZGM",
+		MinAmount:         "500000",
+		Network:           "testnet",
+	})
+
+	var rejected *BroadcastRejectedError
+	require.ErrorAs(t, err, &rejected)
+	assert.Equal(t, RejectionNotEnoughFunds, rejected.Reason)
+}
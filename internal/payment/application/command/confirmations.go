@@ -0,0 +1,39 @@
+package command
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/x402stacks/stacks-facilitator/internal/payment/domain/service"
+	"github.com/x402stacks/stacks-facilitator/internal/payment/domain/valueobject"
+)
+
+// ChainTipProvider interface for reading the current chain tip of a network
+type ChainTipProvider interface {
+	ChainTip(ctx context.Context, network valueobject.Network) (service.ChainTip, error)
+}
+
+// setConfirmations sets how deep a confirmed transaction is below the chain tip. Without
+// a provider a confirmed transaction counts as one block deep.
+func setConfirmations(ctx context.Context, provider ChainTipProvider, tx *service.BlockchainTransaction, network valueobject.Network) error {
+	if !tx.IsConfirmed {
+		tx.Confirmations, tx.BurnConfirmations = 0, 0
+		return nil
+	}
+
+	var tip service.ChainTip
+	if provider != nil {
+		var err error
+		tip, err = provider.ChainTip(ctx, network)
+		if err != nil {
+			return fmt.Errorf("failed to fetch chain tip: %w", err)
+		}
+	}
+	tx.Confirmations, tx.BurnConfirmations = tip.Confirmations(*tx)
+	return nil
+}
+
+// hasConfirmations reports whether a confirmed transaction is as deep as the criteria require
+func hasConfirmations(tx service.BlockchainTransaction, criteria service.VerificationCriteria) bool {
+	return tx.Confirmations >= criteria.MinConfirmations && tx.BurnConfirmations >= criteria.MinBurnConfirmations
+}
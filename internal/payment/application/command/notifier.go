@@ -0,0 +1,94 @@
+package command
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/netip"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/x402stacks/stacks-facilitator/internal/payment/domain/service"
+	"github.com/x402stacks/stacks-facilitator/internal/payment/domain/valueobject"
+)
+
+// ErrInvalidCallbackURL is returned when a callback URL is malformed or webhooks are not enabled
+var ErrInvalidCallbackURL = errors.New("invalid callback url")
+
+// Payment event types, one for each final payment status
+const (
+	EventPaymentConfirmed = "payment.confirmed"
+	EventPaymentFailed    = "payment.failed"
+	EventPaymentDropped   = "payment.dropped"
+)
+
+// PaymentEvent describes a settled payment moving from one status to the next. A payment mined
+// without passing verification moves to failed, and Failures says why.
+type PaymentEvent struct {
+	ID                string
+	Type              string // EventPaymentConfirmed, EventPaymentFailed or EventPaymentDropped
+	TxID              string
+	Network           string
+	TokenType         string
+	PreviousStatus    valueobject.PaymentStatus
+	Status            valueobject.PaymentStatus
+	ReplacedByTxID    string // Transaction that replaced a dropped one, when known
+	SenderAddress     string
+	RecipientAddress  string
+	Amount            string // Base units as a decimal string
+	BlockHeight       uint64
+	Confirmations     uint64
+	BurnConfirmations uint64
+	Failures          []service.VerificationFailure // Why the payment failed verification; empty when confirmed
+	OccurredAt        time.Time
+}
+
+// PaymentNotifier tells subscribers about payment status changes
+type PaymentNotifier interface {
+	// Notify delivers event to every globally configured subscriber and to callbackURLs,
+	// the callback URLs of the settlements that broadcast the payment
+	Notify(ctx context.Context, event PaymentEvent, callbackURLs []string) error
+}
+
+// parseCallbackURL checks that a callback URL is an absolute http(s) URL that can be notified
+func parseCallbackURL(raw string, tracker *PaymentTracker) (string, error) {
+	if raw == "" {
+		return "", nil
+	}
+	if tracker == nil {
+		return "", fmt.Errorf("%w: webhooks are not enabled", ErrInvalidCallbackURL)
+	}
+
+	u, err := url.Parse(raw)
+	if err != nil {
+		return "", fmt.Errorf("%w: %v", ErrInvalidCallbackURL, err)
+	}
+	if (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return "", fmt.Errorf("%w: must be an absolute http or https url", ErrInvalidCallbackURL)
+	}
+
+	// Names are checked again when the dispatcher resolves them; this rejects the obvious cases early
+	host := strings.ToLower(strings.TrimSuffix(u.Hostname(), "."))
+	if host == "localhost" || strings.HasSuffix(host, ".localhost") {
+		return "", fmt.Errorf("%w: %s is not a public host", ErrInvalidCallbackURL, u.Hostname())
+	}
+	if addr, err := netip.ParseAddr(host); err == nil && !PublicAddress(addr) {
+		return "", fmt.Errorf("%w: %s is not a public address", ErrInvalidCallbackURL, u.Hostname())
+	}
+	return u.String(), nil
+}
+
+// PublicAddress reports whether a callback may be delivered to addr. Loopback, link-local,
+// private, unspecified and multicast addresses reach the facilitator's own network and are refused.
+func PublicAddress(addr netip.Addr) bool {
+	addr = addr.Unmap()
+	return addr.IsValid() &&
+		!addr.IsLoopback() &&
+		!addr.IsLinkLocalUnicast() &&
+		!addr.IsLinkLocalMulticast() &&
+		!addr.IsInterfaceLocalMulticast() &&
+		!addr.IsMulticast() &&
+		!addr.IsPrivate() &&
+		!addr.IsUnspecified()
+}
@@ -0,0 +1,98 @@
+package command
+
+import (
+	"context"
+	"net/netip"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/x402stacks/stacks-facilitator/internal/payment/domain/service"
+	"github.com/x402stacks/stacks-facilitator/internal/payment/domain/valueobject"
+)
+
+// MockNotifier records the events it is asked to deliver
+type MockNotifier struct {
+	mu        sync.Mutex
+	events    []PaymentEvent
+	callbacks []string
+	Err       error
+}
+
+func (m *MockNotifier) Notify(ctx context.Context, event PaymentEvent, callbackURLs []string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.events = append(m.events, event)
+	m.callbacks = append(m.callbacks, callbackURLs...)
+	return m.Err
+}
+
+func (m *MockNotifier) Events() []PaymentEvent {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]PaymentEvent(nil), m.events...)
+}
+
+func TestSettlePaymentHandler_InvalidCallbackURL(t *testing.T) {
+	enabled := NewPaymentTracker(rejectingBroadcaster(t), service.NewVerificationService(), &MockNotifier{})
+	tests := []struct {
+		name        string
+		callbackURL string
+		tracker     *PaymentTracker
+	}{
+		{"relative", "/hooks/payments", enabled},
+		{"not http", "ftp://merchant.example/hooks", enabled},
+		{"malformed", "https://merchant example/%zz", enabled},
+		{"webhooks disabled", "https://merchant.example/hooks", nil},
+		{"localhost", "http://localhost:8080/hooks", enabled},
+		{"localhost subdomain", "http://api.localhost/hooks", enabled},
+		{"loopback", "http://127.0.0.1/hooks", enabled},
+		{"ipv6 loopback", "http://[::1]:8080/hooks", enabled},
+		{"cloud metadata", "http://169.254.169.254/latest/meta-data/", enabled},
+		{"private", "https://10.0.0.5/hooks", enabled},
+		{"unspecified", "http://0.0.0.0/hooks", enabled},
+		{"ipv4-mapped private", "http://[::ffff:192.168.1.1]/hooks", enabled},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := NewSettlePaymentHandler(rejectingBroadcaster(t), decoderReturning(createMockTransaction(), valueobject.NetworkTestnet), service.NewVerificationService(),
+				WithTracker(tt.tracker))
+
+			cmd := asyncSettleCommand
+			cmd.CallbackURL = tt.callbackURL
+			_, err := handler.Handle(context.Background(), cmd)
+
+			assert.ErrorIs(t, err, ErrInvalidCallbackURL)
+		})
+	}
+}
+
+func TestPublicAddress(t *testing.T) {
+	tests := []struct {
+		addr     string
+		expected bool
+	}{
+		{"93.184.216.34", true},
+		{"2606:2800:220:1:248:1893:25c8:1946", true},
+		{"127.0.0.1", false},
+		{"::1", false},
+		{"169.254.169.254", false},
+		{"fe80::1", false},
+		{"10.1.2.3", false},
+		{"172.16.0.1", false},
+		{"192.168.1.1", false},
+		{"fd00::1", false},
+		{"0.0.0.0", false},
+		{"::", false},
+		{"224.0.0.1", false},
+		{"ff02::1", false},
+		{"::ffff:127.0.0.1", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.addr, func(t *testing.T) {
+			assert.Equal(t, tt.expected, PublicAddress(netip.MustParseAddr(tt.addr)))
+		})
+	}
+}
@@ -0,0 +1,107 @@
+package command
+
+import (
+	"time"
+
+	"github.com/x402stacks/stacks-facilitator/internal/payment/domain/service"
+)
+
+// Option configures optional behaviour of a command handler
+type Option func(*options)
+
+// options holds the tunable settings shared by the command handlers
+type options struct {
+	maxRetries    int
+	retryDelay    time.Duration
+	tokenRegistry *service.TokenRegistry
+	paymentStore  PaymentStore
+	sponsor       TransactionSponsor
+	sponsorPolicy service.SponsorPolicy
+
+	chainTip             ChainTipProvider
+	minConfirmations     uint64
+	minBurnConfirmations uint64
+
+	tracker *PaymentTracker
+
+	signedDecoder TransactionDecoder
+	accounts      AccountStateProvider
+}
+
+// WithRetry overrides how many times the blockchain is polled and the delay between polls
+func WithRetry(maxRetries int, retryDelay time.Duration) Option {
+	return func(o *options) {
+		if maxRetries > 0 {
+			o.maxRetries = maxRetries
+		}
+		if retryDelay > 0 {
+			o.retryDelay = retryDelay
+		}
+	}
+}
+
+// WithTokenRegistry overrides the registry used to bind SIP-010 tokens to their contracts
+func WithTokenRegistry(registry *service.TokenRegistry) Option {
+	return func(o *options) {
+		if registry != nil {
+			o.tokenRegistry = registry
+		}
+	}
+}
+
+// WithPaymentStore enables replay protection by recording each accepted payment
+func WithPaymentStore(store PaymentStore) Option {
+	return func(o *options) {
+		o.paymentStore = store
+	}
+}
+
+// WithSponsor lets settlement counter-sign sponsored transactions that satisfy policy
+func WithSponsor(sponsor TransactionSponsor, policy service.SponsorPolicy) Option {
+	return func(o *options) {
+		o.sponsor = sponsor
+		o.sponsorPolicy = policy
+	}
+}
+
+// WithChainTip sets where the current chain tip is read from to count a transaction's confirmations
+func WithChainTip(provider ChainTipProvider) Option {
+	return func(o *options) {
+		o.chainTip = provider
+	}
+}
+
+// WithMinConfirmations sets how many Stacks blocks and Bitcoin burn blocks deep every payment
+// must be, counting the block that holds it. A command may ask for more but not for less.
+func WithMinConfirmations(stacksBlocks, burnBlocks uint64) Option {
+	return func(o *options) {
+		o.minConfirmations = stacksBlocks
+		o.minBurnConfirmations = burnBlocks
+	}
+}
+
+// WithTracker has settlement hand each broadcast payment to tracker, which notifies
+// subscribers when the payment leaves the pending status
+func WithTracker(tracker *PaymentTracker) Option {
+	return func(o *options) {
+		o.tracker = tracker
+	}
+}
+
+// WithSignedTransactions lets verification check signed transactions before broadcast,
+// reading the payer's next nonce and balances from accounts
+func WithSignedTransactions(decoder TransactionDecoder, accounts AccountStateProvider) Option {
+	return func(o *options) {
+		o.signedDecoder = decoder
+		o.accounts = accounts
+	}
+}
+
+// applyOptions applies opts on top of the given defaults
+func applyOptions(defaults options, opts []Option) options {
+	defaults.tokenRegistry = service.DefaultTokenRegistry()
+	for _, opt := range opts {
+		opt(&defaults)
+	}
+	return defaults
+}
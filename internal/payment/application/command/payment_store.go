@@ -0,0 +1,46 @@
+package command
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ErrPaymentAlreadyUsed is returned when a transaction has already been accepted as payment
+var ErrPaymentAlreadyUsed = errors.New("already_used")
+
+// ConsumedPayment records a transaction that was accepted as payment
+type ConsumedPayment struct {
+	TxID          string    `json:"tx_id"`
+	BroadcastTxID string    `json:"broadcast_tx_id,omitempty"` // Transaction settlement broadcast; differs from TxID once a sponsor counter-signed it, empty for verify and while settlement is claiming it
+	Network       string    `json:"network"`
+	Resource      string    `json:"resource,omitempty"`
+	Nonce         string    `json:"nonce,omitempty"`
+	ConsumedAt    time.Time `json:"consumed_at"`
+}
+
+// AlreadyUsedError describes a rejected reuse of the payment; it wraps ErrPaymentAlreadyUsed
+func (p ConsumedPayment) AlreadyUsedError() error {
+	if p.Resource != "" {
+		return fmt.Errorf("%w: transaction %s was already accepted for %s at %s", ErrPaymentAlreadyUsed,
+			p.TxID, p.Resource, p.ConsumedAt.UTC().Format("2006-01-02T15:04:05Z"))
+	}
+	return fmt.Errorf("%w: transaction %s was already accepted at %s", ErrPaymentAlreadyUsed,
+		p.TxID, p.ConsumedAt.UTC().Format("2006-01-02T15:04:05Z"))
+}
+
+// PaymentStore records consumed payments so a transaction can only be accepted once
+type PaymentStore interface {
+	// MarkConsumed atomically records the payment. It returns an error wrapping
+	// ErrPaymentAlreadyUsed if the transaction was recorded before.
+	MarkConsumed(ctx context.Context, payment ConsumedPayment) error
+	// Get returns the recorded payment for a transaction, if any
+	Get(ctx context.Context, txID string) (ConsumedPayment, bool, error)
+	// Update replaces the recorded payment for a transaction, as when a settlement that
+	// claimed it learns the txid it broadcast
+	Update(ctx context.Context, payment ConsumedPayment) error
+	// Release removes the record of a transaction, so a settlement claim whose
+	// transaction was never broadcast does not block a retry
+	Release(ctx context.Context, txID string) error
+}
@@ -0,0 +1,198 @@
+package command
+
+import (
+	"context"
+	"log"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/x402stacks/stacks-facilitator/internal/payment/domain/service"
+	"github.com/x402stacks/stacks-facilitator/internal/payment/domain/valueobject"
+)
+
+// PaymentTracker follows settled payments in the background until their status is final,
+// then verifies them and notifies subscribers. Settlement hands a payment over as soon as it
+// is broadcast, so it is reported even when the settle call stopped waiting while it was pending.
+type PaymentTracker struct {
+	broadcaster     TransactionBroadcaster
+	verificationSvc *service.VerificationService
+	notifier        PaymentNotifier
+	chainTip        ChainTipProvider
+	maxRetries      int
+	retryDelay      time.Duration
+	now             func() time.Time
+
+	mu       sync.Mutex
+	ctx      context.Context   // Set while Run is active
+	queued   []*trackedPayment // Handed over while Run was not active
+	tracking map[valueobject.TransactionID]*trackedPayment
+	wg       sync.WaitGroup
+}
+
+// trackedPayment is a broadcast payment waiting for a final status
+type trackedPayment struct {
+	txID         valueobject.TransactionID
+	pending      pendingSettlement
+	callbackURLs []string // Guarded by the tracker's mu; a resumed settlement can add its own
+}
+
+// NewPaymentTracker creates a PaymentTracker polling broadcaster every retry delay, up to max
+// retries per payment, and telling notifier about each payment that leaves the pending status
+func NewPaymentTracker(broadcaster TransactionBroadcaster, verificationSvc *service.VerificationService, notifier PaymentNotifier, opts ...Option) *PaymentTracker {
+	o := applyOptions(options{maxRetries: 900, retryDelay: 2 * time.Second}, opts)
+
+	return &PaymentTracker{
+		broadcaster:     broadcaster,
+		verificationSvc: verificationSvc,
+		notifier:        notifier,
+		chainTip:        o.chainTip,
+		maxRetries:      o.maxRetries,
+		retryDelay:      o.retryDelay,
+		now:             time.Now,
+		tracking:        make(map[valueobject.TransactionID]*trackedPayment),
+	}
+}
+
+// Run follows the payments handed over so far, then new ones until ctx is cancelled.
+// Payments still pending at that point are not reported.
+func (t *PaymentTracker) Run(ctx context.Context) {
+	t.mu.Lock()
+	t.ctx = ctx
+	for _, payment := range t.queued {
+		t.startLocked(payment)
+	}
+	t.queued = nil
+	t.mu.Unlock()
+
+	<-ctx.Done()
+
+	t.mu.Lock()
+	t.ctx = nil
+	t.mu.Unlock()
+	t.wg.Wait()
+}
+
+// track hands a broadcast payment over to be followed. For a payment already being followed,
+// only p's callback URL is added, so a resumed settlement is notified along with the first.
+func (t *PaymentTracker) track(txID valueobject.TransactionID, p pendingSettlement) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if payment, ok := t.tracking[txID]; ok {
+		payment.addCallbackLocked(p.callbackURL)
+		return
+	}
+
+	payment := &trackedPayment{txID: txID, pending: p}
+	payment.addCallbackLocked(p.callbackURL)
+	t.tracking[txID] = payment
+	if t.ctx == nil {
+		t.queued = append(t.queued, payment)
+		return
+	}
+	t.startLocked(payment)
+}
+
+// addCallbackLocked adds a callback URL the payment's event is also sent to; the caller holds
+// the tracker's mu
+func (p *trackedPayment) addCallbackLocked(callbackURL string) {
+	if callbackURL == "" || slices.Contains(p.callbackURLs, callbackURL) {
+		return
+	}
+	p.callbackURLs = append(p.callbackURLs, callbackURL)
+}
+
+// startLocked follows a payment in the background; the caller holds mu while Run is active
+func (t *PaymentTracker) startLocked(payment *trackedPayment) {
+	ctx := t.ctx
+	t.wg.Add(1)
+	go func() {
+		defer t.wg.Done()
+		t.follow(ctx, payment)
+
+		t.mu.Lock()
+		delete(t.tracking, payment.txID)
+		t.mu.Unlock()
+	}()
+}
+
+// follow polls a payment until it is final and, once confirmed, as deep as its criteria
+// require, then notifies subscribers. A payment still pending when the polls run out is logged.
+func (t *PaymentTracker) follow(ctx context.Context, payment *trackedPayment) {
+	p := payment.pending
+	for attempt := 0; attempt < t.maxRetries; attempt++ {
+		if attempt > 0 {
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(t.retryDelay):
+			}
+		}
+
+		// Not found yet is expected right after broadcast; any error is retried on the next poll
+		tx, err := t.broadcaster.WaitForConfirmation(ctx, payment.txID, p.tokenType, p.network, 1, 0)
+		if ctx.Err() != nil {
+			return
+		}
+		if err != nil {
+			continue
+		}
+		if err := setConfirmations(ctx, t.chainTip, &tx, p.network); err != nil {
+			continue
+		}
+
+		status := service.PaymentStatusOf(tx)
+		if status.IsFinal() && (!status.IsConfirmed() || hasConfirmations(tx, p.criteria)) {
+			t.notify(ctx, payment, tx)
+			return
+		}
+	}
+
+	log.Printf("payment %s: still pending after %d polls, not notified", payment.txID, t.maxRetries)
+}
+
+// notify verifies a final payment and tells the notifier about it. A transaction mined without
+// passing verification, such as one with the wrong memo, is reported as failed with its failures.
+// Delivery problems are logged.
+func (t *PaymentTracker) notify(ctx context.Context, payment *trackedPayment, tx service.BlockchainTransaction) {
+	p := payment.pending
+	criteria := p.criteria
+	criteria.AcceptUnconfirmed = false
+	result := t.verificationSvc.Verify(tx, criteria)
+
+	status := service.PaymentStatusOf(tx)
+	if status.IsConfirmed() && !result.Valid {
+		status = valueobject.StatusFailed
+	}
+
+	id, err := newRandomID()
+	if err != nil {
+		log.Printf("payment %s: failed to create %s event: %v", tx.TxID, status, err)
+		return
+	}
+	event := PaymentEvent{
+		ID:                id,
+		Type:              "payment." + status.String(),
+		TxID:              tx.TxID.String(),
+		Network:           p.network.String(),
+		TokenType:         p.tokenType.String(),
+		PreviousStatus:    valueobject.StatusPending,
+		Status:            status,
+		ReplacedByTxID:    tx.ReplacedBy.String(),
+		SenderAddress:     tx.Sender.String(),
+		RecipientAddress:  tx.Recipient.String(),
+		Amount:            tx.Amount.String(),
+		BlockHeight:       tx.BlockHeight,
+		Confirmations:     tx.Confirmations,
+		BurnConfirmations: tx.BurnConfirmations,
+		Failures:          result.Failures,
+		OccurredAt:        t.now().UTC(),
+	}
+	t.mu.Lock()
+	callbackURLs := slices.Clone(payment.callbackURLs)
+	t.mu.Unlock()
+	if err := t.notifier.Notify(ctx, event, callbackURLs); err != nil {
+		log.Printf("payment %s: failed to notify %s: %v", tx.TxID, event.Type, err)
+	}
+}
@@ -0,0 +1,255 @@
+package command
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/x402stacks/stacks-facilitator/internal/payment/domain/service"
+	"github.com/x402stacks/stacks-facilitator/internal/payment/domain/valueobject"
+)
+
+// broadcasterReturning returns a broadcaster whose transaction is always found as tx
+func broadcasterReturning(tx service.BlockchainTransaction) *MockBroadcaster {
+	return &MockBroadcaster{
+		BroadcastFn: func(ctx context.Context, signedTx string, network valueobject.Network) (valueobject.TransactionID, error) {
+			return tx.TxID, nil
+		},
+		WaitForConfirmFn: func(ctx context.Context, txID valueobject.TransactionID, tokenType valueobject.TokenType, network valueobject.Network, maxRetries int, retryDelay time.Duration) (service.BlockchainTransaction, error) {
+			return tx, nil
+		},
+	}
+}
+
+// runTracker runs tracker until the test ends
+func runTracker(t *testing.T, tracker *PaymentTracker) {
+	t.Helper()
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		tracker.Run(ctx)
+	}()
+	t.Cleanup(func() {
+		cancel()
+		<-done
+	})
+}
+
+// waitUntilIdle waits until the tracker has stopped following every payment
+func waitUntilIdle(t *testing.T, tracker *PaymentTracker) {
+	t.Helper()
+	require.Eventually(t, func() bool {
+		tracker.mu.Lock()
+		defer tracker.mu.Unlock()
+		return len(tracker.tracking) == 0
+	}, 2*time.Second, time.Millisecond)
+}
+
+func TestPaymentTracker_NotifiesWhenPaymentLeavesPending(t *testing.T) {
+	confirmed := createMockTransaction()
+	dropped := createMockTransaction()
+	dropped.IsConfirmed = false
+	dropped.BlockHeight = 0
+	dropped.Status = valueobject.TxStatusDroppedReplaceByFee
+
+	tests := []struct {
+		name         string
+		tx           service.BlockchainTransaction
+		expectedType string
+	}{
+		{"confirmed", confirmed, EventPaymentConfirmed},
+		{"dropped", dropped, EventPaymentDropped},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			notifier := &MockNotifier{}
+			broadcaster := broadcasterReturning(tt.tx)
+			tracker := NewPaymentTracker(broadcaster, service.NewVerificationService(), notifier, WithRetry(5, time.Millisecond))
+			runTracker(t, tracker)
+			handler := NewSettlePaymentHandler(broadcaster, decoderReturning(createMockTransaction(), valueobject.NetworkTestnet), service.NewVerificationService(),
+				WithRetry(1, time.Millisecond), WithTracker(tracker))
+
+			cmd := asyncSettleCommand
+			cmd.CallbackURL = "https://merchant.example/hooks/payments"
+			_, err := handler.Handle(context.Background(), cmd)
+			require.NoError(t, err)
+
+			waitUntilIdle(t, tracker)
+			events := notifier.Events()
+			require.Len(t, events, 1)
+			assert.Equal(t, tt.expectedType, events[0].Type)
+			assert.Len(t, events[0].ID, 32)
+			assert.Equal(t, tt.tx.TxID.String(), events[0].TxID)
+			assert.Equal(t, valueobject.StatusPending, events[0].PreviousStatus)
+			assert.Equal(t, "testnet", events[0].Network)
+			assert.Equal(t, []string{cmd.CallbackURL}, notifier.callbacks)
+		})
+	}
+}
+
+func TestPaymentTracker_KeepsFollowingAfterSettleStopsWaiting(t *testing.T) {
+	pending := createMockTransaction()
+	pending.IsConfirmed = false
+	pending.BlockHeight = 0
+	pending.Status = valueobject.TxStatusPending
+	confirmed := createMockTransaction()
+
+	var polls atomic.Int32
+	broadcaster := &MockBroadcaster{
+		BroadcastFn: func(ctx context.Context, signedTx string, network valueobject.Network) (valueobject.TransactionID, error) {
+			return pending.TxID, nil
+		},
+		WaitForConfirmFn: func(ctx context.Context, txID valueobject.TransactionID, tokenType valueobject.TokenType, network valueobject.Network, maxRetries int, retryDelay time.Duration) (service.BlockchainTransaction, error) {
+			if polls.Add(1) <= 5 {
+				return pending, nil
+			}
+			return confirmed, nil
+		},
+	}
+	notifier := &MockNotifier{}
+	tracker := NewPaymentTracker(broadcaster, service.NewVerificationService(), notifier, WithRetry(20, time.Millisecond))
+	handler := NewSettlePaymentHandler(broadcaster, decoderReturning(pending, valueobject.NetworkTestnet), service.NewVerificationService(),
+		WithRetry(1, time.Millisecond), WithTracker(tracker))
+
+	result, err := handler.Handle(context.Background(), asyncSettleCommand)
+	require.NoError(t, err)
+	assert.Equal(t, "pending", result.Status)
+
+	// Handed over before Run, the payment is followed once Run starts
+	runTracker(t, tracker)
+	waitUntilIdle(t, tracker)
+
+	events := notifier.Events()
+	require.Len(t, events, 1)
+	assert.Equal(t, EventPaymentConfirmed, events[0].Type)
+	assert.Empty(t, events[0].Failures)
+}
+
+func TestPaymentTracker_ReportsUnverifiedPaymentAsFailed(t *testing.T) {
+	underpaid := createMockTransaction()
+	underpaid.Amount = valueobject.NewAmount(100)
+
+	notifier := &MockNotifier{}
+	broadcaster := broadcasterReturning(underpaid)
+	tracker := NewPaymentTracker(broadcaster, service.NewVerificationService(), notifier, WithRetry(5, time.Millisecond))
+	runTracker(t, tracker)
+	handler := NewSettlePaymentHandler(broadcaster, decoderReturning(createMockTransaction(), valueobject.NetworkTestnet), service.NewVerificationService(),
+		WithRetry(1, time.Millisecond), WithTracker(tracker))
+
+	result, err := handler.Handle(context.Background(), asyncSettleCommand)
+	require.NoError(t, err)
+	assert.False(t, result.Success)
+
+	waitUntilIdle(t, tracker)
+	events := notifier.Events()
+	require.Len(t, events, 1)
+	assert.Equal(t, EventPaymentFailed, events[0].Type)
+	assert.Equal(t, valueobject.StatusFailed, events[0].Status)
+	require.NotEmpty(t, events[0].Failures)
+	assert.Equal(t, service.FailureInsufficientAmount, events[0].Failures[0].Code)
+}
+
+func TestPaymentTracker_WaitsForConfirmations(t *testing.T) {
+	mockTx := createMockTransaction()
+	chainTip := &MockChainTip{Tips: []service.ChainTip{{StacksHeight: 12345}, {StacksHeight: 12346}, {StacksHeight: 12347}}}
+
+	notifier := &MockNotifier{}
+	broadcaster := broadcasterReturning(mockTx)
+	tracker := NewPaymentTracker(broadcaster, service.NewVerificationService(), notifier, WithRetry(10, time.Millisecond), WithChainTip(chainTip))
+	runTracker(t, tracker)
+	handler := NewSettlePaymentHandler(broadcaster, decoderReturning(mockTx, valueobject.NetworkTestnet), service.NewVerificationService(),
+		WithRetry(1, time.Millisecond), WithMinConfirmations(3, 0), WithTracker(tracker))
+
+	_, err := handler.Handle(context.Background(), asyncSettleCommand)
+	require.NoError(t, err)
+
+	waitUntilIdle(t, tracker)
+	events := notifier.Events()
+	require.Len(t, events, 1)
+	assert.Equal(t, EventPaymentConfirmed, events[0].Type)
+	assert.Equal(t, uint64(3), events[0].Confirmations)
+}
+
+func TestPaymentTracker_NoNotificationWhileStillPending(t *testing.T) {
+	pending := createMockTransaction()
+	pending.IsConfirmed = false
+	pending.Status = valueobject.TxStatusPending
+
+	notifier := &MockNotifier{}
+	broadcaster := broadcasterReturning(pending)
+	tracker := NewPaymentTracker(broadcaster, service.NewVerificationService(), notifier, WithRetry(3, time.Millisecond))
+	runTracker(t, tracker)
+	handler := NewSettlePaymentHandler(broadcaster, decoderReturning(pending, valueobject.NetworkTestnet), service.NewVerificationService(),
+		WithRetry(1, time.Millisecond), WithTracker(tracker))
+
+	result, err := handler.Handle(context.Background(), asyncSettleCommand)
+
+	require.NoError(t, err)
+	assert.Equal(t, "pending", result.Status)
+	waitUntilIdle(t, tracker)
+	assert.Empty(t, notifier.Events())
+}
+
+func TestPaymentTracker_NotifierErrorDoesNotFailSettlement(t *testing.T) {
+	mockTx := createMockTransaction()
+	notifier := &MockNotifier{Err: errors.New("delivery log unavailable")}
+	broadcaster := broadcasterReturning(mockTx)
+	tracker := NewPaymentTracker(broadcaster, service.NewVerificationService(), notifier, WithRetry(5, time.Millisecond))
+	runTracker(t, tracker)
+	handler := NewSettlePaymentHandler(broadcaster, decoderReturning(mockTx, valueobject.NetworkTestnet), service.NewVerificationService(),
+		WithRetry(1, time.Millisecond), WithTracker(tracker))
+
+	result, err := handler.Handle(context.Background(), asyncSettleCommand)
+
+	require.NoError(t, err)
+	assert.True(t, result.Success)
+	waitUntilIdle(t, tracker)
+	assert.Len(t, notifier.Events(), 1)
+}
+
+func TestPaymentTracker_FollowsEachPaymentOnce(t *testing.T) {
+	mockTx := createMockTransaction()
+	notifier := &MockNotifier{}
+	tracker := NewPaymentTracker(broadcasterReturning(mockTx), service.NewVerificationService(), notifier, WithRetry(5, time.Millisecond))
+
+	p := pendingSettlement{decoded: mockTx, tokenType: valueobject.TokenSTX, network: valueobject.NetworkTestnet}
+	tracker.track(mockTx.TxID, p)
+	tracker.track(mockTx.TxID, p)
+	runTracker(t, tracker)
+
+	waitUntilIdle(t, tracker)
+	assert.Len(t, notifier.Events(), 1)
+}
+
+func TestPaymentTracker_NotifiesCallbackOfResumedSettlement(t *testing.T) {
+	mockTx := createMockTransaction()
+	notifier := &MockNotifier{}
+	broadcaster := broadcasterReturning(mockTx)
+	tracker := NewPaymentTracker(broadcaster, service.NewVerificationService(), notifier, WithRetry(5, time.Millisecond))
+	store := &memoryPaymentStore{payments: map[string]ConsumedPayment{}}
+	handler := NewSettlePaymentHandler(broadcaster, decoderReturning(mockTx, valueobject.NetworkTestnet), service.NewVerificationService(),
+		WithRetry(1, time.Millisecond), WithTracker(tracker), WithPaymentStore(store))
+
+	cmd := asyncSettleCommand
+	cmd.Resource = "/premium"
+	cmd.Nonce = "abc"
+	cmd.CallbackURL = "https://merchant.example/hooks/first"
+	_, err := handler.Handle(context.Background(), cmd)
+	require.NoError(t, err)
+
+	// The same settlement is retried with another callback before the payment is reported
+	cmd.CallbackURL = "https://merchant.example/hooks/retry"
+	_, err = handler.Handle(context.Background(), cmd)
+	require.NoError(t, err)
+
+	runTracker(t, tracker)
+	waitUntilIdle(t, tracker)
+	assert.Len(t, notifier.Events(), 1)
+	assert.Equal(t, []string{"https://merchant.example/hooks/first", "https://merchant.example/hooks/retry"}, notifier.callbacks)
+}
@@ -0,0 +1,323 @@
+package command
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/x402stacks/stacks-facilitator/internal/payment/domain/service"
+	"github.com/x402stacks/stacks-facilitator/internal/payment/domain/valueobject"
+)
+
+// ErrInvalidWatchRequest is returned when a payment to watch has a malformed txid or network
+var ErrInvalidWatchRequest = errors.New("invalid watch request")
+
+// ErrWatcherClosed is returned when subscribing after the watcher has been closed
+var ErrWatcherClosed = errors.New("payment watcher closed")
+
+// subscriptionBuffer is how many updates a slow subscriber may fall behind before it is dropped
+const subscriptionBuffer = 16
+
+// Kinds of payment update, in the order a payment usually goes through them
+const (
+	PaymentUpdateMempool       = "mempool"        // Seen pending in the mempool
+	PaymentUpdateConfirmed     = "confirmed"      // Mined in a block
+	PaymentUpdateConfirmations = "confirmations"  // Confirmation count of a mined payment changed
+	PaymentUpdateFailed        = "failed"         // Mined but aborted (final)
+	PaymentUpdateDropped       = "dropped"        // Dropped from the mempool (final)
+	PaymentUpdateTimeout       = "timeout"        // Not final within the watcher's polls (final)
+	PaymentUpdateTokenMismatch = "token_mismatch" // Not a transfer of the subscriber's token (final)
+)
+
+// WatchPaymentCommand represents a request to follow a payment's status
+type WatchPaymentCommand struct {
+	TxID                 string
+	TokenType            string
+	Network              string
+	MinConfirmations     uint64 // Stacks blocks deep before the watch ends; raised to the watcher's minimum
+	MinBurnConfirmations uint64 // Bitcoin burn blocks deep before the watch ends; raised to the watcher's minimum
+}
+
+// PaymentUpdate is a change in a watched payment's status
+type PaymentUpdate struct {
+	Kind              string // PaymentUpdateMempool, PaymentUpdateConfirmed, ...
+	TxID              string
+	TokenType         string
+	Network           string
+	Status            valueobject.PaymentStatus
+	ReplacedByTxID    string // Transaction that replaced a dropped one, when known
+	BlockHeight       uint64
+	Confirmations     uint64
+	BurnConfirmations uint64
+}
+
+// watchKey identifies the transaction a poll follows, whatever token its subscribers expect
+type watchKey struct {
+	txID    valueobject.TransactionID // Lowercased, so every spelling of a txid shares the poll
+	network valueobject.Network
+}
+
+// paymentPoll is the single upstream poller shared by every subscriber to one transaction
+type paymentPoll struct {
+	subscribers map[*subscription]struct{}
+	last        *PaymentUpdate                 // Replayed to subscribers that join late
+	lastTx      *service.BlockchainTransaction // Checked against each subscriber's token
+	cancel      context.CancelFunc
+}
+
+// subscription is one subscriber's view of a poll
+type subscription struct {
+	updates              chan PaymentUpdate
+	tokenType            valueobject.TokenType
+	contract             *service.TokenContract // Canonical contract for a SIP-010 tokenType
+	minConfirmations     uint64
+	minBurnConfirmations uint64
+}
+
+// done reports whether update is the last one the subscriber wants
+func (s *subscription) done(update PaymentUpdate) bool {
+	if update.Status == valueobject.StatusConfirmed {
+		return update.Confirmations >= s.minConfirmations && update.BurnConfirmations >= s.minBurnConfirmations
+	}
+	return update.Status.IsFinal()
+}
+
+// PaymentWatcher follows payments on chain and pushes their status changes to subscribers.
+// Subscribers to the same transaction share one poller, which stops once the last leaves.
+type PaymentWatcher struct {
+	client               BlockchainClient
+	verifier             *service.VerificationService
+	tokenRegistry        *service.TokenRegistry
+	chainTip             ChainTipProvider
+	maxRetries           int
+	retryDelay           time.Duration
+	minConfirmations     uint64
+	minBurnConfirmations uint64
+
+	mu     sync.Mutex
+	polls  map[watchKey]*paymentPoll
+	closed bool
+}
+
+// NewPaymentWatcher creates a PaymentWatcher polling client every retry delay, up to max retries per watch
+func NewPaymentWatcher(client BlockchainClient, opts ...Option) *PaymentWatcher {
+	o := applyOptions(options{maxRetries: 900, retryDelay: 2 * time.Second}, opts)
+
+	return &PaymentWatcher{
+		client:               client,
+		verifier:             service.NewVerificationService(),
+		tokenRegistry:        o.tokenRegistry,
+		chainTip:             o.chainTip,
+		maxRetries:           o.maxRetries,
+		retryDelay:           o.retryDelay,
+		minConfirmations:     o.minConfirmations,
+		minBurnConfirmations: o.minBurnConfirmations,
+		polls:                make(map[watchKey]*paymentPoll),
+	}
+}
+
+// Subscribe starts following a payment. The returned channel receives each status change
+// and is closed once the payment is final and deep enough, the polls run out, the
+// subscriber falls too far behind, or unsubscribe is called.
+func (w *PaymentWatcher) Subscribe(ctx context.Context, cmd WatchPaymentCommand) (<-chan PaymentUpdate, func(), error) {
+	txID, err := valueobject.NewTransactionID(strings.ToLower(cmd.TxID))
+	if err != nil {
+		return nil, nil, fmt.Errorf("%w: invalid transaction ID: %v", ErrInvalidWatchRequest, err)
+	}
+	tokenType, err := parseTokenType(cmd.TokenType)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%w: %v", ErrInvalidWatchRequest, err)
+	}
+	network, err := valueobject.NewNetwork(cmd.Network)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%w: invalid network: %v", ErrInvalidWatchRequest, err)
+	}
+	contract, err := expectedContract(w.tokenRegistry, tokenType, network)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%w: %v", ErrInvalidWatchRequest, err)
+	}
+
+	key := watchKey{txID: txID, network: network}
+	sub := &subscription{
+		updates:              make(chan PaymentUpdate, subscriptionBuffer),
+		tokenType:            tokenType,
+		contract:             contract,
+		minConfirmations:     max(w.minConfirmations, cmd.MinConfirmations),
+		minBurnConfirmations: max(w.minBurnConfirmations, cmd.MinBurnConfirmations),
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.closed {
+		return nil, nil, ErrWatcherClosed
+	}
+
+	p, ok := w.polls[key]
+	if !ok {
+		pollCtx, cancel := context.WithCancel(context.Background())
+		p = &paymentPoll{subscribers: make(map[*subscription]struct{}), cancel: cancel}
+		w.polls[key] = p
+		go w.poll(pollCtx, key, p)
+	}
+	p.subscribers[sub] = struct{}{}
+	if p.last != nil {
+		w.sendLocked(key, p, sub, *p.last)
+	}
+
+	unsubscribe := func() {
+		w.mu.Lock()
+		defer w.mu.Unlock()
+		w.removeLocked(key, p, sub)
+	}
+	return sub.updates, unsubscribe, nil
+}
+
+// Close ends every subscription and refuses new ones
+func (w *PaymentWatcher) Close() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.closed = true
+	for key, p := range w.polls {
+		for sub := range p.subscribers {
+			w.removeLocked(key, p, sub)
+		}
+	}
+}
+
+// poll fetches the transaction every retry delay and publishes each change until every
+// subscriber has left or the polls run out
+func (w *PaymentWatcher) poll(ctx context.Context, key watchKey, p *paymentPoll) {
+	var last *PaymentUpdate
+	for attempt := 0; attempt < w.maxRetries; attempt++ {
+		if attempt > 0 {
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(w.retryDelay):
+			}
+		}
+
+		// Not found yet is expected right after broadcast; any error is retried on the next poll.
+		// The token is left empty here and checked per subscriber.
+		tx, err := w.client.GetTransactionWithRetry(ctx, key.txID, "", key.network, 1, 0)
+		if err != nil {
+			continue
+		}
+		if err := setConfirmations(ctx, w.chainTip, &tx, key.network); err != nil {
+			continue
+		}
+
+		update, changed := paymentUpdate(last, tx, key.network)
+		if !changed {
+			continue
+		}
+		last = &update
+		if !w.publish(key, p, update, &tx) {
+			return
+		}
+	}
+
+	if ctx.Err() != nil {
+		return
+	}
+	timeout := PaymentUpdate{Kind: PaymentUpdateTimeout, TxID: key.txID.String(), Network: key.network.String(), Status: valueobject.StatusPending}
+	if last != nil {
+		timeout = *last
+		timeout.Kind = PaymentUpdateTimeout
+	}
+	w.publish(key, p, timeout, nil)
+}
+
+// publish sends an update to every subscriber, ending the subscriptions it completes.
+// tx is the transaction the update describes, or nil to keep the last one seen.
+// It returns false once no subscribers are left.
+func (w *PaymentWatcher) publish(key watchKey, p *paymentPoll, update PaymentUpdate, tx *service.BlockchainTransaction) bool {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	p.last = &update
+	if tx != nil {
+		p.lastTx = tx
+	}
+	for sub := range p.subscribers {
+		w.sendLocked(key, p, sub, update)
+	}
+	return len(p.subscribers) > 0
+}
+
+// sendLocked delivers an update to one subscriber and ends its subscription when the update
+// completes it or the subscriber has fallen behind; the caller holds mu.
+// A transaction whose transfer events do not move the subscriber's token is sent as
+// PaymentUpdateTokenMismatch. Until those events are known the token is not checked: a
+// payment through a router calls another contract than the token's, as settle accepts.
+func (w *PaymentWatcher) sendLocked(key watchKey, p *paymentPoll, sub *subscription, update PaymentUpdate) {
+	update.TokenType = sub.tokenType.String()
+	if p.lastTx != nil && p.lastTx.Transfers != nil && len(w.verifier.VerifyToken(*p.lastTx, sub.tokenType, sub.contract)) > 0 {
+		update.Kind = PaymentUpdateTokenMismatch
+	}
+
+	select {
+	case sub.updates <- update:
+	default:
+		w.removeLocked(key, p, sub)
+		return
+	}
+
+	if update.Kind == PaymentUpdateTimeout || update.Kind == PaymentUpdateTokenMismatch || sub.done(update) {
+		w.removeLocked(key, p, sub)
+	}
+}
+
+// removeLocked ends a subscription, stopping the poll when it was the last; the caller holds mu
+func (w *PaymentWatcher) removeLocked(key watchKey, p *paymentPoll, sub *subscription) {
+	if _, ok := p.subscribers[sub]; !ok {
+		return
+	}
+	delete(p.subscribers, sub)
+	close(sub.updates)
+
+	if len(p.subscribers) == 0 {
+		p.cancel()
+		if w.polls[key] == p {
+			delete(w.polls, key)
+		}
+	}
+}
+
+// paymentUpdate describes tx as an update and reports whether it differs from the last one
+func paymentUpdate(last *PaymentUpdate, tx service.BlockchainTransaction, network valueobject.Network) (PaymentUpdate, bool) {
+	status := service.PaymentStatusOf(tx)
+	update := PaymentUpdate{
+		TxID:              tx.TxID.String(),
+		Network:           network.String(),
+		Status:            status,
+		ReplacedByTxID:    tx.ReplacedBy.String(),
+		BlockHeight:       tx.BlockHeight,
+		Confirmations:     tx.Confirmations,
+		BurnConfirmations: tx.BurnConfirmations,
+	}
+
+	switch {
+	case last != nil && last.Status == status && status == valueobject.StatusConfirmed:
+		if last.Confirmations == update.Confirmations && last.BurnConfirmations == update.BurnConfirmations && last.BlockHeight == update.BlockHeight {
+			return update, false
+		}
+		update.Kind = PaymentUpdateConfirmations
+	case last != nil && last.Status == status:
+		return update, false
+	case status == valueobject.StatusConfirmed:
+		update.Kind = PaymentUpdateConfirmed
+	case status == valueobject.StatusFailed:
+		update.Kind = PaymentUpdateFailed
+	case status == valueobject.StatusDropped:
+		update.Kind = PaymentUpdateDropped
+	default:
+		update.Kind = PaymentUpdateMempool
+	}
+	return update, true
+}
@@ -0,0 +1,285 @@
+package command
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/x402stacks/stacks-facilitator/internal/payment/domain/service"
+	"github.com/x402stacks/stacks-facilitator/internal/payment/domain/valueobject"
+)
+
+const otherTxID = "0xabcdef1234567890abcdef1234567890abcdef1234567890abcdef1234567890"
+
+// sequenceClient is a BlockchainClient returning txs in order, repeating the last one;
+// a zero transaction in the sequence is reported as not found
+type sequenceClient struct {
+	mu    sync.Mutex
+	txs   []service.BlockchainTransaction
+	calls int
+}
+
+func (c *sequenceClient) GetTransactionWithRetry(ctx context.Context, txID valueobject.TransactionID, tokenType valueobject.TokenType, network valueobject.Network, maxRetries int, retryDelay time.Duration) (service.BlockchainTransaction, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	tx := c.txs[min(c.calls, len(c.txs)-1)]
+	c.calls++
+	if tx.TxID.IsZero() {
+		return service.BlockchainTransaction{}, errors.New("transaction not found")
+	}
+	return tx, nil
+}
+
+func (c *sequenceClient) Calls() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.calls
+}
+
+// drain collects updates until the channel is closed
+func drain(t *testing.T, updates <-chan PaymentUpdate) []PaymentUpdate {
+	t.Helper()
+	var received []PaymentUpdate
+	timeout := time.After(2 * time.Second)
+	for {
+		select {
+		case update, ok := <-updates:
+			if !ok {
+				return received
+			}
+			received = append(received, update)
+		case <-timeout:
+			t.Fatalf("subscription not closed; received %v", received)
+		}
+	}
+}
+
+func watchCommand(txID string) WatchPaymentCommand {
+	return WatchPaymentCommand{TxID: txID, TokenType: "STX", Network: "testnet"}
+}
+
+func pendingTransaction() service.BlockchainTransaction {
+	tx := createMockTransaction()
+	tx.Status = valueobject.TxStatusPending
+	tx.IsConfirmed = false
+	tx.BlockHeight = 0
+	return tx
+}
+
+func TestPaymentWatcher_StreamsUntilDeepEnough(t *testing.T) {
+	confirmed := createMockTransaction()
+	confirmed.BlockHeight = 100
+	client := &sequenceClient{txs: []service.BlockchainTransaction{{}, pendingTransaction(), pendingTransaction(), confirmed}}
+	chainTip := &MockChainTip{Tips: []service.ChainTip{{StacksHeight: 100}, {StacksHeight: 101}, {StacksHeight: 101}, {StacksHeight: 102}}}
+	w := NewPaymentWatcher(client, WithRetry(20, time.Millisecond), WithChainTip(chainTip))
+
+	cmd := watchCommand(confirmed.TxID.String())
+	cmd.MinConfirmations = 3
+	updates, unsubscribe, err := w.Subscribe(context.Background(), cmd)
+	require.NoError(t, err)
+	defer unsubscribe()
+
+	received := drain(t, updates)
+
+	require.Len(t, received, 4)
+	kinds := []string{received[0].Kind, received[1].Kind, received[2].Kind, received[3].Kind}
+	assert.Equal(t, []string{PaymentUpdateMempool, PaymentUpdateConfirmed, PaymentUpdateConfirmations, PaymentUpdateConfirmations}, kinds)
+	assert.Equal(t, valueobject.StatusPending, received[0].Status)
+	assert.Equal(t, valueobject.StatusConfirmed, received[1].Status)
+	assert.Equal(t, uint64(1), received[1].Confirmations)
+	assert.Equal(t, uint64(3), received[3].Confirmations)
+	assert.Equal(t, "testnet", received[3].Network)
+	assert.Eventually(t, func() bool {
+		w.mu.Lock()
+		defer w.mu.Unlock()
+		return len(w.polls) == 0
+	}, time.Second, time.Millisecond)
+}
+
+func TestPaymentWatcher_SharesOnePollerPerTransaction(t *testing.T) {
+	client := &sequenceClient{txs: []service.BlockchainTransaction{pendingTransaction()}}
+	w := NewPaymentWatcher(client, WithRetry(1000, time.Millisecond))
+
+	first, unsubscribeFirst, err := w.Subscribe(context.Background(), watchCommand(createMockTransaction().TxID.String()))
+	require.NoError(t, err)
+	require.Equal(t, PaymentUpdateMempool, (<-first).Kind)
+
+	// A late subscriber is sent the latest update straight away
+	second, unsubscribeSecond, err := w.Subscribe(context.Background(), watchCommand(createMockTransaction().TxID.String()))
+	require.NoError(t, err)
+	require.Equal(t, PaymentUpdateMempool, (<-second).Kind)
+
+	other, unsubscribeOther, err := w.Subscribe(context.Background(), watchCommand(otherTxID))
+	require.NoError(t, err)
+
+	w.mu.Lock()
+	assert.Len(t, w.polls, 2)
+	w.mu.Unlock()
+
+	unsubscribeFirst()
+	_, open := <-first
+	assert.False(t, open)
+	w.mu.Lock()
+	assert.Len(t, w.polls, 2, "the poll continues for the remaining subscriber")
+	w.mu.Unlock()
+
+	unsubscribeSecond()
+	unsubscribeOther()
+	unsubscribeOther()
+	drain(t, other)
+
+	w.mu.Lock()
+	assert.Empty(t, w.polls)
+	w.mu.Unlock()
+
+	// The pollers stop once every subscriber has left
+	time.Sleep(5 * time.Millisecond)
+	calls := client.Calls()
+	time.Sleep(10 * time.Millisecond)
+	assert.Equal(t, calls, client.Calls())
+}
+
+// confirmedWithTransfer is the mock transaction confirmed with one transfer event of asset
+func confirmedWithTransfer(asset string) service.BlockchainTransaction {
+	tx := createMockTransaction()
+	sender, _ := valueobject.NewPrincipal(tx.Sender.String())
+	tx.Transfers = []service.AssetTransfer{{Asset: asset, Sender: sender, Recipient: tx.Recipient, Amount: tx.Amount}}
+	return tx
+}
+
+func TestPaymentWatcher_SharesPollAcrossTokensAndCase(t *testing.T) {
+	client := &sequenceClient{txs: []service.BlockchainTransaction{pendingTransaction(), pendingTransaction(), confirmedWithTransfer("STX")}}
+	w := NewPaymentWatcher(client, WithRetry(1000, time.Millisecond))
+
+	stx, unsubscribeSTX, err := w.Subscribe(context.Background(), watchCommand(createMockTransaction().TxID.String()))
+	require.NoError(t, err)
+	defer unsubscribeSTX()
+	require.Equal(t, PaymentUpdateMempool, (<-stx).Kind)
+
+	// The same txid in upper case, expecting sBTC, joins the poll; its events show an STX transfer
+	cmd := watchCommand(strings.ToUpper(createMockTransaction().TxID.String()))
+	cmd.TokenType = "sBTC"
+	sbtc, unsubscribeSBTC, err := w.Subscribe(context.Background(), cmd)
+	require.NoError(t, err)
+	defer unsubscribeSBTC()
+
+	w.mu.Lock()
+	assert.Len(t, w.polls, 1)
+	w.mu.Unlock()
+
+	received := drain(t, sbtc)
+	require.Len(t, received, 2)
+	assert.Equal(t, PaymentUpdateMempool, received[0].Kind, "the pending call is not judged before its events are known")
+	assert.Equal(t, PaymentUpdateTokenMismatch, received[1].Kind)
+	assert.Equal(t, "SBTC", received[1].TokenType)
+	assert.Equal(t, createMockTransaction().TxID.String(), received[1].TxID)
+
+	stxReceived := drain(t, stx)
+	require.Len(t, stxReceived, 1)
+	assert.Equal(t, PaymentUpdateConfirmed, stxReceived[0].Kind)
+}
+
+func TestPaymentWatcher_RouterCallConfirmedByEvents(t *testing.T) {
+	router := pendingTransaction()
+	router.TokenType = valueobject.TokenSBTC
+	router.ContractID = "ST2J6ZY48GV1EZ5V2V5RB9MP66SW86PYKKQYAC0RQ.swap-router"
+	confirmed := confirmedWithTransfer("ST1F7QA2MDF17S807EPA36TSS8AMEFY4KA9TVGWXT.sbtc-token::sbtc-token")
+	confirmed.TokenType = valueobject.TokenSBTC
+	confirmed.ContractID = router.ContractID
+
+	client := &sequenceClient{txs: []service.BlockchainTransaction{router, router, confirmed}}
+	w := NewPaymentWatcher(client, WithRetry(20, time.Millisecond))
+
+	cmd := watchCommand(router.TxID.String())
+	cmd.TokenType = "sBTC"
+	updates, unsubscribe, err := w.Subscribe(context.Background(), cmd)
+	require.NoError(t, err)
+	defer unsubscribe()
+
+	received := drain(t, updates)
+
+	require.Len(t, received, 2)
+	assert.Equal(t, PaymentUpdateMempool, received[0].Kind)
+	assert.Equal(t, PaymentUpdateConfirmed, received[1].Kind)
+	assert.Equal(t, "SBTC", received[1].TokenType)
+}
+
+func TestPaymentWatcher_EndsWhenDropped(t *testing.T) {
+	replacement, _ := valueobject.NewTransactionID(otherTxID)
+	dropped := pendingTransaction()
+	dropped.Status = valueobject.TxStatusDroppedReplaceByFee
+	dropped.ReplacedBy = replacement
+
+	client := &sequenceClient{txs: []service.BlockchainTransaction{pendingTransaction(), dropped}}
+	w := NewPaymentWatcher(client, WithRetry(20, time.Millisecond))
+
+	updates, unsubscribe, err := w.Subscribe(context.Background(), watchCommand(dropped.TxID.String()))
+	require.NoError(t, err)
+	defer unsubscribe()
+
+	received := drain(t, updates)
+
+	require.Len(t, received, 2)
+	assert.Equal(t, PaymentUpdateDropped, received[1].Kind)
+	assert.Equal(t, valueobject.StatusDropped, received[1].Status)
+	assert.Equal(t, otherTxID, received[1].ReplacedByTxID)
+}
+
+func TestPaymentWatcher_TimesOut(t *testing.T) {
+	client := &sequenceClient{txs: []service.BlockchainTransaction{{}}}
+	w := NewPaymentWatcher(client, WithRetry(3, time.Millisecond))
+
+	updates, unsubscribe, err := w.Subscribe(context.Background(), watchCommand(createMockTransaction().TxID.String()))
+	require.NoError(t, err)
+	defer unsubscribe()
+
+	received := drain(t, updates)
+
+	require.Len(t, received, 1)
+	assert.Equal(t, PaymentUpdateTimeout, received[0].Kind)
+	assert.Equal(t, valueobject.StatusPending, received[0].Status)
+	assert.Equal(t, createMockTransaction().TxID.String(), received[0].TxID)
+	assert.Equal(t, 3, client.Calls())
+}
+
+func TestPaymentWatcher_InvalidRequest(t *testing.T) {
+	w := NewPaymentWatcher(&sequenceClient{})
+
+	_, _, err := w.Subscribe(context.Background(), watchCommand("0x1234"))
+	assert.ErrorIs(t, err, ErrInvalidWatchRequest)
+
+	cmd := watchCommand(createMockTransaction().TxID.String())
+	cmd.Network = "devnet"
+	_, _, err = w.Subscribe(context.Background(), cmd)
+	assert.ErrorIs(t, err, ErrInvalidWatchRequest)
+
+	cmd = watchCommand(createMockTransaction().TxID.String())
+	cmd.TokenType = "not a token"
+	_, _, err = w.Subscribe(context.Background(), cmd)
+	assert.ErrorIs(t, err, ErrInvalidWatchRequest)
+
+	cmd = watchCommand(createMockTransaction().TxID.String())
+	cmd.TokenType = "ALEX"
+	_, _, err = w.Subscribe(context.Background(), cmd)
+	assert.ErrorIs(t, err, ErrInvalidWatchRequest, "a token with no contract on the network")
+}
+
+func TestPaymentWatcher_Close(t *testing.T) {
+	client := &sequenceClient{txs: []service.BlockchainTransaction{pendingTransaction()}}
+	w := NewPaymentWatcher(client, WithRetry(1000, time.Millisecond))
+
+	updates, unsubscribe, err := w.Subscribe(context.Background(), watchCommand(createMockTransaction().TxID.String()))
+	require.NoError(t, err)
+
+	w.Close()
+	drain(t, updates)
+	unsubscribe()
+
+	_, _, err = w.Subscribe(context.Background(), watchCommand(createMockTransaction().TxID.String()))
+	assert.ErrorIs(t, err, ErrWatcherClosed)
+}
@@ -0,0 +1,230 @@
+package command
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/x402stacks/stacks-facilitator/internal/payment/domain/service"
+	"github.com/x402stacks/stacks-facilitator/internal/payment/domain/valueobject"
+)
+
+// ErrSettlementQueueFull is returned, before broadcast, when every worker and queue slot is taken
+var ErrSettlementQueueFull = errors.New("settlement queue full")
+
+// ErrSettlementStopped is returned, before broadcast, once Run has stopped the worker pool
+var ErrSettlementStopped = errors.New("settlement workers stopped")
+
+// ErrSettlementInterrupted is the error of a settlement that shutdown stopped confirming
+var ErrSettlementInterrupted = errors.New("settlement interrupted by shutdown before a final state; settle the same transaction again to resume it")
+
+// AsyncSettleResult is the outcome of submitting a settlement asynchronously
+type AsyncSettleResult struct {
+	Accepted   bool                // Broadcast and queued for confirmation
+	Settlement Settlement          // The queued settlement, when Accepted
+	Rejected   SettlePaymentResult // Why the transaction was refused before broadcast, otherwise
+}
+
+// settlementJob is a broadcast transaction waiting for a worker
+type settlementJob struct {
+	settlement Settlement
+	txID       valueobject.TransactionID
+	pending    pendingSettlement
+}
+
+// AsyncSettlePaymentHandler broadcasts settlements and confirms them in the background,
+// so the caller does not wait for the transaction to be mined
+type AsyncSettlePaymentHandler struct {
+	settle  *SettlePaymentHandler
+	store   SettlementStore
+	workers int
+	jobs    chan settlementJob
+	slots   chan struct{} // Held from broadcast until the settlement reaches a final state
+	now     func() time.Time
+
+	mu      sync.RWMutex // Held for reading by Handle until its job is queued
+	stopped bool
+}
+
+// NewAsyncSettlePaymentHandler creates an AsyncSettlePaymentHandler that confirms up to
+// workers settlements at once and queues up to queueSize more
+func NewAsyncSettlePaymentHandler(settle *SettlePaymentHandler, store SettlementStore, workers, queueSize int) *AsyncSettlePaymentHandler {
+	workers = max(workers, 1)
+	queueSize = max(queueSize, 0)
+
+	return &AsyncSettlePaymentHandler{
+		settle:  settle,
+		store:   store,
+		workers: workers,
+		jobs:    make(chan settlementJob, workers+queueSize),
+		slots:   make(chan struct{}, workers+queueSize),
+		now:     time.Now,
+	}
+}
+
+// Run drives queued settlements with the worker pool until ctx is cancelled.
+// Handle then refuses new settlements with ErrSettlementStopped, and every settlement
+// still in progress or queued is failed as retryable with ErrSettlementInterrupted.
+// Run returns once they are saved.
+func (h *AsyncSettlePaymentHandler) Run(ctx context.Context) {
+	var wg sync.WaitGroup
+	for i := 0; i < h.workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				select {
+				case <-ctx.Done():
+					return
+				case job := <-h.jobs:
+					h.track(ctx, job)
+				}
+			}
+		}()
+	}
+
+	<-ctx.Done()
+	h.mu.Lock()
+	h.stopped = true
+	h.mu.Unlock()
+	wg.Wait()
+
+	// No worker is left to confirm the queued settlements
+	for {
+		select {
+		case job := <-h.jobs:
+			h.interrupt(context.WithoutCancel(ctx), job.settlement)
+			<-h.slots
+		default:
+			return
+		}
+	}
+}
+
+// Handle checks and broadcasts the transaction, then queues it for confirmation.
+// A transaction refused before broadcast is returned as Rejected, as Handle on
+// SettlePaymentHandler would.
+func (h *AsyncSettlePaymentHandler) Handle(ctx context.Context, cmd SettlePaymentCommand) (AsyncSettleResult, error) {
+	p, rejected, err := h.settle.prepare(cmd)
+	if err != nil {
+		return AsyncSettleResult{}, err
+	}
+	if rejected != nil {
+		return AsyncSettleResult{Rejected: *rejected}, nil
+	}
+
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	if h.stopped {
+		return AsyncSettleResult{}, ErrSettlementStopped
+	}
+
+	// Reserve a slot first so an accepted broadcast is never left without a worker
+	select {
+	case h.slots <- struct{}{}:
+	default:
+		return AsyncSettleResult{}, ErrSettlementQueueFull
+	}
+
+	txID, err := h.settle.broadcast(ctx, cmd.SignedTransaction, p)
+	if err != nil {
+		<-h.slots
+		if rejected := h.settle.sponsorFeeRejection(p, err); rejected != nil {
+			return AsyncSettleResult{Rejected: *rejected}, nil
+		}
+		return AsyncSettleResult{}, err
+	}
+
+	id, err := newRandomID()
+	if err != nil {
+		<-h.slots
+		return AsyncSettleResult{}, fmt.Errorf("transaction %s was broadcast but not queued: %w", txID, err)
+	}
+	now := h.now().UTC()
+	settlement := Settlement{
+		ID:        id,
+		TxID:      txID.String(),
+		Network:   p.network.String(),
+		TokenType: p.tokenType.String(),
+		State:     SettlementBroadcast,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	if err := h.store.Save(ctx, settlement); err != nil {
+		<-h.slots
+		return AsyncSettleResult{}, fmt.Errorf("transaction %s was broadcast but not queued: %w", txID, err)
+	}
+
+	h.jobs <- settlementJob{settlement: settlement, txID: txID, pending: p}
+	return AsyncSettleResult{Accepted: true, Settlement: settlement}, nil
+}
+
+// Get returns a settlement by ID
+func (h *AsyncSettlePaymentHandler) Get(ctx context.Context, id string) (Settlement, bool, error) {
+	return h.store.Get(ctx, id)
+}
+
+// track confirms one settlement, saving each change of state
+func (h *AsyncSettlePaymentHandler) track(ctx context.Context, job settlementJob) {
+	defer func() { <-h.slots }()
+
+	settlement := job.settlement
+	save := func() {
+		settlement.UpdatedAt = h.now().UTC()
+		if err := h.store.Save(ctx, settlement); err != nil {
+			log.Printf("settlement %s: failed to save %s state: %v", settlement.ID, settlement.State, err)
+		}
+	}
+
+	progress := func(state SettlementState, tx service.BlockchainTransaction) {
+		if state == settlement.State && tx.Confirmations == settlement.Confirmations && tx.BurnConfirmations == settlement.BurnConfirmations {
+			return
+		}
+		settlement.State = state
+		settlement.Confirmations, settlement.BurnConfirmations = tx.Confirmations, tx.BurnConfirmations
+		save()
+	}
+
+	result, err := h.settle.confirm(ctx, job.txID, job.pending, progress)
+	if err != nil {
+		if ctx.Err() != nil {
+			h.interrupt(context.WithoutCancel(ctx), settlement)
+			return
+		}
+		settlement.State = SettlementFailed
+		settlement.Error = err.Error()
+		save()
+		return
+	}
+
+	settlement.State = settlementState(result)
+	settlement.Confirmations, settlement.BurnConfirmations = result.Confirmations, result.BurnConfirmations
+	settlement.Result = &result
+	save()
+}
+
+// interrupt fails a settlement that shutdown stopped confirming. It is marked retryable:
+// settling the same transaction again resumes confirming it.
+func (h *AsyncSettlePaymentHandler) interrupt(ctx context.Context, settlement Settlement) {
+	settlement.State = SettlementFailed
+	settlement.Error = ErrSettlementInterrupted.Error()
+	settlement.Retryable = true
+	settlement.UpdatedAt = h.now().UTC()
+	if err := h.store.Save(ctx, settlement); err != nil {
+		log.Printf("settlement %s: failed to save interrupted state: %v", settlement.ID, err)
+	}
+}
+
+// newRandomID returns a random 128-bit hex identifier
+func newRandomID() (string, error) {
+	var b [16]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b[:]), nil
+}
@@ -0,0 +1,291 @@
+package command
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/x402stacks/stacks-facilitator/internal/payment/domain/service"
+	"github.com/x402stacks/stacks-facilitator/internal/payment/domain/valueobject"
+)
+
+// MockSettlementStore is a SettlementStore that remembers every state it was saved in
+type MockSettlementStore struct {
+	mu          sync.Mutex
+	settlements map[string]Settlement
+	states      []SettlementState
+}
+
+func (m *MockSettlementStore) Save(ctx context.Context, settlement Settlement) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.settlements == nil {
+		m.settlements = make(map[string]Settlement)
+	}
+	m.settlements[settlement.ID] = settlement
+	m.states = append(m.states, settlement.State)
+	return nil
+}
+
+func (m *MockSettlementStore) Get(ctx context.Context, id string) (Settlement, bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	settlement, ok := m.settlements[id]
+	return settlement, ok, nil
+}
+
+func (m *MockSettlementStore) States() []SettlementState {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]SettlementState(nil), m.states...)
+}
+
+var asyncSettleCommand = SettlePaymentCommand{
+	SignedTransaction: "0x00000001deadbeef",
+	TokenType:         "STX",
+	ExpectedRecipient: "ST1PQHQKV0RJXZFY1DGX8MNSNYVE3VGZJSRTPGZGM",
+	MinAmount:         "500000",
+	Network:           "testnet",
+}
+
+// waitForFinalState polls the store until the settlement reaches a final state
+func waitForFinalState(t *testing.T, h *AsyncSettlePaymentHandler, id string) Settlement {
+	t.Helper()
+	var settlement Settlement
+	require.Eventually(t, func() bool {
+		settlement, _, _ = h.Get(context.Background(), id)
+		return settlement.State.IsFinal()
+	}, time.Second, time.Millisecond)
+	return settlement
+}
+
+func TestAsyncSettlePaymentHandler_ConfirmsInBackground(t *testing.T) {
+	mockTx := createMockTransaction()
+	pending := mockTx
+	pending.Status = valueobject.TxStatusPending
+	pending.IsConfirmed = false
+	pending.BlockHeight = 0
+
+	var mu sync.Mutex
+	polls := 0
+	mockBroadcaster := &MockBroadcaster{
+		BroadcastFn: func(ctx context.Context, signedTx string, network valueobject.Network) (valueobject.TransactionID, error) {
+			return mockTx.TxID, nil
+		},
+		WaitForConfirmFn: func(ctx context.Context, txID valueobject.TransactionID, tokenType valueobject.TokenType, network valueobject.Network, maxRetries int, retryDelay time.Duration) (service.BlockchainTransaction, error) {
+			mu.Lock()
+			defer mu.Unlock()
+			polls++
+			switch polls {
+			case 1:
+				return service.BlockchainTransaction{}, errors.New("transaction not found")
+			case 2, 3:
+				return pending, nil
+			default:
+				return mockTx, nil
+			}
+		},
+	}
+
+	settle := NewSettlePaymentHandler(mockBroadcaster, decoderReturning(pending, valueobject.NetworkTestnet), service.NewVerificationService(),
+		WithRetry(10, time.Millisecond))
+	store := &MockSettlementStore{}
+	h := NewAsyncSettlePaymentHandler(settle, store, 2, 4)
+
+	result, err := h.Handle(context.Background(), asyncSettleCommand)
+
+	require.NoError(t, err)
+	require.True(t, result.Accepted)
+	assert.Len(t, result.Settlement.ID, 32)
+	assert.Equal(t, mockTx.TxID.String(), result.Settlement.TxID)
+	assert.Equal(t, SettlementBroadcast, result.Settlement.State)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go h.Run(ctx)
+
+	settlement := waitForFinalState(t, h, result.Settlement.ID)
+	assert.Equal(t, SettlementConfirmed, settlement.State)
+	require.NotNil(t, settlement.Result)
+	assert.True(t, settlement.Result.Success)
+	assert.Equal(t, uint64(1), settlement.Confirmations)
+	assert.Equal(t, []SettlementState{SettlementBroadcast, SettlementMempool, SettlementConfirmed}, store.States())
+}
+
+func TestAsyncSettlePaymentHandler_RejectedBeforeBroadcast(t *testing.T) {
+	decoded := createMockTransaction()
+	decoded.Amount = valueobject.NewAmount(100)
+
+	mockBroadcaster := &MockBroadcaster{
+		BroadcastFn: func(ctx context.Context, signedTx string, network valueobject.Network) (valueobject.TransactionID, error) {
+			t.Fatal("rejected transaction was broadcast")
+			return valueobject.TransactionID{}, nil
+		},
+	}
+
+	settle := NewSettlePaymentHandler(mockBroadcaster, decoderReturning(decoded, valueobject.NetworkTestnet), service.NewVerificationService())
+	store := &MockSettlementStore{}
+	h := NewAsyncSettlePaymentHandler(settle, store, 1, 0)
+
+	result, err := h.Handle(context.Background(), asyncSettleCommand)
+
+	require.NoError(t, err)
+	assert.False(t, result.Accepted)
+	assert.False(t, result.Rejected.Success)
+	assert.Contains(t, result.Rejected.Errors[0], "insufficient amount")
+	assert.Empty(t, store.States())
+}
+
+func TestAsyncSettlePaymentHandler_SponsorFeeAboveMax(t *testing.T) {
+	decoded := createMockTransaction()
+	decoded.Sponsored = true
+	sponsor := &MockSponsor{
+		SponsorFn: func(ctx context.Context, signedTx string, network valueobject.Network, maxFee uint64) (SponsoredTransaction, error) {
+			return SponsoredTransaction{}, &SponsorFeeError{Fee: 28300, MaxFee: maxFee}
+		},
+	}
+
+	settle := NewSettlePaymentHandler(rejectingBroadcaster(t), decoderReturning(decoded, valueobject.NetworkTestnet), service.NewVerificationService(),
+		WithSponsor(sponsor, service.SponsorPolicy{MaxFee: 5000}))
+	store := &MockSettlementStore{}
+	h := NewAsyncSettlePaymentHandler(settle, store, 1, 0)
+
+	result, err := h.Handle(context.Background(), asyncSettleCommand)
+
+	require.NoError(t, err)
+	assert.False(t, result.Accepted)
+	require.Len(t, result.Rejected.Failures, 1)
+	assert.Equal(t, service.FailureSponsorPolicy, result.Rejected.Failures[0].Code)
+	assert.Empty(t, store.States())
+}
+
+func TestAsyncSettlePaymentHandler_QueueFull(t *testing.T) {
+	broadcasts := 0
+	mockBroadcaster := &MockBroadcaster{
+		BroadcastFn: func(ctx context.Context, signedTx string, network valueobject.Network) (valueobject.TransactionID, error) {
+			broadcasts++
+			return createMockTransaction().TxID, nil
+		},
+	}
+
+	settle := NewSettlePaymentHandler(mockBroadcaster, decoderReturning(createMockTransaction(), valueobject.NetworkTestnet), service.NewVerificationService())
+	h := NewAsyncSettlePaymentHandler(settle, &MockSettlementStore{}, 1, 0)
+
+	_, err := h.Handle(context.Background(), asyncSettleCommand)
+	require.NoError(t, err)
+
+	_, err = h.Handle(context.Background(), asyncSettleCommand)
+
+	assert.ErrorIs(t, err, ErrSettlementQueueFull)
+	assert.Equal(t, 1, broadcasts, "no broadcast without a free slot")
+}
+
+func TestAsyncSettlePaymentHandler_StoppedAfterRun(t *testing.T) {
+	broadcasts := 0
+	mockBroadcaster := &MockBroadcaster{
+		BroadcastFn: func(ctx context.Context, signedTx string, network valueobject.Network) (valueobject.TransactionID, error) {
+			broadcasts++
+			return createMockTransaction().TxID, nil
+		},
+	}
+
+	settle := NewSettlePaymentHandler(mockBroadcaster, decoderReturning(createMockTransaction(), valueobject.NetworkTestnet), service.NewVerificationService())
+	store := &MockSettlementStore{}
+	h := NewAsyncSettlePaymentHandler(settle, store, 1, 1)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	h.Run(ctx)
+
+	_, err := h.Handle(context.Background(), asyncSettleCommand)
+
+	assert.ErrorIs(t, err, ErrSettlementStopped)
+	assert.Zero(t, broadcasts, "no broadcast once the workers have stopped")
+	assert.Empty(t, store.States())
+}
+
+func TestAsyncSettlePaymentHandler_RunFailsQueuedOnShutdown(t *testing.T) {
+	mockBroadcaster := &MockBroadcaster{
+		BroadcastFn: func(ctx context.Context, signedTx string, network valueobject.Network) (valueobject.TransactionID, error) {
+			return createMockTransaction().TxID, nil
+		},
+		// A worker may still pick the job up as Run stops; it finds the context cancelled
+		WaitForConfirmFn: func(ctx context.Context, txID valueobject.TransactionID, tokenType valueobject.TokenType, network valueobject.Network, maxRetries int, retryDelay time.Duration) (service.BlockchainTransaction, error) {
+			return service.BlockchainTransaction{}, ctx.Err()
+		},
+	}
+
+	settle := NewSettlePaymentHandler(mockBroadcaster, decoderReturning(createMockTransaction(), valueobject.NetworkTestnet), service.NewVerificationService())
+	h := NewAsyncSettlePaymentHandler(settle, &MockSettlementStore{}, 1, 1)
+
+	// Accepted while no worker runs, then shut down before one picks it up
+	result, err := h.Handle(context.Background(), asyncSettleCommand)
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	h.Run(ctx)
+
+	settlement, ok, err := h.Get(context.Background(), result.Settlement.ID)
+	require.NoError(t, err)
+	require.True(t, ok)
+	assert.Equal(t, SettlementFailed, settlement.State)
+	assert.True(t, settlement.Retryable)
+	assert.Equal(t, ErrSettlementInterrupted.Error(), settlement.Error)
+	assert.Empty(t, h.slots, "the slot is released")
+}
+
+func TestAsyncSettlePaymentHandler_BroadcastErrorFreesSlot(t *testing.T) {
+	fail := true
+	mockBroadcaster := &MockBroadcaster{
+		BroadcastFn: func(ctx context.Context, signedTx string, network valueobject.Network) (valueobject.TransactionID, error) {
+			if fail {
+				return valueobject.TransactionID{}, errors.New("ConflictingNonceInMempool")
+			}
+			return createMockTransaction().TxID, nil
+		},
+	}
+
+	settle := NewSettlePaymentHandler(mockBroadcaster, decoderReturning(createMockTransaction(), valueobject.NetworkTestnet), service.NewVerificationService())
+	h := NewAsyncSettlePaymentHandler(settle, &MockSettlementStore{}, 1, 0)
+
+	_, err := h.Handle(context.Background(), asyncSettleCommand)
+	assert.ErrorContains(t, err, "failed to broadcast transaction")
+
+	fail = false
+	result, err := h.Handle(context.Background(), asyncSettleCommand)
+	require.NoError(t, err)
+	assert.True(t, result.Accepted)
+}
+
+func TestAsyncSettlePaymentHandler_TrackingError(t *testing.T) {
+	mockTx := createMockTransaction()
+	mockBroadcaster := &MockBroadcaster{
+		BroadcastFn: func(ctx context.Context, signedTx string, network valueobject.Network) (valueobject.TransactionID, error) {
+			return mockTx.TxID, nil
+		},
+		WaitForConfirmFn: func(ctx context.Context, txID valueobject.TransactionID, tokenType valueobject.TokenType, network valueobject.Network, maxRetries int, retryDelay time.Duration) (service.BlockchainTransaction, error) {
+			return mockTx, nil
+		},
+	}
+
+	settle := NewSettlePaymentHandler(mockBroadcaster, decoderReturning(mockTx, valueobject.NetworkTestnet), service.NewVerificationService(),
+		WithChainTip(&MockChainTip{Err: errors.New("node unavailable")}))
+	h := NewAsyncSettlePaymentHandler(settle, &MockSettlementStore{}, 1, 0)
+
+	result, err := h.Handle(context.Background(), asyncSettleCommand)
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go h.Run(ctx)
+
+	settlement := waitForFinalState(t, h, result.Settlement.ID)
+	assert.Equal(t, SettlementFailed, settlement.State)
+	assert.Nil(t, settlement.Result)
+	assert.Contains(t, settlement.Error, "node unavailable")
+}
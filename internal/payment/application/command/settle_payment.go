@@ -2,7 +2,9 @@ package command
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"strconv"
 	"time"
 
 	"github.com/x402stacks/stacks-facilitator/internal/payment/domain/service"
@@ -15,93 +17,361 @@ type TransactionBroadcaster interface {
 	WaitForConfirmation(ctx context.Context, txID valueobject.TransactionID, tokenType valueobject.TokenType, network valueobject.Network, maxRetries int, retryDelay time.Duration) (service.BlockchainTransaction, error)
 }
 
+// TransactionDecoder interface for decoding signed transactions before broadcast
+type TransactionDecoder interface {
+	DecodeTransaction(signedTx string, tokenType valueobject.TokenType) (service.BlockchainTransaction, valueobject.Network, error)
+	// RecoverSigner returns the origin address recovered from the transaction's signatures
+	RecoverSigner(signedTx string) (valueobject.StacksAddress, error)
+}
+
+// ErrInvalidSignedTransaction is returned when a signed transaction cannot be decoded
+var ErrInvalidSignedTransaction = errors.New("invalid signed transaction")
+
 // SettlePaymentCommand represents a request to settle a payment
 type SettlePaymentCommand struct {
 	SignedTransaction string
 	TokenType         string
 	ExpectedRecipient string
-	MinAmount         uint64
+	MinAmount         string // Decimal string in AmountUnit
+	AmountUnit        string // AmountUnitBase (default) or AmountUnitToken
 	ExpectedSender    *string
 	Network           string
+	Resource          string // Resource the payment unlocks, recorded for replay protection
+	Nonce             string // Client-supplied payment nonce, recorded for replay protection
+
+	MinConfirmations     uint64 // Stacks blocks deep the tx must be; raised to the handler's minimum
+	MinBurnConfirmations uint64 // Bitcoin burn blocks deep the tx must be; raised to the handler's minimum
+
+	CallbackURL string // Notified, as well as the global webhooks, when the payment leaves pending
 }
 
 // SettlePaymentResult represents the result of a settlement
 type SettlePaymentResult struct {
-	Success          bool
-	TxID             string
-	SenderAddress    string
-	RecipientAddress string
-	Amount           uint64
-	Fee              uint64
-	Status           string
-	BlockHeight      uint64
-	TokenType        string
-	Network          string
-	Errors           []string
+	Success           bool
+	TxID              string
+	SenderAddress     string
+	RecipientAddress  string
+	Amount            string // Base units as a decimal string
+	DisplayAmount     string // Whole tokens with symbol, e.g. "0.0001 sBTC"
+	Fee               uint64
+	Status            string // pending, confirmed, failed or dropped
+	ReplacedByTxID    string // Transaction that replaced a dropped one, when known
+	BlockHeight       uint64
+	Confirmations     uint64 // Stacks blocks deep, counting the tx's own block; 0 when unconfirmed
+	BurnConfirmations uint64 // Bitcoin burn blocks deep, counting the tx's own; 0 when unconfirmed
+	TokenType         string
+	Network           string
+	Errors            []string                      // Message of each failure, in order
+	Failures          []service.VerificationFailure // Why settlement failed verification, with machine-readable codes
 }
 
 // SettlePaymentHandler handles settle payment commands
 type SettlePaymentHandler struct {
 	broadcaster     TransactionBroadcaster
+	decoder         TransactionDecoder
 	verificationSvc *service.VerificationService
 	maxRetries      int
 	retryDelay      time.Duration
+	tokenRegistry   *service.TokenRegistry
+	sponsor         TransactionSponsor
+	sponsorPolicy   service.SponsorPolicy
+	paymentStore    PaymentStore
+
+	chainTip             ChainTipProvider
+	minConfirmations     uint64
+	minBurnConfirmations uint64
+
+	tracker *PaymentTracker
+	now     func() time.Time
 }
 
 // NewSettlePaymentHandler creates a new SettlePaymentHandler
-func NewSettlePaymentHandler(broadcaster TransactionBroadcaster, verificationSvc *service.VerificationService) *SettlePaymentHandler {
+func NewSettlePaymentHandler(broadcaster TransactionBroadcaster, decoder TransactionDecoder, verificationSvc *service.VerificationService, opts ...Option) *SettlePaymentHandler {
+	o := applyOptions(options{maxRetries: 15, retryDelay: 2 * time.Second}, opts)
+
 	return &SettlePaymentHandler{
 		broadcaster:     broadcaster,
+		decoder:         decoder,
 		verificationSvc: verificationSvc,
-		maxRetries:      15,
-		retryDelay:      2 * time.Second,
+		maxRetries:      o.maxRetries,
+		retryDelay:      o.retryDelay,
+		tokenRegistry:   o.tokenRegistry,
+		sponsor:         o.sponsor,
+		sponsorPolicy:   o.sponsorPolicy,
+		paymentStore:    o.paymentStore,
+
+		chainTip:             o.chainTip,
+		minConfirmations:     o.minConfirmations,
+		minBurnConfirmations: o.minBurnConfirmations,
+
+		tracker: o.tracker,
+		now:     time.Now,
 	}
 }
 
 // Handle processes the settle payment command
 func (h *SettlePaymentHandler) Handle(ctx context.Context, cmd SettlePaymentCommand) (SettlePaymentResult, error) {
+	p, rejected, err := h.prepare(cmd)
+	if err != nil {
+		return SettlePaymentResult{}, err
+	}
+	if rejected != nil {
+		return *rejected, nil
+	}
+
+	txID, err := h.broadcast(ctx, cmd.SignedTransaction, p)
+	if rejected := h.sponsorFeeRejection(p, err); rejected != nil {
+		return *rejected, nil
+	}
+	if err != nil {
+		return SettlePaymentResult{}, err
+	}
+
+	return h.confirm(ctx, txID, p, nil)
+}
+
+// pendingSettlement is a decoded transaction that passed the pre-broadcast checks
+type pendingSettlement struct {
+	decoded   service.BlockchainTransaction
+	tokenType valueobject.TokenType
+	network   valueobject.Network
+	criteria  service.VerificationCriteria
+
+	resource    string
+	nonce       string
+	callbackURL string
+}
+
+// settlementProgress is told each time a broadcast transaction is polled
+type settlementProgress func(state SettlementState, tx service.BlockchainTransaction)
+
+// prepare parses the command and checks the decoded transaction before broadcast.
+// A transaction that fails the checks is returned as a rejected result.
+func (h *SettlePaymentHandler) prepare(cmd SettlePaymentCommand) (pendingSettlement, *SettlePaymentResult, error) {
 	// Parse and validate inputs
-	tokenType, err := valueobject.NewTokenType(cmd.TokenType)
+	tokenType, err := parseTokenType(cmd.TokenType)
 	if err != nil {
-		tokenType = valueobject.TokenSTX // Default to STX
+		return pendingSettlement{}, nil, err
 	}
 
 	network, err := valueobject.NewNetwork(cmd.Network)
 	if err != nil {
-		return SettlePaymentResult{}, fmt.Errorf("invalid network: %w", err)
+		return pendingSettlement{}, nil, fmt.Errorf("invalid network: %w", err)
 	}
 
-	expectedRecipient, err := valueobject.NewStacksAddress(cmd.ExpectedRecipient)
+	expectedRecipient, err := parseNetworkPrincipal(cmd.ExpectedRecipient, network)
 	if err != nil {
-		return SettlePaymentResult{}, fmt.Errorf("invalid expected recipient: %w", err)
+		return pendingSettlement{}, nil, fmt.Errorf("invalid expected recipient: %w", err)
 	}
 
-	// Broadcast the transaction
-	txID, err := h.broadcaster.BroadcastTransaction(ctx, cmd.SignedTransaction, network)
+	minAmount, err := parseMinAmount(cmd.MinAmount, cmd.AmountUnit, h.tokenRegistry, tokenType)
 	if err != nil {
-		return SettlePaymentResult{}, fmt.Errorf("failed to broadcast transaction: %w", err)
+		return pendingSettlement{}, nil, err
 	}
 
-	// Wait for transaction to be confirmed
-	tx, err := h.broadcaster.WaitForConfirmation(ctx, txID, tokenType, network, h.maxRetries, h.retryDelay)
+	contract, err := expectedContract(h.tokenRegistry, tokenType, network)
 	if err != nil {
-		return SettlePaymentResult{}, fmt.Errorf("failed to confirm transaction: %w", err)
+		return pendingSettlement{}, nil, err
+	}
+
+	callbackURL, err := parseCallbackURL(cmd.CallbackURL, h.tracker)
+	if err != nil {
+		return pendingSettlement{}, nil, err
 	}
 
-	// Build verification criteria (always require confirmation for settlement)
+	// Build verification criteria
 	criteria := service.VerificationCriteria{
 		ExpectedRecipient: expectedRecipient,
-		MinAmount:         valueobject.NewAmount(cmd.MinAmount),
-		AcceptUnconfirmed: false,
+		MinAmount:         minAmount,
+		ExpectedToken:     tokenType,
+		ExpectedContract:  contract,
+
+		MinConfirmations:     max(h.minConfirmations, cmd.MinConfirmations),
+		MinBurnConfirmations: max(h.minBurnConfirmations, cmd.MinBurnConfirmations),
 	}
 
 	// Optional sender
 	if cmd.ExpectedSender != nil {
-		sender, err := valueobject.NewStacksAddress(*cmd.ExpectedSender)
-		if err == nil {
-			criteria.ExpectedSender = &sender
+		sender, err := parseNetworkAddress(*cmd.ExpectedSender, network)
+		if err != nil {
+			return pendingSettlement{}, nil, fmt.Errorf("invalid expected sender: %w", err)
+		}
+		criteria.ExpectedSender = &sender
+	}
+
+	// Decode and check the transaction before it is broadcast
+	decoded, preResult, err := checkSignedTransaction(h.decoder, h.verificationSvc, cmd.SignedTransaction, tokenType, network, criteria)
+	if err != nil {
+		return pendingSettlement{}, nil, err
+	}
+	if !preResult.Valid {
+		return h.rejectedSettlement(decoded, tokenType, network, preResult.Failures)
+	}
+
+	// Sponsored transactions are counter-signed by the facilitator, which pays the fee
+	if decoded.Sponsored {
+		if h.sponsor == nil {
+			return h.rejectedSettlement(decoded, tokenType, network, []service.VerificationFailure{{
+				Code:    service.FailureSponsorPolicy,
+				Message: "sponsor policy: sponsored transactions are not accepted",
+			}})
+		}
+		if failures := h.sponsorPolicy.Check(decoded); len(failures) > 0 {
+			return h.rejectedSettlement(decoded, tokenType, network, failures)
+		}
+	}
+
+	return pendingSettlement{
+		decoded:     decoded,
+		tokenType:   tokenType,
+		network:     network,
+		criteria:    criteria,
+		resource:    cmd.Resource,
+		nonce:       cmd.Nonce,
+		callbackURL: callbackURL,
+	}, nil, nil
+}
+
+// broadcast submits a prepared transaction and, with a payment store, records it as consumed
+// under the txid it was signed with. The txid is claimed in the store before anything is
+// sponsored or broadcast, so of two concurrent settlements of a transaction only one gets
+// that far; the claim is released if the node rejects the transaction. A transaction the
+// store already holds is not broadcast again: the settlement that recorded it, identified
+// by the same resource and nonce, resumes with the txid it broadcast, which for a sponsored
+// transaction is the counter-signed one, and hands it to the tracker for its callback. Any
+// other settlement, or one still claiming the transaction, is refused with ErrPaymentAlreadyUsed.
+func (h *SettlePaymentHandler) broadcast(ctx context.Context, signedTx string, p pendingSettlement) (valueobject.TransactionID, error) {
+	if h.paymentStore == nil {
+		return h.submit(ctx, signedTx, p)
+	}
+
+	existing, ok, err := h.paymentStore.Get(ctx, p.decoded.TxID.String())
+	if err != nil {
+		return valueobject.TransactionID{}, fmt.Errorf("failed to check payment store: %w", err)
+	}
+	if ok {
+		txID, err := resumedSettlement(existing, p)
+		if err != nil {
+			return valueobject.TransactionID{}, err
+		}
+		if h.tracker != nil {
+			h.tracker.track(txID, p)
+		}
+		return txID, nil
+	}
+
+	payment := ConsumedPayment{
+		TxID:       p.decoded.TxID.String(),
+		Network:    p.network.String(),
+		Resource:   p.resource,
+		Nonce:      p.nonce,
+		ConsumedAt: h.now().UTC(),
+	}
+	if err := h.paymentStore.MarkConsumed(ctx, payment); err != nil {
+		if errors.Is(err, ErrPaymentAlreadyUsed) {
+			return valueobject.TransactionID{}, err
+		}
+		return valueobject.TransactionID{}, fmt.Errorf("failed to claim transaction: %w", err)
+	}
+
+	txID, err := h.submit(ctx, signedTx, p)
+	if err != nil {
+		if notBroadcast(err) {
+			if releaseErr := h.paymentStore.Release(ctx, payment.TxID); releaseErr != nil {
+				return valueobject.TransactionID{}, errors.Join(err, fmt.Errorf("failed to release claim on %s: %w", payment.TxID, releaseErr))
+			}
+		}
+		return valueobject.TransactionID{}, err
+	}
+
+	payment.BroadcastTxID = txID.String()
+	if err := h.paymentStore.Update(ctx, payment); err != nil {
+		return valueobject.TransactionID{}, recordError(txID, err)
+	}
+
+	// A sponsored transaction is mined under its counter-signed txid, which must not be
+	// accepted again by verifying it on chain
+	if txID != p.decoded.TxID {
+		payment.TxID = txID.String()
+		if err := h.paymentStore.MarkConsumed(ctx, payment); err != nil {
+			return valueobject.TransactionID{}, recordError(txID, err)
+		}
+	}
+
+	return txID, nil
+}
+
+// notBroadcast reports whether a failed submission certainly left the transaction off the
+// network, so its claim can be released. Other failures, such as an unreachable node, may
+// have happened after the node accepted it, and keep the claim.
+func notBroadcast(err error) bool {
+	return errors.Is(err, ErrBroadcastRejected) || errors.Is(err, ErrSponsorFailed) || errors.Is(err, ErrSponsorFeeExceeded)
+}
+
+// resumedSettlement returns the txid a recorded settlement broadcast when p is that same
+// settlement. A payment recorded by verify, a settlement claim not yet broadcast, or one
+// without a resource or nonce to tell its settlement apart, cannot be resumed.
+func resumedSettlement(existing ConsumedPayment, p pendingSettlement) (valueobject.TransactionID, error) {
+	if existing.BroadcastTxID == "" || existing.Resource == "" && existing.Nonce == "" ||
+		existing.Resource != p.resource || existing.Nonce != p.nonce {
+		return valueobject.TransactionID{}, existing.AlreadyUsedError()
+	}
+
+	txID, err := valueobject.NewTransactionID(existing.BroadcastTxID)
+	if err != nil {
+		return valueobject.TransactionID{}, fmt.Errorf("corrupt settlement record for %s: %w", existing.TxID, err)
+	}
+	return txID, nil
+}
+
+// recordError reports a broadcast transaction that could not be recorded as consumed
+func recordError(txID valueobject.TransactionID, err error) error {
+	if errors.Is(err, ErrPaymentAlreadyUsed) {
+		return err
+	}
+	return fmt.Errorf("transaction %s was broadcast but not recorded: %w", txID, err)
+}
+
+// submit broadcasts a prepared transaction, having it sponsored first when needed, and hands
+// it to the tracker when there is one
+func (h *SettlePaymentHandler) submit(ctx context.Context, signedTx string, p pendingSettlement) (valueobject.TransactionID, error) {
+	var txID valueobject.TransactionID
+	var err error
+	if p.decoded.Sponsored {
+		txID, err = h.sponsorAndBroadcast(ctx, signedTx, p.network)
+	} else {
+		txID, err = h.broadcaster.BroadcastTransaction(ctx, signedTx, p.network)
+		if err != nil {
+			err = fmt.Errorf("failed to broadcast transaction: %w", err)
 		}
 	}
+	if err != nil {
+		return valueobject.TransactionID{}, err
+	}
+
+	if h.tracker != nil {
+		h.tracker.track(txID, p)
+	}
+	return txID, nil
+}
+
+// confirm waits for a broadcast transaction to be confirmed as deep as the criteria require
+// and verifies it. When progress is set, the transaction is polled one attempt at a time
+// and progress is told its state after each poll.
+func (h *SettlePaymentHandler) confirm(ctx context.Context, txID valueobject.TransactionID, p pendingSettlement, progress settlementProgress) (SettlePaymentResult, error) {
+	// Wait for transaction to be confirmed
+	tx, err := h.waitForConfirmation(ctx, txID, p, progress)
+	if err != nil {
+		return SettlePaymentResult{}, fmt.Errorf("failed to confirm transaction: %w", err)
+	}
+	tx, err = h.waitForConfirmations(ctx, tx, p.tokenType, p.network, p.criteria, progress)
+	if err != nil {
+		return SettlePaymentResult{}, err
+	}
+
+	// Settlement always requires confirmation
+	criteria := p.criteria
+	criteria.AcceptUnconfirmed = false
 
 	// Verify transaction
 	verificationResult := h.verificationSvc.Verify(tx, criteria)
@@ -110,16 +380,157 @@ func (h *SettlePaymentHandler) Handle(ctx context.Context, cmd SettlePaymentComm
 	status := determinePaymentStatus(tx)
 
 	return SettlePaymentResult{
-		Success:          verificationResult.Valid,
-		TxID:             tx.TxID.String(),
-		SenderAddress:    tx.Sender.String(),
-		RecipientAddress: tx.Recipient.String(),
-		Amount:           tx.Amount.Value(),
-		Fee:              tx.Fee.Value(),
-		Status:           status,
-		BlockHeight:      tx.BlockHeight,
-		TokenType:        tx.TokenType.String(),
+		Success:           verificationResult.Valid,
+		TxID:              tx.TxID.String(),
+		SenderAddress:     tx.Sender.String(),
+		RecipientAddress:  tx.Recipient.String(),
+		Amount:            tx.Amount.String(),
+		DisplayAmount:     displayAmount(h.tokenRegistry, tx.Amount, tx.TokenType),
+		Fee:               feeValue(tx.Fee),
+		Status:            status,
+		ReplacedByTxID:    tx.ReplacedBy.String(),
+		BlockHeight:       tx.BlockHeight,
+		Confirmations:     tx.Confirmations,
+		BurnConfirmations: tx.BurnConfirmations,
+		TokenType:         tx.TokenType.String(),
+		Network:           p.network.String(),
+		Errors:            verificationResult.Errors(),
+		Failures:          verificationResult.Failures,
+	}, nil
+}
+
+// waitForConfirmation polls until the transaction is mined, fails or is dropped, within the
+// handler's retry budget. With progress set, a transaction the API has not indexed yet is
+// retried rather than treated as an error until the budget runs out.
+func (h *SettlePaymentHandler) waitForConfirmation(ctx context.Context, txID valueobject.TransactionID, p pendingSettlement, progress settlementProgress) (service.BlockchainTransaction, error) {
+	if progress == nil {
+		return h.broadcaster.WaitForConfirmation(ctx, txID, p.tokenType, p.network, h.maxRetries, h.retryDelay)
+	}
+
+	var tx service.BlockchainTransaction
+	var err error
+	for attempt := 0; attempt < h.maxRetries; attempt++ {
+		tx, err = h.broadcaster.WaitForConfirmation(ctx, txID, p.tokenType, p.network, 1, h.retryDelay)
+		if ctx.Err() != nil {
+			return service.BlockchainTransaction{}, ctx.Err()
+		}
+		if err != nil {
+			continue
+		}
+		if tx.IsConfirmed || tx.Status.IsFailed() || tx.Status.IsDropped() {
+			return tx, nil
+		}
+		progress(SettlementMempool, tx)
+	}
+	return tx, err
+}
+
+// waitForConfirmations keeps polling a confirmed transaction until it is as deep as the
+// criteria require, within the handler's retry budget. The transaction is refetched on
+// each poll so a block that was reorganized away is not counted.
+func (h *SettlePaymentHandler) waitForConfirmations(ctx context.Context, tx service.BlockchainTransaction, tokenType valueobject.TokenType, network valueobject.Network, criteria service.VerificationCriteria, progress settlementProgress) (service.BlockchainTransaction, error) {
+	if err := setConfirmations(ctx, h.chainTip, &tx, network); err != nil {
+		return service.BlockchainTransaction{}, err
+	}
+
+	for attempt := 0; tx.IsConfirmed && !hasConfirmations(tx, criteria) && attempt < h.maxRetries; attempt++ {
+		if progress != nil {
+			progress(SettlementMined, tx)
+		}
+
+		select {
+		case <-ctx.Done():
+			return service.BlockchainTransaction{}, ctx.Err()
+		case <-time.After(h.retryDelay):
+		}
+
+		next, err := h.broadcaster.WaitForConfirmation(ctx, tx.TxID, tokenType, network, 1, h.retryDelay)
+		if err != nil {
+			return service.BlockchainTransaction{}, fmt.Errorf("failed to confirm transaction: %w", err)
+		}
+		if err := setConfirmations(ctx, h.chainTip, &next, network); err != nil {
+			return service.BlockchainTransaction{}, err
+		}
+		tx = next
+	}
+
+	return tx, nil
+}
+
+// sponsorAndBroadcast counter-signs a sponsored transaction and broadcasts it,
+// re-sponsoring with a fresh nonce when the sponsor's nonce was rejected. A rejection of the
+// fee the sponsor pays is the sponsor's failure and wraps ErrSponsorFailed.
+func (h *SettlePaymentHandler) sponsorAndBroadcast(ctx context.Context, signedTx string, network valueobject.Network) (valueobject.TransactionID, error) {
+	for attempt := 1; ; attempt++ {
+		sponsored, err := h.sponsor.SponsorTransaction(ctx, signedTx, network, h.sponsorPolicy.MaxFee)
+		if errors.Is(err, ErrSponsorFeeExceeded) {
+			return valueobject.TransactionID{}, err
+		}
+		if err != nil {
+			return valueobject.TransactionID{}, fmt.Errorf("%w: %w", ErrSponsorFailed, err)
+		}
+
+		txID, err := h.broadcaster.BroadcastTransaction(ctx, sponsored.SignedTransaction, network)
+		retry := h.sponsor.Release(sponsored, err)
+		if err == nil {
+			return txID, nil
+		}
+		if isSponsorFeeRejection(err, sponsored) {
+			return valueobject.TransactionID{}, fmt.Errorf("%w: %w", ErrSponsorFailed, err)
+		}
+		if !retry || attempt == maxSponsorAttempts {
+			return valueobject.TransactionID{}, fmt.Errorf("failed to broadcast transaction: %w", err)
+		}
+	}
+}
+
+// isSponsorFeeRejection reports whether the node refused a sponsored transaction for the
+// fee the sponsor pays: the fee was too low, or the sponsor could not afford it. The node
+// checks the fee payer's balance first and names the fee as the amount it needed.
+func isSponsorFeeRejection(err error, sponsored SponsoredTransaction) bool {
+	var rejected *BroadcastRejectedError
+	if !errors.As(err, &rejected) {
+		return false
+	}
+	switch rejected.Reason {
+	case RejectionFeeTooLow:
+		return true
+	case RejectionNotEnoughFunds:
+		return rejected.Expected == strconv.FormatUint(sponsored.Fee, 10)
+	default:
+		return false
+	}
+}
+
+// sponsorFeeRejection reports a settlement refused because sponsoring it would cost more
+// than the sponsor policy's max fee, or nil for any other outcome of broadcast
+func (h *SettlePaymentHandler) sponsorFeeRejection(p pendingSettlement, err error) *SettlePaymentResult {
+	var feeErr *SponsorFeeError
+	if !errors.As(err, &feeErr) {
+		return nil
+	}
+	failures := h.sponsorPolicy.CheckFee(feeErr.Fee)
+	if len(failures) == 0 {
+		return nil
+	}
+	_, rejected, _ := h.rejectedSettlement(p.decoded, p.tokenType, p.network, failures)
+	return rejected
+}
+
+// rejectedSettlement reports a transaction refused before broadcast
+func (h *SettlePaymentHandler) rejectedSettlement(decoded service.BlockchainTransaction, tokenType valueobject.TokenType, network valueobject.Network, failures []service.VerificationFailure) (pendingSettlement, *SettlePaymentResult, error) {
+	return pendingSettlement{}, &SettlePaymentResult{
+		Success:          false,
+		TxID:             decoded.TxID.String(),
+		SenderAddress:    decoded.Sender.String(),
+		RecipientAddress: decoded.Recipient.String(),
+		Amount:           decoded.Amount.String(),
+		DisplayAmount:    displayAmount(h.tokenRegistry, decoded.Amount, tokenType),
+		Fee:              feeValue(decoded.Fee),
+		Status:           "failed",
+		TokenType:        tokenType.String(),
 		Network:          network.String(),
-		Errors:           verificationResult.Errors,
+		Errors:           service.FailureMessages(failures),
+		Failures:         failures,
 	}, nil
 }
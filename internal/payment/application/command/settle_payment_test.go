@@ -3,6 +3,8 @@ package command
 import (
 	"context"
 	"errors"
+	"fmt"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -14,8 +16,8 @@ import (
 
 // MockBroadcaster is a mock implementation for testing
 type MockBroadcaster struct {
-	BroadcastFn         func(ctx context.Context, signedTx string, network valueobject.Network) (valueobject.TransactionID, error)
-	WaitForConfirmFn    func(ctx context.Context, txID valueobject.TransactionID, tokenType valueobject.TokenType, network valueobject.Network, maxRetries int, retryDelay time.Duration) (service.BlockchainTransaction, error)
+	BroadcastFn      func(ctx context.Context, signedTx string, network valueobject.Network) (valueobject.TransactionID, error)
+	WaitForConfirmFn func(ctx context.Context, txID valueobject.TransactionID, tokenType valueobject.TokenType, network valueobject.Network, maxRetries int, retryDelay time.Duration) (service.BlockchainTransaction, error)
 }
 
 func (m *MockBroadcaster) BroadcastTransaction(ctx context.Context, signedTx string, network valueobject.Network) (valueobject.TransactionID, error) {
@@ -26,10 +28,37 @@ func (m *MockBroadcaster) WaitForConfirmation(ctx context.Context, txID valueobj
 	return m.WaitForConfirmFn(ctx, txID, tokenType, network, maxRetries, retryDelay)
 }
 
+// MockDecoder is a mock implementation for testing
+type MockDecoder struct {
+	DecodeFn  func(signedTx string, tokenType valueobject.TokenType) (service.BlockchainTransaction, valueobject.Network, error)
+	RecoverFn func(signedTx string) (valueobject.StacksAddress, error) // Defaults to the decoded sender
+}
+
+func (m *MockDecoder) DecodeTransaction(signedTx string, tokenType valueobject.TokenType) (service.BlockchainTransaction, valueobject.Network, error) {
+	return m.DecodeFn(signedTx, tokenType)
+}
+
+func (m *MockDecoder) RecoverSigner(signedTx string) (valueobject.StacksAddress, error) {
+	if m.RecoverFn != nil {
+		return m.RecoverFn(signedTx)
+	}
+	tx, _, err := m.DecodeFn(signedTx, valueobject.TokenSTX)
+	return tx.Sender, err
+}
+
+// decoderReturning returns a decoder that yields tx on network
+func decoderReturning(tx service.BlockchainTransaction, network valueobject.Network) *MockDecoder {
+	return &MockDecoder{
+		DecodeFn: func(signedTx string, tokenType valueobject.TokenType) (service.BlockchainTransaction, valueobject.Network, error) {
+			return tx, network, nil
+		},
+	}
+}
+
 func TestSettlePaymentHandler_Success(t *testing.T) {
 	txID, _ := valueobject.NewTransactionID("0x1234567890abcdef1234567890abcdef1234567890abcdef1234567890abcdef")
-	sender, _ := valueobject.NewStacksAddress("ST2J6ZY48GV1EZ5V2V5RB9MP66SW86PYKKNRV9EJ7")
-	recipient, _ := valueobject.NewStacksAddress("ST1PQHQKV0RJXZFY1DGX8MNSNYVE3VGZJSRTPGZGM")
+	sender, _ := valueobject.NewStacksAddress("ST2J6ZY48GV1EZ5V2V5RB9MP66SW86PYKKQYAC0RQ")
+	recipient, _ := valueobject.NewPrincipal("ST1PQHQKV0RJXZFY1DGX8MNSNYVE3VGZJSRTPGZGM")
 
 	mockTx := service.BlockchainTransaction{
 		TxID:        txID,
@@ -54,13 +83,13 @@ func TestSettlePaymentHandler_Success(t *testing.T) {
 	}
 
 	verificationSvc := service.NewVerificationService()
-	handler := NewSettlePaymentHandler(mockBroadcaster, verificationSvc)
+	handler := NewSettlePaymentHandler(mockBroadcaster, decoderReturning(mockTx, valueobject.NetworkTestnet), verificationSvc)
 
 	cmd := SettlePaymentCommand{
 		SignedTransaction: "0x00000001deadbeef",
 		TokenType:         "STX",
 		ExpectedRecipient: "ST1PQHQKV0RJXZFY1DGX8MNSNYVE3VGZJSRTPGZGM",
-		MinAmount:         500000,
+		MinAmount:         "500000",
 		Network:           "testnet",
 	}
 
@@ -70,9 +99,161 @@ func TestSettlePaymentHandler_Success(t *testing.T) {
 	assert.True(t, result.Success)
 	assert.Equal(t, "confirmed", result.Status)
 	assert.Equal(t, txID.String(), result.TxID)
+	assert.Equal(t, "1000000", result.Amount)
+	assert.Equal(t, "1 STX", result.DisplayAmount)
+}
+
+func TestSettlePaymentHandler_WaitsForMinConfirmations(t *testing.T) {
+	txID, _ := valueobject.NewTransactionID("0x1234567890abcdef1234567890abcdef1234567890abcdef1234567890abcdef")
+	sender, _ := valueobject.NewStacksAddress("ST2J6ZY48GV1EZ5V2V5RB9MP66SW86PYKKQYAC0RQ")
+	recipient, _ := valueobject.NewPrincipal("ST1PQHQKV0RJXZFY1DGX8MNSNYVE3VGZJSRTPGZGM")
+
+	mockTx := service.BlockchainTransaction{
+		TxID:        txID,
+		TokenType:   valueobject.TokenSTX,
+		Sender:      sender,
+		Recipient:   recipient,
+		Amount:      valueobject.NewAmount(1000000),
+		BlockHeight: 12345,
+		Status:      "success",
+		IsConfirmed: true,
+	}
+
+	fetches := 0
+	mockBroadcaster := &MockBroadcaster{
+		BroadcastFn: func(ctx context.Context, signedTx string, network valueobject.Network) (valueobject.TransactionID, error) {
+			return txID, nil
+		},
+		WaitForConfirmFn: func(ctx context.Context, txID valueobject.TransactionID, tokenType valueobject.TokenType, network valueobject.Network, maxRetries int, retryDelay time.Duration) (service.BlockchainTransaction, error) {
+			fetches++
+			return mockTx, nil
+		},
+	}
+	chainTip := &MockChainTip{Tips: []service.ChainTip{{StacksHeight: 12345}, {StacksHeight: 12346}, {StacksHeight: 12347}}}
+
+	handler := NewSettlePaymentHandler(mockBroadcaster, decoderReturning(mockTx, valueobject.NetworkTestnet), service.NewVerificationService(),
+		WithRetry(5, time.Millisecond), WithChainTip(chainTip), WithMinConfirmations(3, 0))
+
+	result, err := handler.Handle(context.Background(), SettlePaymentCommand{
+		SignedTransaction: "0x00000001deadbeef",
+		TokenType:         "STX",
+		ExpectedRecipient: "ST1PQHQKV0RJXZFY1DGX8MNSNYVE3VGZJSRTPGZGM",
+		MinAmount:         "500000",
+		Network:           "testnet",
+	})
+
+	require.NoError(t, err)
+	assert.True(t, result.Success, result.Errors)
+	assert.Equal(t, uint64(3), result.Confirmations)
+	assert.Equal(t, 3, fetches)
+}
+
+func TestSettlePaymentHandler_MinConfirmationsNotReached(t *testing.T) {
+	txID, _ := valueobject.NewTransactionID("0x1234567890abcdef1234567890abcdef1234567890abcdef1234567890abcdef")
+	sender, _ := valueobject.NewStacksAddress("ST2J6ZY48GV1EZ5V2V5RB9MP66SW86PYKKQYAC0RQ")
+	recipient, _ := valueobject.NewPrincipal("ST1PQHQKV0RJXZFY1DGX8MNSNYVE3VGZJSRTPGZGM")
+
+	mockTx := service.BlockchainTransaction{
+		TxID:            txID,
+		TokenType:       valueobject.TokenSTX,
+		Sender:          sender,
+		Recipient:       recipient,
+		Amount:          valueobject.NewAmount(1000000),
+		BlockHeight:     12345,
+		BurnBlockHeight: 880000,
+		Status:          "success",
+		IsConfirmed:     true,
+	}
+
+	mockBroadcaster := &MockBroadcaster{
+		BroadcastFn: func(ctx context.Context, signedTx string, network valueobject.Network) (valueobject.TransactionID, error) {
+			return txID, nil
+		},
+		WaitForConfirmFn: func(ctx context.Context, txID valueobject.TransactionID, tokenType valueobject.TokenType, network valueobject.Network, maxRetries int, retryDelay time.Duration) (service.BlockchainTransaction, error) {
+			return mockTx, nil
+		},
+	}
+	chainTip := &MockChainTip{Tips: []service.ChainTip{{StacksHeight: 12350, BurnHeight: 880000}}}
+
+	handler := NewSettlePaymentHandler(mockBroadcaster, decoderReturning(mockTx, valueobject.NetworkTestnet), service.NewVerificationService(),
+		WithRetry(2, time.Millisecond), WithChainTip(chainTip))
+
+	result, err := handler.Handle(context.Background(), SettlePaymentCommand{
+		SignedTransaction:    "0x00000001deadbeef",
+		TokenType:            "STX",
+		ExpectedRecipient:    "ST1PQHQKV0RJXZFY1DGX8MNSNYVE3VGZJSRTPGZGM",
+		MinAmount:            "500000",
+		Network:              "testnet",
+		MinBurnConfirmations: 2,
+	})
+
+	require.NoError(t, err)
+	assert.False(t, result.Success)
+	assert.Equal(t, "confirmed", result.Status)
+	assert.Equal(t, uint64(6), result.Confirmations)
+	assert.Equal(t, uint64(1), result.BurnConfirmations)
+	assert.Equal(t, []string{"insufficient confirmations: expected at least 2 burn blocks, got 1"}, result.Errors)
+}
+
+func TestSettlePaymentHandler_DroppedTransaction(t *testing.T) {
+	txID, _ := valueobject.NewTransactionID("0x1234567890abcdef1234567890abcdef1234567890abcdef1234567890abcdef")
+	replacement, _ := valueobject.NewTransactionID("0xabababababababababababababababababababababababababababababababab")
+	sender, _ := valueobject.NewStacksAddress("ST2J6ZY48GV1EZ5V2V5RB9MP66SW86PYKKQYAC0RQ")
+	recipient, _ := valueobject.NewPrincipal("ST1PQHQKV0RJXZFY1DGX8MNSNYVE3VGZJSRTPGZGM")
+
+	decoded := service.BlockchainTransaction{
+		TxID:      txID,
+		TokenType: valueobject.TokenSTX,
+		Sender:    sender,
+		Recipient: recipient,
+		Amount:    valueobject.NewAmount(1000000),
+		Status:    valueobject.TxStatusPending,
+	}
+	dropped := decoded
+	dropped.Status = valueobject.TxStatusDroppedReplaceByFee
+	dropped.ReplacedBy = replacement
+
+	waits := 0
+	mockBroadcaster := &MockBroadcaster{
+		BroadcastFn: func(ctx context.Context, signedTx string, network valueobject.Network) (valueobject.TransactionID, error) {
+			return txID, nil
+		},
+		WaitForConfirmFn: func(ctx context.Context, txID valueobject.TransactionID, tokenType valueobject.TokenType, network valueobject.Network, maxRetries int, retryDelay time.Duration) (service.BlockchainTransaction, error) {
+			waits++
+			return dropped, nil
+		},
+	}
+
+	handler := NewSettlePaymentHandler(mockBroadcaster, decoderReturning(decoded, valueobject.NetworkTestnet), service.NewVerificationService(),
+		WithRetry(5, time.Millisecond), WithMinConfirmations(3, 0))
+
+	result, err := handler.Handle(context.Background(), SettlePaymentCommand{
+		SignedTransaction: "0x00000001deadbeef",
+		TokenType:         "STX",
+		ExpectedRecipient: "ST1PQHQKV0RJXZFY1DGX8MNSNYVE3VGZJSRTPGZGM",
+		MinAmount:         "500000",
+		Network:           "testnet",
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, 1, waits)
+	assert.False(t, result.Success)
+	assert.Equal(t, "dropped", result.Status)
+	assert.Equal(t, replacement.String(), result.ReplacedByTxID)
+	assert.Contains(t, result.Errors, "transaction dropped with status: dropped_replace_by_fee, replaced by "+replacement.String())
 }
 
 func TestSettlePaymentHandler_BroadcastError(t *testing.T) {
+	sender, _ := valueobject.NewStacksAddress("ST2J6ZY48GV1EZ5V2V5RB9MP66SW86PYKKQYAC0RQ")
+	recipient, _ := valueobject.NewPrincipal("ST1PQHQKV0RJXZFY1DGX8MNSNYVE3VGZJSRTPGZGM")
+	decoded := service.BlockchainTransaction{
+		TokenType: valueobject.TokenSTX,
+		Sender:    sender,
+		Recipient: recipient,
+		Amount:    valueobject.NewAmount(1000000),
+		Status:    "pending",
+	}
+
 	mockBroadcaster := &MockBroadcaster{
 		BroadcastFn: func(ctx context.Context, signedTx string, network valueobject.Network) (valueobject.TransactionID, error) {
 			return valueobject.TransactionID{}, errors.New("broadcast failed")
@@ -80,13 +261,13 @@ func TestSettlePaymentHandler_BroadcastError(t *testing.T) {
 	}
 
 	verificationSvc := service.NewVerificationService()
-	handler := NewSettlePaymentHandler(mockBroadcaster, verificationSvc)
+	handler := NewSettlePaymentHandler(mockBroadcaster, decoderReturning(decoded, valueobject.NetworkTestnet), verificationSvc)
 
 	cmd := SettlePaymentCommand{
 		SignedTransaction: "0x00000001deadbeef",
 		TokenType:         "STX",
 		ExpectedRecipient: "ST1PQHQKV0RJXZFY1DGX8MNSNYVE3VGZJSRTPGZGM",
-		MinAmount:         500000,
+		MinAmount:         "500000",
 		Network:           "testnet",
 	}
 
@@ -98,8 +279,18 @@ func TestSettlePaymentHandler_BroadcastError(t *testing.T) {
 
 func TestSettlePaymentHandler_VerificationFailed(t *testing.T) {
 	txID, _ := valueobject.NewTransactionID("0x1234567890abcdef1234567890abcdef1234567890abcdef1234567890abcdef")
-	sender, _ := valueobject.NewStacksAddress("ST2J6ZY48GV1EZ5V2V5RB9MP66SW86PYKKNRV9EJ7")
-	wrongRecipient, _ := valueobject.NewStacksAddress("ST9J6ZY48GV1EZ5V2V5RB9MP66SW86PYKKNRV9EJ9")
+	sender, _ := valueobject.NewStacksAddress("ST2J6ZY48GV1EZ5V2V5RB9MP66SW86PYKKQYAC0RQ")
+	wrongRecipient, _ := valueobject.NewPrincipal("ST3T6ZY48GV1EZ5V2V5RB9MP66SW86PYKKMKH9H62")
+
+	recipient, _ := valueobject.NewPrincipal("ST1PQHQKV0RJXZFY1DGX8MNSNYVE3VGZJSRTPGZGM")
+
+	decoded := service.BlockchainTransaction{
+		TokenType: valueobject.TokenSTX,
+		Sender:    sender,
+		Recipient: recipient,
+		Amount:    valueobject.NewAmount(1000000),
+		Status:    "pending",
+	}
 
 	mockTx := service.BlockchainTransaction{
 		TxID:        txID,
@@ -123,13 +314,13 @@ func TestSettlePaymentHandler_VerificationFailed(t *testing.T) {
 	}
 
 	verificationSvc := service.NewVerificationService()
-	handler := NewSettlePaymentHandler(mockBroadcaster, verificationSvc)
+	handler := NewSettlePaymentHandler(mockBroadcaster, decoderReturning(decoded, valueobject.NetworkTestnet), verificationSvc)
 
 	cmd := SettlePaymentCommand{
 		SignedTransaction: "0x00000001deadbeef",
 		TokenType:         "STX",
 		ExpectedRecipient: "ST1PQHQKV0RJXZFY1DGX8MNSNYVE3VGZJSRTPGZGM",
-		MinAmount:         500000,
+		MinAmount:         "500000",
 		Network:           "testnet",
 	}
 
@@ -143,13 +334,13 @@ func TestSettlePaymentHandler_VerificationFailed(t *testing.T) {
 func TestSettlePaymentHandler_InvalidNetwork(t *testing.T) {
 	mockBroadcaster := &MockBroadcaster{}
 	verificationSvc := service.NewVerificationService()
-	handler := NewSettlePaymentHandler(mockBroadcaster, verificationSvc)
+	handler := NewSettlePaymentHandler(mockBroadcaster, &MockDecoder{}, verificationSvc)
 
 	cmd := SettlePaymentCommand{
 		SignedTransaction: "0x00000001deadbeef",
 		TokenType:         "STX",
 		ExpectedRecipient: "ST1PQHQKV0RJXZFY1DGX8MNSNYVE3VGZJSRTPGZGM",
-		MinAmount:         500000,
+		MinAmount:         "500000",
 		Network:           "invalid",
 	}
 
@@ -158,16 +349,36 @@ func TestSettlePaymentHandler_InvalidNetwork(t *testing.T) {
 	assert.Error(t, err)
 }
 
+func TestSettlePaymentHandler_UnknownTokenType(t *testing.T) {
+	mockBroadcaster := &MockBroadcaster{}
+	verificationSvc := service.NewVerificationService()
+	handler := NewSettlePaymentHandler(mockBroadcaster, &MockDecoder{}, verificationSvc, WithTokenRegistry(service.DefaultTokenRegistry()))
+
+	for _, tokenType := range []string{"not a token", "DOGE"} {
+		cmd := SettlePaymentCommand{
+			SignedTransaction: "0x00000001deadbeef",
+			TokenType:         tokenType,
+			ExpectedRecipient: "ST1PQHQKV0RJXZFY1DGX8MNSNYVE3VGZJSRTPGZGM",
+			MinAmount:         "500000",
+			Network:           "testnet",
+		}
+
+		_, err := handler.Handle(context.Background(), cmd)
+
+		assert.ErrorIs(t, err, ErrUnsupportedToken, tokenType)
+	}
+}
+
 func TestSettlePaymentHandler_InvalidRecipient(t *testing.T) {
 	mockBroadcaster := &MockBroadcaster{}
 	verificationSvc := service.NewVerificationService()
-	handler := NewSettlePaymentHandler(mockBroadcaster, verificationSvc)
+	handler := NewSettlePaymentHandler(mockBroadcaster, &MockDecoder{}, verificationSvc)
 
 	cmd := SettlePaymentCommand{
 		SignedTransaction: "0x00000001deadbeef",
 		TokenType:         "STX",
 		ExpectedRecipient: "invalid",
-		MinAmount:         500000,
+		MinAmount:         "500000",
 		Network:           "testnet",
 	}
 
@@ -175,3 +386,808 @@ func TestSettlePaymentHandler_InvalidRecipient(t *testing.T) {
 
 	assert.Error(t, err)
 }
+
+func TestSettlePaymentHandler_MinAmountInWholeTokens(t *testing.T) {
+	sender, _ := valueobject.NewStacksAddress("ST2J6ZY48GV1EZ5V2V5RB9MP66SW86PYKKQYAC0RQ")
+	recipient, _ := valueobject.NewPrincipal("ST1PQHQKV0RJXZFY1DGX8MNSNYVE3VGZJSRTPGZGM")
+	decoded := service.BlockchainTransaction{
+		TokenType: valueobject.TokenSTX,
+		Sender:    sender,
+		Recipient: recipient,
+		Amount:    valueobject.NewAmount(1000000),
+		Status:    "pending",
+	}
+
+	handler := NewSettlePaymentHandler(rejectingBroadcaster(t), decoderReturning(decoded, valueobject.NetworkTestnet), service.NewVerificationService())
+
+	result, err := handler.Handle(context.Background(), SettlePaymentCommand{
+		SignedTransaction: "0x00000001deadbeef",
+		TokenType:         "STX",
+		ExpectedRecipient: "ST1PQHQKV0RJXZFY1DGX8MNSNYVE3VGZJSRTPGZGM",
+		MinAmount:         "1.5",
+		AmountUnit:        AmountUnitToken,
+		Network:           "testnet",
+	})
+
+	require.NoError(t, err)
+	assert.False(t, result.Success)
+	assert.Equal(t, "1 STX", result.DisplayAmount)
+	require.Len(t, result.Errors, 1)
+	assert.Equal(t, "insufficient amount: expected at least 1500000, got 1000000", result.Errors[0])
+}
+
+// rejectingBroadcaster fails the test if anything is broadcast
+func rejectingBroadcaster(t *testing.T) *MockBroadcaster {
+	return &MockBroadcaster{
+		BroadcastFn: func(ctx context.Context, signedTx string, network valueobject.Network) (valueobject.TransactionID, error) {
+			t.Fatal("transaction should not be broadcast")
+			return valueobject.TransactionID{}, nil
+		},
+	}
+}
+
+func TestSettlePaymentHandler_RejectsBeforeBroadcast(t *testing.T) {
+	sender, _ := valueobject.NewStacksAddress("ST2J6ZY48GV1EZ5V2V5RB9MP66SW86PYKKQYAC0RQ")
+	recipient, _ := valueobject.NewPrincipal("ST1PQHQKV0RJXZFY1DGX8MNSNYVE3VGZJSRTPGZGM")
+	wrongRecipient, _ := valueobject.NewPrincipal("ST3T6ZY48GV1EZ5V2V5RB9MP66SW86PYKKMKH9H62")
+
+	stxTransfer := service.BlockchainTransaction{
+		TokenType: valueobject.TokenSTX,
+		Sender:    sender,
+		Recipient: recipient,
+		Amount:    valueobject.NewAmount(1000000),
+		Status:    "pending",
+	}
+
+	tests := []struct {
+		name      string
+		tokenType string
+		decoded   func() service.BlockchainTransaction
+		network   valueobject.Network
+		wantError string
+	}{
+		{
+			name:      "underpayment",
+			tokenType: "STX",
+			decoded: func() service.BlockchainTransaction {
+				tx := stxTransfer
+				tx.Amount = valueobject.NewAmount(100)
+				return tx
+			},
+			network:   valueobject.NetworkTestnet,
+			wantError: "insufficient amount",
+		},
+		{
+			name:      "wrong recipient",
+			tokenType: "STX",
+			decoded: func() service.BlockchainTransaction {
+				tx := stxTransfer
+				tx.Recipient = wrongRecipient
+				return tx
+			},
+			network:   valueobject.NetworkTestnet,
+			wantError: "recipient mismatch",
+		},
+		{
+			name:      "wrong network",
+			tokenType: "STX",
+			decoded:   func() service.BlockchainTransaction { return stxTransfer },
+			network:   valueobject.NetworkMainnet,
+			wantError: "network mismatch: expected testnet, got mainnet",
+		},
+		{
+			name:      "wrong token",
+			tokenType: "sBTC",
+			decoded:   func() service.BlockchainTransaction { return stxTransfer },
+			network:   valueobject.NetworkTestnet,
+			wantError: "token mismatch",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := NewSettlePaymentHandler(rejectingBroadcaster(t), decoderReturning(tt.decoded(), tt.network), service.NewVerificationService())
+
+			cmd := SettlePaymentCommand{
+				SignedTransaction: "0x00000001deadbeef",
+				TokenType:         tt.tokenType,
+				ExpectedRecipient: "ST1PQHQKV0RJXZFY1DGX8MNSNYVE3VGZJSRTPGZGM",
+				MinAmount:         "500000",
+				Network:           "testnet",
+			}
+
+			result, err := handler.Handle(context.Background(), cmd)
+
+			require.NoError(t, err)
+			assert.False(t, result.Success)
+			assert.Equal(t, "failed", result.Status)
+			require.NotEmpty(t, result.Errors)
+			assert.Contains(t, result.Errors[0], tt.wantError)
+		})
+	}
+}
+
+func TestSettlePaymentHandler_UndecodableTransaction(t *testing.T) {
+	decoder := &MockDecoder{
+		DecodeFn: func(signedTx string, tokenType valueobject.TokenType) (service.BlockchainTransaction, valueobject.Network, error) {
+			return service.BlockchainTransaction{}, "", errors.New("malformed transaction: unexpected end of input")
+		},
+	}
+	handler := NewSettlePaymentHandler(rejectingBroadcaster(t), decoder, service.NewVerificationService())
+
+	cmd := SettlePaymentCommand{
+		SignedTransaction: "0x00000001deadbeef",
+		TokenType:         "STX",
+		ExpectedRecipient: "ST1PQHQKV0RJXZFY1DGX8MNSNYVE3VGZJSRTPGZGM",
+		MinAmount:         "500000",
+		Network:           "testnet",
+	}
+
+	_, err := handler.Handle(context.Background(), cmd)
+
+	require.Error(t, err)
+	assert.ErrorIs(t, err, ErrInvalidSignedTransaction)
+}
+
+func TestSettlePaymentHandler_ChecksRecoveredSigner(t *testing.T) {
+	sender, _ := valueobject.NewStacksAddress("ST2J6ZY48GV1EZ5V2V5RB9MP66SW86PYKKQYAC0RQ")
+	signer, _ := valueobject.NewStacksAddress("ST3T6ZY48GV1EZ5V2V5RB9MP66SW86PYKKMKH9H62")
+	recipient, _ := valueobject.NewPrincipal("ST1PQHQKV0RJXZFY1DGX8MNSNYVE3VGZJSRTPGZGM")
+	decoded := service.BlockchainTransaction{
+		TokenType: valueobject.TokenSTX,
+		Sender:    sender,
+		Recipient: recipient,
+		Amount:    valueobject.NewAmount(1000000),
+		Status:    "pending",
+	}
+
+	tests := []struct {
+		name      string
+		recover   func(signedTx string) (valueobject.StacksAddress, error)
+		wantError string
+	}{
+		{
+			name: "bad signature",
+			recover: func(signedTx string) (valueobject.StacksAddress, error) {
+				return valueobject.StacksAddress{}, errors.New("invalid signature: signature does not match signer")
+			},
+			wantError: "invalid signature",
+		},
+		{
+			name: "signed by someone other than the expected sender",
+			recover: func(signedTx string) (valueobject.StacksAddress, error) {
+				return signer, nil
+			},
+			wantError: "sender mismatch",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			decoder := decoderReturning(decoded, valueobject.NetworkTestnet)
+			decoder.RecoverFn = tt.recover
+			handler := NewSettlePaymentHandler(rejectingBroadcaster(t), decoder, service.NewVerificationService())
+
+			expectedSender := sender.String()
+			cmd := SettlePaymentCommand{
+				SignedTransaction: "0x00000001deadbeef",
+				TokenType:         "STX",
+				ExpectedRecipient: "ST1PQHQKV0RJXZFY1DGX8MNSNYVE3VGZJSRTPGZGM",
+				ExpectedSender:    &expectedSender,
+				MinAmount:         "500000",
+				Network:           "testnet",
+			}
+
+			result, err := handler.Handle(context.Background(), cmd)
+
+			require.NoError(t, err)
+			assert.False(t, result.Success)
+			require.NotEmpty(t, result.Errors)
+			assert.Contains(t, result.Errors[0], tt.wantError)
+		})
+	}
+}
+
+// MockSponsor is a mock implementation for testing
+type MockSponsor struct {
+	SponsorFn func(ctx context.Context, signedTx string, network valueobject.Network, maxFee uint64) (SponsoredTransaction, error)
+	ReleaseFn func(sponsored SponsoredTransaction, broadcastErr error) bool
+}
+
+func (m *MockSponsor) SponsorTransaction(ctx context.Context, signedTx string, network valueobject.Network, maxFee uint64) (SponsoredTransaction, error) {
+	return m.SponsorFn(ctx, signedTx, network, maxFee)
+}
+
+func (m *MockSponsor) Release(sponsored SponsoredTransaction, broadcastErr error) bool {
+	if m.ReleaseFn == nil {
+		return false
+	}
+	return m.ReleaseFn(sponsored, broadcastErr)
+}
+
+func TestSettlePaymentHandler_SponsoredTransaction(t *testing.T) {
+	txID, _ := valueobject.NewTransactionID("0x1234567890abcdef1234567890abcdef1234567890abcdef1234567890abcdef")
+	sender, _ := valueobject.NewStacksAddress("ST2J6ZY48GV1EZ5V2V5RB9MP66SW86PYKKQYAC0RQ")
+	recipient, _ := valueobject.NewPrincipal("ST1PQHQKV0RJXZFY1DGX8MNSNYVE3VGZJSRTPGZGM")
+
+	decoded := service.BlockchainTransaction{
+		TokenType: valueobject.TokenSTX,
+		Sender:    sender,
+		Recipient: recipient,
+		Amount:    valueobject.NewAmount(1000000),
+		Status:    "pending",
+		Sponsored: true,
+	}
+	confirmed := decoded
+	confirmed.TxID = txID
+	confirmed.Fee = valueobject.NewAmount(400)
+	confirmed.BlockHeight = 12345
+	confirmed.Status = "success"
+	confirmed.IsConfirmed = true
+
+	var broadcastTx string
+	broadcaster := &MockBroadcaster{
+		BroadcastFn: func(ctx context.Context, signedTx string, network valueobject.Network) (valueobject.TransactionID, error) {
+			broadcastTx = signedTx
+			return txID, nil
+		},
+		WaitForConfirmFn: func(ctx context.Context, txID valueobject.TransactionID, tokenType valueobject.TokenType, network valueobject.Network, maxRetries int, retryDelay time.Duration) (service.BlockchainTransaction, error) {
+			return confirmed, nil
+		},
+	}
+	sponsor := &MockSponsor{
+		SponsorFn: func(ctx context.Context, signedTx string, network valueobject.Network, maxFee uint64) (SponsoredTransaction, error) {
+			assert.Equal(t, "0xpayer", signedTx)
+			assert.Equal(t, valueobject.NetworkTestnet, network)
+			assert.Equal(t, uint64(5000), maxFee)
+			return SponsoredTransaction{SignedTransaction: "0xsponsored", Nonce: 3}, nil
+		},
+		ReleaseFn: func(sponsored SponsoredTransaction, broadcastErr error) bool {
+			assert.Equal(t, uint64(3), sponsored.Nonce)
+			assert.NoError(t, broadcastErr)
+			return false
+		},
+	}
+
+	handler := NewSettlePaymentHandler(broadcaster, decoderReturning(decoded, valueobject.NetworkTestnet), service.NewVerificationService(),
+		WithSponsor(sponsor, service.SponsorPolicy{MaxFee: 5000}))
+
+	cmd := SettlePaymentCommand{
+		SignedTransaction: "0xpayer",
+		TokenType:         "STX",
+		ExpectedRecipient: "ST1PQHQKV0RJXZFY1DGX8MNSNYVE3VGZJSRTPGZGM",
+		MinAmount:         "500000",
+		Network:           "testnet",
+	}
+
+	result, err := handler.Handle(context.Background(), cmd)
+
+	require.NoError(t, err)
+	assert.True(t, result.Success)
+	assert.Equal(t, "0xsponsored", broadcastTx)
+	assert.Equal(t, uint64(400), result.Fee)
+}
+
+func TestSettlePaymentHandler_SponsoredTransactionSettledTwice(t *testing.T) {
+	payerTxID, _ := valueobject.NewTransactionID("0xaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa")
+	sponsoredTxID, _ := valueobject.NewTransactionID("0xbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbb")
+	sender, _ := valueobject.NewStacksAddress("ST2J6ZY48GV1EZ5V2V5RB9MP66SW86PYKKQYAC0RQ")
+	recipient, _ := valueobject.NewPrincipal("ST1PQHQKV0RJXZFY1DGX8MNSNYVE3VGZJSRTPGZGM")
+
+	decoded := service.BlockchainTransaction{
+		TxID:      payerTxID, // The payload as the payer signed it
+		TokenType: valueobject.TokenSTX,
+		Sender:    sender,
+		Recipient: recipient,
+		Amount:    valueobject.NewAmount(1000000),
+		Status:    "pending",
+		Sponsored: true,
+	}
+	confirmed := decoded
+	confirmed.TxID = sponsoredTxID
+	confirmed.Status = "success"
+	confirmed.IsConfirmed = true
+
+	var sponsorings, broadcasts int
+	var polled []valueobject.TransactionID
+	broadcaster := &MockBroadcaster{
+		BroadcastFn: func(ctx context.Context, signedTx string, network valueobject.Network) (valueobject.TransactionID, error) {
+			broadcasts++
+			return sponsoredTxID, nil
+		},
+		WaitForConfirmFn: func(ctx context.Context, txID valueobject.TransactionID, tokenType valueobject.TokenType, network valueobject.Network, maxRetries int, retryDelay time.Duration) (service.BlockchainTransaction, error) {
+			polled = append(polled, txID)
+			return confirmed, nil
+		},
+	}
+	sponsor := &MockSponsor{
+		SponsorFn: func(ctx context.Context, signedTx string, network valueobject.Network, maxFee uint64) (SponsoredTransaction, error) {
+			sponsorings++
+			return SponsoredTransaction{SignedTransaction: "0xsponsored", Nonce: uint64(sponsorings)}, nil
+		},
+	}
+	store := &memoryPaymentStore{payments: map[string]ConsumedPayment{}}
+	handler := NewSettlePaymentHandler(broadcaster, decoderReturning(decoded, valueobject.NetworkTestnet), service.NewVerificationService(),
+		WithSponsor(sponsor, service.SponsorPolicy{MaxFee: 5000}), WithPaymentStore(store))
+
+	cmd := SettlePaymentCommand{
+		SignedTransaction: "0xpayer",
+		TokenType:         "STX",
+		ExpectedRecipient: "ST1PQHQKV0RJXZFY1DGX8MNSNYVE3VGZJSRTPGZGM",
+		MinAmount:         "500000",
+		Network:           "testnet",
+		Resource:          "/premium",
+		Nonce:             "abc",
+	}
+
+	first, err := handler.Handle(context.Background(), cmd)
+	require.NoError(t, err)
+	assert.True(t, first.Success)
+	assert.Equal(t, sponsoredTxID.String(), first.TxID)
+	assert.Equal(t, sponsoredTxID.String(), store.payments[payerTxID.String()].BroadcastTxID)
+	assert.Contains(t, store.payments, sponsoredTxID.String(), "the sponsored txid cannot be verified again either")
+
+	// The same settlement resumes from its record instead of sponsoring a second transaction
+	second, err := handler.Handle(context.Background(), cmd)
+	require.NoError(t, err)
+	assert.True(t, second.Success)
+	assert.Equal(t, sponsoredTxID.String(), second.TxID)
+	assert.Equal(t, 1, sponsorings)
+	assert.Equal(t, 1, broadcasts)
+	assert.Equal(t, []valueobject.TransactionID{sponsoredTxID, sponsoredTxID}, polled)
+
+	// Any other settlement of the payload is a replay
+	cmd.Nonce = "def"
+	_, err = handler.Handle(context.Background(), cmd)
+	assert.ErrorIs(t, err, ErrPaymentAlreadyUsed)
+	assert.Equal(t, 1, broadcasts)
+}
+
+func TestSettlePaymentHandler_ReplayedTransaction(t *testing.T) {
+	txID, _ := valueobject.NewTransactionID("0x1234567890abcdef1234567890abcdef1234567890abcdef1234567890abcdef")
+	mockTx := createMockTransaction()
+	mockTx.TxID = txID
+
+	broadcasts := 0
+	broadcaster := &MockBroadcaster{
+		BroadcastFn: func(ctx context.Context, signedTx string, network valueobject.Network) (valueobject.TransactionID, error) {
+			broadcasts++
+			return txID, nil
+		},
+		WaitForConfirmFn: func(ctx context.Context, txID valueobject.TransactionID, tokenType valueobject.TokenType, network valueobject.Network, maxRetries int, retryDelay time.Duration) (service.BlockchainTransaction, error) {
+			return mockTx, nil
+		},
+	}
+
+	cmd := SettlePaymentCommand{
+		SignedTransaction: "0x00000001deadbeef",
+		TokenType:         "STX",
+		ExpectedRecipient: "ST1PQHQKV0RJXZFY1DGX8MNSNYVE3VGZJSRTPGZGM",
+		MinAmount:         "500000",
+		Network:           "testnet",
+	}
+
+	tests := []struct {
+		name     string
+		recorded ConsumedPayment
+	}{
+		{"accepted by verify", ConsumedPayment{TxID: txID.String(), Resource: "/premium"}},
+		{"settled for another resource", ConsumedPayment{TxID: txID.String(), BroadcastTxID: txID.String(), Resource: "/other"}},
+		{"settled without resource or nonce", ConsumedPayment{TxID: txID.String(), BroadcastTxID: txID.String()}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := &memoryPaymentStore{payments: map[string]ConsumedPayment{txID.String(): tt.recorded}}
+			handler := NewSettlePaymentHandler(broadcaster, decoderReturning(mockTx, valueobject.NetworkTestnet), service.NewVerificationService(),
+				WithPaymentStore(store))
+
+			cmd := cmd
+			cmd.Resource = "/premium"
+			if tt.recorded.Resource == "" {
+				cmd.Resource = ""
+			}
+			_, err := handler.Handle(context.Background(), cmd)
+
+			assert.ErrorIs(t, err, ErrPaymentAlreadyUsed)
+			assert.Zero(t, broadcasts)
+		})
+	}
+}
+
+func TestSettlePaymentHandler_ConcurrentSettlesBroadcastOnce(t *testing.T) {
+	txID, _ := valueobject.NewTransactionID("0x1234567890abcdef1234567890abcdef1234567890abcdef1234567890abcdef")
+	mockTx := createMockTransaction()
+	mockTx.TxID = txID
+
+	var broadcasts atomic.Int32
+	proceed := make(chan struct{})
+	broadcaster := &MockBroadcaster{
+		BroadcastFn: func(ctx context.Context, signedTx string, network valueobject.Network) (valueobject.TransactionID, error) {
+			broadcasts.Add(1)
+			<-proceed
+			return txID, nil
+		},
+		WaitForConfirmFn: func(ctx context.Context, txID valueobject.TransactionID, tokenType valueobject.TokenType, network valueobject.Network, maxRetries int, retryDelay time.Duration) (service.BlockchainTransaction, error) {
+			return mockTx, nil
+		},
+	}
+	store := &memoryPaymentStore{payments: map[string]ConsumedPayment{}}
+	handler := NewSettlePaymentHandler(broadcaster, decoderReturning(mockTx, valueobject.NetworkTestnet), service.NewVerificationService(),
+		WithPaymentStore(store))
+
+	cmd := SettlePaymentCommand{
+		SignedTransaction: "0x00000001deadbeef",
+		TokenType:         "STX",
+		ExpectedRecipient: "ST1PQHQKV0RJXZFY1DGX8MNSNYVE3VGZJSRTPGZGM",
+		MinAmount:         "500000",
+		Network:           "testnet",
+		Resource:          "/premium",
+		Nonce:             "abc",
+	}
+
+	const settles = 8
+	errs := make(chan error, settles)
+	for i := 0; i < settles; i++ {
+		go func() {
+			_, err := handler.Handle(context.Background(), cmd)
+			errs <- err
+		}()
+	}
+
+	// Every settlement but the one holding the claim is refused without broadcasting
+	for i := 0; i < settles-1; i++ {
+		select {
+		case err := <-errs:
+			assert.ErrorIs(t, err, ErrPaymentAlreadyUsed)
+		case <-time.After(5 * time.Second):
+			t.Fatal("more than one settlement reached broadcast")
+		}
+	}
+	close(proceed)
+	assert.NoError(t, <-errs)
+	assert.Equal(t, int32(1), broadcasts.Load())
+	assert.Equal(t, txID.String(), store.payments[txID.String()].BroadcastTxID)
+}
+
+func TestSettlePaymentHandler_ReleasesClaimWhenNotBroadcast(t *testing.T) {
+	txID, _ := valueobject.NewTransactionID("0x1234567890abcdef1234567890abcdef1234567890abcdef1234567890abcdef")
+	mockTx := createMockTransaction()
+	mockTx.TxID = txID
+
+	tests := []struct {
+		name      string
+		err       error
+		wantClaim bool
+	}{
+		{"rejected by the node", &BroadcastRejectedError{Reason: RejectionNotEnoughFunds, Message: "insufficient balance"}, false},
+		{"node unreachable", errors.New("connection refused"), true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			broadcaster := &MockBroadcaster{
+				BroadcastFn: func(ctx context.Context, signedTx string, network valueobject.Network) (valueobject.TransactionID, error) {
+					return valueobject.TransactionID{}, tt.err
+				},
+			}
+			store := &memoryPaymentStore{payments: map[string]ConsumedPayment{}}
+			handler := NewSettlePaymentHandler(broadcaster, decoderReturning(mockTx, valueobject.NetworkTestnet), service.NewVerificationService(),
+				WithPaymentStore(store))
+
+			_, err := handler.Handle(context.Background(), SettlePaymentCommand{
+				SignedTransaction: "0x00000001deadbeef",
+				TokenType:         "STX",
+				ExpectedRecipient: "ST1PQHQKV0RJXZFY1DGX8MNSNYVE3VGZJSRTPGZGM",
+				MinAmount:         "500000",
+				Network:           "testnet",
+				Resource:          "/premium",
+			})
+
+			assert.ErrorIs(t, err, tt.err)
+			claim, ok := store.payments[txID.String()]
+			assert.Equal(t, tt.wantClaim, ok)
+			assert.Empty(t, claim.BroadcastTxID)
+		})
+	}
+}
+
+func TestSettlePaymentHandler_SponsoredRejected(t *testing.T) {
+	sender, _ := valueobject.NewStacksAddress("ST2J6ZY48GV1EZ5V2V5RB9MP66SW86PYKKQYAC0RQ")
+	recipient, _ := valueobject.NewPrincipal("ST1PQHQKV0RJXZFY1DGX8MNSNYVE3VGZJSRTPGZGM")
+
+	decoded := service.BlockchainTransaction{
+		TokenType: valueobject.TokenSTX,
+		Sender:    sender,
+		Recipient: recipient,
+		Amount:    valueobject.NewAmount(1000000),
+		Status:    "pending",
+		Sponsored: true,
+	}
+
+	unusedSponsor := &MockSponsor{
+		SponsorFn: func(ctx context.Context, signedTx string, network valueobject.Network, maxFee uint64) (SponsoredTransaction, error) {
+			t.Fatal("transaction should not be sponsored")
+			return SponsoredTransaction{}, nil
+		},
+	}
+
+	tests := []struct {
+		name      string
+		opts      []Option
+		wantError string
+	}{
+		{
+			name:      "no sponsor configured",
+			wantError: "sponsor policy: sponsored transactions are not accepted",
+		},
+		{
+			name:      "token not allowed",
+			opts:      []Option{WithSponsor(unusedSponsor, service.SponsorPolicy{AllowedTokens: []valueobject.TokenType{valueobject.TokenSBTC}})},
+			wantError: "sponsor policy: token STX is not sponsored",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := NewSettlePaymentHandler(rejectingBroadcaster(t), decoderReturning(decoded, valueobject.NetworkTestnet), service.NewVerificationService(), tt.opts...)
+
+			cmd := SettlePaymentCommand{
+				SignedTransaction: "0x00000001deadbeef",
+				TokenType:         "STX",
+				ExpectedRecipient: "ST1PQHQKV0RJXZFY1DGX8MNSNYVE3VGZJSRTPGZGM",
+				MinAmount:         "500000",
+				Network:           "testnet",
+			}
+
+			result, err := handler.Handle(context.Background(), cmd)
+
+			require.NoError(t, err)
+			assert.False(t, result.Success)
+			assert.Equal(t, "failed", result.Status)
+			assert.Equal(t, []string{tt.wantError}, result.Errors)
+			require.Len(t, result.Failures, 1)
+			assert.Equal(t, service.FailureSponsorPolicy, result.Failures[0].Code)
+		})
+	}
+}
+
+func TestSettlePaymentHandler_SponsorError(t *testing.T) {
+	sender, _ := valueobject.NewStacksAddress("ST2J6ZY48GV1EZ5V2V5RB9MP66SW86PYKKQYAC0RQ")
+	recipient, _ := valueobject.NewPrincipal("ST1PQHQKV0RJXZFY1DGX8MNSNYVE3VGZJSRTPGZGM")
+
+	decoded := service.BlockchainTransaction{
+		TokenType: valueobject.TokenSTX,
+		Sender:    sender,
+		Recipient: recipient,
+		Amount:    valueobject.NewAmount(1000000),
+		Status:    "pending",
+		Sponsored: true,
+	}
+	sponsor := &MockSponsor{
+		SponsorFn: func(ctx context.Context, signedTx string, network valueobject.Network, maxFee uint64) (SponsoredTransaction, error) {
+			return SponsoredTransaction{}, errors.New("sponsor balance 10 is below fee 400")
+		},
+	}
+
+	handler := NewSettlePaymentHandler(rejectingBroadcaster(t), decoderReturning(decoded, valueobject.NetworkTestnet), service.NewVerificationService(),
+		WithSponsor(sponsor, service.SponsorPolicy{}))
+
+	cmd := SettlePaymentCommand{
+		SignedTransaction: "0x00000001deadbeef",
+		TokenType:         "STX",
+		ExpectedRecipient: "ST1PQHQKV0RJXZFY1DGX8MNSNYVE3VGZJSRTPGZGM",
+		MinAmount:         "500000",
+		Network:           "testnet",
+	}
+
+	_, err := handler.Handle(context.Background(), cmd)
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to sponsor transaction")
+}
+
+func TestSettlePaymentHandler_SponsorFeeAboveMax(t *testing.T) {
+	txID, _ := valueobject.NewTransactionID("0x1234567890abcdef1234567890abcdef1234567890abcdef1234567890abcdef")
+	sender, _ := valueobject.NewStacksAddress("ST2J6ZY48GV1EZ5V2V5RB9MP66SW86PYKKQYAC0RQ")
+	recipient, _ := valueobject.NewPrincipal("ST1PQHQKV0RJXZFY1DGX8MNSNYVE3VGZJSRTPGZGM")
+
+	decoded := service.BlockchainTransaction{
+		TxID:      txID,
+		TokenType: valueobject.TokenSTX,
+		Sender:    sender,
+		Recipient: recipient,
+		Amount:    valueobject.NewAmount(1000000),
+		Status:    "pending",
+		Sponsored: true,
+	}
+	sponsor := &MockSponsor{
+		SponsorFn: func(ctx context.Context, signedTx string, network valueobject.Network, maxFee uint64) (SponsoredTransaction, error) {
+			return SponsoredTransaction{}, &SponsorFeeError{Fee: 28300, MaxFee: maxFee}
+		},
+	}
+	store := &memoryPaymentStore{payments: map[string]ConsumedPayment{}}
+	handler := NewSettlePaymentHandler(rejectingBroadcaster(t), decoderReturning(decoded, valueobject.NetworkTestnet), service.NewVerificationService(),
+		WithSponsor(sponsor, service.SponsorPolicy{MaxFee: 5000}), WithPaymentStore(store))
+
+	result, err := handler.Handle(context.Background(), SettlePaymentCommand{
+		SignedTransaction: "0x00000001deadbeef",
+		TokenType:         "STX",
+		ExpectedRecipient: "ST1PQHQKV0RJXZFY1DGX8MNSNYVE3VGZJSRTPGZGM",
+		MinAmount:         "500000",
+		Network:           "testnet",
+	})
+
+	require.NoError(t, err)
+	assert.False(t, result.Success)
+	assert.Equal(t, "failed", result.Status)
+	require.Len(t, result.Failures, 1)
+	assert.Equal(t, service.FailureSponsorPolicy, result.Failures[0].Code)
+	assert.Equal(t, "28300", result.Failures[0].Actual)
+	assert.Empty(t, store.payments, "the claim is released")
+}
+
+func TestSettlePaymentHandler_SponsorFeeRejected(t *testing.T) {
+	sender, _ := valueobject.NewStacksAddress("ST2J6ZY48GV1EZ5V2V5RB9MP66SW86PYKKQYAC0RQ")
+	recipient, _ := valueobject.NewPrincipal("ST1PQHQKV0RJXZFY1DGX8MNSNYVE3VGZJSRTPGZGM")
+
+	decoded := service.BlockchainTransaction{
+		TokenType: valueobject.TokenSTX,
+		Sender:    sender,
+		Recipient: recipient,
+		Amount:    valueobject.NewAmount(1000000),
+		Status:    "pending",
+		Sponsored: true,
+	}
+
+	tests := []struct {
+		name        string
+		rejection   *BroadcastRejectedError
+		wantSponsor bool
+	}{
+		{"fee too low", &BroadcastRejectedError{Reason: RejectionFeeTooLow, Expected: "500", Actual: "400"}, true},
+		{"sponsor cannot pay the fee", &BroadcastRejectedError{Reason: RejectionNotEnoughFunds, Expected: "400", Actual: "10"}, true},
+		{"payer cannot pay the amount", &BroadcastRejectedError{Reason: RejectionNotEnoughFunds, Expected: "1000000", Actual: "10"}, false},
+		{"post-condition", &BroadcastRejectedError{Reason: RejectionPostConditionFailed}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			broadcaster := &MockBroadcaster{
+				BroadcastFn: func(ctx context.Context, signedTx string, network valueobject.Network) (valueobject.TransactionID, error) {
+					return valueobject.TransactionID{}, tt.rejection
+				},
+			}
+			sponsor := &MockSponsor{
+				SponsorFn: func(ctx context.Context, signedTx string, network valueobject.Network, maxFee uint64) (SponsoredTransaction, error) {
+					return SponsoredTransaction{SignedTransaction: "0xsponsored", Fee: 400}, nil
+				},
+			}
+			handler := NewSettlePaymentHandler(broadcaster, decoderReturning(decoded, valueobject.NetworkTestnet), service.NewVerificationService(),
+				WithSponsor(sponsor, service.SponsorPolicy{}))
+
+			_, err := handler.Handle(context.Background(), SettlePaymentCommand{
+				SignedTransaction: "0x00000001deadbeef",
+				TokenType:         "STX",
+				ExpectedRecipient: "ST1PQHQKV0RJXZFY1DGX8MNSNYVE3VGZJSRTPGZGM",
+				MinAmount:         "500000",
+				Network:           "testnet",
+			})
+
+			assert.ErrorIs(t, err, tt.rejection)
+			assert.Equal(t, tt.wantSponsor, errors.Is(err, ErrSponsorFailed))
+		})
+	}
+}
+
+func TestSettlePaymentHandler_SponsorNonceConflictRetries(t *testing.T) {
+	txID, _ := valueobject.NewTransactionID("0x1234567890abcdef1234567890abcdef1234567890abcdef1234567890abcdef")
+	sender, _ := valueobject.NewStacksAddress("ST2J6ZY48GV1EZ5V2V5RB9MP66SW86PYKKQYAC0RQ")
+	recipient, _ := valueobject.NewPrincipal("ST1PQHQKV0RJXZFY1DGX8MNSNYVE3VGZJSRTPGZGM")
+
+	decoded := service.BlockchainTransaction{
+		TokenType: valueobject.TokenSTX,
+		Sender:    sender,
+		Recipient: recipient,
+		Amount:    valueobject.NewAmount(1000000),
+		Status:    "pending",
+		Sponsored: true,
+	}
+	confirmed := decoded
+	confirmed.TxID = txID
+	confirmed.BlockHeight = 12345
+	confirmed.Status = "success"
+	confirmed.IsConfirmed = true
+
+	conflict := errors.New("broadcast failed: ConflictingNonceInMempool")
+	nonce := uint64(7)
+	var broadcasts []string
+	broadcaster := &MockBroadcaster{
+		BroadcastFn: func(ctx context.Context, signedTx string, network valueobject.Network) (valueobject.TransactionID, error) {
+			broadcasts = append(broadcasts, signedTx)
+			if len(broadcasts) == 1 {
+				return valueobject.TransactionID{}, conflict
+			}
+			return txID, nil
+		},
+		WaitForConfirmFn: func(ctx context.Context, txID valueobject.TransactionID, tokenType valueobject.TokenType, network valueobject.Network, maxRetries int, retryDelay time.Duration) (service.BlockchainTransaction, error) {
+			return confirmed, nil
+		},
+	}
+	var released []uint64
+	sponsor := &MockSponsor{
+		SponsorFn: func(ctx context.Context, signedTx string, network valueobject.Network, maxFee uint64) (SponsoredTransaction, error) {
+			nonce++
+			return SponsoredTransaction{SignedTransaction: fmt.Sprintf("0xsponsored-%d", nonce), Nonce: nonce}, nil
+		},
+		ReleaseFn: func(sponsored SponsoredTransaction, broadcastErr error) bool {
+			released = append(released, sponsored.Nonce)
+			return errors.Is(broadcastErr, conflict)
+		},
+	}
+
+	handler := NewSettlePaymentHandler(broadcaster, decoderReturning(decoded, valueobject.NetworkTestnet), service.NewVerificationService(),
+		WithSponsor(sponsor, service.SponsorPolicy{}))
+
+	cmd := SettlePaymentCommand{
+		SignedTransaction: "0xpayer",
+		TokenType:         "STX",
+		ExpectedRecipient: "ST1PQHQKV0RJXZFY1DGX8MNSNYVE3VGZJSRTPGZGM",
+		MinAmount:         "500000",
+		Network:           "testnet",
+	}
+
+	result, err := handler.Handle(context.Background(), cmd)
+
+	require.NoError(t, err)
+	assert.True(t, result.Success)
+	assert.Equal(t, []string{"0xsponsored-8", "0xsponsored-9"}, broadcasts)
+	assert.Equal(t, []uint64{8, 9}, released)
+}
+
+func TestSettlePaymentHandler_SponsorNonceConflictGivesUp(t *testing.T) {
+	sender, _ := valueobject.NewStacksAddress("ST2J6ZY48GV1EZ5V2V5RB9MP66SW86PYKKQYAC0RQ")
+	recipient, _ := valueobject.NewPrincipal("ST1PQHQKV0RJXZFY1DGX8MNSNYVE3VGZJSRTPGZGM")
+
+	decoded := service.BlockchainTransaction{
+		TokenType: valueobject.TokenSTX,
+		Sender:    sender,
+		Recipient: recipient,
+		Amount:    valueobject.NewAmount(1000000),
+		Status:    "pending",
+		Sponsored: true,
+	}
+
+	attempts := 0
+	broadcaster := &MockBroadcaster{
+		BroadcastFn: func(ctx context.Context, signedTx string, network valueobject.Network) (valueobject.TransactionID, error) {
+			attempts++
+			return valueobject.TransactionID{}, errors.New("broadcast failed: BadNonce")
+		},
+	}
+	sponsor := &MockSponsor{
+		SponsorFn: func(ctx context.Context, signedTx string, network valueobject.Network, maxFee uint64) (SponsoredTransaction, error) {
+			return SponsoredTransaction{SignedTransaction: "0xsponsored"}, nil
+		},
+		ReleaseFn: func(sponsored SponsoredTransaction, broadcastErr error) bool {
+			return true
+		},
+	}
+
+	handler := NewSettlePaymentHandler(broadcaster, decoderReturning(decoded, valueobject.NetworkTestnet), service.NewVerificationService(),
+		WithSponsor(sponsor, service.SponsorPolicy{}))
+
+	cmd := SettlePaymentCommand{
+		SignedTransaction: "0xpayer",
+		TokenType:         "STX",
+		ExpectedRecipient: "ST1PQHQKV0RJXZFY1DGX8MNSNYVE3VGZJSRTPGZGM",
+		MinAmount:         "500000",
+		Network:           "testnet",
+	}
+
+	_, err := handler.Handle(context.Background(), cmd)
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to broadcast transaction")
+	assert.Equal(t, maxSponsorAttempts, attempts)
+}
@@ -0,0 +1,59 @@
+package command
+
+import (
+	"context"
+	"time"
+)
+
+// SettlementState is the progress of an asynchronous settlement
+type SettlementState string
+
+const (
+	SettlementBroadcast SettlementState = "broadcast" // Accepted by the node, not yet seen by the API
+	SettlementMempool   SettlementState = "mempool"   // Pending in the mempool
+	SettlementMined     SettlementState = "mined"     // In a block, waiting for the required confirmations
+	SettlementConfirmed SettlementState = "confirmed" // Confirmed and verified
+	SettlementFailed    SettlementState = "failed"    // Aborted, failed verification or never confirmed
+	SettlementDropped   SettlementState = "dropped"   // Dropped from the mempool without being mined
+)
+
+// IsFinal returns true once the settlement will not change again
+func (s SettlementState) IsFinal() bool {
+	return s == SettlementConfirmed || s == SettlementFailed || s == SettlementDropped
+}
+
+// Settlement records the progress of a transaction settled asynchronously
+type Settlement struct {
+	ID                string
+	TxID              string
+	Network           string
+	TokenType         string
+	State             SettlementState
+	Confirmations     uint64
+	BurnConfirmations uint64
+	Result            *SettlePaymentResult // Set once a final state is reached by verifying the tx
+	Error             string               // Set when the tx could not be tracked to a result
+	Retryable         bool                 // Settling the same tx again may still succeed, e.g. after shutdown interrupted it
+	CreatedAt         time.Time
+	UpdatedAt         time.Time
+}
+
+// SettlementStore keeps asynchronous settlements so their progress can be looked up
+type SettlementStore interface {
+	// Save creates or replaces the settlement with the same ID
+	Save(ctx context.Context, settlement Settlement) error
+	// Get returns the settlement with the given ID, if any
+	Get(ctx context.Context, id string) (Settlement, bool, error)
+}
+
+// settlementState is the final state a settlement reaches with result
+func settlementState(result SettlePaymentResult) SettlementState {
+	switch {
+	case result.Success:
+		return SettlementConfirmed
+	case result.Status == "dropped":
+		return SettlementDropped
+	default:
+		return SettlementFailed
+	}
+}
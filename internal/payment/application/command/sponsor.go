@@ -0,0 +1,64 @@
+package command
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/x402stacks/stacks-facilitator/internal/payment/domain/valueobject"
+)
+
+// maxSponsorAttempts bounds how often a settlement is re-sponsored after a sponsor nonce conflict
+const maxSponsorAttempts = 3
+
+// ErrSponsorFailed is wrapped when a sponsored transaction could not be counter-signed,
+// or the node refused it for the fee the sponsor pays, so it was never broadcast. It is
+// the facilitator's problem, not the payer's.
+var ErrSponsorFailed = errors.New("failed to sponsor transaction")
+
+// ErrSponsorFeeExceeded is wrapped by every SponsorFeeError
+var ErrSponsorFeeExceeded = errors.New("sponsor fee exceeds max fee")
+
+// SponsorFeeError is returned when the fee for sponsoring a transaction is above the
+// sponsor's max fee, before the transaction is signed as sponsor
+type SponsorFeeError struct {
+	Fee    uint64 // Network fee rate times the transaction's size, in microSTX
+	MaxFee uint64
+}
+
+// Error returns the fee and the max fee
+func (e *SponsorFeeError) Error() string {
+	return fmt.Sprintf("%s: fee %d microSTX is above %d", ErrSponsorFeeExceeded, e.Fee, e.MaxFee)
+}
+
+// Unwrap exposes ErrSponsorFeeExceeded
+func (e *SponsorFeeError) Unwrap() error {
+	return ErrSponsorFeeExceeded
+}
+
+// TransactionSponsor interface for counter-signing sponsored transactions as fee payer
+type TransactionSponsor interface {
+	// SponsorTransaction signs as sponsor, reserving a nonce on one of the sponsor accounts.
+	// It returns a *SponsorFeeError, without reserving a nonce, when the fee is above maxFee.
+	SponsorTransaction(ctx context.Context, signedTx string, network valueobject.Network, maxFee uint64) (SponsoredTransaction, error)
+	// Release reports the broadcast outcome for a reserved nonce and returns true
+	// when the rejection was a sponsor nonce conflict worth retrying
+	Release(sponsored SponsoredTransaction, broadcastErr error) bool
+}
+
+// SponsoredTransaction is a transaction counter-signed by a sponsor account
+type SponsoredTransaction struct {
+	SignedTransaction string
+	Network           valueobject.Network
+	Sponsor           valueobject.StacksAddress
+	Nonce             uint64
+	Fee               uint64
+}
+
+// SponsorAccount is the state of one sponsor key on a network
+type SponsorAccount struct {
+	Address   valueobject.StacksAddress
+	Balance   uint64 // microSTX available for fees
+	NextNonce uint64 // Next nonce the facilitator will hand out
+	Pending   int    // Nonces reserved or broadcast and not yet executed
+}
@@ -2,6 +2,7 @@ package command
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"time"
 
@@ -9,68 +10,124 @@ import (
 	"github.com/x402stacks/stacks-facilitator/internal/payment/domain/valueobject"
 )
 
-// BlockchainClient interface for fetching transactions
+// ErrInvalidAddress is returned when an expected address or principal is malformed or belongs to another network
+var ErrInvalidAddress = errors.New("invalid address")
+
+// ErrInvalidAmount is returned when a minimum amount is not a base-10 uint128
+var ErrInvalidAmount = errors.New("invalid amount")
+
+// ErrUnsupportedToken is returned when a token type is not a token name, or a SIP-010
+// token has no registered contract on the network
+var ErrUnsupportedToken = errors.New("unsupported token")
+
+// ErrTransactionNotFound is returned when the chain has no transaction with the ID being verified
+var ErrTransactionNotFound = errors.New("transaction not found")
+
+// ErrTransactionUnavailable is returned when a transaction could not be looked up because
+// the chain API was unreachable or failing; the same lookup may succeed later
+var ErrTransactionUnavailable = errors.New("transaction lookup unavailable")
+
+// Units a command's MinAmount can be given in
+const (
+	AmountUnitBase  = "base"  // Base units (microSTX, satoshis); the default
+	AmountUnitToken = "token" // Whole tokens as a decimal string, e.g. "0.0001" sBTC
+)
+
+// BlockchainClient interface for fetching transactions. A failed lookup wraps
+// ErrTransactionNotFound or ErrTransactionUnavailable when it is one of those.
 type BlockchainClient interface {
 	GetTransactionWithRetry(ctx context.Context, txID valueobject.TransactionID, tokenType valueobject.TokenType, network valueobject.Network, maxRetries int, retryDelay time.Duration) (service.BlockchainTransaction, error)
 }
 
 // VerifyPaymentCommand represents a request to verify a payment
 type VerifyPaymentCommand struct {
-	TxID              string
-	TokenType         string
-	ExpectedRecipient string
-	MinAmount         uint64
-	ExpectedSender    *string
-	ExpectedMemo      *string
-	Network           string
+	TxID                 string
+	SignedTransaction    string // Verified without broadcast instead of looking up TxID when set
+	TokenType            string
+	ExpectedRecipient    string
+	MinAmount            string // Decimal string in AmountUnit
+	AmountUnit           string // AmountUnitBase (default) or AmountUnitToken
+	ExpectedSender       *string
+	ExpectedMemo         *string
+	Network              string
+	MinConfirmations     uint64 // Stacks blocks deep the tx must be; raised to the handler's minimum
+	MinBurnConfirmations uint64 // Bitcoin burn blocks deep the tx must be; raised to the handler's minimum
+	Resource             string // Resource the payment unlocks, recorded for replay protection
+	Nonce                string // Client-supplied payment nonce, recorded for replay protection
 }
 
 // VerifyPaymentResult represents the result of a verification
 type VerifyPaymentResult struct {
-	Valid            bool
-	TxID             string
-	SenderAddress    string
-	RecipientAddress string
-	Amount           uint64
-	Fee              uint64
-	Nonce            uint64
-	Status           string
-	BlockHeight      uint64
-	TokenType        string
-	Memo             string
-	Network          string
-	Errors           []string
+	Valid             bool
+	TxID              string // Empty for a signed transaction that has not been broadcast
+	SenderAddress     string
+	RecipientAddress  string
+	Amount            string // Base units as a decimal string
+	DisplayAmount     string // Whole tokens with symbol, e.g. "0.0001 sBTC"
+	Fee               uint64
+	Nonce             uint64
+	Status            string // pending, confirmed, failed or dropped
+	ReplacedByTxID    string // Transaction that replaced a dropped one, when known
+	BlockHeight       uint64
+	Confirmations     uint64 // Stacks blocks deep, counting the tx's own block; 0 when unconfirmed
+	BurnConfirmations uint64 // Bitcoin burn blocks deep, counting the tx's own; 0 when unconfirmed
+	TokenType         string
+	Memo              string
+	Network           string
+	Errors            []string                      // Message of each failure, in order
+	Failures          []service.VerificationFailure // Why verification failed, with machine-readable codes
 }
 
 // VerifyPaymentHandler handles verify payment commands
 type VerifyPaymentHandler struct {
-	blockchainClient  BlockchainClient
-	verificationSvc   *service.VerificationService
-	maxRetries        int
-	retryDelay        time.Duration
+	blockchainClient     BlockchainClient
+	verificationSvc      *service.VerificationService
+	maxRetries           int
+	retryDelay           time.Duration
+	tokenRegistry        *service.TokenRegistry
+	paymentStore         PaymentStore
+	chainTip             ChainTipProvider
+	minConfirmations     uint64
+	minBurnConfirmations uint64
+	decoder              TransactionDecoder
+	accounts             AccountStateProvider
 }
 
 // NewVerifyPaymentHandler creates a new VerifyPaymentHandler
-func NewVerifyPaymentHandler(client BlockchainClient, verificationSvc *service.VerificationService) *VerifyPaymentHandler {
+func NewVerifyPaymentHandler(client BlockchainClient, verificationSvc *service.VerificationService, opts ...Option) *VerifyPaymentHandler {
+	o := applyOptions(options{maxRetries: 10, retryDelay: 2 * time.Second}, opts)
+
 	return &VerifyPaymentHandler{
-		blockchainClient:  client,
-		verificationSvc:   verificationSvc,
-		maxRetries:        10,
-		retryDelay:        2 * time.Second,
+		blockchainClient:     client,
+		verificationSvc:      verificationSvc,
+		maxRetries:           o.maxRetries,
+		retryDelay:           o.retryDelay,
+		tokenRegistry:        o.tokenRegistry,
+		paymentStore:         o.paymentStore,
+		chainTip:             o.chainTip,
+		minConfirmations:     o.minConfirmations,
+		minBurnConfirmations: o.minBurnConfirmations,
+		decoder:              o.signedDecoder,
+		accounts:             o.accounts,
 	}
 }
 
-// Handle processes the verify payment command
+// Handle processes the verify payment command. A command with a signed transaction is
+// verified before broadcast; otherwise the transaction is looked up on chain by ID.
 func (h *VerifyPaymentHandler) Handle(ctx context.Context, cmd VerifyPaymentCommand) (VerifyPaymentResult, error) {
 	// Parse and validate inputs
-	txID, err := valueobject.NewTransactionID(cmd.TxID)
-	if err != nil {
-		return VerifyPaymentResult{}, fmt.Errorf("invalid transaction ID: %w", err)
+	var txID valueobject.TransactionID
+	if cmd.SignedTransaction == "" {
+		var err error
+		txID, err = valueobject.NewTransactionID(cmd.TxID)
+		if err != nil {
+			return VerifyPaymentResult{}, fmt.Errorf("invalid transaction ID: %w", err)
+		}
 	}
 
-	tokenType, err := valueobject.NewTokenType(cmd.TokenType)
+	tokenType, err := parseTokenType(cmd.TokenType)
 	if err != nil {
-		tokenType = valueobject.TokenSTX // Default to STX
+		return VerifyPaymentResult{}, err
 	}
 
 	network, err := valueobject.NewNetwork(cmd.Network)
@@ -78,30 +135,39 @@ func (h *VerifyPaymentHandler) Handle(ctx context.Context, cmd VerifyPaymentComm
 		return VerifyPaymentResult{}, fmt.Errorf("invalid network: %w", err)
 	}
 
-	expectedRecipient, err := valueobject.NewStacksAddress(cmd.ExpectedRecipient)
+	expectedRecipient, err := parseNetworkPrincipal(cmd.ExpectedRecipient, network)
 	if err != nil {
 		return VerifyPaymentResult{}, fmt.Errorf("invalid expected recipient: %w", err)
 	}
 
-	// Fetch transaction from blockchain
-	tx, err := h.blockchainClient.GetTransactionWithRetry(ctx, txID, tokenType, network, h.maxRetries, h.retryDelay)
+	minAmount, err := parseMinAmount(cmd.MinAmount, cmd.AmountUnit, h.tokenRegistry, tokenType)
 	if err != nil {
-		return VerifyPaymentResult{}, fmt.Errorf("failed to fetch transaction: %w", err)
+		return VerifyPaymentResult{}, err
+	}
+
+	contract, err := expectedContract(h.tokenRegistry, tokenType, network)
+	if err != nil {
+		return VerifyPaymentResult{}, err
 	}
 
 	// Build verification criteria
 	criteria := service.VerificationCriteria{
-		ExpectedRecipient: expectedRecipient,
-		MinAmount:         valueobject.NewAmount(cmd.MinAmount),
-		AcceptUnconfirmed: false, // Always require confirmation
+		ExpectedRecipient:    expectedRecipient,
+		MinAmount:            minAmount,
+		AcceptUnconfirmed:    false, // Always require confirmation
+		ExpectedToken:        tokenType,
+		ExpectedContract:     contract,
+		MinConfirmations:     max(h.minConfirmations, cmd.MinConfirmations),
+		MinBurnConfirmations: max(h.minBurnConfirmations, cmd.MinBurnConfirmations),
 	}
 
 	// Optional sender
 	if cmd.ExpectedSender != nil {
-		sender, err := valueobject.NewStacksAddress(*cmd.ExpectedSender)
-		if err == nil {
-			criteria.ExpectedSender = &sender
+		sender, err := parseNetworkAddress(*cmd.ExpectedSender, network)
+		if err != nil {
+			return VerifyPaymentResult{}, fmt.Errorf("invalid expected sender: %w", err)
 		}
+		criteria.ExpectedSender = &sender
 	}
 
 	// Optional memo
@@ -109,36 +175,155 @@ func (h *VerifyPaymentHandler) Handle(ctx context.Context, cmd VerifyPaymentComm
 		criteria.ExpectedMemo = cmd.ExpectedMemo
 	}
 
+	if cmd.SignedTransaction != "" {
+		return h.verifySigned(ctx, cmd.SignedTransaction, tokenType, network, criteria)
+	}
+
+	// Fetch transaction from blockchain
+	tx, err := h.blockchainClient.GetTransactionWithRetry(ctx, txID, tokenType, network, h.maxRetries, h.retryDelay)
+	if err != nil {
+		return VerifyPaymentResult{}, fmt.Errorf("failed to fetch transaction: %w", err)
+	}
+	if err := setConfirmations(ctx, h.chainTip, &tx, network); err != nil {
+		return VerifyPaymentResult{}, err
+	}
+
 	// Verify transaction
 	verificationResult := h.verificationSvc.Verify(tx, criteria)
 
+	// Consume the payment so the same transaction cannot be accepted again
+	if verificationResult.Valid && h.paymentStore != nil {
+		err := h.paymentStore.MarkConsumed(ctx, ConsumedPayment{
+			TxID:       tx.TxID.String(),
+			Network:    network.String(),
+			Resource:   cmd.Resource,
+			Nonce:      cmd.Nonce,
+			ConsumedAt: time.Now().UTC(),
+		})
+		if err != nil {
+			return VerifyPaymentResult{}, err
+		}
+	}
+
 	// Determine status
 	status := determinePaymentStatus(tx)
 
 	return VerifyPaymentResult{
-		Valid:            verificationResult.Valid,
-		TxID:             tx.TxID.String(),
-		SenderAddress:    tx.Sender.String(),
-		RecipientAddress: tx.Recipient.String(),
-		Amount:           tx.Amount.Value(),
-		Fee:              tx.Fee.Value(),
-		Nonce:            tx.Nonce,
-		Status:           status,
-		BlockHeight:      tx.BlockHeight,
-		TokenType:        tx.TokenType.String(),
-		Memo:             tx.Memo,
-		Network:          network.String(),
-		Errors:           verificationResult.Errors,
+		Valid:             verificationResult.Valid,
+		TxID:              tx.TxID.String(),
+		SenderAddress:     tx.Sender.String(),
+		RecipientAddress:  tx.Recipient.String(),
+		Amount:            tx.Amount.String(),
+		DisplayAmount:     displayAmount(h.tokenRegistry, tx.Amount, tx.TokenType),
+		Fee:               feeValue(tx.Fee),
+		Nonce:             tx.Nonce,
+		Status:            status,
+		ReplacedByTxID:    tx.ReplacedBy.String(),
+		BlockHeight:       tx.BlockHeight,
+		Confirmations:     tx.Confirmations,
+		BurnConfirmations: tx.BurnConfirmations,
+		TokenType:         tx.TokenType.String(),
+		Memo:              tx.Memo,
+		Network:           network.String(),
+		Errors:            verificationResult.Errors(),
+		Failures:          verificationResult.Failures,
 	}, nil
 }
 
 // determinePaymentStatus converts blockchain status to payment status
 func determinePaymentStatus(tx service.BlockchainTransaction) string {
-	if tx.IsConfirmed {
-		return "confirmed"
+	return service.PaymentStatusOf(tx).String()
+}
+
+// parseTokenType parses a command's token type, defaulting to STX when none is given.
+// A malformed token type is unsupported rather than read as STX.
+func parseTokenType(s string) (valueobject.TokenType, error) {
+	if s == "" {
+		return valueobject.TokenSTX, nil
+	}
+	tokenType, err := valueobject.NewTokenType(s)
+	if err != nil {
+		return "", fmt.Errorf("%w: %v", ErrUnsupportedToken, err)
+	}
+	return tokenType, nil
+}
+
+// expectedContract resolves the canonical contract for a SIP-010 token on a network
+func expectedContract(registry *service.TokenRegistry, tokenType valueobject.TokenType, network valueobject.Network) (*service.TokenContract, error) {
+	if tokenType.IsNative() {
+		return nil, nil
+	}
+
+	contract, ok := registry.Contract(tokenType, network)
+	if !ok {
+		return nil, fmt.Errorf("%w: %s is not supported on %s", ErrUnsupportedToken, tokenType.String(), network.String())
+	}
+
+	return &contract, nil
+}
+
+// parseNetworkAddress parses a c32check address and checks that it belongs to the network
+func parseNetworkAddress(addr string, network valueobject.Network) (valueobject.StacksAddress, error) {
+	address, err := valueobject.NewStacksAddress(addr)
+	if err != nil {
+		return valueobject.StacksAddress{}, fmt.Errorf("%w: %v", ErrInvalidAddress, err)
 	}
-	if tx.Status == "failed" || tx.Status == "abort_by_response" || tx.Status == "abort_by_post_condition" {
-		return "failed"
+	if address.Network() != network {
+		return valueobject.StacksAddress{}, fmt.Errorf("%w: %s is not a %s address", ErrInvalidAddress, addr, network)
 	}
-	return "pending"
+	return address, nil
+}
+
+// parseNetworkPrincipal parses a standard or contract principal and checks that it belongs to the network
+func parseNetworkPrincipal(s string, network valueobject.Network) (valueobject.Principal, error) {
+	principal, err := valueobject.NewPrincipal(s)
+	if err != nil {
+		return valueobject.Principal{}, fmt.Errorf("%w: %v", ErrInvalidAddress, err)
+	}
+	if principal.Network() != network {
+		return valueobject.Principal{}, fmt.Errorf("%w: %s is not a %s principal", ErrInvalidAddress, s, network)
+	}
+	return principal, nil
+}
+
+// parseMinAmount parses a minimum amount given in base units or, for AmountUnitToken,
+// in whole tokens converted with the token's registered decimals, which a token without
+// metadata does not support. An omitted amount is zero, as it was before amounts were
+// carried as strings.
+func parseMinAmount(s, unit string, registry *service.TokenRegistry, tokenType valueobject.TokenType) (valueobject.Amount, error) {
+	if s == "" {
+		s = "0"
+	}
+
+	var amount valueobject.Amount
+	var err error
+	switch unit {
+	case "", AmountUnitBase:
+		amount, err = valueobject.ParseAmount(s)
+	case AmountUnitToken:
+		// Without registered decimals a whole-token amount cannot be converted
+		metadata, ok := registry.Metadata(tokenType)
+		if !ok {
+			return valueobject.Amount{}, fmt.Errorf("%w: no decimals registered for %s", ErrUnsupportedToken, tokenType.String())
+		}
+		amount, err = valueobject.ParseDecimalAmount(s, metadata.Decimals)
+	default:
+		err = errors.New("unknown amount unit: " + unit)
+	}
+	if err != nil {
+		return valueobject.Amount{}, fmt.Errorf("invalid min amount: %w: %v", ErrInvalidAmount, err)
+	}
+	return amount, nil
+}
+
+// displayAmount returns an amount in whole tokens with the token's registered symbol
+func displayAmount(registry *service.TokenRegistry, amount valueobject.Amount, tokenType valueobject.TokenType) string {
+	metadata, _ := registry.Metadata(tokenType)
+	return metadata.FormatAmount(amount)
+}
+
+// feeValue returns a transaction fee, which consensus limits to a u64
+func feeValue(fee valueobject.Amount) uint64 {
+	v, _ := fee.Uint64()
+	return v
 }
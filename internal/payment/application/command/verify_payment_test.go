@@ -2,6 +2,8 @@ package command
 
 import (
 	"context"
+	"errors"
+	"sync"
 	"testing"
 	"time"
 
@@ -20,10 +22,26 @@ func (m *MockBlockchainClient) GetTransactionWithRetry(ctx context.Context, txID
 	return m.GetTransactionFn(ctx, txID, tokenType, network)
 }
 
+// MockChainTip is a ChainTipProvider reporting a fixed tip, or the tips in order
+type MockChainTip struct {
+	Tips  []service.ChainTip
+	Err   error
+	calls int
+}
+
+func (m *MockChainTip) ChainTip(ctx context.Context, network valueobject.Network) (service.ChainTip, error) {
+	if m.Err != nil {
+		return service.ChainTip{}, m.Err
+	}
+	tip := m.Tips[min(m.calls, len(m.Tips)-1)]
+	m.calls++
+	return tip, nil
+}
+
 func createMockTransaction() service.BlockchainTransaction {
 	txID, _ := valueobject.NewTransactionID("0x1234567890abcdef1234567890abcdef1234567890abcdef1234567890abcdef")
-	sender, _ := valueobject.NewStacksAddress("ST2J6ZY48GV1EZ5V2V5RB9MP66SW86PYKKNRV9EJ7")
-	recipient, _ := valueobject.NewStacksAddress("ST1PQHQKV0RJXZFY1DGX8MNSNYVE3VGZJSRTPGZGM")
+	sender, _ := valueobject.NewStacksAddress("ST2J6ZY48GV1EZ5V2V5RB9MP66SW86PYKKQYAC0RQ")
+	recipient, _ := valueobject.NewPrincipal("ST1PQHQKV0RJXZFY1DGX8MNSNYVE3VGZJSRTPGZGM")
 
 	return service.BlockchainTransaction{
 		TxID:        txID,
@@ -55,7 +73,7 @@ func TestVerifyPaymentHandler_Success(t *testing.T) {
 		TxID:              "0x1234567890abcdef1234567890abcdef1234567890abcdef1234567890abcdef",
 		TokenType:         "STX",
 		ExpectedRecipient: "ST1PQHQKV0RJXZFY1DGX8MNSNYVE3VGZJSRTPGZGM",
-		MinAmount:         500000,
+		MinAmount:         "500000",
 		Network:           "testnet",
 	}
 
@@ -64,8 +82,36 @@ func TestVerifyPaymentHandler_Success(t *testing.T) {
 	require.NoError(t, err)
 	assert.True(t, result.Valid)
 	assert.Equal(t, "confirmed", result.Status)
-	assert.Equal(t, uint64(1000000), result.Amount)
-	assert.Equal(t, "ST2J6ZY48GV1EZ5V2V5RB9MP66SW86PYKKNRV9EJ7", result.SenderAddress)
+	assert.Equal(t, "1000000", result.Amount)
+	assert.Equal(t, "ST2J6ZY48GV1EZ5V2V5RB9MP66SW86PYKKQYAC0RQ", result.SenderAddress)
+}
+
+func TestVerifyPaymentHandler_DroppedTransaction(t *testing.T) {
+	mockTx := createMockTransaction()
+	mockTx.Status = valueobject.TxStatusDroppedReplaceByFee
+	mockTx.IsConfirmed = false
+	mockTx.BlockHeight = 0
+	mockTx.ReplacedBy, _ = valueobject.NewTransactionID("0xabababababababababababababababababababababababababababababababab")
+	mockClient := &MockBlockchainClient{
+		GetTransactionFn: func(ctx context.Context, txID valueobject.TransactionID, tokenType valueobject.TokenType, network valueobject.Network) (service.BlockchainTransaction, error) {
+			return mockTx, nil
+		},
+	}
+
+	handler := NewVerifyPaymentHandler(mockClient, service.NewVerificationService())
+
+	result, err := handler.Handle(context.Background(), VerifyPaymentCommand{
+		TxID:              "0x1234567890abcdef1234567890abcdef1234567890abcdef1234567890abcdef",
+		TokenType:         "STX",
+		ExpectedRecipient: "ST1PQHQKV0RJXZFY1DGX8MNSNYVE3VGZJSRTPGZGM",
+		MinAmount:         "500000",
+		Network:           "testnet",
+	})
+
+	require.NoError(t, err)
+	assert.False(t, result.Valid)
+	assert.Equal(t, "dropped", result.Status)
+	assert.Equal(t, "0xabababababababababababababababababababababababababababababababab", result.ReplacedByTxID)
 }
 
 func TestVerifyPaymentHandler_InvalidRecipient(t *testing.T) {
@@ -82,8 +128,8 @@ func TestVerifyPaymentHandler_InvalidRecipient(t *testing.T) {
 	cmd := VerifyPaymentCommand{
 		TxID:              "0x1234567890abcdef1234567890abcdef1234567890abcdef1234567890abcdef",
 		TokenType:         "STX",
-		ExpectedRecipient: "ST9J6ZY48GV1EZ5V2V5RB9MP66SW86PYKKNRV9EJ9", // Wrong recipient
-		MinAmount:         500000,
+		ExpectedRecipient: "ST3T6ZY48GV1EZ5V2V5RB9MP66SW86PYKKMKH9H62", // Wrong recipient
+		MinAmount:         "500000",
 		Network:           "testnet",
 	}
 
@@ -109,7 +155,7 @@ func TestVerifyPaymentHandler_InsufficientAmount(t *testing.T) {
 		TxID:              "0x1234567890abcdef1234567890abcdef1234567890abcdef1234567890abcdef",
 		TokenType:         "STX",
 		ExpectedRecipient: "ST1PQHQKV0RJXZFY1DGX8MNSNYVE3VGZJSRTPGZGM",
-		MinAmount:         5000000, // More than tx amount
+		MinAmount:         "5000000", // More than tx amount
 		Network:           "testnet",
 	}
 
@@ -119,6 +165,131 @@ func TestVerifyPaymentHandler_InsufficientAmount(t *testing.T) {
 	assert.False(t, result.Valid)
 }
 
+func TestVerifyPaymentHandler_AmountBeyondUint64(t *testing.T) {
+	mockTx := createMockTransaction()
+	mockTx.Amount, _ = valueobject.ParseAmount("18446744073709551616") // 2^64
+	mockClient := &MockBlockchainClient{
+		GetTransactionFn: func(ctx context.Context, txID valueobject.TransactionID, tokenType valueobject.TokenType, network valueobject.Network) (service.BlockchainTransaction, error) {
+			return mockTx, nil
+		},
+	}
+
+	verificationSvc := service.NewVerificationService()
+	handler := NewVerifyPaymentHandler(mockClient, verificationSvc)
+
+	cmd := VerifyPaymentCommand{
+		TxID:              "0x1234567890abcdef1234567890abcdef1234567890abcdef1234567890abcdef",
+		TokenType:         "STX",
+		ExpectedRecipient: "ST1PQHQKV0RJXZFY1DGX8MNSNYVE3VGZJSRTPGZGM",
+		MinAmount:         "18446744073709551615",
+		Network:           "testnet",
+	}
+
+	result, err := handler.Handle(context.Background(), cmd)
+	require.NoError(t, err)
+	assert.True(t, result.Valid)
+	assert.Equal(t, "18446744073709551616", result.Amount)
+
+	cmd.MinAmount = "18446744073709551617"
+	result, err = handler.Handle(context.Background(), cmd)
+	require.NoError(t, err)
+	assert.False(t, result.Valid)
+}
+
+func TestVerifyPaymentHandler_MinAmountInWholeTokens(t *testing.T) {
+	mockTx := createMockTransaction()
+	mockClient := &MockBlockchainClient{
+		GetTransactionFn: func(ctx context.Context, txID valueobject.TransactionID, tokenType valueobject.TokenType, network valueobject.Network) (service.BlockchainTransaction, error) {
+			return mockTx, nil
+		},
+	}
+
+	handler := NewVerifyPaymentHandler(mockClient, service.NewVerificationService())
+
+	tests := []struct {
+		minAmount string
+		valid     bool
+	}{
+		{"0.5", true},
+		{"1", true},
+		{"1.000001", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.minAmount, func(t *testing.T) {
+			result, err := handler.Handle(context.Background(), VerifyPaymentCommand{
+				TxID:              "0x1234567890abcdef1234567890abcdef1234567890abcdef1234567890abcdef",
+				TokenType:         "STX",
+				ExpectedRecipient: "ST1PQHQKV0RJXZFY1DGX8MNSNYVE3VGZJSRTPGZGM",
+				MinAmount:         tt.minAmount,
+				AmountUnit:        AmountUnitToken,
+				Network:           "testnet",
+			})
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.valid, result.Valid, result.Errors)
+			assert.Equal(t, "1000000", result.Amount)
+			assert.Equal(t, "1 STX", result.DisplayAmount)
+		})
+	}
+}
+
+func TestVerifyPaymentHandler_InvalidMinAmount(t *testing.T) {
+	mockClient := &MockBlockchainClient{
+		GetTransactionFn: func(ctx context.Context, txID valueobject.TransactionID, tokenType valueobject.TokenType, network valueobject.Network) (service.BlockchainTransaction, error) {
+			t.Fatal("transaction should not be fetched")
+			return service.BlockchainTransaction{}, nil
+		},
+	}
+
+	handler := NewVerifyPaymentHandler(mockClient, service.NewVerificationService())
+
+	tests := []struct {
+		minAmount string
+		unit      string
+	}{
+		{"-1", ""},
+		{"1.5", ""},
+		{"1e6", AmountUnitBase},
+		{"340282366920938463463374607431768211456", ""},
+		{"0.0000001", AmountUnitToken}, // STX has 6 decimals
+		{"1", "satoshi"},
+	}
+
+	for _, tt := range tests {
+		_, err := handler.Handle(context.Background(), VerifyPaymentCommand{
+			TxID:              "0x1234567890abcdef1234567890abcdef1234567890abcdef1234567890abcdef",
+			ExpectedRecipient: "ST1PQHQKV0RJXZFY1DGX8MNSNYVE3VGZJSRTPGZGM",
+			MinAmount:         tt.minAmount,
+			AmountUnit:        tt.unit,
+			Network:           "testnet",
+		})
+		assert.ErrorIs(t, err, ErrInvalidAmount, "min amount %q %q", tt.minAmount, tt.unit)
+	}
+}
+
+func TestVerifyPaymentHandler_OmittedMinAmount(t *testing.T) {
+	mockTx := createMockTransaction()
+	mockClient := &MockBlockchainClient{
+		GetTransactionFn: func(ctx context.Context, txID valueobject.TransactionID, tokenType valueobject.TokenType, network valueobject.Network) (service.BlockchainTransaction, error) {
+			return mockTx, nil
+		},
+	}
+	handler := NewVerifyPaymentHandler(mockClient, service.NewVerificationService())
+
+	for _, unit := range []string{"", AmountUnitBase, AmountUnitToken} {
+		result, err := handler.Handle(context.Background(), VerifyPaymentCommand{
+			TxID:              "0x1234567890abcdef1234567890abcdef1234567890abcdef1234567890abcdef",
+			ExpectedRecipient: "ST1PQHQKV0RJXZFY1DGX8MNSNYVE3VGZJSRTPGZGM",
+			AmountUnit:        unit,
+			Network:           "testnet",
+		})
+
+		require.NoError(t, err, unit)
+		assert.True(t, result.Valid, unit)
+	}
+}
+
 func TestVerifyPaymentHandler_WithOptionalSender(t *testing.T) {
 	mockTx := createMockTransaction()
 	mockClient := &MockBlockchainClient{
@@ -130,12 +301,12 @@ func TestVerifyPaymentHandler_WithOptionalSender(t *testing.T) {
 	verificationSvc := service.NewVerificationService()
 	handler := NewVerifyPaymentHandler(mockClient, verificationSvc)
 
-	expectedSender := "ST2J6ZY48GV1EZ5V2V5RB9MP66SW86PYKKNRV9EJ7"
+	expectedSender := "ST2J6ZY48GV1EZ5V2V5RB9MP66SW86PYKKQYAC0RQ"
 	cmd := VerifyPaymentCommand{
 		TxID:              "0x1234567890abcdef1234567890abcdef1234567890abcdef1234567890abcdef",
 		TokenType:         "STX",
 		ExpectedRecipient: "ST1PQHQKV0RJXZFY1DGX8MNSNYVE3VGZJSRTPGZGM",
-		MinAmount:         500000,
+		MinAmount:         "500000",
 		ExpectedSender:    &expectedSender,
 		Network:           "testnet",
 	}
@@ -162,7 +333,7 @@ func TestVerifyPaymentHandler_WithOptionalMemo(t *testing.T) {
 		TxID:              "0x1234567890abcdef1234567890abcdef1234567890abcdef1234567890abcdef",
 		TokenType:         "STX",
 		ExpectedRecipient: "ST1PQHQKV0RJXZFY1DGX8MNSNYVE3VGZJSRTPGZGM",
-		MinAmount:         500000,
+		MinAmount:         "500000",
 		ExpectedMemo:      &expectedMemo,
 		Network:           "testnet",
 	}
@@ -182,7 +353,7 @@ func TestVerifyPaymentHandler_InvalidTxID(t *testing.T) {
 		TxID:              "invalid",
 		TokenType:         "STX",
 		ExpectedRecipient: "ST1PQHQKV0RJXZFY1DGX8MNSNYVE3VGZJSRTPGZGM",
-		MinAmount:         500000,
+		MinAmount:         "500000",
 		Network:           "testnet",
 	}
 
@@ -200,7 +371,7 @@ func TestVerifyPaymentHandler_InvalidNetwork(t *testing.T) {
 		TxID:              "0x1234567890abcdef1234567890abcdef1234567890abcdef1234567890abcdef",
 		TokenType:         "STX",
 		ExpectedRecipient: "ST1PQHQKV0RJXZFY1DGX8MNSNYVE3VGZJSRTPGZGM",
-		MinAmount:         500000,
+		MinAmount:         "500000",
 		Network:           "invalid",
 	}
 
@@ -208,3 +379,408 @@ func TestVerifyPaymentHandler_InvalidNetwork(t *testing.T) {
 
 	assert.Error(t, err)
 }
+
+func TestVerifyPaymentHandler_RejectsAddressFromOtherNetwork(t *testing.T) {
+	sender := "SP2J6ZY48GV1EZ5V2V5RB9MP66SW86PYKKNRV9EJ7"
+	tests := []struct {
+		name      string
+		recipient string
+		sender    *string
+	}{
+		{"mainnet recipient", "SP1PQHQKV0RJXZFY1DGX8MNSNYVE3VGZJSRCBGD7R", nil},
+		{"bad checksum", "ST1PQHQKV0RJXZFY1DGX8MNSNYVE3VGZJSRTPGZGN", nil},
+		{"mainnet sender", "ST1PQHQKV0RJXZFY1DGX8MNSNYVE3VGZJSRTPGZGM", &sender},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockClient := &MockBlockchainClient{
+				GetTransactionFn: func(ctx context.Context, txID valueobject.TransactionID, tokenType valueobject.TokenType, network valueobject.Network) (service.BlockchainTransaction, error) {
+					t.Fatal("transaction should not be fetched")
+					return service.BlockchainTransaction{}, nil
+				},
+			}
+			handler := NewVerifyPaymentHandler(mockClient, service.NewVerificationService())
+
+			cmd := VerifyPaymentCommand{
+				TxID:              "0x1234567890abcdef1234567890abcdef1234567890abcdef1234567890abcdef",
+				TokenType:         "STX",
+				ExpectedRecipient: tt.recipient,
+				ExpectedSender:    tt.sender,
+				MinAmount:         "500000",
+				Network:           "testnet",
+			}
+
+			_, err := handler.Handle(context.Background(), cmd)
+
+			require.Error(t, err)
+			assert.True(t, errors.Is(err, ErrInvalidAddress))
+		})
+	}
+}
+
+func TestVerifyPaymentHandler_ContractPrincipalRecipient(t *testing.T) {
+	mockTx := createMockTransaction()
+	mockTx.Recipient, _ = valueobject.NewPrincipal("ST1PQHQKV0RJXZFY1DGX8MNSNYVE3VGZJSRTPGZGM.my-vault")
+	mockClient := &MockBlockchainClient{
+		GetTransactionFn: func(ctx context.Context, txID valueobject.TransactionID, tokenType valueobject.TokenType, network valueobject.Network) (service.BlockchainTransaction, error) {
+			return mockTx, nil
+		},
+	}
+
+	handler := NewVerifyPaymentHandler(mockClient, service.NewVerificationService())
+
+	cmd := VerifyPaymentCommand{
+		TxID:              "0x1234567890abcdef1234567890abcdef1234567890abcdef1234567890abcdef",
+		TokenType:         "STX",
+		ExpectedRecipient: "ST1PQHQKV0RJXZFY1DGX8MNSNYVE3VGZJSRTPGZGM.my-vault",
+		MinAmount:         "500000",
+		Network:           "testnet",
+	}
+
+	result, err := handler.Handle(context.Background(), cmd)
+
+	require.NoError(t, err)
+	assert.True(t, result.Valid)
+	assert.Equal(t, "ST1PQHQKV0RJXZFY1DGX8MNSNYVE3VGZJSRTPGZGM.my-vault", result.RecipientAddress)
+}
+
+func TestVerifyPaymentHandler_RejectsCounterfeitSIP010Contract(t *testing.T) {
+	mockTx := createMockTransaction()
+	mockTx.TokenType = valueobject.TokenSBTC
+	mockTx.ContractID = "ST2J6ZY48GV1EZ5V2V5RB9MP66SW86PYKKQYAC0RQ.sbtc-token"
+	mockClient := &MockBlockchainClient{
+		GetTransactionFn: func(ctx context.Context, txID valueobject.TransactionID, tokenType valueobject.TokenType, network valueobject.Network) (service.BlockchainTransaction, error) {
+			return mockTx, nil
+		},
+	}
+
+	handler := NewVerifyPaymentHandler(mockClient, service.NewVerificationService())
+
+	cmd := VerifyPaymentCommand{
+		TxID:              "0x1234567890abcdef1234567890abcdef1234567890abcdef1234567890abcdef",
+		TokenType:         "SBTC",
+		ExpectedRecipient: "ST1PQHQKV0RJXZFY1DGX8MNSNYVE3VGZJSRTPGZGM",
+		MinAmount:         "500000",
+		Network:           "testnet",
+	}
+
+	result, err := handler.Handle(context.Background(), cmd)
+
+	require.NoError(t, err)
+	assert.False(t, result.Valid)
+	assert.Contains(t, result.Errors[0], "token contract mismatch")
+}
+
+func TestVerifyPaymentHandler_UsesConfiguredTokenRegistry(t *testing.T) {
+	mockTx := createMockTransaction()
+	mockTx.TokenType = valueobject.TokenSBTC
+	mockTx.ContractID = "ST2J6ZY48GV1EZ5V2V5RB9MP66SW86PYKKQYAC0RQ.sbtc-token"
+	mockClient := &MockBlockchainClient{
+		GetTransactionFn: func(ctx context.Context, txID valueobject.TransactionID, tokenType valueobject.TokenType, network valueobject.Network) (service.BlockchainTransaction, error) {
+			return mockTx, nil
+		},
+	}
+
+	registry := service.NewTokenRegistry()
+	registry.Register(valueobject.NetworkTestnet, valueobject.TokenSBTC, service.TokenContract{
+		ContractID: "ST2J6ZY48GV1EZ5V2V5RB9MP66SW86PYKKQYAC0RQ.sbtc-token",
+		AssetName:  "sbtc-token",
+	})
+	handler := NewVerifyPaymentHandler(mockClient, service.NewVerificationService(), WithTokenRegistry(registry))
+
+	cmd := VerifyPaymentCommand{
+		TxID:              "0x1234567890abcdef1234567890abcdef1234567890abcdef1234567890abcdef",
+		TokenType:         "SBTC",
+		ExpectedRecipient: "ST1PQHQKV0RJXZFY1DGX8MNSNYVE3VGZJSRTPGZGM",
+		MinAmount:         "500000",
+		Network:           "testnet",
+	}
+
+	result, err := handler.Handle(context.Background(), cmd)
+
+	require.NoError(t, err)
+	assert.True(t, result.Valid)
+}
+
+func TestVerifyPaymentHandler_UnknownTokenType(t *testing.T) {
+	mockClient := &MockBlockchainClient{
+		GetTransactionFn: func(ctx context.Context, txID valueobject.TransactionID, tokenType valueobject.TokenType, network valueobject.Network) (service.BlockchainTransaction, error) {
+			t.Fatal("an unknown token type must not be looked up as STX")
+			return service.BlockchainTransaction{}, nil
+		},
+	}
+	handler := NewVerifyPaymentHandler(mockClient, service.NewVerificationService(), WithTokenRegistry(service.DefaultTokenRegistry()))
+
+	for _, tokenType := range []string{"not a token", "DOGE"} {
+		cmd := VerifyPaymentCommand{
+			TxID:              "0x1234567890abcdef1234567890abcdef1234567890abcdef1234567890abcdef",
+			TokenType:         tokenType,
+			ExpectedRecipient: "ST1PQHQKV0RJXZFY1DGX8MNSNYVE3VGZJSRTPGZGM",
+			MinAmount:         "500000",
+			Network:           "testnet",
+		}
+
+		_, err := handler.Handle(context.Background(), cmd)
+
+		assert.ErrorIs(t, err, ErrUnsupportedToken, tokenType)
+	}
+}
+
+func TestVerifyPaymentHandler_ConfiguredSIP010Token(t *testing.T) {
+	alex := valueobject.TokenType("ALEX")
+	mockTx := createMockTransaction()
+	mockTx.TokenType = alex
+	mockTx.ContractID = "ST2J6ZY48GV1EZ5V2V5RB9MP66SW86PYKKQYAC0RQ.token-alex"
+	mockClient := &MockBlockchainClient{
+		GetTransactionFn: func(ctx context.Context, txID valueobject.TransactionID, tokenType valueobject.TokenType, network valueobject.Network) (service.BlockchainTransaction, error) {
+			assert.Equal(t, alex, tokenType)
+			return mockTx, nil
+		},
+	}
+
+	registry := service.DefaultTokenRegistry()
+	registry.Register(valueobject.NetworkTestnet, alex, service.TokenContract{
+		ContractID: "ST2J6ZY48GV1EZ5V2V5RB9MP66SW86PYKKQYAC0RQ.token-alex",
+		AssetName:  "alex",
+	})
+	registry.SetMetadata(alex, service.TokenMetadata{Symbol: "ALEX", Decimals: 8})
+	handler := NewVerifyPaymentHandler(mockClient, service.NewVerificationService(), WithTokenRegistry(registry))
+
+	cmd := VerifyPaymentCommand{
+		TxID:              "0x1234567890abcdef1234567890abcdef1234567890abcdef1234567890abcdef",
+		TokenType:         "alex",
+		ExpectedRecipient: "ST1PQHQKV0RJXZFY1DGX8MNSNYVE3VGZJSRTPGZGM",
+		MinAmount:         "0.01",
+		AmountUnit:        AmountUnitToken,
+		Network:           "testnet",
+	}
+
+	result, err := handler.Handle(context.Background(), cmd)
+
+	require.NoError(t, err)
+	assert.True(t, result.Valid, result.Errors)
+	assert.Equal(t, "ALEX", result.TokenType)
+	assert.Equal(t, "0.01 ALEX", result.DisplayAmount)
+
+	// The same token is not supported on a network without a registered contract
+	cmd.Network = "mainnet"
+	cmd.ExpectedRecipient = "SP3K8BC0PPEVCV7NZ6QSRWPQ2JE9E5B6N3PA0KBR9"
+	_, err = handler.Handle(context.Background(), cmd)
+	assert.ErrorIs(t, err, ErrUnsupportedToken)
+	assert.Contains(t, err.Error(), "ALEX is not supported on mainnet")
+}
+
+func TestVerifyPaymentHandler_TokenUnitWithoutMetadata(t *testing.T) {
+	alex := valueobject.TokenType("ALEX")
+	mockClient := &MockBlockchainClient{
+		GetTransactionFn: func(ctx context.Context, txID valueobject.TransactionID, tokenType valueobject.TokenType, network valueobject.Network) (service.BlockchainTransaction, error) {
+			t.Fatal("transaction should not be fetched")
+			return service.BlockchainTransaction{}, nil
+		},
+	}
+
+	// A contract but no decimals: "1" must not be read as 1 base unit
+	registry := service.DefaultTokenRegistry()
+	registry.Register(valueobject.NetworkTestnet, alex, service.TokenContract{
+		ContractID: "ST2J6ZY48GV1EZ5V2V5RB9MP66SW86PYKKQYAC0RQ.token-alex",
+		AssetName:  "alex",
+	})
+	handler := NewVerifyPaymentHandler(mockClient, service.NewVerificationService(), WithTokenRegistry(registry))
+
+	_, err := handler.Handle(context.Background(), VerifyPaymentCommand{
+		TxID:              "0x1234567890abcdef1234567890abcdef1234567890abcdef1234567890abcdef",
+		TokenType:         "alex",
+		ExpectedRecipient: "ST1PQHQKV0RJXZFY1DGX8MNSNYVE3VGZJSRTPGZGM",
+		MinAmount:         "1",
+		AmountUnit:        AmountUnitToken,
+		Network:           "testnet",
+	})
+
+	assert.ErrorIs(t, err, ErrUnsupportedToken)
+	assert.Contains(t, err.Error(), "no decimals registered for ALEX")
+}
+
+func TestVerifyPaymentHandler_ReportsConfirmations(t *testing.T) {
+	mockTx := createMockTransaction()
+	mockTx.BurnBlockHeight = 880000
+	mockClient := &MockBlockchainClient{
+		GetTransactionFn: func(ctx context.Context, txID valueobject.TransactionID, tokenType valueobject.TokenType, network valueobject.Network) (service.BlockchainTransaction, error) {
+			return mockTx, nil
+		},
+	}
+	chainTip := &MockChainTip{Tips: []service.ChainTip{{StacksHeight: 12347, BurnHeight: 880000}}}
+
+	handler := NewVerifyPaymentHandler(mockClient, service.NewVerificationService(), WithChainTip(chainTip))
+
+	result, err := handler.Handle(context.Background(), VerifyPaymentCommand{
+		TxID:              "0x1234567890abcdef1234567890abcdef1234567890abcdef1234567890abcdef",
+		TokenType:         "STX",
+		ExpectedRecipient: "ST1PQHQKV0RJXZFY1DGX8MNSNYVE3VGZJSRTPGZGM",
+		MinAmount:         "500000",
+		Network:           "testnet",
+	})
+
+	require.NoError(t, err)
+	assert.True(t, result.Valid)
+	assert.Equal(t, uint64(3), result.Confirmations)
+	assert.Equal(t, uint64(1), result.BurnConfirmations)
+}
+
+func TestVerifyPaymentHandler_MinConfirmations(t *testing.T) {
+	mockTx := createMockTransaction()
+	mockClient := &MockBlockchainClient{
+		GetTransactionFn: func(ctx context.Context, txID valueobject.TransactionID, tokenType valueobject.TokenType, network valueobject.Network) (service.BlockchainTransaction, error) {
+			return mockTx, nil
+		},
+	}
+	chainTip := &MockChainTip{Tips: []service.ChainTip{{StacksHeight: 12347}}}
+
+	tests := []struct {
+		name       string
+		handlerMin uint64
+		requestMin uint64
+		valid      bool
+	}{
+		{"no minimum", 0, 0, true},
+		{"request minimum met", 0, 3, true},
+		{"request minimum not met", 0, 4, false},
+		{"handler minimum not met", 6, 0, false},
+		{"request cannot lower handler minimum", 6, 1, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := NewVerifyPaymentHandler(mockClient, service.NewVerificationService(),
+				WithChainTip(chainTip), WithMinConfirmations(tt.handlerMin, 0))
+
+			result, err := handler.Handle(context.Background(), VerifyPaymentCommand{
+				TxID:              "0x1234567890abcdef1234567890abcdef1234567890abcdef1234567890abcdef",
+				TokenType:         "STX",
+				ExpectedRecipient: "ST1PQHQKV0RJXZFY1DGX8MNSNYVE3VGZJSRTPGZGM",
+				MinAmount:         "500000",
+				Network:           "testnet",
+				MinConfirmations:  tt.requestMin,
+			})
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.valid, result.Valid, result.Errors)
+			assert.Equal(t, uint64(3), result.Confirmations)
+			if !tt.valid {
+				assert.Contains(t, result.Errors[0], "insufficient confirmations")
+			}
+		})
+	}
+}
+
+func TestVerifyPaymentHandler_ChainTipError(t *testing.T) {
+	mockClient := &MockBlockchainClient{
+		GetTransactionFn: func(ctx context.Context, txID valueobject.TransactionID, tokenType valueobject.TokenType, network valueobject.Network) (service.BlockchainTransaction, error) {
+			return createMockTransaction(), nil
+		},
+	}
+
+	handler := NewVerifyPaymentHandler(mockClient, service.NewVerificationService(),
+		WithChainTip(&MockChainTip{Err: errors.New("node unavailable")}))
+
+	_, err := handler.Handle(context.Background(), VerifyPaymentCommand{
+		TxID:              "0x1234567890abcdef1234567890abcdef1234567890abcdef1234567890abcdef",
+		TokenType:         "STX",
+		ExpectedRecipient: "ST1PQHQKV0RJXZFY1DGX8MNSNYVE3VGZJSRTPGZGM",
+		MinAmount:         "500000",
+		Network:           "testnet",
+	})
+
+	assert.ErrorContains(t, err, "failed to fetch chain tip")
+}
+
+// memoryPaymentStore is a minimal PaymentStore for testing
+type memoryPaymentStore struct {
+	mu       sync.Mutex
+	payments map[string]ConsumedPayment
+}
+
+func (s *memoryPaymentStore) MarkConsumed(ctx context.Context, payment ConsumedPayment) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.payments[payment.TxID]; ok {
+		return ErrPaymentAlreadyUsed
+	}
+	s.payments[payment.TxID] = payment
+	return nil
+}
+
+func (s *memoryPaymentStore) Get(ctx context.Context, txID string) (ConsumedPayment, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	payment, ok := s.payments[txID]
+	return payment, ok, nil
+}
+
+func (s *memoryPaymentStore) Update(ctx context.Context, payment ConsumedPayment) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.payments[payment.TxID] = payment
+	return nil
+}
+
+func (s *memoryPaymentStore) Release(ctx context.Context, txID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.payments, txID)
+	return nil
+}
+
+func TestVerifyPaymentHandler_RejectsReusedTransaction(t *testing.T) {
+	mockTx := createMockTransaction()
+	mockClient := &MockBlockchainClient{
+		GetTransactionFn: func(ctx context.Context, txID valueobject.TransactionID, tokenType valueobject.TokenType, network valueobject.Network) (service.BlockchainTransaction, error) {
+			return mockTx, nil
+		},
+	}
+	store := &memoryPaymentStore{payments: map[string]ConsumedPayment{}}
+	handler := NewVerifyPaymentHandler(mockClient, service.NewVerificationService(), WithPaymentStore(store))
+
+	cmd := VerifyPaymentCommand{
+		TxID:              "0x1234567890abcdef1234567890abcdef1234567890abcdef1234567890abcdef",
+		TokenType:         "STX",
+		ExpectedRecipient: "ST1PQHQKV0RJXZFY1DGX8MNSNYVE3VGZJSRTPGZGM",
+		MinAmount:         "500000",
+		Network:           "testnet",
+		Resource:          "/premium",
+		Nonce:             "abc",
+	}
+
+	first, err := handler.Handle(context.Background(), cmd)
+	require.NoError(t, err)
+	assert.True(t, first.Valid)
+	assert.Equal(t, "/premium", store.payments[mockTx.TxID.String()].Resource)
+
+	_, err = handler.Handle(context.Background(), cmd)
+	assert.ErrorIs(t, err, ErrPaymentAlreadyUsed)
+}
+
+func TestVerifyPaymentHandler_InvalidPaymentIsNotConsumed(t *testing.T) {
+	mockTx := createMockTransaction()
+	mockClient := &MockBlockchainClient{
+		GetTransactionFn: func(ctx context.Context, txID valueobject.TransactionID, tokenType valueobject.TokenType, network valueobject.Network) (service.BlockchainTransaction, error) {
+			return mockTx, nil
+		},
+	}
+	store := &memoryPaymentStore{payments: map[string]ConsumedPayment{}}
+	handler := NewVerifyPaymentHandler(mockClient, service.NewVerificationService(), WithPaymentStore(store))
+
+	cmd := VerifyPaymentCommand{
+		TxID:              "0x1234567890abcdef1234567890abcdef1234567890abcdef1234567890abcdef",
+		TokenType:         "STX",
+		ExpectedRecipient: "ST1PQHQKV0RJXZFY1DGX8MNSNYVE3VGZJSRTPGZGM",
+		MinAmount:         "5000000",
+		Network:           "testnet",
+	}
+
+	result, err := handler.Handle(context.Background(), cmd)
+
+	require.NoError(t, err)
+	assert.False(t, result.Valid)
+	assert.Empty(t, store.payments)
+}
@@ -0,0 +1,165 @@
+package command
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/x402stacks/stacks-facilitator/internal/payment/domain/service"
+	"github.com/x402stacks/stacks-facilitator/internal/payment/domain/valueobject"
+)
+
+// AccountState is what an account can spend with its next transaction
+type AccountState struct {
+	NextNonce    uint64             // Next nonce the account can use, counting its transactions in the mempool
+	Balance      valueobject.Amount // STX balance in microSTX
+	TokenBalance valueobject.Amount // Balance of the SIP-010 contract asked about; zero when none was
+}
+
+// AccountStateProvider reads the next nonce and balances of an account
+type AccountStateProvider interface {
+	AccountState(ctx context.Context, address valueobject.StacksAddress, contract *service.TokenContract, network valueobject.Network) (AccountState, error)
+}
+
+// verifySigned checks a signed transaction that has not been broadcast: its signature,
+// network and transfer against the criteria, and that the payer's nonce is current and
+// their balance covers the payment and fee. A transaction already recorded in the payment
+// store, because it was settled, is rejected with ErrPaymentAlreadyUsed; verifying does
+// not consume it, settling does.
+func (h *VerifyPaymentHandler) verifySigned(ctx context.Context, signedTx string, tokenType valueobject.TokenType, network valueobject.Network, criteria service.VerificationCriteria) (VerifyPaymentResult, error) {
+	if h.decoder == nil || h.accounts == nil {
+		return VerifyPaymentResult{}, errors.New("verifying signed transactions is not configured")
+	}
+
+	decoded, verificationResult, err := checkSignedTransaction(h.decoder, h.verificationSvc, signedTx, tokenType, network, criteria)
+	if err != nil {
+		return VerifyPaymentResult{}, err
+	}
+
+	if verificationResult.Valid && h.paymentStore != nil {
+		existing, consumed, err := h.paymentStore.Get(ctx, decoded.TxID.String())
+		if err != nil {
+			return VerifyPaymentResult{}, fmt.Errorf("failed to check payment store: %w", err)
+		}
+		if consumed {
+			return VerifyPaymentResult{}, existing.AlreadyUsedError()
+		}
+	}
+
+	// The payer's account is only worth reading for a transaction that is otherwise acceptable
+	if verificationResult.Valid {
+		state, err := h.accounts.AccountState(ctx, decoded.Sender, criteria.ExpectedContract, network)
+		if err != nil {
+			return VerifyPaymentResult{}, fmt.Errorf("failed to fetch payer account: %w", err)
+		}
+		if failures := accountFailures(decoded, state); len(failures) > 0 {
+			verificationResult.Valid = false
+			verificationResult.Failures = failures
+		}
+	}
+
+	return VerifyPaymentResult{
+		Valid:            verificationResult.Valid,
+		SenderAddress:    decoded.Sender.String(),
+		RecipientAddress: decoded.Recipient.String(),
+		Amount:           decoded.Amount.String(),
+		DisplayAmount:    displayAmount(h.tokenRegistry, decoded.Amount, tokenType),
+		Fee:              feeValue(decoded.Fee),
+		Nonce:            decoded.Nonce,
+		Status:           valueobject.StatusPending.String(),
+		TokenType:        tokenType.String(),
+		Memo:             decoded.Memo,
+		Network:          network.String(),
+		Errors:           verificationResult.Errors(),
+		Failures:         verificationResult.Failures,
+	}, nil
+}
+
+// checkSignedTransaction decodes a transaction that is not on chain yet and checks it
+// against the criteria, ignoring confirmation depth. The sender is the address recovered
+// from the transaction's signatures, so sender checks hold for whoever actually signed;
+// a signature that recovers no matching signer is the first error, ahead of a network
+// mismatch. Only a transaction that cannot be decoded is returned as an error.
+func checkSignedTransaction(decoder TransactionDecoder, svc *service.VerificationService, signedTx string, tokenType valueobject.TokenType, network valueobject.Network, criteria service.VerificationCriteria) (service.BlockchainTransaction, service.VerificationResult, error) {
+	decoded, txNetwork, err := decoder.DecodeTransaction(signedTx, tokenType)
+	if err != nil {
+		return service.BlockchainTransaction{}, service.VerificationResult{}, fmt.Errorf("%w: %v", ErrInvalidSignedTransaction, err)
+	}
+
+	signer, signerErr := decoder.RecoverSigner(signedTx)
+	if signerErr == nil {
+		decoded.Sender = signer
+	}
+
+	criteria.AcceptUnconfirmed = true
+	criteria.MinConfirmations, criteria.MinBurnConfirmations = 0, 0
+
+	result := svc.Verify(decoded, criteria)
+	if txNetwork != network {
+		result.Valid = false
+		result.Failures = append([]service.VerificationFailure{{
+			Code:     service.FailureNetworkMismatch,
+			Expected: network.String(),
+			Actual:   txNetwork.String(),
+			Message:  fmt.Sprintf("network mismatch: expected %s, got %s", network, txNetwork),
+		}}, result.Failures...)
+	}
+	if signerErr != nil {
+		result.Valid = false
+		result.Failures = append([]service.VerificationFailure{{
+			Code:    service.FailureInvalidSignature,
+			Message: signerErr.Error(),
+		}}, result.Failures...)
+	}
+	return decoded, result, nil
+}
+
+// accountFailures checks that the transaction uses the payer's next nonce and that the payer
+// can afford it. The fee of a sponsored transaction is paid by the sponsor, not the payer.
+func accountFailures(tx service.BlockchainTransaction, state AccountState) []service.VerificationFailure {
+	var failures []service.VerificationFailure
+
+	if tx.Nonce != state.NextNonce {
+		failures = append(failures, service.VerificationFailure{
+			Code:     service.FailureNonceMismatch,
+			Expected: strconv.FormatUint(state.NextNonce, 10),
+			Actual:   strconv.FormatUint(tx.Nonce, 10),
+			Message:  fmt.Sprintf("nonce mismatch: account's next nonce is %d, got %d", state.NextNonce, tx.Nonce),
+		})
+	}
+
+	fee := tx.Fee
+	if tx.Sponsored {
+		fee = valueobject.NewAmount(0)
+	}
+
+	if tx.TokenType.IsNative() {
+		total, _ := tx.Amount.Add(fee) // A u64 STX amount plus a u64 fee cannot overflow a u128
+		if !state.Balance.IsGreaterThanOrEqual(total) {
+			failures = append(failures, insufficientBalance(total, state.Balance,
+				fmt.Sprintf("insufficient balance: amount plus fee is %s microSTX, balance is %s", total, state.Balance)))
+		}
+		return failures
+	}
+
+	if !state.TokenBalance.IsGreaterThanOrEqual(tx.Amount) {
+		failures = append(failures, insufficientBalance(tx.Amount, state.TokenBalance,
+			fmt.Sprintf("insufficient balance: amount is %s, %s balance is %s", tx.Amount, tx.TokenType, state.TokenBalance)))
+	}
+	if !state.Balance.IsGreaterThanOrEqual(fee) {
+		failures = append(failures, insufficientBalance(fee, state.Balance,
+			fmt.Sprintf("insufficient balance: fee is %s microSTX, balance is %s", fee, state.Balance)))
+	}
+	return failures
+}
+
+// insufficientBalance reports a balance below what the transaction spends
+func insufficientBalance(needed, balance valueobject.Amount, message string) service.VerificationFailure {
+	return service.VerificationFailure{
+		Code:     service.FailureInsufficientBalance,
+		Expected: needed.String(),
+		Actual:   balance.String(),
+		Message:  message,
+	}
+}
@@ -0,0 +1,320 @@
+package command
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/x402stacks/stacks-facilitator/internal/payment/domain/service"
+	"github.com/x402stacks/stacks-facilitator/internal/payment/domain/valueobject"
+)
+
+// MockAccountState is an AccountStateProvider reporting a fixed state
+type MockAccountState struct {
+	State    AccountState
+	Err      error
+	Address  valueobject.StacksAddress // Account asked about on the last call
+	Contract *service.TokenContract    // Contract asked about on the last call
+	calls    int
+}
+
+func (m *MockAccountState) AccountState(ctx context.Context, address valueobject.StacksAddress, contract *service.TokenContract, network valueobject.Network) (AccountState, error) {
+	m.calls++
+	m.Address = address
+	m.Contract = contract
+	return m.State, m.Err
+}
+
+// unbroadcastTransaction is the mock payment as decoded from a signed transaction
+func unbroadcastTransaction() service.BlockchainTransaction {
+	tx := createMockTransaction()
+	tx.TxID = valueobject.TransactionID{}
+	tx.Status = valueobject.TxStatusPending
+	tx.IsConfirmed = false
+	tx.BlockHeight = 0
+	return tx
+}
+
+func signedVerifyCommand() VerifyPaymentCommand {
+	return VerifyPaymentCommand{
+		SignedTransaction: "0x80800000000400deadbeef",
+		TokenType:         "STX",
+		ExpectedRecipient: "ST1PQHQKV0RJXZFY1DGX8MNSNYVE3VGZJSRTPGZGM",
+		MinAmount:         "500000",
+		Network:           "testnet",
+	}
+}
+
+func newSignedVerifyHandler(tx service.BlockchainTransaction, signatureErr error, accounts *MockAccountState) *VerifyPaymentHandler {
+	decoder := decoderReturning(tx, valueobject.NetworkTestnet)
+	if signatureErr != nil {
+		decoder.RecoverFn = func(signedTx string) (valueobject.StacksAddress, error) {
+			return valueobject.StacksAddress{}, signatureErr
+		}
+	}
+	client := &MockBlockchainClient{
+		GetTransactionFn: func(ctx context.Context, txID valueobject.TransactionID, tokenType valueobject.TokenType, network valueobject.Network) (service.BlockchainTransaction, error) {
+			panic("a signed transaction must not be looked up on chain")
+		},
+	}
+	return NewVerifyPaymentHandler(client, service.NewVerificationService(), WithSignedTransactions(decoder, accounts))
+}
+
+func TestVerifyPaymentHandler_SignedTransaction(t *testing.T) {
+	accounts := &MockAccountState{State: AccountState{NextNonce: 5, Balance: valueobject.NewAmount(1000180)}}
+	handler := newSignedVerifyHandler(unbroadcastTransaction(), nil, accounts)
+
+	result, err := handler.Handle(context.Background(), signedVerifyCommand())
+
+	require.NoError(t, err)
+	assert.True(t, result.Valid, result.Errors)
+	assert.Empty(t, result.TxID)
+	assert.Equal(t, "ST2J6ZY48GV1EZ5V2V5RB9MP66SW86PYKKQYAC0RQ", result.SenderAddress)
+	assert.Equal(t, "1000000", result.Amount)
+	assert.Equal(t, uint64(180), result.Fee)
+	assert.Equal(t, uint64(5), result.Nonce)
+	assert.Equal(t, "pending", result.Status)
+	assert.Equal(t, "testnet", result.Network)
+	assert.Nil(t, accounts.Contract)
+}
+
+func TestVerifyPaymentHandler_SignedTransactionAlreadySettled(t *testing.T) {
+	tx := unbroadcastTransaction()
+	tx.TxID = createMockTransaction().TxID // ID of the transaction as signed
+	accounts := &MockAccountState{State: AccountState{NextNonce: 5, Balance: valueobject.NewAmount(1000180)}}
+	store := &memoryPaymentStore{payments: map[string]ConsumedPayment{}}
+	handler := NewVerifyPaymentHandler(&MockBlockchainClient{}, service.NewVerificationService(),
+		WithSignedTransactions(decoderReturning(tx, valueobject.NetworkTestnet), accounts), WithPaymentStore(store))
+
+	// Verifying does not consume the transaction
+	result, err := handler.Handle(context.Background(), signedVerifyCommand())
+	require.NoError(t, err)
+	assert.True(t, result.Valid, result.Errors)
+	assert.Empty(t, store.payments)
+
+	// Once settled it is no longer a valid payment
+	store.payments[tx.TxID.String()] = ConsumedPayment{TxID: tx.TxID.String(), Network: "testnet", Resource: "/premium"}
+	_, err = handler.Handle(context.Background(), signedVerifyCommand())
+	assert.ErrorIs(t, err, ErrPaymentAlreadyUsed)
+	assert.ErrorContains(t, err, "already accepted for /premium")
+}
+
+func TestVerifyPaymentHandler_SignedTransactionRejected(t *testing.T) {
+	tests := []struct {
+		name         string
+		tamper       func(tx *service.BlockchainTransaction)
+		signatureErr error
+		state        AccountState
+		wantError    string
+		wantFailure  service.VerificationFailure
+	}{
+		{
+			name:         "bad signature",
+			signatureErr: errors.New("invalid signature: signature does not match signer"),
+			wantError:    "invalid signature",
+			wantFailure:  service.VerificationFailure{Code: service.FailureInvalidSignature},
+		},
+		{
+			name: "wrong recipient",
+			tamper: func(tx *service.BlockchainTransaction) {
+				tx.Recipient, _ = valueobject.NewPrincipal("ST2J6ZY48GV1EZ5V2V5RB9MP66SW86PYKKQYAC0RQ")
+			},
+			wantError: "recipient mismatch",
+			wantFailure: service.VerificationFailure{
+				Code:     service.FailureRecipientMismatch,
+				Expected: "ST1PQHQKV0RJXZFY1DGX8MNSNYVE3VGZJSRTPGZGM",
+				Actual:   "ST2J6ZY48GV1EZ5V2V5RB9MP66SW86PYKKQYAC0RQ",
+			},
+		},
+		{
+			name:        "amount too low",
+			tamper:      func(tx *service.BlockchainTransaction) { tx.Amount = valueobject.NewAmount(100) },
+			wantError:   "insufficient amount",
+			wantFailure: service.VerificationFailure{Code: service.FailureInsufficientAmount, Expected: "500000", Actual: "100"},
+		},
+		{
+			name:        "stale nonce",
+			state:       AccountState{NextNonce: 6, Balance: valueobject.NewAmount(5000000)},
+			wantError:   "nonce mismatch: account's next nonce is 6, got 5",
+			wantFailure: service.VerificationFailure{Code: service.FailureNonceMismatch, Expected: "6", Actual: "5"},
+		},
+		{
+			name:        "balance covers amount but not fee",
+			state:       AccountState{NextNonce: 5, Balance: valueobject.NewAmount(1000100)},
+			wantError:   "insufficient balance: amount plus fee is 1000180 microSTX, balance is 1000100",
+			wantFailure: service.VerificationFailure{Code: service.FailureInsufficientBalance, Expected: "1000180", Actual: "1000100"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tx := unbroadcastTransaction()
+			if tt.tamper != nil {
+				tt.tamper(&tx)
+			}
+			state := tt.state
+			if state == (AccountState{}) {
+				state = AccountState{NextNonce: 5, Balance: valueobject.NewAmount(5000000)}
+			}
+			accounts := &MockAccountState{State: state}
+
+			result, err := newSignedVerifyHandler(tx, tt.signatureErr, accounts).Handle(context.Background(), signedVerifyCommand())
+
+			require.NoError(t, err)
+			assert.False(t, result.Valid)
+			require.NotEmpty(t, result.Errors)
+			assert.Contains(t, result.Errors[0], tt.wantError)
+			require.Len(t, result.Failures, len(result.Errors))
+			failure := result.Failures[0]
+			assert.Equal(t, result.Errors[0], failure.Message)
+			failure.Message = ""
+			assert.Equal(t, tt.wantFailure, failure)
+		})
+	}
+}
+
+func TestVerifyPaymentHandler_SignedTransactionRecoveredSigner(t *testing.T) {
+	signer, _ := valueobject.NewStacksAddress("ST3T6ZY48GV1EZ5V2V5RB9MP66SW86PYKKMKH9H62")
+	accounts := &MockAccountState{State: AccountState{NextNonce: 5, Balance: valueobject.NewAmount(5000000)}}
+	handler := newSignedVerifyHandler(unbroadcastTransaction(), nil, accounts)
+	handler.decoder.(*MockDecoder).RecoverFn = func(signedTx string) (valueobject.StacksAddress, error) {
+		return signer, nil
+	}
+
+	t.Run("expected sender is the signer", func(t *testing.T) {
+		cmd := signedVerifyCommand()
+		expectedSender := signer.String()
+		cmd.ExpectedSender = &expectedSender
+
+		result, err := handler.Handle(context.Background(), cmd)
+
+		require.NoError(t, err)
+		assert.True(t, result.Valid, result.Errors)
+		assert.Equal(t, signer.String(), result.SenderAddress)
+		assert.Equal(t, signer, accounts.Address)
+	})
+
+	t.Run("expected sender is the decoded origin", func(t *testing.T) {
+		cmd := signedVerifyCommand()
+		expectedSender := "ST2J6ZY48GV1EZ5V2V5RB9MP66SW86PYKKQYAC0RQ"
+		cmd.ExpectedSender = &expectedSender
+
+		result, err := handler.Handle(context.Background(), cmd)
+
+		require.NoError(t, err)
+		assert.False(t, result.Valid)
+		require.NotEmpty(t, result.Errors)
+		assert.Contains(t, result.Errors[0], "sender mismatch")
+	})
+}
+
+func TestVerifyPaymentHandler_SignedTransactionNetworkMismatch(t *testing.T) {
+	decoder := decoderReturning(unbroadcastTransaction(), valueobject.NetworkMainnet)
+	accounts := &MockAccountState{}
+	handler := NewVerifyPaymentHandler(&MockBlockchainClient{}, service.NewVerificationService(), WithSignedTransactions(decoder, accounts))
+
+	result, err := handler.Handle(context.Background(), signedVerifyCommand())
+
+	require.NoError(t, err)
+	assert.False(t, result.Valid)
+	assert.Equal(t, "network mismatch: expected testnet, got mainnet", result.Errors[0])
+	assert.Equal(t, service.VerificationFailure{
+		Code:     service.FailureNetworkMismatch,
+		Expected: "testnet",
+		Actual:   "mainnet",
+		Message:  "network mismatch: expected testnet, got mainnet",
+	}, result.Failures[0])
+	assert.Zero(t, accounts.calls, "the payer account is not read for a transaction that already failed")
+}
+
+func TestVerifyPaymentHandler_SignedSIP010Transaction(t *testing.T) {
+	tx := unbroadcastTransaction()
+	tx.TokenType = valueobject.TokenSBTC
+	tx.ContractID = "ST1F7QA2MDF17S807EPA36TSS8AMEFY4KA9TVGWXT.sbtc-token"
+	tx.Amount = valueobject.NewAmount(2500)
+	cmd := signedVerifyCommand()
+	cmd.TokenType = "SBTC"
+	cmd.MinAmount = "2500"
+
+	tests := []struct {
+		name      string
+		tx        service.BlockchainTransaction
+		state     AccountState
+		wantError string
+	}{
+		{
+			name:  "funded",
+			tx:    tx,
+			state: AccountState{NextNonce: 5, Balance: valueobject.NewAmount(180), TokenBalance: valueobject.NewAmount(2500)},
+		},
+		{
+			name:      "token balance too low",
+			tx:        tx,
+			state:     AccountState{NextNonce: 5, Balance: valueobject.NewAmount(180), TokenBalance: valueobject.NewAmount(2499)},
+			wantError: "insufficient balance: amount is 2500, SBTC balance is 2499",
+		},
+		{
+			name:      "no STX for the fee",
+			tx:        tx,
+			state:     AccountState{NextNonce: 5, TokenBalance: valueobject.NewAmount(2500)},
+			wantError: "insufficient balance: fee is 180 microSTX, balance is 0",
+		},
+		{
+			name: "sponsored needs no STX",
+			tx: func() service.BlockchainTransaction {
+				sponsored := tx
+				sponsored.Sponsored = true
+				return sponsored
+			}(),
+			state: AccountState{NextNonce: 5, TokenBalance: valueobject.NewAmount(2500)},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			accounts := &MockAccountState{State: tt.state}
+
+			result, err := newSignedVerifyHandler(tt.tx, nil, accounts).Handle(context.Background(), cmd)
+
+			require.NoError(t, err)
+			require.NotNil(t, accounts.Contract)
+			assert.Equal(t, tx.ContractID, accounts.Contract.ContractID)
+			if tt.wantError == "" {
+				assert.True(t, result.Valid, result.Errors)
+				return
+			}
+			assert.False(t, result.Valid)
+			assert.Equal(t, []string{tt.wantError}, result.Errors)
+		})
+	}
+}
+
+func TestVerifyPaymentHandler_SignedTransactionErrors(t *testing.T) {
+	t.Run("undecodable", func(t *testing.T) {
+		decoder := &MockDecoder{
+			DecodeFn: func(signedTx string, tokenType valueobject.TokenType) (service.BlockchainTransaction, valueobject.Network, error) {
+				return service.BlockchainTransaction{}, "", errors.New("malformed transaction")
+			},
+		}
+		handler := NewVerifyPaymentHandler(&MockBlockchainClient{}, service.NewVerificationService(), WithSignedTransactions(decoder, &MockAccountState{}))
+
+		_, err := handler.Handle(context.Background(), signedVerifyCommand())
+		assert.ErrorIs(t, err, ErrInvalidSignedTransaction)
+	})
+
+	t.Run("account unavailable", func(t *testing.T) {
+		accounts := &MockAccountState{Err: errors.New("node unavailable")}
+
+		_, err := newSignedVerifyHandler(unbroadcastTransaction(), nil, accounts).Handle(context.Background(), signedVerifyCommand())
+		assert.ErrorContains(t, err, "node unavailable")
+	})
+
+	t.Run("not configured", func(t *testing.T) {
+		handler := NewVerifyPaymentHandler(&MockBlockchainClient{}, service.NewVerificationService())
+
+		_, err := handler.Handle(context.Background(), signedVerifyCommand())
+		assert.ErrorContains(t, err, "not configured")
+	})
+}
@@ -0,0 +1,25 @@
+package service
+
+// ChainTip is the latest Stacks block and Bitcoin burn block seen on a network
+type ChainTip struct {
+	StacksHeight uint64
+	BurnHeight   uint64
+}
+
+// Confirmations returns how many Stacks blocks and Bitcoin burn blocks deep a transaction is,
+// counting the block that holds it. An unconfirmed transaction has none; a confirmed one
+// has at least one even when the tip lags behind the API that reported the transaction.
+func (t ChainTip) Confirmations(tx BlockchainTransaction) (stacks uint64, burn uint64) {
+	if !tx.IsConfirmed {
+		return 0, 0
+	}
+	return depth(t.StacksHeight, tx.BlockHeight), depth(t.BurnHeight, tx.BurnBlockHeight)
+}
+
+// depth returns tip - height + 1, and 1 when the tip is not past height
+func depth(tip, height uint64) uint64 {
+	if tip <= height {
+		return 1
+	}
+	return tip - height + 1
+}
@@ -0,0 +1,42 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestChainTip_Confirmations(t *testing.T) {
+	tip := ChainTip{StacksHeight: 1005, BurnHeight: 880002}
+	tx := BlockchainTransaction{BlockHeight: 1000, BurnBlockHeight: 880000, IsConfirmed: true}
+
+	stacks, burn := tip.Confirmations(tx)
+
+	assert.Equal(t, uint64(6), stacks)
+	assert.Equal(t, uint64(3), burn)
+}
+
+func TestChainTip_ConfirmationsInTipBlock(t *testing.T) {
+	tip := ChainTip{StacksHeight: 1000, BurnHeight: 880000}
+
+	stacks, burn := tip.Confirmations(BlockchainTransaction{BlockHeight: 1000, BurnBlockHeight: 880000, IsConfirmed: true})
+
+	assert.Equal(t, uint64(1), stacks)
+	assert.Equal(t, uint64(1), burn)
+}
+
+func TestChainTip_ConfirmationsWithLaggingTip(t *testing.T) {
+	stacks, burn := ChainTip{}.Confirmations(BlockchainTransaction{BlockHeight: 1000, BurnBlockHeight: 880000, IsConfirmed: true})
+
+	assert.Equal(t, uint64(1), stacks)
+	assert.Equal(t, uint64(1), burn)
+}
+
+func TestChainTip_ConfirmationsUnconfirmed(t *testing.T) {
+	tip := ChainTip{StacksHeight: 1005, BurnHeight: 880002}
+
+	stacks, burn := tip.Confirmations(BlockchainTransaction{Status: "pending"})
+
+	assert.Zero(t, stacks)
+	assert.Zero(t, burn)
+}
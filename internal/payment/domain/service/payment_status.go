@@ -0,0 +1,19 @@
+package service
+
+import "github.com/x402stacks/stacks-facilitator/internal/payment/domain/valueobject"
+
+// PaymentStatusOf places a blockchain transaction in the payment lifecycle: confirmed once
+// mined successfully, failed if mined but aborted, dropped if evicted from the mempool,
+// and pending otherwise
+func PaymentStatusOf(tx BlockchainTransaction) valueobject.PaymentStatus {
+	switch {
+	case tx.IsConfirmed:
+		return valueobject.StatusConfirmed
+	case tx.Status.IsFailed():
+		return valueobject.StatusFailed
+	case tx.Status.IsDropped():
+		return valueobject.StatusDropped
+	default:
+		return valueobject.StatusPending
+	}
+}
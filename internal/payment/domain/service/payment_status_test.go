@@ -0,0 +1,31 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/x402stacks/stacks-facilitator/internal/payment/domain/valueobject"
+)
+
+func TestPaymentStatusOf(t *testing.T) {
+	tests := []struct {
+		name        string
+		status      valueobject.TransactionStatus
+		isConfirmed bool
+		expected    valueobject.PaymentStatus
+	}{
+		{"mempool", valueobject.TxStatusPending, false, valueobject.StatusPending},
+		{"confirmed", valueobject.TxStatusSuccess, true, valueobject.StatusConfirmed},
+		{"success not yet anchored", valueobject.TxStatusSuccess, false, valueobject.StatusPending},
+		{"aborted", valueobject.TxStatusAbortByPostCondition, false, valueobject.StatusFailed},
+		{"replaced", valueobject.TxStatusDroppedReplaceByFee, false, valueobject.StatusDropped},
+		{"garbage collected", valueobject.TxStatusDroppedStaleGarbageCollect, false, valueobject.StatusDropped},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tx := BlockchainTransaction{Status: tt.status, IsConfirmed: tt.isConfirmed}
+			assert.Equal(t, tt.expected, PaymentStatusOf(tx))
+		})
+	}
+}
@@ -0,0 +1,75 @@
+package service
+
+import (
+	"fmt"
+
+	"github.com/x402stacks/stacks-facilitator/internal/payment/domain/valueobject"
+)
+
+// SponsorPolicy limits which sponsored transactions the facilitator will pay the fee for
+type SponsorPolicy struct {
+	MaxFee           uint64                  // Upper bound on the fee paid per transaction, in microSTX
+	AllowedTokens    []valueobject.TokenType // Tokens that may be sponsored; any when empty
+	AllowedContracts []string                // Contracts that may be called; any when empty
+}
+
+// Check returns the reasons a transaction may not be sponsored, or nil if it may
+func (p SponsorPolicy) Check(tx BlockchainTransaction) []VerificationFailure {
+	var failures []VerificationFailure
+
+	if !tx.Sponsored {
+		failures = append(failures, VerificationFailure{
+			Code:    FailureSponsorPolicy,
+			Message: "sponsor policy: transaction is not sponsored",
+		})
+	}
+
+	if len(p.AllowedTokens) > 0 && !containsToken(p.AllowedTokens, tx.TokenType) {
+		failures = append(failures, VerificationFailure{
+			Code:    FailureSponsorPolicy,
+			Actual:  tx.TokenType.String(),
+			Message: fmt.Sprintf("sponsor policy: token %s is not sponsored", tx.TokenType),
+		})
+	}
+
+	if tx.ContractID != "" && len(p.AllowedContracts) > 0 && !containsString(p.AllowedContracts, tx.ContractID) {
+		failures = append(failures, VerificationFailure{
+			Code:    FailureSponsorPolicy,
+			Actual:  tx.ContractID,
+			Message: fmt.Sprintf("sponsor policy: contract %s is not sponsored", tx.ContractID),
+		})
+	}
+
+	return failures
+}
+
+// CheckFee returns the reason a sponsor fee may not be paid, or nil if it may
+func (p SponsorPolicy) CheckFee(fee uint64) []VerificationFailure {
+	if p.MaxFee == 0 || fee <= p.MaxFee {
+		return nil
+	}
+	return []VerificationFailure{{
+		Code:     FailureSponsorPolicy,
+		Expected: fmt.Sprintf("%d", p.MaxFee),
+		Actual:   fmt.Sprintf("%d", fee),
+		Message:  fmt.Sprintf("sponsor policy: fee %d microSTX exceeds the sponsor's max fee of %d", fee, p.MaxFee),
+	}}
+}
+
+func containsToken(tokens []valueobject.TokenType, tokenType valueobject.TokenType) bool {
+	for _, t := range tokens {
+		if t == tokenType {
+			return true
+		}
+	}
+	return false
+}
+
+func containsString(values []string, s string) bool {
+	for _, v := range values {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
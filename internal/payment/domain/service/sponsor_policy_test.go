@@ -0,0 +1,80 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/x402stacks/stacks-facilitator/internal/payment/domain/valueobject"
+)
+
+func TestSponsorPolicy_Check(t *testing.T) {
+	usdcx := "ST1PQHQKV0RJXZFY1DGX8MNSNYVE3VGZJSRTPGZGM.usdcx"
+
+	tests := []struct {
+		name    string
+		policy  SponsorPolicy
+		tx      BlockchainTransaction
+		wantErr string
+	}{
+		{
+			name:   "open policy",
+			policy: SponsorPolicy{},
+			tx:     BlockchainTransaction{TokenType: valueobject.TokenSTX, Sponsored: true},
+		},
+		{
+			name:   "allowed token and contract",
+			policy: SponsorPolicy{AllowedTokens: []valueobject.TokenType{valueobject.TokenUSDCX}, AllowedContracts: []string{usdcx}},
+			tx:     BlockchainTransaction{TokenType: valueobject.TokenUSDCX, ContractID: usdcx, Sponsored: true},
+		},
+		{
+			name:    "not sponsored",
+			policy:  SponsorPolicy{},
+			tx:      BlockchainTransaction{TokenType: valueobject.TokenSTX},
+			wantErr: "sponsor policy: transaction is not sponsored",
+		},
+		{
+			name:    "token not allowed",
+			policy:  SponsorPolicy{AllowedTokens: []valueobject.TokenType{valueobject.TokenSBTC}},
+			tx:      BlockchainTransaction{TokenType: valueobject.TokenSTX, Sponsored: true},
+			wantErr: "sponsor policy: token STX is not sponsored",
+		},
+		{
+			name:    "contract not allowed",
+			policy:  SponsorPolicy{AllowedContracts: []string{"ST1PQHQKV0RJXZFY1DGX8MNSNYVE3VGZJSRTPGZGM.other"}},
+			tx:      BlockchainTransaction{TokenType: valueobject.TokenUSDCX, ContractID: usdcx, Sponsored: true},
+			wantErr: "sponsor policy: contract " + usdcx + " is not sponsored",
+		},
+		{
+			name:   "contract list ignores native transfers",
+			policy: SponsorPolicy{AllowedContracts: []string{usdcx}},
+			tx:     BlockchainTransaction{TokenType: valueobject.TokenSTX, Sponsored: true},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			failures := tt.policy.Check(tt.tx)
+			if tt.wantErr == "" {
+				assert.Empty(t, failures)
+				return
+			}
+			assert.Equal(t, []string{tt.wantErr}, FailureMessages(failures))
+			assert.Equal(t, FailureSponsorPolicy, failures[0].Code)
+		})
+	}
+}
+
+func TestSponsorPolicy_CheckFee(t *testing.T) {
+	policy := SponsorPolicy{MaxFee: 5000}
+
+	assert.Empty(t, policy.CheckFee(5000))
+	assert.Empty(t, SponsorPolicy{}.CheckFee(1000000), "no max fee")
+
+	failures := policy.CheckFee(28300)
+	require.Len(t, failures, 1)
+	assert.Equal(t, FailureSponsorPolicy, failures[0].Code)
+	assert.Equal(t, "5000", failures[0].Expected)
+	assert.Equal(t, "28300", failures[0].Actual)
+	assert.Equal(t, "sponsor policy: fee 28300 microSTX exceeds the sponsor's max fee of 5000", failures[0].Message)
+}
@@ -0,0 +1,140 @@
+package service
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/x402stacks/stacks-facilitator/internal/payment/domain/valueobject"
+)
+
+// TokenContract identifies the SIP-010 contract that backs a token on one network
+type TokenContract struct {
+	ContractID string // Contract principal, e.g. SM3VDXK3WZZSA84XXFKAFAF15NNZX32CTSG82JFQ4.sbtc-token
+	AssetName  string // Fungible token name declared by define-fungible-token
+}
+
+// AssetIdentifier returns the fully qualified asset identifier (contract::asset)
+func (c TokenContract) AssetIdentifier() string {
+	return fmt.Sprintf("%s::%s", c.ContractID, c.AssetName)
+}
+
+// TokenMetadata describes how a token's base units are displayed
+type TokenMetadata struct {
+	Symbol   string // Display symbol, e.g. sBTC
+	Decimals int    // Number of decimal places in one whole token
+}
+
+// FormatAmount returns the amount in whole tokens followed by the symbol (e.g. "0.0001 sBTC")
+func (m TokenMetadata) FormatAmount(amount valueobject.Amount) string {
+	return amount.Format(m.Decimals) + " " + m.Symbol
+}
+
+// TokenRegistry maps token types to their canonical contracts per network
+// and to their display metadata
+type TokenRegistry struct {
+	contracts map[valueobject.Network]map[valueobject.TokenType]TokenContract
+	metadata  map[valueobject.TokenType]TokenMetadata
+}
+
+// NewTokenRegistry creates a TokenRegistry that knows only native STX
+func NewTokenRegistry() *TokenRegistry {
+	return &TokenRegistry{
+		contracts: make(map[valueobject.Network]map[valueobject.TokenType]TokenContract),
+		metadata: map[valueobject.TokenType]TokenMetadata{
+			valueobject.TokenSTX: {Symbol: "STX", Decimals: 6},
+		},
+	}
+}
+
+// DefaultTokenRegistry returns a registry with the canonical sBTC and USDCx contracts
+func DefaultTokenRegistry() *TokenRegistry {
+	r := NewTokenRegistry()
+
+	r.SetMetadata(valueobject.TokenSBTC, TokenMetadata{Symbol: "sBTC", Decimals: 8})
+	r.SetMetadata(valueobject.TokenUSDCX, TokenMetadata{Symbol: "USDCx", Decimals: 6})
+
+	r.Register(valueobject.NetworkMainnet, valueobject.TokenSBTC, TokenContract{
+		ContractID: "SM3VDXK3WZZSA84XXFKAFAF15NNZX32CTSG82JFQ4.sbtc-token",
+		AssetName:  "sbtc-token",
+	})
+	r.Register(valueobject.NetworkTestnet, valueobject.TokenSBTC, TokenContract{
+		ContractID: "ST1F7QA2MDF17S807EPA36TSS8AMEFY4KA9TVGWXT.sbtc-token",
+		AssetName:  "sbtc-token",
+	})
+	r.Register(valueobject.NetworkMainnet, valueobject.TokenUSDCX, TokenContract{
+		ContractID: "SP120SBRBQJ00MCWS7TM5R8WJNTTKD5K0HFRC2CNE.usdcx",
+		AssetName:  "usdcx-token",
+	})
+	r.Register(valueobject.NetworkTestnet, valueobject.TokenUSDCX, TokenContract{
+		ContractID: "ST1PQHQKV0RJXZFY1DGX8MNSNYVE3VGZJSRTPGZGM.usdcx",
+		AssetName:  "usdcx-token",
+	})
+
+	return r
+}
+
+// Register binds a token type to a contract on a network, replacing any previous binding
+func (r *TokenRegistry) Register(network valueobject.Network, tokenType valueobject.TokenType, contract TokenContract) {
+	if r.contracts[network] == nil {
+		r.contracts[network] = make(map[valueobject.TokenType]TokenContract)
+	}
+	r.contracts[network][tokenType] = contract
+}
+
+// SetMetadata sets the display metadata for a token type, replacing any previous metadata
+func (r *TokenRegistry) SetMetadata(tokenType valueobject.TokenType, metadata TokenMetadata) {
+	r.metadata[tokenType] = metadata
+}
+
+// Metadata returns the display metadata for a token type. Tokens without
+// metadata are shown in base units under their token type.
+func (r *TokenRegistry) Metadata(tokenType valueobject.TokenType) (TokenMetadata, bool) {
+	metadata, ok := r.metadata[tokenType]
+	if !ok {
+		return TokenMetadata{Symbol: tokenType.String()}, false
+	}
+	return metadata, true
+}
+
+// TokenTypes returns STX followed by every token type with a contract on any network, sorted
+func (r *TokenRegistry) TokenTypes() []valueobject.TokenType {
+	seen := make(map[valueobject.TokenType]bool)
+	var tokenTypes []valueobject.TokenType
+	for _, contracts := range r.contracts {
+		for tokenType := range contracts {
+			if !seen[tokenType] {
+				seen[tokenType] = true
+				tokenTypes = append(tokenTypes, tokenType)
+			}
+		}
+	}
+	sort.Slice(tokenTypes, func(i, j int) bool { return tokenTypes[i] < tokenTypes[j] })
+
+	return append([]valueobject.TokenType{valueobject.TokenSTX}, tokenTypes...)
+}
+
+// Contract returns the contract registered for a token type on a network
+func (r *TokenRegistry) Contract(tokenType valueobject.TokenType, network valueobject.Network) (TokenContract, bool) {
+	contract, ok := r.contracts[network][tokenType]
+	return contract, ok
+}
+
+// IsSIP010 reports whether a token type is a SIP-010 token, that is has a contract on any network
+func (r *TokenRegistry) IsSIP010(tokenType valueobject.TokenType) bool {
+	for _, contracts := range r.contracts {
+		if _, ok := contracts[tokenType]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+// TokenTypeForContract returns the token type bound to a contract on a network
+func (r *TokenRegistry) TokenTypeForContract(contractID string, network valueobject.Network) (valueobject.TokenType, bool) {
+	for tokenType, contract := range r.contracts[network] {
+		if contract.ContractID == contractID {
+			return tokenType, true
+		}
+	}
+	return "", false
+}
@@ -0,0 +1,96 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/x402stacks/stacks-facilitator/internal/payment/domain/valueobject"
+)
+
+func TestDefaultTokenRegistry_SBTC(t *testing.T) {
+	r := DefaultTokenRegistry()
+
+	mainnet, ok := r.Contract(valueobject.TokenSBTC, valueobject.NetworkMainnet)
+	assert.True(t, ok)
+	assert.Equal(t, "SM3VDXK3WZZSA84XXFKAFAF15NNZX32CTSG82JFQ4.sbtc-token", mainnet.ContractID)
+	assert.Equal(t, "SM3VDXK3WZZSA84XXFKAFAF15NNZX32CTSG82JFQ4.sbtc-token::sbtc-token", mainnet.AssetIdentifier())
+
+	testnet, ok := r.Contract(valueobject.TokenSBTC, valueobject.NetworkTestnet)
+	assert.True(t, ok)
+	assert.NotEqual(t, mainnet.ContractID, testnet.ContractID)
+}
+
+func TestDefaultTokenRegistry_NoContractForSTX(t *testing.T) {
+	r := DefaultTokenRegistry()
+
+	_, ok := r.Contract(valueobject.TokenSTX, valueobject.NetworkMainnet)
+
+	assert.False(t, ok)
+}
+
+func TestTokenRegistry_RegisterOverrides(t *testing.T) {
+	r := DefaultTokenRegistry()
+	custom := TokenContract{ContractID: "ST1PQHQKV0RJXZFY1DGX8MNSNYVE3VGZJSRTPGZGM.my-sbtc", AssetName: "sbtc"}
+
+	r.Register(valueobject.NetworkTestnet, valueobject.TokenSBTC, custom)
+
+	got, ok := r.Contract(valueobject.TokenSBTC, valueobject.NetworkTestnet)
+	assert.True(t, ok)
+	assert.Equal(t, custom, got)
+}
+
+func TestTokenRegistry_TokenTypeForContract(t *testing.T) {
+	r := DefaultTokenRegistry()
+
+	tokenType, ok := r.TokenTypeForContract("SP120SBRBQJ00MCWS7TM5R8WJNTTKD5K0HFRC2CNE.usdcx", valueobject.NetworkMainnet)
+	assert.True(t, ok)
+	assert.Equal(t, valueobject.TokenUSDCX, tokenType)
+
+	_, ok = r.TokenTypeForContract("SP120SBRBQJ00MCWS7TM5R8WJNTTKD5K0HFRC2CNE.usdcx", valueobject.NetworkTestnet)
+	assert.False(t, ok)
+}
+
+func TestTokenRegistry_IsSIP010(t *testing.T) {
+	r := DefaultTokenRegistry()
+	alex := valueobject.TokenType("ALEX")
+
+	assert.True(t, r.IsSIP010(valueobject.TokenSBTC))
+	assert.False(t, r.IsSIP010(valueobject.TokenSTX))
+	assert.False(t, r.IsSIP010(alex), "a well-formed token type is not SIP-010 until registered")
+
+	r.Register(valueobject.NetworkMainnet, alex, TokenContract{ContractID: "SP2J6ZY48GV1EZ5V2V5RB9MP66SW86PYKKNRV9EJ7.token-alex", AssetName: "alex"})
+	assert.True(t, r.IsSIP010(alex))
+}
+
+func TestTokenRegistry_Metadata(t *testing.T) {
+	r := DefaultTokenRegistry()
+
+	stx, ok := r.Metadata(valueobject.TokenSTX)
+	assert.True(t, ok)
+	assert.Equal(t, "1.5 STX", stx.FormatAmount(valueobject.NewAmount(1500000)))
+
+	sbtc, ok := r.Metadata(valueobject.TokenSBTC)
+	assert.True(t, ok)
+	assert.Equal(t, "0.0001 sBTC", sbtc.FormatAmount(valueobject.NewAmount(10000)))
+
+	usdcx, ok := r.Metadata(valueobject.TokenUSDCX)
+	assert.True(t, ok)
+	assert.Equal(t, "2.5 USDCx", usdcx.FormatAmount(valueobject.NewAmount(2500000)))
+
+	// Unknown tokens fall back to base units under the token type
+	unknown, ok := r.Metadata(valueobject.TokenType("ALEX"))
+	assert.False(t, ok)
+	assert.Equal(t, "42 ALEX", unknown.FormatAmount(valueobject.NewAmount(42)))
+}
+
+func TestTokenRegistry_TokenTypes(t *testing.T) {
+	r := DefaultTokenRegistry()
+	alex := valueobject.TokenType("ALEX")
+	r.Register(valueobject.NetworkMainnet, alex, TokenContract{
+		ContractID: "SP3K8BC0PPEVCV7NZ6QSRWPQ2JE9E5B6N3PA0KBR9.token-alex",
+		AssetName:  "alex",
+	})
+
+	assert.Equal(t, []valueobject.TokenType{valueobject.TokenSTX, alex, valueobject.TokenSBTC, valueobject.TokenUSDCX}, r.TokenTypes())
+	assert.Equal(t, []valueobject.TokenType{valueobject.TokenSTX}, NewTokenRegistry().TokenTypes())
+}
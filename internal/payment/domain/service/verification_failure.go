@@ -0,0 +1,48 @@
+package service
+
+// FailureCode identifies why a transaction failed verification, independently of the message wording
+type FailureCode string
+
+const (
+	FailureTxFailed                      FailureCode = "tx_failed"
+	FailureTxDropped                     FailureCode = "tx_dropped"
+	FailureNotConfirmed                  FailureCode = "not_confirmed"
+	FailureInsufficientConfirmations     FailureCode = "insufficient_confirmations"
+	FailureInsufficientBurnConfirmations FailureCode = "insufficient_burn_confirmations"
+	FailureTokenMismatch                 FailureCode = "token_mismatch"
+	FailureTokenContractMismatch         FailureCode = "token_contract_mismatch"
+	FailureRecipientMismatch             FailureCode = "recipient_mismatch"
+	FailureInsufficientAmount            FailureCode = "insufficient_amount"
+	FailureSenderMismatch                FailureCode = "sender_mismatch"
+	FailureMemoMismatch                  FailureCode = "memo_mismatch"
+	FailureNetworkMismatch               FailureCode = "network_mismatch"
+	FailureInvalidSignature              FailureCode = "invalid_signature"
+	FailureNonceMismatch                 FailureCode = "nonce_mismatch"
+	FailureInsufficientBalance           FailureCode = "insufficient_balance"
+	FailureSponsorPolicy                 FailureCode = "sponsor_policy"
+)
+
+// String returns the code
+func (c FailureCode) String() string {
+	return string(c)
+}
+
+// VerificationFailure is one reason a transaction failed verification
+type VerificationFailure struct {
+	Code     FailureCode
+	Expected string // Value the criteria required; empty when the check has none
+	Actual   string // Value the transaction or account had; empty when there is none
+	Message  string // Human-readable explanation; its wording may change, the code does not
+}
+
+// FailureMessages returns the message of each failure, in order
+func FailureMessages(failures []VerificationFailure) []string {
+	if len(failures) == 0 {
+		return nil
+	}
+	messages := make([]string, len(failures))
+	for i, f := range failures {
+		messages[i] = f.Message
+	}
+	return messages
+}
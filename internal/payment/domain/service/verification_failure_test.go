@@ -0,0 +1,15 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFailureMessages(t *testing.T) {
+	assert.Nil(t, FailureMessages(nil))
+	assert.Equal(t, []string{"transaction not confirmed", "memo mismatch: expected a, got b"}, FailureMessages([]VerificationFailure{
+		{Code: FailureNotConfirmed, Message: "transaction not confirmed"},
+		{Code: FailureMemoMismatch, Expected: "a", Actual: "b", Message: "memo mismatch: expected a, got b"},
+	}))
+}
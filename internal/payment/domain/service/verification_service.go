@@ -2,38 +2,65 @@ package service
 
 import (
 	"fmt"
+	"strconv"
 
 	"github.com/x402stacks/stacks-facilitator/internal/payment/domain/valueobject"
 )
 
 // BlockchainTransaction represents a transaction fetched from the blockchain
 type BlockchainTransaction struct {
-	TxID        valueobject.TransactionID
-	TokenType   valueobject.TokenType
-	Sender      valueobject.StacksAddress
-	Recipient   valueobject.StacksAddress
-	Amount      valueobject.Amount
-	Fee         valueobject.Amount
-	Nonce       uint64
-	BlockHeight uint64
-	Memo        string
-	Status      string
-	IsConfirmed bool
+	TxID              valueobject.TransactionID
+	TokenType         valueobject.TokenType
+	Sender            valueobject.StacksAddress
+	Recipient         valueobject.Principal
+	ContractID        string // Contract called for SIP-010 transfers, empty for native token_transfer
+	Amount            valueobject.Amount
+	Fee               valueobject.Amount
+	Nonce             uint64
+	BlockHeight       uint64
+	BurnBlockHeight   uint64 // Bitcoin block the Stacks block was anchored to
+	Memo              string
+	Status            valueobject.TransactionStatus
+	UnknownStatus     string                    // tx_status the API reported when it is not a known one; Status is then pending
+	ReplacedBy        valueobject.TransactionID // Transaction that replaced a dropped one, when known
+	IsConfirmed       bool
+	Confirmations     uint64          // Stacks blocks deep, set from the chain tip; 0 when unconfirmed
+	BurnConfirmations uint64          // Bitcoin burn blocks deep, set from the chain tip; 0 when unconfirmed
+	Sponsored         bool            // Fee is paid by a sponsor rather than the sender
+	Transfers         []AssetTransfer // Transfer events emitted by a confirmed transaction; nil when events are unavailable
+}
+
+// AssetTransfer is a token movement emitted as an event by a confirmed transaction
+type AssetTransfer struct {
+	Asset     string // "STX", or the fungible token's asset identifier (contract::asset)
+	Sender    valueobject.Principal
+	Recipient valueobject.Principal
+	Amount    valueobject.Amount
 }
 
 // VerificationCriteria defines the criteria for validating a transaction
 type VerificationCriteria struct {
-	ExpectedRecipient valueobject.StacksAddress
+	ExpectedRecipient valueobject.Principal
 	MinAmount         valueobject.Amount
 	ExpectedSender    *valueobject.StacksAddress
 	ExpectedMemo      *string
 	AcceptUnconfirmed bool
+	ExpectedToken     valueobject.TokenType // Token the payment must be made in; unchecked when empty
+	ExpectedContract  *TokenContract        // Canonical contract for a SIP-010 ExpectedToken
+
+	MinConfirmations     uint64 // Stacks blocks a confirmed tx must be deep, counting its own block; 0 and 1 accept any confirmed tx
+	MinBurnConfirmations uint64 // Bitcoin burn blocks a confirmed tx must be deep, counting its own
 }
 
 // VerificationResult contains the result of a verification
 type VerificationResult struct {
-	Valid  bool
-	Errors []string
+	Valid    bool
+	Failures []VerificationFailure
+}
+
+// Errors returns the message of each failure, in order
+func (r VerificationResult) Errors() []string {
+	return FailureMessages(r.Failures)
 }
 
 // VerificationService validates blockchain transactions against criteria
@@ -46,59 +73,259 @@ func NewVerificationService() *VerificationService {
 
 // Verify validates a blockchain transaction against the given criteria
 func (s *VerificationService) Verify(tx BlockchainTransaction, criteria VerificationCriteria) VerificationResult {
-	var errors []string
+	var failures []VerificationFailure
 
-	// Check if transaction failed
-	if isFailedStatus(tx.Status) {
-		errors = append(errors, fmt.Sprintf("transaction failed with status: %s", tx.Status))
+	// Check if transaction failed or was dropped from the mempool
+	if tx.Status.IsFailed() {
+		failures = append(failures, VerificationFailure{
+			Code:    FailureTxFailed,
+			Actual:  tx.Status.String(),
+			Message: fmt.Sprintf("transaction failed with status: %s", tx.Status),
+		})
+	}
+	if tx.Status.IsDropped() {
+		failures = append(failures, droppedFailure(tx))
 	}
 
 	// Check confirmation requirement
 	if !criteria.AcceptUnconfirmed && !tx.IsConfirmed {
-		errors = append(errors, "transaction not confirmed")
+		failures = append(failures, VerificationFailure{
+			Code:     FailureNotConfirmed,
+			Expected: valueobject.StatusConfirmed.String(),
+			Actual:   tx.Status.String(),
+			Message:  "transaction not confirmed",
+		})
 	}
 
-	// Check recipient
-	if !tx.Recipient.Equals(criteria.ExpectedRecipient) {
-		errors = append(errors, fmt.Sprintf("recipient mismatch: expected %s, got %s",
-			criteria.ExpectedRecipient.String(), tx.Recipient.String()))
+	// Check confirmation depth
+	if tx.IsConfirmed && tx.Confirmations < criteria.MinConfirmations {
+		failures = append(failures, VerificationFailure{
+			Code:     FailureInsufficientConfirmations,
+			Expected: strconv.FormatUint(criteria.MinConfirmations, 10),
+			Actual:   strconv.FormatUint(tx.Confirmations, 10),
+			Message: fmt.Sprintf("insufficient confirmations: expected at least %d blocks, got %d",
+				criteria.MinConfirmations, tx.Confirmations),
+		})
+	}
+	if tx.IsConfirmed && tx.BurnConfirmations < criteria.MinBurnConfirmations {
+		failures = append(failures, VerificationFailure{
+			Code:     FailureInsufficientBurnConfirmations,
+			Expected: strconv.FormatUint(criteria.MinBurnConfirmations, 10),
+			Actual:   strconv.FormatUint(tx.BurnConfirmations, 10),
+			Message: fmt.Sprintf("insufficient confirmations: expected at least %d burn blocks, got %d",
+				criteria.MinBurnConfirmations, tx.BurnConfirmations),
+		})
 	}
 
-	// Check amount
-	if !tx.Amount.IsGreaterThanOrEqual(criteria.MinAmount) {
-		errors = append(errors, fmt.Sprintf("insufficient amount: expected at least %s, got %s",
-			criteria.MinAmount.String(), tx.Amount.String()))
+	if tx.Transfers != nil {
+		// Check token, recipient and amount against what the transaction actually moved
+		failures = append(failures, verifyTransfers(tx, criteria)...)
+	} else {
+		// Check token
+		failures = append(failures, verifyToken(tx, criteria)...)
+
+		// Check recipient
+		if !tx.Recipient.Equals(criteria.ExpectedRecipient) {
+			failures = append(failures, VerificationFailure{
+				Code:     FailureRecipientMismatch,
+				Expected: criteria.ExpectedRecipient.String(),
+				Actual:   tx.Recipient.String(),
+				Message: fmt.Sprintf("recipient mismatch: expected %s, got %s",
+					criteria.ExpectedRecipient.String(), tx.Recipient.String()),
+			})
+		}
+
+		// Check amount
+		if !tx.Amount.IsGreaterThanOrEqual(criteria.MinAmount) {
+			failures = append(failures, insufficientAmount(criteria.MinAmount, tx.Amount))
+		}
 	}
 
 	// Check optional sender
 	if criteria.ExpectedSender != nil && !tx.Sender.Equals(*criteria.ExpectedSender) {
-		errors = append(errors, fmt.Sprintf("sender mismatch: expected %s, got %s",
-			criteria.ExpectedSender.String(), tx.Sender.String()))
+		failures = append(failures, VerificationFailure{
+			Code:     FailureSenderMismatch,
+			Expected: criteria.ExpectedSender.String(),
+			Actual:   tx.Sender.String(),
+			Message: fmt.Sprintf("sender mismatch: expected %s, got %s",
+				criteria.ExpectedSender.String(), tx.Sender.String()),
+		})
 	}
 
 	// Check optional memo
 	if criteria.ExpectedMemo != nil && tx.Memo != *criteria.ExpectedMemo {
-		errors = append(errors, fmt.Sprintf("memo mismatch: expected %s, got %s",
-			*criteria.ExpectedMemo, tx.Memo))
+		failures = append(failures, VerificationFailure{
+			Code:     FailureMemoMismatch,
+			Expected: *criteria.ExpectedMemo,
+			Actual:   tx.Memo,
+			Message: fmt.Sprintf("memo mismatch: expected %s, got %s",
+				*criteria.ExpectedMemo, tx.Memo),
+		})
 	}
 
 	return VerificationResult{
-		Valid:  len(errors) == 0,
-		Errors: errors,
+		Valid:    len(failures) == 0,
+		Failures: failures,
+	}
+}
+
+// VerifyToken runs only the token check of Verify: against the transfer events of a
+// confirmed transaction, otherwise against the contract it called
+func (s *VerificationService) VerifyToken(tx BlockchainTransaction, token valueobject.TokenType, contract *TokenContract) []VerificationFailure {
+	criteria := VerificationCriteria{ExpectedToken: token, ExpectedContract: contract}
+	if tx.Transfers == nil {
+		return verifyToken(tx, criteria)
+	}
+
+	var failures []VerificationFailure
+	for _, failure := range verifyTransfers(tx, criteria) {
+		if failure.Code == FailureTokenMismatch {
+			failures = append(failures, failure)
+		}
+	}
+	return failures
+}
+
+// verifyToken checks that the transaction moved the requested token through its canonical contract
+func verifyToken(tx BlockchainTransaction, criteria VerificationCriteria) []VerificationFailure {
+	if criteria.ExpectedToken == "" {
+		return nil
+	}
+
+	if criteria.ExpectedToken.IsNative() {
+		if tx.ContractID != "" {
+			return []VerificationFailure{{
+				Code:     FailureTokenMismatch,
+				Expected: valueobject.TokenSTX.String(),
+				Actual:   tx.ContractID,
+				Message:  fmt.Sprintf("token mismatch: expected STX token_transfer, got contract call to %s", tx.ContractID),
+			}}
+		}
+		return nil
+	}
+
+	if tx.ContractID == "" {
+		return []VerificationFailure{{
+			Code:     FailureTokenMismatch,
+			Expected: criteria.ExpectedToken.String(),
+			Actual:   valueobject.TokenSTX.String(),
+			Message:  fmt.Sprintf("token mismatch: expected %s transfer, got STX token_transfer", criteria.ExpectedToken.String()),
+		}}
 	}
+
+	if criteria.ExpectedContract == nil {
+		return []VerificationFailure{unregisteredToken(criteria.ExpectedToken)}
+	}
+
+	if tx.ContractID != criteria.ExpectedContract.ContractID {
+		return []VerificationFailure{{
+			Code:     FailureTokenContractMismatch,
+			Expected: criteria.ExpectedContract.ContractID,
+			Actual:   tx.ContractID,
+			Message: fmt.Sprintf("token contract mismatch: expected %s, got %s",
+				criteria.ExpectedContract.ContractID, tx.ContractID),
+		}}
+	}
+
+	return nil
 }
 
-// isFailedStatus checks if the transaction status indicates failure
-func isFailedStatus(status string) bool {
-	failedStatuses := []string{
-		"failed",
-		"abort_by_response",
-		"abort_by_post_condition",
+// verifyTransfers checks that the transaction's transfer events moved at least the minimum
+// amount of the requested asset to the recipient, whichever contract emitted them
+func verifyTransfers(tx BlockchainTransaction, criteria VerificationCriteria) []VerificationFailure {
+	asset, failures := expectedAsset(criteria)
+	if failures != nil {
+		return failures
 	}
-	for _, s := range failedStatuses {
-		if status == s {
-			return true
+
+	var sawAsset bool
+	var received valueobject.Amount
+	for _, transfer := range tx.Transfers {
+		if asset != "" && transfer.Asset != asset {
+			continue
+		}
+		sawAsset = true
+		// A recipient moving its own funds, such as a vault called by the payer, is not a payment
+		if transfer.Recipient.Equals(criteria.ExpectedRecipient) && !transfer.Sender.Equals(transfer.Recipient) {
+			sum, err := received.Add(transfer.Amount)
+			if err != nil {
+				// The total exceeds uint128, so it covers any minimum
+				return nil
+			}
+			received = sum
 		}
 	}
-	return false
+
+	if !sawAsset {
+		if asset == "" {
+			return []VerificationFailure{{
+				Code:    FailureTokenMismatch,
+				Message: "token mismatch: transaction emitted no transfer events",
+			}}
+		}
+		return []VerificationFailure{{
+			Code:     FailureTokenMismatch,
+			Expected: asset,
+			Message:  fmt.Sprintf("token mismatch: no %s transfer in transaction events", asset),
+		}}
+	}
+	if received.IsZero() {
+		return []VerificationFailure{{
+			Code:     FailureRecipientMismatch,
+			Expected: criteria.ExpectedRecipient.String(),
+			Message: fmt.Sprintf("recipient mismatch: expected %s, no transfer to it in transaction events",
+				criteria.ExpectedRecipient.String()),
+		}}
+	}
+	if !received.IsGreaterThanOrEqual(criteria.MinAmount) {
+		return []VerificationFailure{insufficientAmount(criteria.MinAmount, received)}
+	}
+	return nil
+}
+
+// expectedAsset returns the AssetTransfer.Asset the criteria require, empty when any asset is accepted
+func expectedAsset(criteria VerificationCriteria) (string, []VerificationFailure) {
+	switch {
+	case criteria.ExpectedToken == "":
+		return "", nil
+	case criteria.ExpectedToken.IsNative():
+		return valueobject.TokenSTX.String(), nil
+	case criteria.ExpectedContract == nil:
+		return "", []VerificationFailure{unregisteredToken(criteria.ExpectedToken)}
+	default:
+		return criteria.ExpectedContract.AssetIdentifier(), nil
+	}
+}
+
+// unregisteredToken reports a SIP-010 token with no contract to check the transfer against
+func unregisteredToken(token valueobject.TokenType) VerificationFailure {
+	return VerificationFailure{
+		Code:     FailureTokenMismatch,
+		Expected: token.String(),
+		Message:  fmt.Sprintf("token mismatch: no contract registered for %s", token.String()),
+	}
+}
+
+// insufficientAmount reports a payment below the minimum
+func insufficientAmount(minAmount, amount valueobject.Amount) VerificationFailure {
+	return VerificationFailure{
+		Code:     FailureInsufficientAmount,
+		Expected: minAmount.String(),
+		Actual:   amount.String(),
+		Message: fmt.Sprintf("insufficient amount: expected at least %s, got %s",
+			minAmount.String(), amount.String()),
+	}
+}
+
+// droppedFailure describes a transaction that left the mempool without being mined
+func droppedFailure(tx BlockchainTransaction) VerificationFailure {
+	failure := VerificationFailure{
+		Code:    FailureTxDropped,
+		Actual:  tx.Status.String(),
+		Message: fmt.Sprintf("transaction dropped with status: %s", tx.Status),
+	}
+	if !tx.ReplacedBy.IsZero() {
+		failure.Message = fmt.Sprintf("transaction dropped with status: %s, replaced by %s", tx.Status, tx.ReplacedBy)
+	}
+	return failure
 }
@@ -1,6 +1,7 @@
 package service
 
 import (
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -10,8 +11,8 @@ import (
 
 func createTestTransaction() BlockchainTransaction {
 	txID, _ := valueobject.NewTransactionID("0x1234567890abcdef1234567890abcdef1234567890abcdef1234567890abcdef")
-	sender, _ := valueobject.NewStacksAddress("ST2J6ZY48GV1EZ5V2V5RB9MP66SW86PYKKNRV9EJ7")
-	recipient, _ := valueobject.NewStacksAddress("ST1PQHQKV0RJXZFY1DGX8MNSNYVE3VGZJSRTPGZGM")
+	sender, _ := valueobject.NewStacksAddress("ST2J6ZY48GV1EZ5V2V5RB9MP66SW86PYKKQYAC0RQ")
+	recipient, _ := valueobject.NewPrincipal("ST1PQHQKV0RJXZFY1DGX8MNSNYVE3VGZJSRTPGZGM")
 
 	return BlockchainTransaction{
 		TxID:        txID,
@@ -31,7 +32,7 @@ func createTestTransaction() BlockchainTransaction {
 func TestVerificationService_ValidTransaction(t *testing.T) {
 	svc := NewVerificationService()
 	tx := createTestTransaction()
-	recipient, _ := valueobject.NewStacksAddress("ST1PQHQKV0RJXZFY1DGX8MNSNYVE3VGZJSRTPGZGM")
+	recipient, _ := valueobject.NewPrincipal("ST1PQHQKV0RJXZFY1DGX8MNSNYVE3VGZJSRTPGZGM")
 
 	criteria := VerificationCriteria{
 		ExpectedRecipient: recipient,
@@ -42,13 +43,13 @@ func TestVerificationService_ValidTransaction(t *testing.T) {
 	result := svc.Verify(tx, criteria)
 
 	assert.True(t, result.Valid)
-	assert.Empty(t, result.Errors)
+	assert.Empty(t, result.Errors())
 }
 
 func TestVerificationService_RejectsWrongRecipient(t *testing.T) {
 	svc := NewVerificationService()
 	tx := createTestTransaction()
-	wrongRecipient, _ := valueobject.NewStacksAddress("ST3J6ZY48GV1EZ5V2V5RB9MP66SW86PYKKNRV9EJ8")
+	wrongRecipient, _ := valueobject.NewPrincipal("ST3J6ZY48GV1EZ5V2V5RB9MP66SW86PYKKP6R6Z11")
 
 	criteria := VerificationCriteria{
 		ExpectedRecipient: wrongRecipient,
@@ -59,13 +60,13 @@ func TestVerificationService_RejectsWrongRecipient(t *testing.T) {
 	result := svc.Verify(tx, criteria)
 
 	assert.False(t, result.Valid)
-	assert.Contains(t, result.Errors[0], "recipient mismatch")
+	assert.Contains(t, result.Errors()[0], "recipient mismatch")
 }
 
 func TestVerificationService_RejectsInsufficientAmount(t *testing.T) {
 	svc := NewVerificationService()
 	tx := createTestTransaction()
-	recipient, _ := valueobject.NewStacksAddress("ST1PQHQKV0RJXZFY1DGX8MNSNYVE3VGZJSRTPGZGM")
+	recipient, _ := valueobject.NewPrincipal("ST1PQHQKV0RJXZFY1DGX8MNSNYVE3VGZJSRTPGZGM")
 
 	criteria := VerificationCriteria{
 		ExpectedRecipient: recipient,
@@ -76,7 +77,7 @@ func TestVerificationService_RejectsInsufficientAmount(t *testing.T) {
 	result := svc.Verify(tx, criteria)
 
 	assert.False(t, result.Valid)
-	assert.Contains(t, result.Errors[0], "insufficient amount")
+	assert.Contains(t, result.Errors()[0], "insufficient amount")
 }
 
 func TestVerificationService_RejectsUnconfirmedWhenRequired(t *testing.T) {
@@ -84,7 +85,7 @@ func TestVerificationService_RejectsUnconfirmedWhenRequired(t *testing.T) {
 	tx := createTestTransaction()
 	tx.IsConfirmed = false
 	tx.BlockHeight = 0
-	recipient, _ := valueobject.NewStacksAddress("ST1PQHQKV0RJXZFY1DGX8MNSNYVE3VGZJSRTPGZGM")
+	recipient, _ := valueobject.NewPrincipal("ST1PQHQKV0RJXZFY1DGX8MNSNYVE3VGZJSRTPGZGM")
 
 	criteria := VerificationCriteria{
 		ExpectedRecipient: recipient,
@@ -95,7 +96,7 @@ func TestVerificationService_RejectsUnconfirmedWhenRequired(t *testing.T) {
 	result := svc.Verify(tx, criteria)
 
 	assert.False(t, result.Valid)
-	assert.Contains(t, result.Errors[0], "transaction not confirmed")
+	assert.Contains(t, result.Errors()[0], "transaction not confirmed")
 }
 
 func TestVerificationService_AcceptsUnconfirmedWhenAllowed(t *testing.T) {
@@ -103,7 +104,7 @@ func TestVerificationService_AcceptsUnconfirmedWhenAllowed(t *testing.T) {
 	tx := createTestTransaction()
 	tx.IsConfirmed = false
 	tx.BlockHeight = 0
-	recipient, _ := valueobject.NewStacksAddress("ST1PQHQKV0RJXZFY1DGX8MNSNYVE3VGZJSRTPGZGM")
+	recipient, _ := valueobject.NewPrincipal("ST1PQHQKV0RJXZFY1DGX8MNSNYVE3VGZJSRTPGZGM")
 
 	criteria := VerificationCriteria{
 		ExpectedRecipient: recipient,
@@ -116,11 +117,66 @@ func TestVerificationService_AcceptsUnconfirmedWhenAllowed(t *testing.T) {
 	assert.True(t, result.Valid)
 }
 
+func TestVerificationService_MinConfirmations(t *testing.T) {
+	svc := NewVerificationService()
+	tx := createTestTransaction()
+	tx.Confirmations = 3
+	tx.BurnConfirmations = 1
+	recipient, _ := valueobject.NewPrincipal("ST1PQHQKV0RJXZFY1DGX8MNSNYVE3VGZJSRTPGZGM")
+
+	tests := []struct {
+		name                 string
+		minConfirmations     uint64
+		minBurnConfirmations uint64
+		wantErr              string
+	}{
+		{"deep enough", 3, 1, ""},
+		{"too few stacks blocks", 6, 0, "insufficient confirmations: expected at least 6 blocks, got 3"},
+		{"too few burn blocks", 0, 2, "insufficient confirmations: expected at least 2 burn blocks, got 1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := svc.Verify(tx, VerificationCriteria{
+				ExpectedRecipient:    recipient,
+				MinAmount:            valueobject.NewAmount(500000),
+				MinConfirmations:     tt.minConfirmations,
+				MinBurnConfirmations: tt.minBurnConfirmations,
+			})
+
+			if tt.wantErr == "" {
+				assert.True(t, result.Valid, result.Errors())
+				return
+			}
+			assert.False(t, result.Valid)
+			assert.Equal(t, []string{tt.wantErr}, result.Errors())
+		})
+	}
+}
+
+func TestVerificationService_MinConfirmationsIgnoredBeforeConfirmation(t *testing.T) {
+	svc := NewVerificationService()
+	tx := createTestTransaction()
+	tx.IsConfirmed = false
+	tx.BlockHeight = 0
+	recipient, _ := valueobject.NewPrincipal("ST1PQHQKV0RJXZFY1DGX8MNSNYVE3VGZJSRTPGZGM")
+
+	// A pending transaction checked before broadcast cannot have any depth yet
+	result := svc.Verify(tx, VerificationCriteria{
+		ExpectedRecipient: recipient,
+		MinAmount:         valueobject.NewAmount(500000),
+		AcceptUnconfirmed: true,
+		MinConfirmations:  6,
+	})
+
+	assert.True(t, result.Valid, result.Errors())
+}
+
 func TestVerificationService_RejectsFailedTransaction(t *testing.T) {
 	svc := NewVerificationService()
 	tx := createTestTransaction()
 	tx.Status = "failed"
-	recipient, _ := valueobject.NewStacksAddress("ST1PQHQKV0RJXZFY1DGX8MNSNYVE3VGZJSRTPGZGM")
+	recipient, _ := valueobject.NewPrincipal("ST1PQHQKV0RJXZFY1DGX8MNSNYVE3VGZJSRTPGZGM")
 
 	criteria := VerificationCriteria{
 		ExpectedRecipient: recipient,
@@ -131,14 +187,14 @@ func TestVerificationService_RejectsFailedTransaction(t *testing.T) {
 	result := svc.Verify(tx, criteria)
 
 	assert.False(t, result.Valid)
-	assert.Contains(t, result.Errors[0], "transaction failed")
+	assert.Contains(t, result.Errors()[0], "transaction failed")
 }
 
 func TestVerificationService_RejectsAbortedTransaction(t *testing.T) {
 	svc := NewVerificationService()
 	tx := createTestTransaction()
 	tx.Status = "abort_by_response"
-	recipient, _ := valueobject.NewStacksAddress("ST1PQHQKV0RJXZFY1DGX8MNSNYVE3VGZJSRTPGZGM")
+	recipient, _ := valueobject.NewPrincipal("ST1PQHQKV0RJXZFY1DGX8MNSNYVE3VGZJSRTPGZGM")
 
 	criteria := VerificationCriteria{
 		ExpectedRecipient: recipient,
@@ -149,14 +205,42 @@ func TestVerificationService_RejectsAbortedTransaction(t *testing.T) {
 	result := svc.Verify(tx, criteria)
 
 	assert.False(t, result.Valid)
-	assert.Contains(t, result.Errors[0], "transaction failed")
+	assert.Contains(t, result.Errors()[0], "transaction failed")
+}
+
+func TestVerificationService_RejectsDroppedTransaction(t *testing.T) {
+	svc := NewVerificationService()
+	tx := createTestTransaction()
+	tx.Status = valueobject.TxStatusDroppedReplaceByFee
+	tx.IsConfirmed = false
+	tx.BlockHeight = 0
+	tx.ReplacedBy, _ = valueobject.NewTransactionID("0x" + strings.Repeat("ab", 32))
+	recipient, _ := valueobject.NewPrincipal("ST1PQHQKV0RJXZFY1DGX8MNSNYVE3VGZJSRTPGZGM")
+
+	criteria := VerificationCriteria{
+		ExpectedRecipient: recipient,
+		MinAmount:         valueobject.NewAmount(500000),
+		AcceptUnconfirmed: true,
+	}
+
+	result := svc.Verify(tx, criteria)
+
+	assert.False(t, result.Valid)
+	require.Len(t, result.Errors(), 1)
+	assert.Equal(t, "transaction dropped with status: dropped_replace_by_fee, replaced by 0x"+strings.Repeat("ab", 32), result.Errors()[0])
+
+	tx.Status = valueobject.TxStatusDroppedStaleGarbageCollect
+	tx.ReplacedBy = valueobject.TransactionID{}
+	result = svc.Verify(tx, criteria)
+
+	assert.Equal(t, []string{"transaction dropped with status: dropped_stale_garbage_collect"}, result.Errors())
 }
 
 func TestVerificationService_ValidatesOptionalSender(t *testing.T) {
 	svc := NewVerificationService()
 	tx := createTestTransaction()
-	recipient, _ := valueobject.NewStacksAddress("ST1PQHQKV0RJXZFY1DGX8MNSNYVE3VGZJSRTPGZGM")
-	wrongSender, _ := valueobject.NewStacksAddress("ST9J6ZY48GV1EZ5V2V5RB9MP66SW86PYKKNRV9EJ9")
+	recipient, _ := valueobject.NewPrincipal("ST1PQHQKV0RJXZFY1DGX8MNSNYVE3VGZJSRTPGZGM")
+	wrongSender, _ := valueobject.NewStacksAddress("ST3T6ZY48GV1EZ5V2V5RB9MP66SW86PYKKMKH9H62")
 
 	criteria := VerificationCriteria{
 		ExpectedRecipient: recipient,
@@ -168,13 +252,13 @@ func TestVerificationService_ValidatesOptionalSender(t *testing.T) {
 	result := svc.Verify(tx, criteria)
 
 	assert.False(t, result.Valid)
-	assert.Contains(t, result.Errors[0], "sender mismatch")
+	assert.Contains(t, result.Errors()[0], "sender mismatch")
 }
 
 func TestVerificationService_ValidatesOptionalMemo(t *testing.T) {
 	svc := NewVerificationService()
 	tx := createTestTransaction()
-	recipient, _ := valueobject.NewStacksAddress("ST1PQHQKV0RJXZFY1DGX8MNSNYVE3VGZJSRTPGZGM")
+	recipient, _ := valueobject.NewPrincipal("ST1PQHQKV0RJXZFY1DGX8MNSNYVE3VGZJSRTPGZGM")
 	expectedMemo := "wrong memo"
 
 	criteria := VerificationCriteria{
@@ -187,13 +271,13 @@ func TestVerificationService_ValidatesOptionalMemo(t *testing.T) {
 	result := svc.Verify(tx, criteria)
 
 	assert.False(t, result.Valid)
-	assert.Contains(t, result.Errors[0], "memo mismatch")
+	assert.Contains(t, result.Errors()[0], "memo mismatch")
 }
 
 func TestVerificationService_MatchingMemo(t *testing.T) {
 	svc := NewVerificationService()
 	tx := createTestTransaction()
-	recipient, _ := valueobject.NewStacksAddress("ST1PQHQKV0RJXZFY1DGX8MNSNYVE3VGZJSRTPGZGM")
+	recipient, _ := valueobject.NewPrincipal("ST1PQHQKV0RJXZFY1DGX8MNSNYVE3VGZJSRTPGZGM")
 	expectedMemo := "test payment"
 
 	criteria := VerificationCriteria{
@@ -212,7 +296,7 @@ func TestVerificationService_CollectsMultipleErrors(t *testing.T) {
 	svc := NewVerificationService()
 	tx := createTestTransaction()
 	tx.Status = "failed"
-	wrongRecipient, _ := valueobject.NewStacksAddress("ST3J6ZY48GV1EZ5V2V5RB9MP66SW86PYKKNRV9EJ8")
+	wrongRecipient, _ := valueobject.NewPrincipal("ST3J6ZY48GV1EZ5V2V5RB9MP66SW86PYKKP6R6Z11")
 
 	criteria := VerificationCriteria{
 		ExpectedRecipient: wrongRecipient,
@@ -223,5 +307,420 @@ func TestVerificationService_CollectsMultipleErrors(t *testing.T) {
 	result := svc.Verify(tx, criteria)
 
 	assert.False(t, result.Valid)
-	require.GreaterOrEqual(t, len(result.Errors), 2)
+	require.GreaterOrEqual(t, len(result.Errors()), 2)
+}
+
+func TestVerificationService_AcceptsCanonicalSIP010Contract(t *testing.T) {
+	svc := NewVerificationService()
+	tx := createTestTransaction()
+	tx.TokenType = valueobject.TokenSBTC
+	tx.ContractID = "ST1F7QA2MDF17S807EPA36TSS8AMEFY4KA9TVGWXT.sbtc-token"
+	contract, _ := DefaultTokenRegistry().Contract(valueobject.TokenSBTC, valueobject.NetworkTestnet)
+
+	criteria := VerificationCriteria{
+		ExpectedRecipient: tx.Recipient,
+		MinAmount:         valueobject.NewAmount(500000),
+		ExpectedToken:     valueobject.TokenSBTC,
+		ExpectedContract:  &contract,
+	}
+
+	result := svc.Verify(tx, criteria)
+
+	assert.True(t, result.Valid)
+}
+
+func TestVerificationService_RejectsUnknownSIP010Contract(t *testing.T) {
+	svc := NewVerificationService()
+	tx := createTestTransaction()
+	tx.TokenType = valueobject.TokenSBTC
+	tx.ContractID = "ST2J6ZY48GV1EZ5V2V5RB9MP66SW86PYKKQYAC0RQ.fake-sbtc"
+	contract, _ := DefaultTokenRegistry().Contract(valueobject.TokenSBTC, valueobject.NetworkTestnet)
+
+	criteria := VerificationCriteria{
+		ExpectedRecipient: tx.Recipient,
+		MinAmount:         valueobject.NewAmount(500000),
+		ExpectedToken:     valueobject.TokenSBTC,
+		ExpectedContract:  &contract,
+	}
+
+	result := svc.Verify(tx, criteria)
+
+	assert.False(t, result.Valid)
+	assert.Contains(t, result.Errors()[0], "token contract mismatch")
+}
+
+func TestVerificationService_RejectsSTXTransferWhenSIP010Requested(t *testing.T) {
+	svc := NewVerificationService()
+	tx := createTestTransaction()
+	contract, _ := DefaultTokenRegistry().Contract(valueobject.TokenUSDCX, valueobject.NetworkTestnet)
+
+	criteria := VerificationCriteria{
+		ExpectedRecipient: tx.Recipient,
+		MinAmount:         valueobject.NewAmount(500000),
+		ExpectedToken:     valueobject.TokenUSDCX,
+		ExpectedContract:  &contract,
+	}
+
+	result := svc.Verify(tx, criteria)
+
+	assert.False(t, result.Valid)
+	assert.Contains(t, result.Errors()[0], "token mismatch")
+}
+
+func TestVerificationService_RejectsContractCallWhenSTXRequested(t *testing.T) {
+	svc := NewVerificationService()
+	tx := createTestTransaction()
+	tx.ContractID = "ST1F7QA2MDF17S807EPA36TSS8AMEFY4KA9TVGWXT.sbtc-token"
+
+	criteria := VerificationCriteria{
+		ExpectedRecipient: tx.Recipient,
+		MinAmount:         valueobject.NewAmount(500000),
+		ExpectedToken:     valueobject.TokenSTX,
+	}
+
+	result := svc.Verify(tx, criteria)
+
+	assert.False(t, result.Valid)
+	assert.Contains(t, result.Errors()[0], "token mismatch")
+}
+
+func TestVerificationService_ContractPrincipalRecipient(t *testing.T) {
+	svc := NewVerificationService()
+	vault, _ := valueobject.NewPrincipal("ST1PQHQKV0RJXZFY1DGX8MNSNYVE3VGZJSRTPGZGM.my-vault")
+	deployer, _ := valueobject.NewPrincipal("ST1PQHQKV0RJXZFY1DGX8MNSNYVE3VGZJSRTPGZGM")
+
+	tx := createTestTransaction()
+	tx.Recipient = vault
+
+	result := svc.Verify(tx, VerificationCriteria{ExpectedRecipient: vault, MinAmount: valueobject.NewAmount(500000)})
+	assert.True(t, result.Valid)
+
+	// Paying the contract's deployer is not paying the contract
+	result = svc.Verify(tx, VerificationCriteria{ExpectedRecipient: deployer, MinAmount: valueobject.NewAmount(500000)})
+	assert.False(t, result.Valid)
+	require.NotEmpty(t, result.Errors())
+	assert.Contains(t, result.Errors()[0], "recipient mismatch")
+}
+
+// routerTransaction is a confirmed call to a router contract whose events moved sBTC
+func routerTransaction(transfers ...AssetTransfer) BlockchainTransaction {
+	tx := createTestTransaction()
+	tx.TokenType = valueobject.TokenSBTC
+	tx.ContractID = "ST2J6ZY48GV1EZ5V2V5RB9MP66SW86PYKKQYAC0RQ.swap-router"
+	tx.Recipient = valueobject.Principal{}
+	tx.Amount = valueobject.Amount{}
+	tx.Transfers = append([]AssetTransfer{}, transfers...)
+	return tx
+}
+
+func sbtcTransfer(recipient string, amount uint64) AssetTransfer {
+	sender, _ := valueobject.NewPrincipal("ST2J6ZY48GV1EZ5V2V5RB9MP66SW86PYKKQYAC0RQ")
+	to, _ := valueobject.NewPrincipal(recipient)
+	return AssetTransfer{
+		Asset:     "ST1F7QA2MDF17S807EPA36TSS8AMEFY4KA9TVGWXT.sbtc-token::sbtc-token",
+		Sender:    sender,
+		Recipient: to,
+		Amount:    valueobject.NewAmount(amount),
+	}
+}
+
+func TestVerificationService_TransferEvents(t *testing.T) {
+	const recipient = "ST1PQHQKV0RJXZFY1DGX8MNSNYVE3VGZJSRTPGZGM"
+	const other = "ST3J6ZY48GV1EZ5V2V5RB9MP66SW86PYKKP6R6Z11"
+
+	counterfeit := sbtcTransfer(recipient, 1000000)
+	counterfeit.Asset = "ST2J6ZY48GV1EZ5V2V5RB9MP66SW86PYKKQYAC0RQ.sbtc-token::sbtc-token"
+
+	stxSender, _ := valueobject.NewPrincipal("ST2J6ZY48GV1EZ5V2V5RB9MP66SW86PYKKQYAC0RQ")
+	stxRecipient, _ := valueobject.NewPrincipal(recipient)
+	stx := AssetTransfer{Asset: "STX", Sender: stxSender, Recipient: stxRecipient, Amount: valueobject.NewAmount(1000000)}
+
+	selfTransfer := sbtcTransfer(recipient, 1000000)
+	selfTransfer.Sender = selfTransfer.Recipient
+
+	tests := []struct {
+		name      string
+		tx        BlockchainTransaction
+		wantError string
+	}{
+		{"router transfer", routerTransaction(sbtcTransfer(other, 10), sbtcTransfer(recipient, 600000)), ""},
+		{"split transfers are summed", routerTransaction(sbtcTransfer(recipient, 300000), sbtcTransfer(recipient, 300000)), ""},
+		{"insufficient total", routerTransaction(sbtcTransfer(recipient, 300000), sbtcTransfer(other, 300000)), "insufficient amount"},
+		{"paid to someone else", routerTransaction(sbtcTransfer(other, 1000000)), "recipient mismatch"},
+		{"counterfeit asset", routerTransaction(counterfeit), "token mismatch"},
+		{"STX instead of sBTC", routerTransaction(stx), "token mismatch"},
+		{"no transfer events", routerTransaction(), "token mismatch"},
+		{"recipient paying itself", routerTransaction(selfTransfer), "recipient mismatch"},
+		{"self transfer not counted", routerTransaction(selfTransfer, sbtcTransfer(recipient, 300000)), "insufficient amount"},
+	}
+
+	svc := NewVerificationService()
+	expected, _ := valueobject.NewPrincipal(recipient)
+	contract, _ := DefaultTokenRegistry().Contract(valueobject.TokenSBTC, valueobject.NetworkTestnet)
+	criteria := VerificationCriteria{
+		ExpectedRecipient: expected,
+		MinAmount:         valueobject.NewAmount(500000),
+		ExpectedToken:     valueobject.TokenSBTC,
+		ExpectedContract:  &contract,
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := svc.Verify(tt.tx, criteria)
+
+			if tt.wantError == "" {
+				assert.True(t, result.Valid, result.Errors())
+				return
+			}
+			assert.False(t, result.Valid)
+			require.Len(t, result.Errors(), 1)
+			assert.Contains(t, result.Errors()[0], tt.wantError)
+		})
+	}
+}
+
+func TestVerificationService_VerifyToken(t *testing.T) {
+	svc := NewVerificationService()
+	contract, _ := DefaultTokenRegistry().Contract(valueobject.TokenSBTC, valueobject.NetworkTestnet)
+	other := "ST3J6ZY48GV1EZ5V2V5RB9MP66SW86PYKKP6R6Z11"
+
+	assert.Empty(t, svc.VerifyToken(createTestTransaction(), valueobject.TokenSTX, nil))
+	assert.NotEmpty(t, svc.VerifyToken(createTestTransaction(), valueobject.TokenSBTC, &contract))
+
+	// Transfer events are checked for the asset only, not for recipient or amount
+	router := routerTransaction(sbtcTransfer(other, 1))
+	assert.Empty(t, svc.VerifyToken(router, valueobject.TokenSBTC, &contract))
+	failures := svc.VerifyToken(router, valueobject.TokenSTX, nil)
+	require.Len(t, failures, 1)
+	assert.Equal(t, FailureTokenMismatch, failures[0].Code)
+}
+
+func TestVerificationService_TransferEventsSTX(t *testing.T) {
+	svc := NewVerificationService()
+	tx := createTestTransaction()
+	sender, _ := valueobject.NewPrincipal("ST2J6ZY48GV1EZ5V2V5RB9MP66SW86PYKKQYAC0RQ")
+	tx.Transfers = []AssetTransfer{{Asset: "STX", Sender: sender, Recipient: tx.Recipient, Amount: tx.Amount}}
+
+	criteria := VerificationCriteria{
+		ExpectedRecipient: tx.Recipient,
+		MinAmount:         valueobject.NewAmount(1000000),
+		ExpectedToken:     valueobject.TokenSTX,
+	}
+
+	result := svc.Verify(tx, criteria)
+
+	assert.True(t, result.Valid)
+}
+
+func TestVerificationService_TransferEventsBeyondUint64(t *testing.T) {
+	const recipient = "ST1PQHQKV0RJXZFY1DGX8MNSNYVE3VGZJSRTPGZGM"
+
+	large := func(amount string) AssetTransfer {
+		transfer := sbtcTransfer(recipient, 0)
+		transfer.Amount, _ = valueobject.ParseAmount(amount)
+		return transfer
+	}
+
+	svc := NewVerificationService()
+	expected, _ := valueobject.NewPrincipal(recipient)
+	contract, _ := DefaultTokenRegistry().Contract(valueobject.TokenSBTC, valueobject.NetworkTestnet)
+	minAmount, _ := valueobject.ParseAmount("36893488147419103230") // 2 * (2^64 - 1)
+	criteria := VerificationCriteria{
+		ExpectedRecipient: expected,
+		MinAmount:         minAmount,
+		ExpectedToken:     valueobject.TokenSBTC,
+		ExpectedContract:  &contract,
+	}
+
+	// Two transfers that each fit in a uint64 but whose sum does not
+	result := svc.Verify(routerTransaction(large("18446744073709551615"), large("18446744073709551615")), criteria)
+	assert.True(t, result.Valid, result.Errors())
+
+	result = svc.Verify(routerTransaction(large("18446744073709551615"), large("18446744073709551614")), criteria)
+	assert.False(t, result.Valid)
+	require.Len(t, result.Errors(), 1)
+	assert.Equal(t, "insufficient amount: expected at least 36893488147419103230, got 36893488147419103229", result.Errors()[0])
+
+	// A total beyond uint128 covers any minimum
+	maxUint128 := "340282366920938463463374607431768211455"
+	result = svc.Verify(routerTransaction(large(maxUint128), large(maxUint128)), criteria)
+	assert.True(t, result.Valid, result.Errors())
+}
+
+func TestVerificationService_FailureCodes(t *testing.T) {
+	recipient, _ := valueobject.NewPrincipal("ST1PQHQKV0RJXZFY1DGX8MNSNYVE3VGZJSRTPGZGM")
+	otherRecipient, _ := valueobject.NewPrincipal("ST3J6ZY48GV1EZ5V2V5RB9MP66SW86PYKKP6R6Z11")
+	otherSender, _ := valueobject.NewStacksAddress("ST3J6ZY48GV1EZ5V2V5RB9MP66SW86PYKKP6R6Z11")
+	memo := "order-42"
+	contract, _ := DefaultTokenRegistry().Contract(valueobject.TokenSBTC, valueobject.NetworkTestnet)
+
+	tests := []struct {
+		name     string
+		tamper   func(tx *BlockchainTransaction, criteria *VerificationCriteria)
+		expected VerificationFailure
+	}{
+		{
+			name: "failed",
+			tamper: func(tx *BlockchainTransaction, criteria *VerificationCriteria) {
+				tx.Status = valueobject.TxStatusAbortByResponse
+			},
+			expected: VerificationFailure{
+				Code:    FailureTxFailed,
+				Actual:  "abort_by_response",
+				Message: "transaction failed with status: abort_by_response",
+			},
+		},
+		{
+			name: "dropped",
+			tamper: func(tx *BlockchainTransaction, criteria *VerificationCriteria) {
+				tx.Status = valueobject.TxStatusDroppedStaleGarbageCollect
+				criteria.AcceptUnconfirmed = true
+			},
+			expected: VerificationFailure{
+				Code:    FailureTxDropped,
+				Actual:  "dropped_stale_garbage_collect",
+				Message: "transaction dropped with status: dropped_stale_garbage_collect",
+			},
+		},
+		{
+			name: "not confirmed",
+			tamper: func(tx *BlockchainTransaction, criteria *VerificationCriteria) {
+				tx.Status = valueobject.TxStatusPending
+				tx.IsConfirmed = false
+			},
+			expected: VerificationFailure{
+				Code:     FailureNotConfirmed,
+				Expected: "confirmed",
+				Actual:   "pending",
+				Message:  "transaction not confirmed",
+			},
+		},
+		{
+			name: "shallow",
+			tamper: func(tx *BlockchainTransaction, criteria *VerificationCriteria) {
+				tx.Confirmations = 2
+				criteria.MinConfirmations = 6
+			},
+			expected: VerificationFailure{
+				Code:     FailureInsufficientConfirmations,
+				Expected: "6",
+				Actual:   "2",
+				Message:  "insufficient confirmations: expected at least 6 blocks, got 2",
+			},
+		},
+		{
+			name: "shallow burn",
+			tamper: func(tx *BlockchainTransaction, criteria *VerificationCriteria) {
+				tx.BurnConfirmations = 1
+				criteria.MinBurnConfirmations = 3
+			},
+			expected: VerificationFailure{
+				Code:     FailureInsufficientBurnConfirmations,
+				Expected: "3",
+				Actual:   "1",
+				Message:  "insufficient confirmations: expected at least 3 burn blocks, got 1",
+			},
+		},
+		{
+			name: "token",
+			tamper: func(tx *BlockchainTransaction, criteria *VerificationCriteria) {
+				criteria.ExpectedToken = valueobject.TokenSBTC
+				criteria.ExpectedContract = &contract
+			},
+			expected: VerificationFailure{
+				Code:     FailureTokenMismatch,
+				Expected: "SBTC",
+				Actual:   "STX",
+				Message:  "token mismatch: expected SBTC transfer, got STX token_transfer",
+			},
+		},
+		{
+			name: "token contract",
+			tamper: func(tx *BlockchainTransaction, criteria *VerificationCriteria) {
+				tx.TokenType = valueobject.TokenSBTC
+				tx.ContractID = "ST3J6ZY48GV1EZ5V2V5RB9MP66SW86PYKKP6R6Z11.fake-sbtc"
+				criteria.ExpectedToken = valueobject.TokenSBTC
+				criteria.ExpectedContract = &contract
+			},
+			expected: VerificationFailure{
+				Code:     FailureTokenContractMismatch,
+				Expected: contract.ContractID,
+				Actual:   "ST3J6ZY48GV1EZ5V2V5RB9MP66SW86PYKKP6R6Z11.fake-sbtc",
+				Message:  "token contract mismatch: expected " + contract.ContractID + ", got ST3J6ZY48GV1EZ5V2V5RB9MP66SW86PYKKP6R6Z11.fake-sbtc",
+			},
+		},
+		{
+			name: "recipient",
+			tamper: func(tx *BlockchainTransaction, criteria *VerificationCriteria) {
+				criteria.ExpectedRecipient = otherRecipient
+			},
+			expected: VerificationFailure{
+				Code:     FailureRecipientMismatch,
+				Expected: otherRecipient.String(),
+				Actual:   recipient.String(),
+				Message:  "recipient mismatch: expected " + otherRecipient.String() + ", got " + recipient.String(),
+			},
+		},
+		{
+			name: "amount",
+			tamper: func(tx *BlockchainTransaction, criteria *VerificationCriteria) {
+				criteria.MinAmount = valueobject.NewAmount(2000000)
+			},
+			expected: VerificationFailure{
+				Code:     FailureInsufficientAmount,
+				Expected: "2000000",
+				Actual:   "1000000",
+				Message:  "insufficient amount: expected at least 2000000, got 1000000",
+			},
+		},
+		{
+			name: "sender",
+			tamper: func(tx *BlockchainTransaction, criteria *VerificationCriteria) {
+				criteria.ExpectedSender = &otherSender
+			},
+			expected: VerificationFailure{
+				Code:     FailureSenderMismatch,
+				Expected: otherSender.String(),
+				Actual:   "ST2J6ZY48GV1EZ5V2V5RB9MP66SW86PYKKQYAC0RQ",
+				Message:  "sender mismatch: expected " + otherSender.String() + ", got ST2J6ZY48GV1EZ5V2V5RB9MP66SW86PYKKQYAC0RQ",
+			},
+		},
+		{
+			name:   "memo",
+			tamper: func(tx *BlockchainTransaction, criteria *VerificationCriteria) { criteria.ExpectedMemo = &memo },
+			expected: VerificationFailure{
+				Code:     FailureMemoMismatch,
+				Expected: "order-42",
+				Actual:   "test payment",
+				Message:  "memo mismatch: expected order-42, got test payment",
+			},
+		},
+		{
+			name: "no transfer to recipient",
+			tamper: func(tx *BlockchainTransaction, criteria *VerificationCriteria) {
+				tx.Transfers = []AssetTransfer{{Asset: "STX", Recipient: otherRecipient, Amount: valueobject.NewAmount(1000000)}}
+				criteria.ExpectedToken = valueobject.TokenSTX
+			},
+			expected: VerificationFailure{
+				Code:     FailureRecipientMismatch,
+				Expected: recipient.String(),
+				Message:  "recipient mismatch: expected " + recipient.String() + ", no transfer to it in transaction events",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tx := createTestTransaction()
+			criteria := VerificationCriteria{ExpectedRecipient: recipient, MinAmount: valueobject.NewAmount(500000)}
+			tt.tamper(&tx, &criteria)
+
+			result := NewVerificationService().Verify(tx, criteria)
+
+			assert.False(t, result.Valid)
+			require.NotEmpty(t, result.Failures)
+			assert.Equal(t, tt.expected, result.Failures[0])
+			assert.Equal(t, tt.expected.Message, result.Errors()[0])
+		})
+	}
 }
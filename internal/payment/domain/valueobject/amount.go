@@ -1,51 +1,137 @@
 package valueobject
 
-import "fmt"
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+)
 
-// Amount represents a monetary amount in base units (microSTX, satoshis, etc.)
+// maxAmount is the largest Clarity uint (2^128 - 1)
+var maxAmount = new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 128), big.NewInt(1))
+
+// Amount represents a monetary amount in base units (microSTX, satoshis, etc.).
+// It holds any Clarity uint, so SIP-010 amounts above 2^64 are represented exactly.
 type Amount struct {
-	value uint64
+	value *big.Int // nil is zero; never mutated once set
 }
 
 // NewAmount creates a new Amount
 func NewAmount(value uint64) Amount {
-	return Amount{value: value}
+	return Amount{value: new(big.Int).SetUint64(value)}
 }
 
-// Value returns the raw uint64 value
-func (a Amount) Value() uint64 {
-	return a.value
+// NewAmountFromBigInt creates an Amount from a copy of value
+func NewAmountFromBigInt(value *big.Int) (Amount, error) {
+	if value == nil {
+		return Amount{}, errors.New("amount cannot be nil")
+	}
+	if value.Sign() < 0 {
+		return Amount{}, errors.New("amount cannot be negative")
+	}
+	if value.Cmp(maxAmount) > 0 {
+		return Amount{}, errors.New("amount exceeds uint128")
+	}
+	return Amount{value: new(big.Int).Set(value)}, nil
+}
+
+// ParseAmount creates an Amount from a base-10 string of digits
+func ParseAmount(s string) (Amount, error) {
+	if s == "" {
+		return Amount{}, errors.New("amount cannot be empty")
+	}
+	for i := 0; i < len(s); i++ {
+		if s[i] < '0' || s[i] > '9' {
+			return Amount{}, errors.New("amount must be a non-negative integer: " + s)
+		}
+	}
+	value, _ := new(big.Int).SetString(s, 10)
+	return NewAmountFromBigInt(value)
+}
+
+// ParseDecimalAmount creates an Amount from a string in whole tokens (e.g. "0.0001"),
+// converting it exactly to base units. More fractional digits than decimals is an error.
+func ParseDecimalAmount(s string, decimals int) (Amount, error) {
+	whole, frac, hasPoint := strings.Cut(s, ".")
+	if whole == "" || (hasPoint && frac == "") || strings.Trim(whole+frac, "0123456789") != "" {
+		return Amount{}, errors.New("amount must be a non-negative decimal: " + s)
+	}
+	if len(frac) > decimals {
+		frac = strings.TrimRight(frac, "0")
+		if len(frac) > decimals {
+			return Amount{}, fmt.Errorf("amount %s has more than %d decimal places", s, decimals)
+		}
+	}
+	return ParseAmount(whole + frac + strings.Repeat("0", decimals-len(frac)))
+}
+
+// BigInt returns a copy of the amount
+func (a Amount) BigInt() *big.Int {
+	if a.value == nil {
+		return new(big.Int)
+	}
+	return new(big.Int).Set(a.value)
+}
+
+// Uint64 returns the amount as a uint64, and false if it does not fit
+func (a Amount) Uint64() (uint64, bool) {
+	if a.value == nil {
+		return 0, true
+	}
+	return a.value.Uint64(), a.value.IsUint64()
 }
 
 // IsZero checks if the amount is zero
 func (a Amount) IsZero() bool {
-	return a.value == 0
+	return a.value == nil || a.value.Sign() == 0
+}
+
+// Cmp compares two amounts, returning -1, 0 or +1
+func (a Amount) Cmp(other Amount) int {
+	return a.BigInt().Cmp(other.BigInt())
+}
+
+// Equals checks if two amounts are equal
+func (a Amount) Equals(other Amount) bool {
+	return a.Cmp(other) == 0
 }
 
 // IsGreaterThanOrEqual checks if this amount is >= other
 func (a Amount) IsGreaterThanOrEqual(other Amount) bool {
-	return a.value >= other.value
+	return a.Cmp(other) >= 0
 }
 
-// Add adds two amounts
-func (a Amount) Add(other Amount) Amount {
-	return Amount{value: a.value + other.value}
+// Add adds two amounts, failing if the sum exceeds uint128
+func (a Amount) Add(other Amount) (Amount, error) {
+	return NewAmountFromBigInt(new(big.Int).Add(a.BigInt(), other.BigInt()))
 }
 
-// Subtract subtracts other from this amount
-func (a Amount) Subtract(other Amount) Amount {
-	if other.value > a.value {
-		return Amount{value: 0}
+// Subtract subtracts other from this amount, failing if other is larger
+func (a Amount) Subtract(other Amount) (Amount, error) {
+	if a.Cmp(other) < 0 {
+		return Amount{}, errors.New("amount underflow: " + other.String() + " is greater than " + a.String())
 	}
-	return Amount{value: a.value - other.value}
+	return Amount{value: new(big.Int).Sub(a.BigInt(), other.BigInt())}, nil
 }
 
-// ToSTX converts microSTX to STX (1 STX = 1,000,000 microSTX)
-func (a Amount) ToSTX() float64 {
-	return float64(a.value) / 1_000_000
+// Format returns the amount in whole tokens with the given number of decimals,
+// without trailing zeros (e.g. 10000 with 8 decimals is "0.0001")
+func (a Amount) Format(decimals int) string {
+	digits := a.String()
+	if decimals <= 0 {
+		return digits
+	}
+	if len(digits) <= decimals {
+		digits = strings.Repeat("0", decimals-len(digits)+1) + digits
+	}
+	whole, frac := digits[:len(digits)-decimals], strings.TrimRight(digits[len(digits)-decimals:], "0")
+	if frac == "" {
+		return whole
+	}
+	return whole + "." + frac
 }
 
-// String returns the amount as a string
+// String returns the amount as a base-10 string
 func (a Amount) String() string {
-	return fmt.Sprintf("%d", a.value)
+	return a.BigInt().String()
 }
@@ -1,22 +1,26 @@
 package valueobject
 
 import (
+	"math/big"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestNewAmount_Valid(t *testing.T) {
 	amount := NewAmount(1000000)
 
-	assert.Equal(t, uint64(1000000), amount.Value())
+	assert.Equal(t, "1000000", amount.String())
 }
 
 func TestNewAmount_Zero(t *testing.T) {
 	amount := NewAmount(0)
 
-	assert.Equal(t, uint64(0), amount.Value())
+	assert.Equal(t, "0", amount.String())
 	assert.True(t, amount.IsZero())
+	assert.True(t, Amount{}.IsZero())
+	assert.True(t, amount.Equals(Amount{}))
 }
 
 func TestAmount_IsGreaterThanOrEqual(t *testing.T) {
@@ -33,27 +37,86 @@ func TestAmount_Add(t *testing.T) {
 	amount1 := NewAmount(1000000)
 	amount2 := NewAmount(500000)
 
-	result := amount1.Add(amount2)
+	result, err := amount1.Add(amount2)
 
-	assert.Equal(t, uint64(1500000), result.Value())
+	require.NoError(t, err)
+	assert.Equal(t, "1500000", result.String())
 }
 
 func TestAmount_Subtract(t *testing.T) {
 	amount1 := NewAmount(1000000)
 	amount2 := NewAmount(500000)
 
-	result := amount1.Subtract(amount2)
+	result, err := amount1.Subtract(amount2)
+
+	require.NoError(t, err)
+	assert.Equal(t, "500000", result.String())
 
-	assert.Equal(t, uint64(500000), result.Value())
+	_, err = amount2.Subtract(amount1)
+	assert.Error(t, err)
 }
 
-func TestAmount_ToSTX(t *testing.T) {
-	// 1 STX = 1,000,000 microSTX
-	amount := NewAmount(1500000)
+func TestAmount_Format(t *testing.T) {
+	tests := []struct {
+		amount   uint64
+		decimals int
+		want     string
+	}{
+		{1500000, 6, "1.5"},
+		{1000000, 6, "1"},
+		{10000, 8, "0.0001"},
+		{1, 8, "0.00000001"},
+		{0, 6, "0"},
+		{123, 0, "123"},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, NewAmount(tt.amount).Format(tt.decimals))
+	}
+}
 
-	stx := amount.ToSTX()
+func TestParseDecimalAmount(t *testing.T) {
+	tests := []struct {
+		input    string
+		decimals int
+		want     string
+		wantErr  bool
+	}{
+		{input: "0.0001", decimals: 8, want: "10000"},
+		{input: "1.5", decimals: 6, want: "1500000"},
+		{input: "2", decimals: 6, want: "2000000"},
+		{input: "0.00000001", decimals: 8, want: "1"},
+		{input: "1.500000000", decimals: 6, want: "1500000"},
+		{input: "7", decimals: 0, want: "7"},
+		{input: "0.000000001", decimals: 8, wantErr: true},
+		{input: "340282366920938463463374607431.768211456", decimals: 8, wantErr: true},
+		{input: ".5", decimals: 6, wantErr: true},
+		{input: "1.", decimals: 6, wantErr: true},
+		{input: "", decimals: 6, wantErr: true},
+		{input: "-1", decimals: 6, wantErr: true},
+		{input: "1e6", decimals: 6, wantErr: true},
+		{input: "1.2.3", decimals: 6, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			amount, err := ParseDecimalAmount(tt.input, tt.decimals)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, amount.String())
+			assert.Equal(t, amount.String(), mustParseDecimal(t, amount.Format(tt.decimals), tt.decimals).String())
+		})
+	}
+}
 
-	assert.Equal(t, 1.5, stx)
+func mustParseDecimal(t *testing.T, s string, decimals int) Amount {
+	t.Helper()
+	amount, err := ParseDecimalAmount(s, decimals)
+	require.NoError(t, err)
+	return amount
 }
 
 func TestAmount_String(t *testing.T) {
@@ -61,3 +124,80 @@ func TestAmount_String(t *testing.T) {
 
 	assert.Equal(t, "1000000", amount.String())
 }
+
+func TestParseAmount(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    string
+		wantErr bool
+	}{
+		{input: "0", want: "0"},
+		{input: "1000000", want: "1000000"},
+		{input: "18446744073709551616", want: "18446744073709551616"},
+		{input: "340282366920938463463374607431768211455", want: "340282366920938463463374607431768211455"},
+		{input: "340282366920938463463374607431768211456", wantErr: true},
+		{input: "", wantErr: true},
+		{input: "-1", wantErr: true},
+		{input: "+1", wantErr: true},
+		{input: "1.5", wantErr: true},
+		{input: "1e6", wantErr: true},
+		{input: " 1", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			amount, err := ParseAmount(tt.input)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, amount.String())
+		})
+	}
+}
+
+func TestNewAmountFromBigInt(t *testing.T) {
+	value := big.NewInt(42)
+	amount, err := NewAmountFromBigInt(value)
+	require.NoError(t, err)
+
+	// The amount keeps its own copy
+	value.SetInt64(7)
+	assert.Equal(t, "42", amount.String())
+
+	_, err = NewAmountFromBigInt(big.NewInt(-1))
+	assert.Error(t, err)
+	_, err = NewAmountFromBigInt(nil)
+	assert.Error(t, err)
+}
+
+func TestAmount_Uint64(t *testing.T) {
+	v, ok := NewAmount(2500).Uint64()
+	assert.True(t, ok)
+	assert.Equal(t, uint64(2500), v)
+
+	large, _ := ParseAmount("18446744073709551616")
+	_, ok = large.Uint64()
+	assert.False(t, ok)
+}
+
+func TestAmount_AddOverflow(t *testing.T) {
+	largest, _ := ParseAmount("340282366920938463463374607431768211455")
+
+	_, err := largest.Add(NewAmount(1))
+	assert.Error(t, err)
+
+	sum, err := largest.Add(Amount{})
+	require.NoError(t, err)
+	assert.True(t, sum.Equals(largest))
+}
+
+func TestAmount_CompareBeyondUint64(t *testing.T) {
+	small, _ := ParseAmount("18446744073709551615")
+	large, _ := ParseAmount("18446744073709551616")
+
+	assert.True(t, large.IsGreaterThanOrEqual(small))
+	assert.False(t, small.IsGreaterThanOrEqual(large))
+	assert.Equal(t, -1, small.Cmp(large))
+}
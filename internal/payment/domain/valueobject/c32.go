@@ -0,0 +1,108 @@
+package valueobject
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"errors"
+	"math/big"
+	"strings"
+)
+
+// c32Alphabet is the Crockford base32 alphabet used by Stacks addresses
+const c32Alphabet = "0123456789ABCDEFGHJKMNPQRSTVWXYZ"
+
+// Stacks address version bytes
+const (
+	AddressVersionMainnetSingleSig byte = 22 // SP
+	AddressVersionMainnetMultiSig  byte = 20 // SM
+	AddressVersionTestnetSingleSig byte = 26 // ST
+	AddressVersionTestnetMultiSig  byte = 21 // SN
+)
+
+// c32Encode encodes data as a big-endian base32 number, keeping one '0' per leading zero byte
+func c32Encode(data []byte) string {
+	zeros := 0
+	for zeros < len(data) && data[zeros] == 0 {
+		zeros++
+	}
+
+	n := new(big.Int).SetBytes(data)
+	base := big.NewInt(32)
+	mod := new(big.Int)
+
+	var out []byte
+	for n.Sign() > 0 {
+		n.DivMod(n, base, mod)
+		out = append(out, c32Alphabet[mod.Int64()])
+	}
+	for i := 0; i < zeros; i++ {
+		out = append(out, c32Alphabet[0])
+	}
+
+	for i, j := 0, len(out)-1; i < j; i, j = i+1, j-1 {
+		out[i], out[j] = out[j], out[i]
+	}
+	return string(out)
+}
+
+// c32Decode decodes a c32 string, restoring one zero byte per leading '0'
+func c32Decode(s string) ([]byte, error) {
+	zeros := 0
+	for zeros < len(s) && s[zeros] == c32Alphabet[0] {
+		zeros++
+	}
+
+	n := new(big.Int)
+	base := big.NewInt(32)
+	for i := 0; i < len(s); i++ {
+		digit := strings.IndexByte(c32Alphabet, s[i])
+		if digit < 0 {
+			return nil, errors.New("invalid c32 character: " + string(s[i]))
+		}
+		n.Mul(n, base).Add(n, big.NewInt(int64(digit)))
+	}
+
+	return append(make([]byte, zeros), n.Bytes()...), nil
+}
+
+// c32Checksum returns the first four bytes of sha256(sha256(version || data))
+func c32Checksum(version byte, data []byte) []byte {
+	first := sha256.Sum256(append([]byte{version}, data...))
+	second := sha256.Sum256(first[:])
+	return second[:4]
+}
+
+// c32AddressEncode builds a Stacks address string from a version byte and hash160
+func c32AddressEncode(version byte, hash160 [20]byte) string {
+	payload := append(hash160[:], c32Checksum(version, hash160[:])...)
+	return "S" + string(c32Alphabet[version&0x1f]) + c32Encode(payload)
+}
+
+// c32AddressDecode splits a Stacks address string into its version byte and hash160,
+// checking the alphabet, payload length and checksum
+func c32AddressDecode(addr string) (byte, [20]byte, error) {
+	var hash160 [20]byte
+	if len(addr) < 3 || addr[0] != 'S' {
+		return 0, hash160, errors.New("invalid Stacks address prefix")
+	}
+
+	version := strings.IndexByte(c32Alphabet, addr[1])
+	if version < 0 {
+		return 0, hash160, errors.New("invalid Stacks address version")
+	}
+
+	payload, err := c32Decode(addr[2:])
+	if err != nil {
+		return 0, hash160, err
+	}
+	if len(payload) != len(hash160)+4 {
+		return 0, hash160, errors.New("invalid Stacks address length")
+	}
+
+	copy(hash160[:], payload[:20])
+	if !bytes.Equal(payload[20:], c32Checksum(byte(version), hash160[:])) {
+		return 0, hash160, errors.New("invalid Stacks address checksum")
+	}
+
+	return byte(version), hash160, nil
+}
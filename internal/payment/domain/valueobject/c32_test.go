@@ -0,0 +1,87 @@
+package valueobject
+
+import (
+	"encoding/hex"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestC32AddressEncode(t *testing.T) {
+	tests := []struct {
+		name     string
+		version  byte
+		hash160  string
+		expected string
+	}{
+		{
+			name:     "testnet single-sig",
+			version:  AddressVersionTestnetSingleSig,
+			hash160:  "6d78de7b0625dfbfc16c3a8a5735f6dc3dc3f2ce",
+			expected: "ST1PQHQKV0RJXZFY1DGX8MNSNYVE3VGZJSRTPGZGM",
+		},
+		{
+			name:     "mainnet single-sig",
+			version:  AddressVersionMainnetSingleSig,
+			hash160:  "a46ff88886c2ef9762d970b4d2c63678835bd39d",
+			expected: "SP2J6ZY48GV1EZ5V2V5RB9MP66SW86PYKKNRV9EJ7",
+		},
+		{
+			name:     "leading zero bytes",
+			version:  AddressVersionMainnetSingleSig,
+			hash160:  "0000000000000000000000000000000000000000",
+			expected: "SP000000000000000000002Q6VF78",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			raw, err := hex.DecodeString(tt.hash160)
+			require.NoError(t, err)
+
+			var hash [20]byte
+			copy(hash[:], raw)
+
+			assert.Equal(t, tt.expected, c32AddressEncode(tt.version, hash))
+		})
+	}
+}
+
+func TestC32Encode_Empty(t *testing.T) {
+	assert.Equal(t, "", c32Encode(nil))
+}
+
+func TestC32AddressDecode_RoundTrip(t *testing.T) {
+	for _, addr := range []string{
+		"ST1PQHQKV0RJXZFY1DGX8MNSNYVE3VGZJSRTPGZGM",
+		"SP2J6ZY48GV1EZ5V2V5RB9MP66SW86PYKKNRV9EJ7",
+		"SP000000000000000000002Q6VF78",
+		"SM3VDXK3WZZSA84XXFKAFAF15NNZX32CTSG82JFQ4",
+	} {
+		version, hash, err := c32AddressDecode(addr)
+		require.NoError(t, err, addr)
+		assert.Equal(t, addr, c32AddressEncode(version, hash))
+	}
+}
+
+func TestC32AddressDecode_Rejects(t *testing.T) {
+	tests := []struct {
+		name      string
+		addr      string
+		wantError string
+	}{
+		{"short payload", "ST1PQHQKV0RJXZFY1DGX8MNSNYVE3VG", "invalid Stacks address length"},
+		{"long payload", "ST1PQHQKV0RJXZFY1DGX8MNSNYVE3VGZJSRTPGZGM00", "invalid Stacks address length"},
+		{"lowercase", "st1pqhqkv0rjxzfy1dgx8mnsnyve3vgzjsrtpgzgm", "invalid Stacks address prefix"},
+		{"bad version character", "SU1PQHQKV0RJXZFY1DGX8MNSNYVE3VGZJSRTPGZGM", "invalid Stacks address version"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, _, err := c32AddressDecode(tt.addr)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantError)
+		})
+	}
+}
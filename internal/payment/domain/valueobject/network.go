@@ -13,6 +13,11 @@ const (
 	NetworkTestnet Network = "testnet"
 )
 
+// SupportedNetworks returns every network the facilitator accepts
+func SupportedNetworks() []Network {
+	return []Network{NetworkMainnet, NetworkTestnet}
+}
+
 // NewNetwork creates a new Network from a string
 func NewNetwork(s string) (Network, error) {
 	if s == "" {
@@ -20,14 +25,12 @@ func NewNetwork(s string) (Network, error) {
 	}
 
 	normalized := strings.ToLower(s)
-	switch normalized {
-	case "mainnet":
-		return NetworkMainnet, nil
-	case "testnet":
-		return NetworkTestnet, nil
-	default:
-		return "", errors.New("unsupported network: " + s)
+	for _, network := range SupportedNetworks() {
+		if string(network) == normalized {
+			return network, nil
+		}
 	}
+	return "", errors.New("unsupported network: " + s)
 }
 
 // String returns the network as a string
@@ -60,3 +60,12 @@ func TestNetwork_IsTestnet(t *testing.T) {
 	assert.False(t, NetworkMainnet.IsTestnet())
 	assert.True(t, NetworkTestnet.IsTestnet())
 }
+
+func TestSupportedNetworks_AllParse(t *testing.T) {
+	for _, network := range SupportedNetworks() {
+		parsed, err := NewNetwork(network.String())
+
+		require.NoError(t, err)
+		assert.Equal(t, network, parsed)
+	}
+}
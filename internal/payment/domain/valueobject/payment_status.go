@@ -5,13 +5,15 @@ import (
 	"strings"
 )
 
-// PaymentStatus represents the status of a payment
+// PaymentStatus represents the status of a payment. A payment starts pending and
+// moves once to confirmed, failed or dropped, where it stays.
 type PaymentStatus string
 
 const (
 	StatusPending   PaymentStatus = "pending"
 	StatusConfirmed PaymentStatus = "confirmed"
 	StatusFailed    PaymentStatus = "failed"
+	StatusDropped   PaymentStatus = "dropped"
 )
 
 // NewPaymentStatus creates a new PaymentStatus from a string
@@ -28,6 +30,8 @@ func NewPaymentStatus(s string) (PaymentStatus, error) {
 		return StatusConfirmed, nil
 	case "failed":
 		return StatusFailed, nil
+	case "dropped":
+		return StatusDropped, nil
 	default:
 		return "", errors.New("invalid payment status: " + s)
 	}
@@ -48,7 +52,22 @@ func (s PaymentStatus) IsFailed() bool {
 	return s == StatusFailed
 }
 
+// IsDropped returns true if the transaction was dropped from the mempool
+func (s PaymentStatus) IsDropped() bool {
+	return s == StatusDropped
+}
+
 // IsPending returns true if status is pending
 func (s PaymentStatus) IsPending() bool {
 	return s == StatusPending
 }
+
+// IsFinal returns true if the payment can no longer change status
+func (s PaymentStatus) IsFinal() bool {
+	return s == StatusConfirmed || s == StatusFailed || s == StatusDropped
+}
+
+// CanTransitionTo returns true if a payment in this status may move to next
+func (s PaymentStatus) CanTransitionTo(next PaymentStatus) bool {
+	return s == StatusPending && next.IsFinal()
+}
@@ -28,6 +28,15 @@ func TestNewPaymentStatus_Failed(t *testing.T) {
 	assert.Equal(t, StatusFailed, status)
 }
 
+func TestNewPaymentStatus_Dropped(t *testing.T) {
+	status, err := NewPaymentStatus("dropped")
+
+	require.NoError(t, err)
+	assert.Equal(t, StatusDropped, status)
+	assert.True(t, status.IsDropped())
+	assert.False(t, StatusFailed.IsDropped())
+}
+
 func TestNewPaymentStatus_Invalid(t *testing.T) {
 	_, err := NewPaymentStatus("unknown")
 
@@ -59,6 +68,17 @@ func TestPaymentStatus_IsFailed(t *testing.T) {
 	assert.True(t, StatusFailed.IsFailed())
 }
 
+func TestPaymentStatus_Transitions(t *testing.T) {
+	assert.False(t, StatusPending.IsFinal())
+	for _, final := range []PaymentStatus{StatusConfirmed, StatusFailed, StatusDropped} {
+		assert.True(t, final.IsFinal())
+		assert.True(t, StatusPending.CanTransitionTo(final))
+		assert.False(t, final.CanTransitionTo(StatusPending))
+		assert.False(t, final.CanTransitionTo(StatusConfirmed))
+	}
+	assert.False(t, StatusPending.CanTransitionTo(StatusPending))
+}
+
 func TestPaymentStatus_IsPending(t *testing.T) {
 	assert.True(t, StatusPending.IsPending())
 	assert.False(t, StatusConfirmed.IsPending())
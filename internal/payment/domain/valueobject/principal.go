@@ -0,0 +1,88 @@
+package valueobject
+
+import (
+	"errors"
+	"regexp"
+	"strings"
+)
+
+// maxContractNameLength is the longest contract name Clarity accepts
+const maxContractNameLength = 128
+
+// contractNamePattern matches Clarity contract names
+var contractNamePattern = regexp.MustCompile(`^[a-zA-Z][a-zA-Z0-9_-]*$`)
+
+// Principal represents a Stacks principal: a standard principal (an address)
+// or a contract principal (address.contract-name)
+type Principal struct {
+	address  StacksAddress
+	contract string
+}
+
+// NewPrincipal creates a Principal from "SP..." or "SP....contract-name"
+func NewPrincipal(s string) (Principal, error) {
+	if s == "" {
+		return Principal{}, errors.New("principal cannot be empty")
+	}
+
+	addr, contract, isContract := strings.Cut(s, ".")
+	address, err := NewStacksAddress(addr)
+	if err != nil {
+		return Principal{}, err
+	}
+	if !isContract {
+		return NewStandardPrincipal(address), nil
+	}
+	return NewContractPrincipal(address, contract)
+}
+
+// NewStandardPrincipal creates a Principal for an address
+func NewStandardPrincipal(address StacksAddress) Principal {
+	return Principal{address: address}
+}
+
+// NewContractPrincipal creates a Principal for a contract deployed by address
+func NewContractPrincipal(address StacksAddress, name string) (Principal, error) {
+	if len(name) == 0 || len(name) > maxContractNameLength || !contractNamePattern.MatchString(name) {
+		return Principal{}, errors.New("invalid contract name: " + name)
+	}
+	return Principal{address: address, contract: name}, nil
+}
+
+// String returns the principal as a string
+func (p Principal) String() string {
+	if p.contract == "" {
+		return p.address.String()
+	}
+	return p.address.String() + "." + p.contract
+}
+
+// Address returns the standard principal, or the deployer of a contract principal
+func (p Principal) Address() StacksAddress {
+	return p.address
+}
+
+// ContractName returns the contract name, empty for a standard principal
+func (p Principal) ContractName() string {
+	return p.contract
+}
+
+// IsContract checks if the principal is a contract principal
+func (p Principal) IsContract() bool {
+	return p.contract != ""
+}
+
+// Equals checks if two Principals are equal
+func (p Principal) Equals(other Principal) bool {
+	return p.address.Equals(other.address) && p.contract == other.contract
+}
+
+// Network returns the network the principal's address belongs to
+func (p Principal) Network() Network {
+	return p.address.Network()
+}
+
+// IsZero checks if the principal is empty
+func (p Principal) IsZero() bool {
+	return p.address.IsZero()
+}
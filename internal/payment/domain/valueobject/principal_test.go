@@ -0,0 +1,72 @@
+package valueobject
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewPrincipal_Standard(t *testing.T) {
+	p, err := NewPrincipal("ST1PQHQKV0RJXZFY1DGX8MNSNYVE3VGZJSRTPGZGM")
+
+	require.NoError(t, err)
+	assert.False(t, p.IsContract())
+	assert.Empty(t, p.ContractName())
+	assert.Equal(t, "ST1PQHQKV0RJXZFY1DGX8MNSNYVE3VGZJSRTPGZGM", p.String())
+	assert.Equal(t, "ST1PQHQKV0RJXZFY1DGX8MNSNYVE3VGZJSRTPGZGM", p.Address().String())
+	assert.Equal(t, NetworkTestnet, p.Network())
+}
+
+func TestNewPrincipal_Contract(t *testing.T) {
+	p, err := NewPrincipal("SP2J6ZY48GV1EZ5V2V5RB9MP66SW86PYKKNRV9EJ7.my-vault_v2")
+
+	require.NoError(t, err)
+	assert.True(t, p.IsContract())
+	assert.Equal(t, "my-vault_v2", p.ContractName())
+	assert.Equal(t, "SP2J6ZY48GV1EZ5V2V5RB9MP66SW86PYKKNRV9EJ7.my-vault_v2", p.String())
+	assert.Equal(t, "SP2J6ZY48GV1EZ5V2V5RB9MP66SW86PYKKNRV9EJ7", p.Address().String())
+	assert.Equal(t, NetworkMainnet, p.Network())
+}
+
+func TestNewPrincipal_Rejects(t *testing.T) {
+	tests := []struct {
+		name      string
+		principal string
+		wantError string
+	}{
+		{"empty", "", "principal cannot be empty"},
+		{"bad address", "ST1PQHQKV0RJXZFY1DGX8MNSNYVE3VGZJSRTPGZGN.vault", "invalid Stacks address checksum"},
+		{"empty contract name", "ST1PQHQKV0RJXZFY1DGX8MNSNYVE3VGZJSRTPGZGM.", "invalid contract name"},
+		{"name starts with digit", "ST1PQHQKV0RJXZFY1DGX8MNSNYVE3VGZJSRTPGZGM.1vault", "invalid contract name"},
+		{"name with dot", "ST1PQHQKV0RJXZFY1DGX8MNSNYVE3VGZJSRTPGZGM.my.vault", "invalid contract name"},
+		{"quoted", "'ST1PQHQKV0RJXZFY1DGX8MNSNYVE3VGZJSRTPGZGM", "invalid Stacks address prefix"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewPrincipal(tt.principal)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantError)
+		})
+	}
+}
+
+func TestPrincipal_Equals(t *testing.T) {
+	address, _ := NewPrincipal("ST1PQHQKV0RJXZFY1DGX8MNSNYVE3VGZJSRTPGZGM")
+	vault, _ := NewPrincipal("ST1PQHQKV0RJXZFY1DGX8MNSNYVE3VGZJSRTPGZGM.vault")
+	sameVault, _ := NewPrincipal("ST1PQHQKV0RJXZFY1DGX8MNSNYVE3VGZJSRTPGZGM.vault")
+	otherVault, _ := NewPrincipal("ST1PQHQKV0RJXZFY1DGX8MNSNYVE3VGZJSRTPGZGM.escrow")
+
+	assert.True(t, vault.Equals(sameVault))
+	assert.False(t, vault.Equals(address))
+	assert.False(t, vault.Equals(otherVault))
+	assert.True(t, address.Equals(NewStandardPrincipal(address.Address())))
+}
+
+func TestPrincipal_IsZero(t *testing.T) {
+	assert.True(t, Principal{}.IsZero())
+
+	p, _ := NewPrincipal("ST1PQHQKV0RJXZFY1DGX8MNSNYVE3VGZJSRTPGZGM")
+	assert.False(t, p.IsZero())
+}
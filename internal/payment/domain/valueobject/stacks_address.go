@@ -23,13 +23,27 @@ func NewStacksAddress(addr string) (StacksAddress, error) {
 	}
 
 	// Validate length (Stacks addresses are typically 41 characters)
-	if len(addr) < 30 || len(addr) > 50 {
+	if len(addr) < 28 || len(addr) > 41 {
 		return StacksAddress{}, errors.New("invalid Stacks address length")
 	}
 
+	// Validate the c32check encoding: version byte, hash160 and checksum
+	version, hash160, err := c32AddressDecode(addr)
+	if err != nil {
+		return StacksAddress{}, err
+	}
+	if c32AddressEncode(version, hash160) != addr {
+		return StacksAddress{}, errors.New("invalid Stacks address encoding")
+	}
+
 	return StacksAddress{value: addr}, nil
 }
 
+// NewStacksAddressFromHash160 builds the c32check address for a version byte and hash160
+func NewStacksAddressFromHash160(version byte, hash160 [20]byte) StacksAddress {
+	return StacksAddress{value: c32AddressEncode(version, hash160)}
+}
+
 // String returns the address as a string
 func (a StacksAddress) String() string {
 	return a.value
@@ -50,6 +64,26 @@ func (a StacksAddress) IsMainnet() bool {
 	return strings.HasPrefix(a.value, "SP") || strings.HasPrefix(a.value, "SM")
 }
 
+// Version returns the address version byte
+func (a StacksAddress) Version() byte {
+	version, _, _ := c32AddressDecode(a.value)
+	return version
+}
+
+// Hash160 returns the hash160 of the public key or script the address commits to
+func (a StacksAddress) Hash160() [20]byte {
+	_, hash160, _ := c32AddressDecode(a.value)
+	return hash160
+}
+
+// Network returns the network the address belongs to
+func (a StacksAddress) Network() Network {
+	if a.IsMainnet() {
+		return NetworkMainnet
+	}
+	return NetworkTestnet
+}
+
 // IsZero checks if the address is empty
 func (a StacksAddress) IsZero() bool {
 	return a.value == ""
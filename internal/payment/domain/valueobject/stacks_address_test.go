@@ -1,6 +1,7 @@
 package valueobject
 
 import (
+	"encoding/hex"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -17,7 +18,7 @@ func TestNewStacksAddress_ValidTestnetAddress(t *testing.T) {
 }
 
 func TestNewStacksAddress_ValidMainnetAddress(t *testing.T) {
-	validAddr := "SP2J6ZY48GV1EZ5V2V5RB9MP66SW86PYKKQ9H6DPR"
+	validAddr := "SP2J6ZY48GV1EZ5V2V5RB9MP66SW86PYKKNRV9EJ7"
 
 	addr, err := NewStacksAddress(validAddr)
 
@@ -46,10 +47,61 @@ func TestNewStacksAddress_RejectsTooShort(t *testing.T) {
 	assert.Contains(t, err.Error(), "invalid Stacks address length")
 }
 
+func TestNewStacksAddress_RejectsBadChecksum(t *testing.T) {
+	_, err := NewStacksAddress("ST1PQHQKV0RJXZFY1DGX8MNSNYVE3VGZJSRTPGZGN")
+
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid Stacks address checksum")
+}
+
+func TestNewStacksAddress_RejectsInvalidCharacter(t *testing.T) {
+	_, err := NewStacksAddress("ST1PQHQKV0RJXZFY1DGX8MNSNYVE3VGZJSRTPGZGO")
+
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid c32 character")
+}
+
+func TestNewStacksAddress_RejectsVersionPrefixMismatch(t *testing.T) {
+	// Same hash and checksum as ST1PQ..., but the checksum covers version 26, not 22
+	_, err := NewStacksAddress("SP1PQHQKV0RJXZFY1DGX8MNSNYVE3VGZJSRTPGZGM")
+
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid Stacks address checksum")
+}
+
+func TestNewStacksAddress_AcceptsZeroHash(t *testing.T) {
+	addr, err := NewStacksAddress("SP000000000000000000002Q6VF78")
+
+	require.NoError(t, err)
+	assert.Equal(t, [20]byte{}, addr.Hash160())
+}
+
+func TestStacksAddress_Hash160AndVersion(t *testing.T) {
+	addr, err := NewStacksAddress("ST2J6ZY48GV1EZ5V2V5RB9MP66SW86PYKKQYAC0RQ")
+	require.NoError(t, err)
+
+	hash := addr.Hash160()
+	assert.Equal(t, "a46ff88886c2ef9762d970b4d2c63678835bd39d", hex.EncodeToString(hash[:]))
+	assert.Equal(t, AddressVersionTestnetSingleSig, addr.Version())
+
+	mainnet := NewStacksAddressFromHash160(AddressVersionMainnetSingleSig, hash)
+	assert.Equal(t, "SP2J6ZY48GV1EZ5V2V5RB9MP66SW86PYKKNRV9EJ7", mainnet.String())
+}
+
+func TestStacksAddress_Network(t *testing.T) {
+	testnetAddr, _ := NewStacksAddress("ST1PQHQKV0RJXZFY1DGX8MNSNYVE3VGZJSRTPGZGM")
+	mainnetAddr, _ := NewStacksAddress("SP2J6ZY48GV1EZ5V2V5RB9MP66SW86PYKKNRV9EJ7")
+	multisigAddr, _ := NewStacksAddress("SM3VDXK3WZZSA84XXFKAFAF15NNZX32CTSG82JFQ4")
+
+	assert.Equal(t, NetworkTestnet, testnetAddr.Network())
+	assert.Equal(t, NetworkMainnet, mainnetAddr.Network())
+	assert.Equal(t, NetworkMainnet, multisigAddr.Network())
+}
+
 func TestStacksAddress_Equals(t *testing.T) {
 	addr1, _ := NewStacksAddress("ST1PQHQKV0RJXZFY1DGX8MNSNYVE3VGZJSRTPGZGM")
 	addr2, _ := NewStacksAddress("ST1PQHQKV0RJXZFY1DGX8MNSNYVE3VGZJSRTPGZGM")
-	addr3, _ := NewStacksAddress("ST2J6ZY48GV1EZ5V2V5RB9MP66SW86PYKKNRV9EJ7")
+	addr3, _ := NewStacksAddress("ST2J6ZY48GV1EZ5V2V5RB9MP66SW86PYKKQYAC0RQ")
 
 	assert.True(t, addr1.Equals(addr2))
 	assert.False(t, addr1.Equals(addr3))
@@ -57,7 +109,7 @@ func TestStacksAddress_Equals(t *testing.T) {
 
 func TestStacksAddress_IsTestnet(t *testing.T) {
 	testnetAddr, _ := NewStacksAddress("ST1PQHQKV0RJXZFY1DGX8MNSNYVE3VGZJSRTPGZGM")
-	mainnetAddr, _ := NewStacksAddress("SP2J6ZY48GV1EZ5V2V5RB9MP66SW86PYKKQ9H6DPR")
+	mainnetAddr, _ := NewStacksAddress("SP2J6ZY48GV1EZ5V2V5RB9MP66SW86PYKKNRV9EJ7")
 
 	assert.True(t, testnetAddr.IsTestnet())
 	assert.False(t, mainnetAddr.IsTestnet())
@@ -65,8 +117,17 @@ func TestStacksAddress_IsTestnet(t *testing.T) {
 
 func TestStacksAddress_IsMainnet(t *testing.T) {
 	testnetAddr, _ := NewStacksAddress("ST1PQHQKV0RJXZFY1DGX8MNSNYVE3VGZJSRTPGZGM")
-	mainnetAddr, _ := NewStacksAddress("SP2J6ZY48GV1EZ5V2V5RB9MP66SW86PYKKQ9H6DPR")
+	mainnetAddr, _ := NewStacksAddress("SP2J6ZY48GV1EZ5V2V5RB9MP66SW86PYKKNRV9EJ7")
 
 	assert.False(t, testnetAddr.IsMainnet())
 	assert.True(t, mainnetAddr.IsMainnet())
 }
+
+func TestNewStacksAddressFromHash160(t *testing.T) {
+	hash := [20]byte{0x6d, 0x78, 0xde, 0x7b, 0x06, 0x25, 0xdf, 0xbf, 0xc1, 0x6c, 0x3a, 0x8a, 0x57, 0x35, 0xf6, 0xdc, 0x3d, 0xc3, 0xf2, 0xce}
+
+	addr := NewStacksAddressFromHash160(AddressVersionTestnetSingleSig, hash)
+
+	assert.Equal(t, "ST1PQHQKV0RJXZFY1DGX8MNSNYVE3VGZJSRTPGZGM", addr.String())
+	assert.True(t, addr.IsTestnet())
+}
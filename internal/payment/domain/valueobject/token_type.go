@@ -2,10 +2,15 @@ package valueobject
 
 import (
 	"errors"
+	"regexp"
 	"strings"
 )
 
-// TokenType represents a supported token type
+// tokenTypePattern matches token type identifiers such as STX, SBTC or ALEX
+var tokenTypePattern = regexp.MustCompile(`^[A-Z][A-Z0-9_-]{0,31}$`)
+
+// TokenType identifies a token. STX is native; every other token type is a
+// SIP-010 token whose contracts are looked up in the token registry.
 type TokenType string
 
 const (
@@ -14,23 +19,18 @@ const (
 	TokenUSDCX TokenType = "USDCX"
 )
 
-// NewTokenType creates a new TokenType from a string
+// NewTokenType creates a new TokenType from a string. Whether the token is
+// supported on a network is decided by the token registry.
 func NewTokenType(s string) (TokenType, error) {
 	if s == "" {
 		return "", errors.New("token type cannot be empty")
 	}
 
 	normalized := strings.ToUpper(s)
-	switch normalized {
-	case "STX":
-		return TokenSTX, nil
-	case "SBTC":
-		return TokenSBTC, nil
-	case "USDCX":
-		return TokenUSDCX, nil
-	default:
-		return "", errors.New("unsupported token type: " + s)
+	if !tokenTypePattern.MatchString(normalized) {
+		return "", errors.New("invalid token type: " + s)
 	}
+	return TokenType(normalized), nil
 }
 
 // String returns the token type as a string
@@ -43,7 +43,8 @@ func (t TokenType) IsNative() bool {
 	return t == TokenSTX
 }
 
-// IsSIP010 returns true if this is a SIP-010 token (not native STX)
+// IsSIP010 returns true if this is one of the built-in SIP-010 tokens. Whether a
+// configured token type is a SIP-010 token is decided by the token registry.
 func (t TokenType) IsSIP010() bool {
 	return t == TokenSBTC || t == TokenUSDCX
 }
@@ -35,11 +35,22 @@ func TestNewTokenType_CaseInsensitive(t *testing.T) {
 	assert.Equal(t, TokenSTX, tokenType)
 }
 
+func TestNewTokenType_Unlisted(t *testing.T) {
+	// Any well-formed token type parses; the token registry decides whether it is supported
+	tokenType, err := NewTokenType("alex")
+
+	require.NoError(t, err)
+	assert.Equal(t, TokenType("ALEX"), tokenType)
+	assert.False(t, tokenType.IsSIP010())
+}
+
 func TestNewTokenType_Invalid(t *testing.T) {
-	_, err := NewTokenType("BTC")
+	for _, s := range []string{"1INCH", "SP2J6ZY48GV1EZ5V2V5RB9MP66SW86PYKKNRV9EJ7.token", "BTC TOKEN", "ABCDEFGHIJKLMNOPQRSTUVWXYZABCDEFG"} {
+		_, err := NewTokenType(s)
 
-	assert.Error(t, err)
-	assert.Contains(t, err.Error(), "unsupported token type")
+		assert.Error(t, err, s)
+		assert.Contains(t, err.Error(), "invalid token type")
+	}
 }
 
 func TestNewTokenType_Empty(t *testing.T) {
@@ -59,3 +70,11 @@ func TestTokenType_IsNative(t *testing.T) {
 	assert.False(t, TokenSBTC.IsNative())
 	assert.False(t, TokenUSDCX.IsNative())
 }
+
+func TestTokenType_IsSIP010(t *testing.T) {
+	assert.True(t, TokenSBTC.IsSIP010())
+	assert.True(t, TokenUSDCX.IsSIP010())
+	assert.False(t, TokenSTX.IsSIP010())
+	assert.False(t, TokenType("").IsSIP010())
+	assert.False(t, TokenType("BTC").IsSIP010())
+}
@@ -0,0 +1,84 @@
+package valueobject
+
+import "errors"
+
+// TransactionStatus is the status the Stacks API reports for a transaction
+type TransactionStatus string
+
+const (
+	TxStatusPending              TransactionStatus = "pending"
+	TxStatusSuccess              TransactionStatus = "success"
+	TxStatusFailed               TransactionStatus = "failed"
+	TxStatusAbortByResponse      TransactionStatus = "abort_by_response"
+	TxStatusAbortByPostCondition TransactionStatus = "abort_by_post_condition"
+
+	// Mempool transactions that were evicted without being mined
+	TxStatusDroppedReplaceByFee        TransactionStatus = "dropped_replace_by_fee"
+	TxStatusDroppedReplaceAcrossFork   TransactionStatus = "dropped_replace_across_fork"
+	TxStatusDroppedTooExpensive        TransactionStatus = "dropped_too_expensive"
+	TxStatusDroppedStaleGarbageCollect TransactionStatus = "dropped_stale_garbage_collect"
+	TxStatusDroppedProblematic         TransactionStatus = "dropped_problematic"
+)
+
+// transactionStatuses lists every status the Stacks API reports
+var transactionStatuses = []TransactionStatus{
+	TxStatusPending,
+	TxStatusSuccess,
+	TxStatusFailed,
+	TxStatusAbortByResponse,
+	TxStatusAbortByPostCondition,
+	TxStatusDroppedReplaceByFee,
+	TxStatusDroppedReplaceAcrossFork,
+	TxStatusDroppedTooExpensive,
+	TxStatusDroppedStaleGarbageCollect,
+	TxStatusDroppedProblematic,
+}
+
+// NewTransactionStatus creates a TransactionStatus from a Stacks API tx_status
+func NewTransactionStatus(s string) (TransactionStatus, error) {
+	if s == "" {
+		return "", errors.New("transaction status cannot be empty")
+	}
+	for _, status := range transactionStatuses {
+		if string(status) == s {
+			return status, nil
+		}
+	}
+	return "", errors.New("invalid transaction status: " + s)
+}
+
+// String returns the status as a string
+func (s TransactionStatus) String() string {
+	return string(s)
+}
+
+// IsPending returns true while the transaction is in the mempool
+func (s TransactionStatus) IsPending() bool {
+	return s == TxStatusPending
+}
+
+// IsSuccess returns true if the transaction was mined and succeeded
+func (s TransactionStatus) IsSuccess() bool {
+	return s == TxStatusSuccess
+}
+
+// IsFailed returns true if the transaction was mined but aborted
+func (s TransactionStatus) IsFailed() bool {
+	return s == TxStatusFailed || s == TxStatusAbortByResponse || s == TxStatusAbortByPostCondition
+}
+
+// IsDropped returns true if the transaction left the mempool without being mined
+func (s TransactionStatus) IsDropped() bool {
+	switch s {
+	case TxStatusDroppedReplaceByFee, TxStatusDroppedReplaceAcrossFork, TxStatusDroppedTooExpensive,
+		TxStatusDroppedStaleGarbageCollect, TxStatusDroppedProblematic:
+		return true
+	default:
+		return false
+	}
+}
+
+// IsFinal returns true once the status can no longer change by waiting
+func (s TransactionStatus) IsFinal() bool {
+	return s.IsSuccess() || s.IsFailed() || s.IsDropped()
+}
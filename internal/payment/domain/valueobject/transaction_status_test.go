@@ -0,0 +1,60 @@
+package valueobject
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewTransactionStatus(t *testing.T) {
+	for _, s := range []string{
+		"pending", "success", "failed", "abort_by_response", "abort_by_post_condition",
+		"dropped_replace_by_fee", "dropped_replace_across_fork", "dropped_too_expensive",
+		"dropped_stale_garbage_collect", "dropped_problematic",
+	} {
+		status, err := NewTransactionStatus(s)
+		require.NoError(t, err, s)
+		assert.Equal(t, s, status.String())
+	}
+}
+
+func TestNewTransactionStatus_Invalid(t *testing.T) {
+	_, err := NewTransactionStatus("")
+	assert.Error(t, err)
+
+	_, err = NewTransactionStatus("SUCCESS")
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid transaction status")
+}
+
+func TestTransactionStatus_Classification(t *testing.T) {
+	tests := []struct {
+		status  TransactionStatus
+		pending bool
+		success bool
+		failed  bool
+		dropped bool
+	}{
+		{TxStatusPending, true, false, false, false},
+		{TxStatusSuccess, false, true, false, false},
+		{TxStatusFailed, false, false, true, false},
+		{TxStatusAbortByResponse, false, false, true, false},
+		{TxStatusAbortByPostCondition, false, false, true, false},
+		{TxStatusDroppedReplaceByFee, false, false, false, true},
+		{TxStatusDroppedReplaceAcrossFork, false, false, false, true},
+		{TxStatusDroppedTooExpensive, false, false, false, true},
+		{TxStatusDroppedStaleGarbageCollect, false, false, false, true},
+		{TxStatusDroppedProblematic, false, false, false, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.status.String(), func(t *testing.T) {
+			assert.Equal(t, tt.pending, tt.status.IsPending())
+			assert.Equal(t, tt.success, tt.status.IsSuccess())
+			assert.Equal(t, tt.failed, tt.status.IsFailed())
+			assert.Equal(t, tt.dropped, tt.status.IsDropped())
+			assert.Equal(t, !tt.pending, tt.status.IsFinal())
+		})
+	}
+}
@@ -0,0 +1,51 @@
+package blockchain
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/x402stacks/stacks-facilitator/internal/payment/application/command"
+	"github.com/x402stacks/stacks-facilitator/internal/payment/domain/service"
+	"github.com/x402stacks/stacks-facilitator/internal/payment/domain/valueobject"
+	"github.com/x402stacks/stacks-facilitator/internal/stacks/clarity"
+)
+
+// AccountState reads an account's next nonce, counting its transactions in the mempool,
+// its STX balance and, when contract is set, its balance of that SIP-010 token via the
+// contract's get-balance read-only function
+func (a *StacksClientAdapter) AccountState(ctx context.Context, address valueobject.StacksAddress, contract *service.TokenContract, network valueobject.Network) (command.AccountState, error) {
+	client := a.getClientForNetwork(network)
+
+	account, err := client.GetAccount(ctx, address.String())
+	if err != nil {
+		return command.AccountState{}, err
+	}
+	nonces, err := client.GetNonces(ctx, address.String())
+	if err != nil {
+		return command.AccountState{}, err
+	}
+
+	state := command.AccountState{
+		NextNonce: max(account.Nonce, nonces.PossibleNextNonce),
+		Balance:   valueobject.NewAmount(account.Balance),
+	}
+	if contract == nil {
+		return state, nil
+	}
+
+	owner := clarity.StandardPrincipal{Version: address.Version(), Hash160: address.Hash160()}
+	result, err := client.CallReadOnly(ctx, contract.ContractID, "get-balance", address.String(), owner)
+	if err != nil {
+		return command.AccountState{}, err
+	}
+	balance, ok := okValue(result).(clarity.UInt)
+	if !ok || balance.Value == nil {
+		return command.AccountState{}, fmt.Errorf("%s get-balance returned %v, expected (ok uint)", contract.ContractID, result)
+	}
+	state.TokenBalance, err = valueobject.NewAmountFromBigInt(balance.Value)
+	if err != nil {
+		return command.AccountState{}, fmt.Errorf("%s get-balance returned %v: %w", contract.ContractID, result, err)
+	}
+
+	return state, nil
+}
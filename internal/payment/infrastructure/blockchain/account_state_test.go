@@ -0,0 +1,82 @@
+package blockchain
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/x402stacks/stacks-facilitator/internal/payment/application/command"
+	"github.com/x402stacks/stacks-facilitator/internal/payment/domain/valueobject"
+	"github.com/x402stacks/stacks-facilitator/internal/stacks"
+	"github.com/x402stacks/stacks-facilitator/internal/stacks/clarity"
+)
+
+const payerAddress = "ST2J6ZY48GV1EZ5V2V5RB9MP66SW86PYKKQYAC0RQ"
+
+// accountServer answers the account, nonce and get-balance endpoints for one address
+func accountServer(t *testing.T, accountNonce, possibleNextNonce uint64, tokenBalance clarity.Value) *StacksClientAdapter {
+	t.Helper()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/v2/accounts/"+payerAddress:
+			json.NewEncoder(w).Encode(map[string]any{"balance": "0x00000000000000000000000000989680", "nonce": accountNonce})
+		case r.URL.Path == "/extended/v1/address/"+payerAddress+"/nonces":
+			json.NewEncoder(w).Encode(map[string]any{"possible_next_nonce": possibleNextNonce})
+		case strings.HasSuffix(r.URL.Path, "/get-balance"):
+			var body struct {
+				Arguments []string `json:"arguments"`
+			}
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+			require.Len(t, body.Arguments, 1)
+			owner, err := clarity.DecodeHex(body.Arguments[0])
+			require.NoError(t, err)
+			assert.Equal(t, payerAddress, owner.(clarity.StandardPrincipal).String())
+
+			encoded, err := clarity.EncodeHex(tokenBalance)
+			require.NoError(t, err)
+			w.Write([]byte(`{"okay":true,"result":"` + encoded + `"}`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	client := stacks.NewClient(server.URL)
+	return NewStacksClientAdapterWithClients(client, client)
+}
+
+func TestStacksClientAdapter_AccountState(t *testing.T) {
+	adapter := accountServer(t, 4, 6, nil)
+	payer, _ := valueobject.NewStacksAddress(payerAddress)
+
+	state, err := adapter.AccountState(context.Background(), payer, nil, valueobject.NetworkTestnet)
+
+	require.NoError(t, err)
+	assert.Equal(t, command.AccountState{NextNonce: 6, Balance: valueobject.NewAmount(10000000)}, state)
+}
+
+func TestStacksClientAdapter_AccountState_TokenBalance(t *testing.T) {
+	adapter := accountServer(t, 4, 0, clarity.ResponseOk{Value: clarity.NewUInt(2500)})
+	payer, _ := valueobject.NewStacksAddress(payerAddress)
+
+	state, err := adapter.AccountState(context.Background(), payer, &alexContract, valueobject.NetworkTestnet)
+
+	require.NoError(t, err)
+	assert.Equal(t, uint64(4), state.NextNonce, "the account nonce is used when the mempool view lags behind")
+	assert.Equal(t, "2500", state.TokenBalance.String())
+}
+
+func TestStacksClientAdapter_AccountState_InvalidTokenBalance(t *testing.T) {
+	adapter := accountServer(t, 4, 4, clarity.ResponseErr{Value: clarity.NewUInt(1)})
+	payer, _ := valueobject.NewStacksAddress(payerAddress)
+
+	_, err := adapter.AccountState(context.Background(), payer, &alexContract, valueobject.NetworkTestnet)
+
+	assert.ErrorContains(t, err, "expected (ok uint)")
+}
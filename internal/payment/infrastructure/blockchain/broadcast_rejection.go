@@ -0,0 +1,95 @@
+package blockchain
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/x402stacks/stacks-facilitator/internal/payment/application/command"
+	"github.com/x402stacks/stacks-facilitator/internal/stacks"
+)
+
+// broadcastRejection turns a node rejection into a command.BroadcastRejectedError that
+// still wraps the client's error. Errors without a rejection reason are returned as is.
+func broadcastRejection(err error) error {
+	var broadcastErr *stacks.BroadcastError
+	if !errors.As(err, &broadcastErr) || broadcastErr.Reason == "" {
+		return err
+	}
+
+	var data struct {
+		Expected json.RawMessage `json:"expected"`
+		Actual   json.RawMessage `json:"actual"`
+	}
+	_ = json.Unmarshal(broadcastErr.ReasonData, &data)
+
+	return &command.BroadcastRejectedError{
+		Reason:   command.BroadcastRejectionReason(broadcastErr.Reason),
+		Message:  rejectionMessage(broadcastErr),
+		Expected: rejectionNumber(data.Expected),
+		Actual:   rejectionNumber(data.Actual),
+		Err:      err,
+	}
+}
+
+// rejectionMessage explains a rejection from its reason data, falling back to the
+// node's own message
+func rejectionMessage(broadcastErr *stacks.BroadcastError) string {
+	var data struct {
+		Expected           json.RawMessage `json:"expected"`
+		Actual             json.RawMessage `json:"actual"`
+		ContractIdentifier string          `json:"contract_identifier"`
+		Message            string          `json:"message"`
+	}
+	_ = json.Unmarshal(broadcastErr.ReasonData, &data)
+	expected, actual := rejectionNumber(data.Expected), rejectionNumber(data.Actual)
+
+	switch command.BroadcastRejectionReason(broadcastErr.Reason) {
+	case command.RejectionBadNonce:
+		if expected != "" && actual != "" {
+			return fmt.Sprintf("expected nonce %s, got %s", expected, actual)
+		}
+	case command.RejectionNotEnoughFunds:
+		if expected != "" && actual != "" {
+			return fmt.Sprintf("needs %s microSTX, balance is %s", expected, actual)
+		}
+	case command.RejectionFeeTooLow:
+		if expected != "" && actual != "" {
+			return fmt.Sprintf("fee %s is below the minimum of %s", actual, expected)
+		}
+	case command.RejectionContractAlreadyExists:
+		if data.ContractIdentifier != "" {
+			return fmt.Sprintf("contract %s already exists", data.ContractIdentifier)
+		}
+	}
+
+	switch {
+	case data.Message != "":
+		return data.Message
+	case broadcastErr.Message != "":
+		return broadcastErr.Message
+	default:
+		return broadcastErr.Reason
+	}
+}
+
+// rejectionNumber reads a reason data amount, which the node sends either as a JSON number
+// or as a 0x-prefixed big-endian hex string for u128 values. It returns "" when absent.
+func rejectionNumber(raw json.RawMessage) string {
+	var number json.Number
+	if err := json.Unmarshal(raw, &number); err == nil {
+		return number.String()
+	}
+
+	var hexValue string
+	if err := json.Unmarshal(raw, &hexValue); err != nil || !strings.HasPrefix(hexValue, "0x") {
+		return ""
+	}
+	value, ok := new(big.Int).SetString(strings.TrimPrefix(hexValue, "0x"), 16)
+	if !ok {
+		return ""
+	}
+	return value.String()
+}
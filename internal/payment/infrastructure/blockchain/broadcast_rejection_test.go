@@ -0,0 +1,75 @@
+package blockchain
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/x402stacks/stacks-facilitator/internal/payment/application/command"
+	"github.com/x402stacks/stacks-facilitator/internal/stacks"
+)
+
+func TestBroadcastRejection(t *testing.T) {
+	tests := []struct {
+		name        string
+		reason      string
+		reasonData  string
+		wantReason  command.BroadcastRejectionReason
+		wantMessage string
+	}{
+		{"bad nonce", "BadNonce", `{"expected":6,"actual":5,"principal":"ST2J6ZY48GV1EZ5V2V5RB9MP66SW86PYKKQYAC0RQ","is_origin":true}`, command.RejectionBadNonce, "expected nonce 6, got 5"},
+		{"not enough funds", "NotEnoughFunds", `{"expected":"0x000000000000000000000000000f4294","actual":"0x000000000000000000000000000003e8"}`, command.RejectionNotEnoughFunds, "needs 1000084 microSTX, balance is 1000"},
+		{"fee too low", "FeeTooLow", `{"expected":180,"actual":1}`, command.RejectionFeeTooLow, "fee 1 is below the minimum of 180"},
+		{"conflicting nonce", "ConflictingNonceInMempool", ``, command.RejectionConflictingNonceInMempool, "transaction rejected"},
+		{"bad version", "BadTransactionVersion", ``, command.RejectionBadTransactionVersion, "transaction rejected"},
+		{"contract exists", "ContractAlreadyExists", `{"contract_identifier":"ST2J6ZY48GV1EZ5V2V5RB9MP66SW86PYKKQYAC0RQ.token"}`, command.RejectionContractAlreadyExists, "contract ST2J6ZY48GV1EZ5V2V5RB9MP66SW86PYKKQYAC0RQ.token already exists"},
+		{"post-condition", "PostConditionFailed", `{"message":"post-condition check failed"}`, command.RejectionPostConditionFailed, "post-condition check failed"},
+		{"other reason", "Deserialization", `{"message":"unexpected end of input"}`, "Deserialization", "unexpected end of input"},
+		{"unreadable reason data", "BadNonce", `{"expected":"six"}`, command.RejectionBadNonce, "transaction rejected"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cause := &stacks.BroadcastError{
+				StatusCode: http.StatusBadRequest,
+				Message:    "transaction rejected",
+				Reason:     tt.reason,
+				ReasonData: []byte(tt.reasonData),
+			}
+
+			err := broadcastRejection(fmt.Errorf("wrapped: %w", cause))
+
+			var rejected *command.BroadcastRejectedError
+			require.ErrorAs(t, err, &rejected)
+			assert.Equal(t, tt.wantReason, rejected.Reason)
+			assert.Equal(t, tt.wantMessage, rejected.Message)
+			assert.ErrorIs(t, err, command.ErrBroadcastRejected)
+
+			var broadcastErr *stacks.BroadcastError
+			assert.ErrorAs(t, err, &broadcastErr, "the client's error stays reachable")
+		})
+	}
+}
+
+func TestBroadcastRejection_ExpectedAndActual(t *testing.T) {
+	cause := &stacks.BroadcastError{
+		Reason:     "NotEnoughFunds",
+		ReasonData: []byte(`{"expected":"0x000000000000000000000000000f4294","actual":1000}`),
+	}
+
+	var rejected *command.BroadcastRejectedError
+	require.ErrorAs(t, broadcastRejection(cause), &rejected)
+	assert.Equal(t, "1000084", rejected.Expected)
+	assert.Equal(t, "1000", rejected.Actual)
+}
+
+func TestBroadcastRejection_NotARejection(t *testing.T) {
+	transport := errors.New("connection refused")
+	assert.Equal(t, transport, broadcastRejection(transport))
+
+	unparsed := &stacks.BroadcastError{StatusCode: http.StatusInternalServerError, Body: "internal error"}
+	assert.Equal(t, error(unparsed), broadcastRejection(unparsed))
+}
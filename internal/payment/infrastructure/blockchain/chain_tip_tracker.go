@@ -0,0 +1,75 @@
+package blockchain
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/x402stacks/stacks-facilitator/internal/payment/domain/service"
+	"github.com/x402stacks/stacks-facilitator/internal/payment/domain/valueobject"
+	"github.com/x402stacks/stacks-facilitator/internal/stacks"
+)
+
+// chainTipFetcher reads the current chain tip of one network
+type chainTipFetcher func(ctx context.Context) (service.ChainTip, error)
+
+// ChainTipTracker caches the chain tip of each network so that confirmation
+// counts do not cost a /v2/info request per payment
+type ChainTipTracker struct {
+	maxAge time.Duration
+	now    func() time.Time
+	tips   map[valueobject.Network]*trackedTip
+}
+
+// trackedTip is the last tip fetched for one network
+type trackedTip struct {
+	mu        sync.Mutex
+	fetch     chainTipFetcher
+	tip       service.ChainTip
+	fetchedAt time.Time
+}
+
+// NewChainTipTracker creates a tracker that refetches a network's tip once it is older than maxAge
+func NewChainTipTracker(mainnetClient, testnetClient *stacks.Client, maxAge time.Duration) *ChainTipTracker {
+	return newChainTipTracker(map[valueobject.Network]chainTipFetcher{
+		valueobject.NetworkMainnet: mainnetClient.GetChainTip,
+		valueobject.NetworkTestnet: testnetClient.GetChainTip,
+	}, maxAge)
+}
+
+func newChainTipTracker(fetchers map[valueobject.Network]chainTipFetcher, maxAge time.Duration) *ChainTipTracker {
+	tips := make(map[valueobject.Network]*trackedTip, len(fetchers))
+	for network, fetch := range fetchers {
+		tips[network] = &trackedTip{fetch: fetch}
+	}
+	return &ChainTipTracker{maxAge: maxAge, now: time.Now, tips: tips}
+}
+
+// ChainTip returns the tip of a network, fetching it when the cached one is too old.
+// Heights never move backwards, so an API node that lags behind the one that
+// served an earlier request cannot take confirmations away.
+func (t *ChainTipTracker) ChainTip(ctx context.Context, network valueobject.Network) (service.ChainTip, error) {
+	tracked, ok := t.tips[network]
+	if !ok {
+		return service.ChainTip{}, fmt.Errorf("no chain tip source for %s", network)
+	}
+
+	tracked.mu.Lock()
+	defer tracked.mu.Unlock()
+
+	now := t.now()
+	if !tracked.fetchedAt.IsZero() && now.Sub(tracked.fetchedAt) < t.maxAge {
+		return tracked.tip, nil
+	}
+
+	tip, err := tracked.fetch(ctx)
+	if err != nil {
+		return service.ChainTip{}, fmt.Errorf("failed to fetch %s chain tip: %w", network, err)
+	}
+	tracked.tip.StacksHeight = max(tracked.tip.StacksHeight, tip.StacksHeight)
+	tracked.tip.BurnHeight = max(tracked.tip.BurnHeight, tip.BurnHeight)
+	tracked.fetchedAt = now
+
+	return tracked.tip, nil
+}
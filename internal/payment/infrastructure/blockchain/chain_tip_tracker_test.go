@@ -0,0 +1,96 @@
+package blockchain
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/x402stacks/stacks-facilitator/internal/payment/domain/service"
+	"github.com/x402stacks/stacks-facilitator/internal/payment/domain/valueobject"
+)
+
+// sequenceTips returns a fetcher reporting the given tips in order, counting calls
+func sequenceTips(tips []service.ChainTip, calls *int) chainTipFetcher {
+	return func(ctx context.Context) (service.ChainTip, error) {
+		tip := tips[min(*calls, len(tips)-1)]
+		*calls++
+		return tip, nil
+	}
+}
+
+func TestChainTipTracker_CachesUntilMaxAge(t *testing.T) {
+	calls := 0
+	tracker := newChainTipTracker(map[valueobject.Network]chainTipFetcher{
+		valueobject.NetworkTestnet: sequenceTips([]service.ChainTip{{StacksHeight: 100, BurnHeight: 50}, {StacksHeight: 102, BurnHeight: 51}}, &calls),
+	}, 5*time.Second)
+	now := time.Unix(1700000000, 0)
+	tracker.now = func() time.Time { return now }
+
+	tip, err := tracker.ChainTip(context.Background(), valueobject.NetworkTestnet)
+	require.NoError(t, err)
+	assert.Equal(t, service.ChainTip{StacksHeight: 100, BurnHeight: 50}, tip)
+
+	now = now.Add(4 * time.Second)
+	tip, err = tracker.ChainTip(context.Background(), valueobject.NetworkTestnet)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(100), tip.StacksHeight)
+	assert.Equal(t, 1, calls)
+
+	now = now.Add(time.Second)
+	tip, err = tracker.ChainTip(context.Background(), valueobject.NetworkTestnet)
+	require.NoError(t, err)
+	assert.Equal(t, service.ChainTip{StacksHeight: 102, BurnHeight: 51}, tip)
+	assert.Equal(t, 2, calls)
+}
+
+func TestChainTipTracker_NeverMovesBackwards(t *testing.T) {
+	calls := 0
+	tracker := newChainTipTracker(map[valueobject.Network]chainTipFetcher{
+		valueobject.NetworkMainnet: sequenceTips([]service.ChainTip{{StacksHeight: 100, BurnHeight: 50}, {StacksHeight: 98, BurnHeight: 51}}, &calls),
+	}, 0)
+
+	_, err := tracker.ChainTip(context.Background(), valueobject.NetworkMainnet)
+	require.NoError(t, err)
+	tip, err := tracker.ChainTip(context.Background(), valueobject.NetworkMainnet)
+	require.NoError(t, err)
+
+	assert.Equal(t, service.ChainTip{StacksHeight: 100, BurnHeight: 51}, tip)
+}
+
+func TestChainTipTracker_ConcurrentCallersShareOneFetch(t *testing.T) {
+	calls := 0
+	tracker := newChainTipTracker(map[valueobject.Network]chainTipFetcher{
+		valueobject.NetworkTestnet: sequenceTips([]service.ChainTip{{StacksHeight: 100}}, &calls),
+	}, time.Minute)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := tracker.ChainTip(context.Background(), valueobject.NetworkTestnet)
+			assert.NoError(t, err)
+		}()
+	}
+	wg.Wait()
+
+	assert.Equal(t, 1, calls)
+}
+
+func TestChainTipTracker_FetchError(t *testing.T) {
+	tracker := newChainTipTracker(map[valueobject.Network]chainTipFetcher{
+		valueobject.NetworkTestnet: func(ctx context.Context) (service.ChainTip, error) {
+			return service.ChainTip{}, errors.New("node unavailable")
+		},
+	}, time.Minute)
+
+	_, err := tracker.ChainTip(context.Background(), valueobject.NetworkTestnet)
+	assert.ErrorContains(t, err, "node unavailable")
+
+	_, err = tracker.ChainTip(context.Background(), valueobject.NetworkMainnet)
+	assert.Error(t, err)
+}
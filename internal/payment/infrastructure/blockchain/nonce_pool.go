@@ -0,0 +1,146 @@
+package blockchain
+
+import (
+	"context"
+	"sort"
+	"sync"
+)
+
+// noncePool hands out nonces for one sponsor address on one network. Nonces are
+// assigned locally so concurrent settlements never share one; the pool is
+// resynchronized from the node on first use and after a nonce conflict.
+type noncePool struct {
+	mu      sync.Mutex
+	synced  bool
+	next    uint64
+	free    []uint64            // Reserved nonces that were never broadcast, reused lowest first
+	pending map[uint64]struct{} // Reserved or broadcast nonces not yet seen executed
+}
+
+// nonceFetcher reads the node's view of an account: the next possible nonce and any gaps below it
+type nonceFetcher func(ctx context.Context) (next uint64, missing []uint64, err error)
+
+func newNoncePool() *noncePool {
+	return &noncePool{pending: make(map[uint64]struct{})}
+}
+
+// reserve returns the next nonce, fetching the account's next possible nonce first if needed
+func (p *noncePool) reserve(ctx context.Context, fetch nonceFetcher) (uint64, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if err := p.syncLocked(ctx, fetch); err != nil {
+		return 0, err
+	}
+
+	var nonce uint64
+	if len(p.free) > 0 {
+		nonce = p.free[0]
+		p.free = p.free[1:]
+	} else {
+		nonce = p.next
+		p.next++
+	}
+	p.pending[nonce] = struct{}{}
+	return nonce, nil
+}
+
+// unused returns a nonce whose transaction was never accepted
+func (p *noncePool) unused(nonce uint64) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if _, ok := p.pending[nonce]; !ok {
+		return
+	}
+	delete(p.pending, nonce)
+	p.free = append(p.free, nonce)
+	sort.Slice(p.free, func(i, j int) bool { return p.free[i] < p.free[j] })
+}
+
+// conflict drops a nonce the node rejected, or whose broadcast may or may not have reached
+// the node, and forces a resync before the next reservation
+func (p *noncePool) conflict(nonce uint64) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	delete(p.pending, nonce)
+	p.synced = false
+}
+
+// observe records the account's confirmed nonce: everything below it has executed
+func (p *noncePool) observe(accountNonce uint64) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	for nonce := range p.pending {
+		if nonce < accountNonce {
+			delete(p.pending, nonce)
+		}
+	}
+
+	free := p.free[:0]
+	for _, nonce := range p.free {
+		if nonce >= accountNonce {
+			free = append(free, nonce)
+		}
+	}
+	p.free = free
+
+	if p.synced && p.next < accountNonce {
+		p.next = accountNonce
+	}
+}
+
+// snapshot returns the next nonce and the pending count, syncing first if needed
+func (p *noncePool) snapshot(ctx context.Context, fetch nonceFetcher) (uint64, int, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if err := p.syncLocked(ctx, fetch); err != nil {
+		return 0, 0, err
+	}
+	next := p.next
+	if len(p.free) > 0 {
+		next = p.free[0]
+	}
+	return next, len(p.pending), nil
+}
+
+// pendingCount returns the number of nonces in flight
+func (p *noncePool) pendingCount() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return len(p.pending)
+}
+
+func (p *noncePool) syncLocked(ctx context.Context, fetch nonceFetcher) error {
+	if p.synced {
+		return nil
+	}
+
+	next, missing, err := fetch(ctx)
+	if err != nil {
+		return err
+	}
+
+	// Nonces still pending locally may not have reached the node yet
+	for nonce := range p.pending {
+		if nonce >= next {
+			next = nonce + 1
+		}
+	}
+
+	// Gaps left by dropped transactions block everything above them, so fill them first
+	p.free = nil
+	for _, nonce := range missing {
+		if _, ok := p.pending[nonce]; !ok && nonce < next {
+			p.free = append(p.free, nonce)
+		}
+	}
+	sort.Slice(p.free, func(i, j int) bool { return p.free[i] < p.free[j] })
+
+	p.next = next
+	p.synced = true
+	return nil
+}
@@ -0,0 +1,152 @@
+package blockchain
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fixedNonces returns a fetcher reporting the given next nonce and gaps, counting calls
+func fixedNonces(next uint64, missing []uint64, calls *int) nonceFetcher {
+	return func(ctx context.Context) (uint64, []uint64, error) {
+		*calls++
+		return next, missing, nil
+	}
+}
+
+func TestNoncePool_ConcurrentReserve(t *testing.T) {
+	pool := newNoncePool()
+	calls := 0
+	fetch := fixedNonces(10, nil, &calls)
+
+	const n = 50
+	nonces := make(chan uint64, n)
+	var wg sync.WaitGroup
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			nonce, err := pool.reserve(context.Background(), fetch)
+			assert.NoError(t, err)
+			nonces <- nonce
+		}()
+	}
+	wg.Wait()
+	close(nonces)
+
+	seen := make(map[uint64]bool)
+	for nonce := range nonces {
+		assert.False(t, seen[nonce], "nonce %d handed out twice", nonce)
+		assert.GreaterOrEqual(t, nonce, uint64(10))
+		seen[nonce] = true
+	}
+	assert.Len(t, seen, n)
+	assert.Equal(t, 1, calls)
+	assert.Equal(t, n, pool.pendingCount())
+}
+
+func TestNoncePool_UnusedIsReused(t *testing.T) {
+	pool := newNoncePool()
+	calls := 0
+	fetch := fixedNonces(0, nil, &calls)
+
+	first, _ := pool.reserve(context.Background(), fetch)
+	second, _ := pool.reserve(context.Background(), fetch)
+	pool.unused(first)
+
+	next, err := pool.reserve(context.Background(), fetch)
+	require.NoError(t, err)
+	assert.Equal(t, first, next)
+
+	after, err := pool.reserve(context.Background(), fetch)
+	require.NoError(t, err)
+	assert.Equal(t, second+1, after)
+}
+
+func TestNoncePool_ConflictResyncs(t *testing.T) {
+	pool := newNoncePool()
+	calls := 0
+	next := uint64(3)
+	fetch := func(ctx context.Context) (uint64, []uint64, error) {
+		calls++
+		return next, nil, nil
+	}
+
+	nonce, _ := pool.reserve(context.Background(), fetch)
+	require.Equal(t, uint64(3), nonce)
+
+	// Another process used nonces 3 and 4
+	next = 5
+	pool.conflict(nonce)
+
+	nonce, err := pool.reserve(context.Background(), fetch)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(5), nonce)
+	assert.Equal(t, 2, calls)
+}
+
+func TestNoncePool_ResyncKeepsPending(t *testing.T) {
+	pool := newNoncePool()
+	calls := 0
+	fetch := fixedNonces(0, nil, &calls)
+
+	first, _ := pool.reserve(context.Background(), fetch)
+	second, _ := pool.reserve(context.Background(), fetch)
+	pool.conflict(first)
+
+	// The node has not seen the second transaction yet, so its nonce must not be reused
+	nonce, err := pool.reserve(context.Background(), fetch)
+	require.NoError(t, err)
+	assert.Equal(t, second+1, nonce)
+}
+
+func TestNoncePool_FillsMissingNonces(t *testing.T) {
+	pool := newNoncePool()
+	calls := 0
+	fetch := fixedNonces(8, []uint64{5, 3}, &calls)
+
+	var nonces []uint64
+	for i := 0; i < 3; i++ {
+		nonce, err := pool.reserve(context.Background(), fetch)
+		require.NoError(t, err)
+		nonces = append(nonces, nonce)
+	}
+
+	assert.Equal(t, []uint64{3, 5, 8}, nonces)
+}
+
+func TestNoncePool_Observe(t *testing.T) {
+	pool := newNoncePool()
+	calls := 0
+	fetch := fixedNonces(0, nil, &calls)
+
+	for i := 0; i < 3; i++ {
+		_, err := pool.reserve(context.Background(), fetch)
+		require.NoError(t, err)
+	}
+	pool.unused(2)
+
+	// Nonces 0 and 1 executed; 2 was never broadcast but the account has moved past it
+	pool.observe(3)
+	assert.Equal(t, 0, pool.pendingCount())
+
+	next, pending, err := pool.snapshot(context.Background(), fetch)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(3), next)
+	assert.Equal(t, 0, pending)
+}
+
+func TestNoncePool_FetchError(t *testing.T) {
+	pool := newNoncePool()
+	fetch := func(ctx context.Context) (uint64, []uint64, error) {
+		return 0, nil, errors.New("unavailable")
+	}
+
+	_, err := pool.reserve(context.Background(), fetch)
+	assert.Error(t, err)
+	assert.Equal(t, 0, pool.pendingCount())
+}
@@ -2,11 +2,19 @@ package blockchain
 
 import (
 	"context"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log"
+	"net/url"
+	"strings"
 	"time"
 
+	"github.com/x402stacks/stacks-facilitator/internal/payment/application/command"
 	"github.com/x402stacks/stacks-facilitator/internal/payment/domain/service"
 	"github.com/x402stacks/stacks-facilitator/internal/payment/domain/valueobject"
 	"github.com/x402stacks/stacks-facilitator/internal/stacks"
+	"github.com/x402stacks/stacks-facilitator/internal/stacks/transaction"
 )
 
 // StacksClientAdapter adapts the Stacks client for use in the domain layer
@@ -23,19 +31,28 @@ func NewStacksClientAdapter() *StacksClientAdapter {
 	}
 }
 
+// NewStacksClientAdapterWithClients creates a StacksClientAdapter using preconfigured clients
+func NewStacksClientAdapterWithClients(mainnetClient, testnetClient *stacks.Client) *StacksClientAdapter {
+	return &StacksClientAdapter{
+		mainnetClient: mainnetClient,
+		testnetClient: testnetClient,
+	}
+}
+
 // GetTransaction fetches a transaction from the blockchain
 func (a *StacksClientAdapter) GetTransaction(ctx context.Context, txID valueobject.TransactionID, tokenType valueobject.TokenType, network valueobject.Network) (service.BlockchainTransaction, error) {
-	client := a.getClientForNetwork(network)
-	return client.GetTransactionWithTokenType(ctx, txID, tokenType, network)
+	return a.getTransaction(ctx, a.getClientForNetwork(network), txID, tokenType, network)
 }
 
-// GetTransactionWithRetry fetches a transaction with retry logic
+// GetTransactionWithRetry fetches a transaction with retry logic. If every attempt fails,
+// the last error is classified as command.ErrTransactionNotFound or
+// command.ErrTransactionUnavailable where it is one of those.
 func (a *StacksClientAdapter) GetTransactionWithRetry(ctx context.Context, txID valueobject.TransactionID, tokenType valueobject.TokenType, network valueobject.Network, maxRetries int, retryDelay time.Duration) (service.BlockchainTransaction, error) {
 	client := a.getClientForNetwork(network)
 
 	var lastErr error
 	for i := 0; i < maxRetries; i++ {
-		tx, err := client.GetTransactionWithTokenType(ctx, txID, tokenType, network)
+		tx, err := a.getTransaction(ctx, client, txID, tokenType, network)
 		if err == nil {
 			return tx, nil
 		}
@@ -48,19 +65,39 @@ func (a *StacksClientAdapter) GetTransactionWithRetry(ctx context.Context, txID
 		}
 	}
 
-	return service.BlockchainTransaction{}, lastErr
+	return service.BlockchainTransaction{}, lookupError(txID, lastErr)
 }
 
-// WaitForConfirmation waits for a transaction to be confirmed
+// lookupError classifies a failed transaction lookup for the command layer: an unknown
+// transaction, or an API that could not be reached or answered with a transient error
+func lookupError(txID valueobject.TransactionID, err error) error {
+	if errors.Is(err, stacks.ErrTransactionNotFound) {
+		return fmt.Errorf("%w: %s", command.ErrTransactionNotFound, txID)
+	}
+
+	var apiErr *stacks.APIError
+	var urlErr *url.Error
+	if errors.As(err, &apiErr) && apiErr.Transient() || errors.As(err, &urlErr) {
+		return fmt.Errorf("%w: %v", command.ErrTransactionUnavailable, err)
+	}
+
+	return err
+}
+
+// WaitForConfirmation waits for a transaction to be confirmed, fetching it up to maxRetries
+// times (at least once) with retryDelay between fetches. It returns early once the
+// transaction has failed or been dropped from the mempool, since waiting cannot change either,
+// and otherwise returns the last fetch even if not confirmed.
 func (a *StacksClientAdapter) WaitForConfirmation(ctx context.Context, txID valueobject.TransactionID, tokenType valueobject.TokenType, network valueobject.Network, maxRetries int, retryDelay time.Duration) (service.BlockchainTransaction, error) {
 	client := a.getClientForNetwork(network)
 
-	for i := 0; i < maxRetries; i++ {
-		tx, err := client.GetTransactionWithTokenType(ctx, txID, tokenType, network)
-		if err == nil {
-			if tx.IsConfirmed || stacks.IsTransactionFailed(tx.Status) {
-				return tx, nil
-			}
+	for i := 1; ; i++ {
+		tx, err := a.getTransaction(ctx, client, txID, tokenType, network)
+		if i >= maxRetries {
+			return tx, err
+		}
+		if err == nil && (tx.IsConfirmed || tx.Status.IsFailed() || tx.Status.IsDropped()) {
+			return tx, nil
 		}
 
 		select {
@@ -69,15 +106,56 @@ func (a *StacksClientAdapter) WaitForConfirmation(ctx context.Context, txID valu
 		case <-time.After(retryDelay):
 		}
 	}
+}
 
-	// Return last fetched transaction even if not confirmed
-	return client.GetTransactionWithTokenType(ctx, txID, tokenType, network)
+// getTransaction fetches a transaction, logging a tx_status the client did not recognize
+func (a *StacksClientAdapter) getTransaction(ctx context.Context, client *stacks.Client, txID valueobject.TransactionID, tokenType valueobject.TokenType, network valueobject.Network) (service.BlockchainTransaction, error) {
+	tx, err := client.GetTransactionWithTokenType(ctx, txID, tokenType, network)
+	if err == nil && tx.UnknownStatus != "" {
+		log.Printf("stacks: transaction %s has unrecognized status %q, treating it as pending", txID, tx.UnknownStatus)
+	}
+	return tx, err
 }
 
-// BroadcastTransaction broadcasts a signed transaction
+// BroadcastTransaction broadcasts a signed transaction. Broadcasting a transaction that
+// was already accepted is not an error: the node rejects it for reusing its own nonce,
+// and if the API knows the transaction's txid that txid is returned, so settling the
+// same transaction again resumes confirmation polling. Other node rejections, and a
+// nonce rejection of a transaction the API does not know, are returned as a
+// command.BroadcastRejectedError; a failed lookup is returned as is.
 func (a *StacksClientAdapter) BroadcastTransaction(ctx context.Context, signedTx string, network valueobject.Network) (valueobject.TransactionID, error) {
 	client := a.getClientForNetwork(network)
-	return client.BroadcastTransaction(ctx, signedTx)
+	txID, err := client.BroadcastTransaction(ctx, signedTx)
+	if err == nil {
+		return txID, nil
+	}
+	if !isNonceRejection(err) {
+		return valueobject.TransactionID{}, broadcastRejection(err)
+	}
+
+	raw, decodeErr := hex.DecodeString(strings.TrimPrefix(signedTx, "0x"))
+	if decodeErr != nil {
+		return valueobject.TransactionID{}, broadcastRejection(err)
+	}
+	known := transaction.TxID(raw)
+	exists, lookupErr := client.TransactionExists(ctx, known)
+	if lookupErr != nil {
+		return valueobject.TransactionID{}, fmt.Errorf("failed to look up rejected transaction %s: %w", known, lookupErr)
+	}
+	if !exists {
+		return valueobject.TransactionID{}, broadcastRejection(err)
+	}
+	return known, nil
+}
+
+// isNonceRejection reports whether the node refused a transaction for its nonce, as it
+// does when the transaction itself is already in the mempool or mined
+func isNonceRejection(err error) bool {
+	var broadcastErr *stacks.BroadcastError
+	if !errors.As(err, &broadcastErr) {
+		return false
+	}
+	return broadcastErr.Reason == reasonBadNonce || broadcastErr.Reason == reasonConflictingNonceInMempool
 }
 
 // getClientForNetwork returns the appropriate client for the network
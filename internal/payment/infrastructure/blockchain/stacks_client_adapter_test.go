@@ -0,0 +1,195 @@
+package blockchain
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/x402stacks/stacks-facilitator/internal/payment/application/command"
+	"github.com/x402stacks/stacks-facilitator/internal/payment/domain/valueobject"
+	"github.com/x402stacks/stacks-facilitator/internal/stacks"
+)
+
+// statusServer reports a pending transaction until the given poll, then finalStatus
+func statusServer(t *testing.T, finalStatus string, finalAfter int32) (*StacksClientAdapter, *atomic.Int32) {
+	t.Helper()
+
+	var polls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		status := "pending"
+		if polls.Add(1) >= finalAfter {
+			status = finalStatus
+		}
+		fmt.Fprintf(w, `{
+			"tx_id": "0x1234567890abcdef1234567890abcdef1234567890abcdef1234567890abcdef",
+			"tx_status": %q,
+			"replaced_by_tx_id": "0xabababababababababababababababababababababababababababababababab",
+			"tx_type": "token_transfer",
+			"sender_address": "ST2J6ZY48GV1EZ5V2V5RB9MP66SW86PYKKQYAC0RQ",
+			"token_transfer": {"recipient_address": "ST1PQHQKV0RJXZFY1DGX8MNSNYVE3VGZJSRTPGZGM", "amount": "1000"}
+		}`, status)
+	}))
+	t.Cleanup(server.Close)
+
+	client := stacks.NewClient(server.URL)
+	return NewStacksClientAdapterWithClients(client, client), &polls
+}
+
+func TestStacksClientAdapter_WaitForConfirmation_StopsWhenDropped(t *testing.T) {
+	adapter, polls := statusServer(t, "dropped_replace_by_fee", 2)
+	txID, _ := valueobject.NewTransactionID("0x1234567890abcdef1234567890abcdef1234567890abcdef1234567890abcdef")
+
+	tx, err := adapter.WaitForConfirmation(context.Background(), txID, valueobject.TokenSTX, valueobject.NetworkTestnet, 10, time.Millisecond)
+
+	require.NoError(t, err)
+	assert.Equal(t, int32(2), polls.Load())
+	assert.Equal(t, valueobject.TxStatusDroppedReplaceByFee, tx.Status)
+	assert.Equal(t, "0xabababababababababababababababababababababababababababababababab", tx.ReplacedBy.String())
+}
+
+func TestStacksClientAdapter_WaitForConfirmation_KeepsPollingWhilePending(t *testing.T) {
+	adapter, polls := statusServer(t, "pending", 1)
+	txID, _ := valueobject.NewTransactionID("0x1234567890abcdef1234567890abcdef1234567890abcdef1234567890abcdef")
+
+	tx, err := adapter.WaitForConfirmation(context.Background(), txID, valueobject.TokenSTX, valueobject.NetworkTestnet, 3, time.Millisecond)
+
+	require.NoError(t, err)
+	assert.Equal(t, int32(3), polls.Load())
+	assert.Equal(t, valueobject.TxStatusPending, tx.Status)
+	assert.True(t, tx.ReplacedBy.IsZero())
+}
+
+func TestStacksClientAdapter_WaitForConfirmation_SingleAttempt(t *testing.T) {
+	adapter, polls := statusServer(t, "pending", 1)
+	txID, _ := valueobject.NewTransactionID("0x1234567890abcdef1234567890abcdef1234567890abcdef1234567890abcdef")
+
+	start := time.Now()
+	tx, err := adapter.WaitForConfirmation(context.Background(), txID, valueobject.TokenSTX, valueobject.NetworkTestnet, 1, time.Hour)
+
+	require.NoError(t, err)
+	assert.Equal(t, int32(1), polls.Load())
+	assert.Less(t, time.Since(start), time.Minute, "no delay after the only fetch")
+	assert.Equal(t, valueobject.TxStatusPending, tx.Status)
+}
+
+func TestStacksClientAdapter_GetTransactionWithRetry_ClassifiesFailures(t *testing.T) {
+	tests := []struct {
+		name    string
+		status  int
+		wantErr error
+	}{
+		{"not found", http.StatusNotFound, command.ErrTransactionNotFound},
+		{"rate limited", http.StatusTooManyRequests, command.ErrTransactionUnavailable},
+		{"api failing", http.StatusServiceUnavailable, command.ErrTransactionUnavailable},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tt.status)
+			}))
+			t.Cleanup(server.Close)
+			client := stacks.NewClient(server.URL)
+			adapter := NewStacksClientAdapterWithClients(client, client)
+			txID, _ := valueobject.NewTransactionID("0x1234567890abcdef1234567890abcdef1234567890abcdef1234567890abcdef")
+
+			_, err := adapter.GetTransactionWithRetry(context.Background(), txID, valueobject.TokenSTX, valueobject.NetworkTestnet, 2, time.Millisecond)
+
+			assert.ErrorIs(t, err, tt.wantErr)
+		})
+	}
+
+	t.Run("unreachable", func(t *testing.T) {
+		server := httptest.NewServer(http.NotFoundHandler())
+		server.Close()
+		client := stacks.NewClient(server.URL)
+		adapter := NewStacksClientAdapterWithClients(client, client)
+		txID, _ := valueobject.NewTransactionID("0x1234567890abcdef1234567890abcdef1234567890abcdef1234567890abcdef")
+
+		_, err := adapter.GetTransactionWithRetry(context.Background(), txID, valueobject.TokenSTX, valueobject.NetworkTestnet, 1, time.Millisecond)
+
+		assert.ErrorIs(t, err, command.ErrTransactionUnavailable)
+	})
+
+	t.Run("other api error", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusBadRequest)
+		}))
+		t.Cleanup(server.Close)
+		client := stacks.NewClient(server.URL)
+		adapter := NewStacksClientAdapterWithClients(client, client)
+		txID, _ := valueobject.NewTransactionID("0x1234567890abcdef1234567890abcdef1234567890abcdef1234567890abcdef")
+
+		_, err := adapter.GetTransactionWithRetry(context.Background(), txID, valueobject.TokenSTX, valueobject.NetworkTestnet, 1, time.Millisecond)
+
+		var apiErr *stacks.APIError
+		assert.ErrorAs(t, err, &apiErr)
+		assert.NotErrorIs(t, err, command.ErrTransactionNotFound)
+		assert.NotErrorIs(t, err, command.ErrTransactionUnavailable)
+	})
+}
+
+func TestStacksClientAdapter_BroadcastTransaction_AlreadyBroadcast(t *testing.T) {
+	// SHA512/256 of the transaction bytes 00000001deadbeef
+	const localTxID = "0xf0c4b6db6c7ce3e523a945d2051f3e7697886e60ce9271848f3acd0673b1852e"
+
+	tests := []struct {
+		name         string
+		rejection    string
+		lookupStatus int
+		wantLookup   bool
+		wantRejected bool
+		wantErr      bool
+	}{
+		{"mined transaction", `{"error":"transaction rejected","reason":"BadNonce","reason_data":{"expected":6,"actual":5,"is_origin":true}}`, http.StatusOK, true, false, false},
+		{"transaction in mempool", `{"error":"transaction rejected","reason":"ConflictingNonceInMempool"}`, http.StatusOK, true, false, false},
+		{"other transaction with the nonce", `{"error":"transaction rejected","reason":"BadNonce"}`, http.StatusNotFound, true, true, true},
+		{"lookup failing", `{"error":"transaction rejected","reason":"BadNonce"}`, http.StatusServiceUnavailable, true, false, true},
+		{"other rejection", `{"error":"transaction rejected","reason":"NotEnoughFunds"}`, http.StatusOK, false, true, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var lookups atomic.Int32
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.Method == http.MethodPost {
+					w.WriteHeader(http.StatusBadRequest)
+					fmt.Fprint(w, tt.rejection)
+					return
+				}
+
+				lookups.Add(1)
+				assert.Equal(t, "/extended/v1/tx/"+localTxID, r.URL.Path)
+				// Only the status matters; the body is not parsed
+				w.WriteHeader(tt.lookupStatus)
+				fmt.Fprint(w, `{"tx_id": "`+localTxID+`"}`)
+			}))
+			t.Cleanup(server.Close)
+			client := stacks.NewClient(server.URL)
+			adapter := NewStacksClientAdapterWithClients(client, client)
+
+			txID, err := adapter.BroadcastTransaction(context.Background(), "0x00000001deadbeef", valueobject.NetworkTestnet)
+
+			assert.Equal(t, tt.wantLookup, lookups.Load() > 0)
+			if tt.wantErr {
+				var rejected *command.BroadcastRejectedError
+				assert.Equal(t, tt.wantRejected, errors.As(err, &rejected))
+				if !tt.wantRejected {
+					var apiErr *stacks.APIError
+					require.ErrorAs(t, err, &apiErr)
+					assert.Equal(t, http.StatusServiceUnavailable, apiErr.StatusCode)
+				}
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, localTxID, txID.String())
+		})
+	}
+}
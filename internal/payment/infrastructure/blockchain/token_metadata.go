@@ -0,0 +1,55 @@
+package blockchain
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/x402stacks/stacks-facilitator/internal/payment/domain/service"
+	"github.com/x402stacks/stacks-facilitator/internal/payment/domain/valueobject"
+	"github.com/x402stacks/stacks-facilitator/internal/stacks/clarity"
+)
+
+// maxTokenDecimals is the most decimal places a u128 balance can meaningfully carry
+const maxTokenDecimals = 38
+
+// FetchTokenMetadata reads a SIP-010 token's symbol and decimals with the
+// get-symbol and get-decimals read-only functions of its contract
+func (a *StacksClientAdapter) FetchTokenMetadata(ctx context.Context, contract service.TokenContract, network valueobject.Network) (service.TokenMetadata, error) {
+	client := a.getClientForNetwork(network)
+	sender, _, _ := strings.Cut(contract.ContractID, ".")
+
+	result, err := client.CallReadOnly(ctx, contract.ContractID, "get-decimals", sender)
+	if err != nil {
+		return service.TokenMetadata{}, err
+	}
+	decimals, ok := okValue(result).(clarity.UInt)
+	if !ok || decimals.Value == nil || !decimals.Value.IsUint64() || decimals.Value.Uint64() > maxTokenDecimals {
+		return service.TokenMetadata{}, fmt.Errorf("%s get-decimals returned %v, expected (ok uint) up to %d", contract.ContractID, result, maxTokenDecimals)
+	}
+
+	result, err = client.CallReadOnly(ctx, contract.ContractID, "get-symbol", sender)
+	if err != nil {
+		return service.TokenMetadata{}, err
+	}
+	var symbol string
+	switch v := okValue(result).(type) {
+	case clarity.StringASCII:
+		symbol = string(v)
+	case clarity.StringUTF8:
+		symbol = string(v)
+	}
+	if symbol == "" {
+		return service.TokenMetadata{}, fmt.Errorf("%s get-symbol returned %v, expected (ok string)", contract.ContractID, result)
+	}
+
+	return service.TokenMetadata{Symbol: symbol, Decimals: int(decimals.Value.Uint64())}, nil
+}
+
+// okValue unwraps an (ok ...) response, returning nil for anything else
+func okValue(v clarity.Value) clarity.Value {
+	if ok, isOk := v.(clarity.ResponseOk); isOk {
+		return ok.Value
+	}
+	return nil
+}
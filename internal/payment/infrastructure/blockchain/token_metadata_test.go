@@ -0,0 +1,80 @@
+package blockchain
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/x402stacks/stacks-facilitator/internal/payment/domain/service"
+	"github.com/x402stacks/stacks-facilitator/internal/payment/domain/valueobject"
+	"github.com/x402stacks/stacks-facilitator/internal/stacks"
+	"github.com/x402stacks/stacks-facilitator/internal/stacks/clarity"
+)
+
+var alexContract = service.TokenContract{
+	ContractID: "SP3K8BC0PPEVCV7NZ6QSRWPQ2JE9E5B6N3PA0KBR9.token-alex",
+	AssetName:  "alex",
+}
+
+// readOnlyServer answers read-only calls with the encoded value for each function name
+func readOnlyServer(t *testing.T, results map[string]clarity.Value) *stacks.Client {
+	t.Helper()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		function := r.URL.Path[strings.LastIndex(r.URL.Path, "/")+1:]
+		result, ok := results[function]
+		if !ok {
+			w.Write([]byte(`{"okay":false,"cause":"NoSuchPublicFunction"}`))
+			return
+		}
+		encoded, err := clarity.EncodeHex(result)
+		require.NoError(t, err)
+		w.Write([]byte(`{"okay":true,"result":"` + encoded + `"}`))
+	}))
+	t.Cleanup(server.Close)
+
+	return stacks.NewClient(server.URL)
+}
+
+func TestStacksClientAdapter_FetchTokenMetadata(t *testing.T) {
+	client := readOnlyServer(t, map[string]clarity.Value{
+		"get-decimals": clarity.ResponseOk{Value: clarity.NewUInt(8)},
+		"get-symbol":   clarity.ResponseOk{Value: clarity.StringASCII("ALEX")},
+	})
+	adapter := NewStacksClientAdapterWithClients(client, nil)
+
+	metadata, err := adapter.FetchTokenMetadata(context.Background(), alexContract, valueobject.NetworkMainnet)
+
+	require.NoError(t, err)
+	assert.Equal(t, service.TokenMetadata{Symbol: "ALEX", Decimals: 8}, metadata)
+}
+
+func TestStacksClientAdapter_FetchTokenMetadata_Invalid(t *testing.T) {
+	tests := []struct {
+		name    string
+		results map[string]clarity.Value
+	}{
+		{"missing get-decimals", map[string]clarity.Value{"get-symbol": clarity.ResponseOk{Value: clarity.StringASCII("ALEX")}}},
+		{"decimals not ok", map[string]clarity.Value{"get-decimals": clarity.NewUInt(8)}},
+		{"too many decimals", map[string]clarity.Value{"get-decimals": clarity.ResponseOk{Value: clarity.NewUInt(39)}}},
+		{"missing get-symbol", map[string]clarity.Value{"get-decimals": clarity.ResponseOk{Value: clarity.NewUInt(8)}}},
+		{"symbol not a string", map[string]clarity.Value{
+			"get-decimals": clarity.ResponseOk{Value: clarity.NewUInt(8)},
+			"get-symbol":   clarity.ResponseOk{Value: clarity.NewUInt(1)},
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			adapter := NewStacksClientAdapterWithClients(nil, readOnlyServer(t, tt.results))
+
+			_, err := adapter.FetchTokenMetadata(context.Background(), alexContract, valueobject.NetworkTestnet)
+
+			assert.Error(t, err)
+		})
+	}
+}
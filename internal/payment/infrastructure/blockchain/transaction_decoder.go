@@ -0,0 +1,150 @@
+package blockchain
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+
+	"github.com/x402stacks/stacks-facilitator/internal/payment/domain/service"
+	"github.com/x402stacks/stacks-facilitator/internal/payment/domain/valueobject"
+	"github.com/x402stacks/stacks-facilitator/internal/stacks/clarity"
+	"github.com/x402stacks/stacks-facilitator/internal/stacks/transaction"
+)
+
+// TransactionDecoder decodes signed Stacks transactions into payment transfers
+type TransactionDecoder struct{}
+
+// NewTransactionDecoder creates a new TransactionDecoder
+func NewTransactionDecoder() *TransactionDecoder {
+	return &TransactionDecoder{}
+}
+
+// DecodeTransaction decodes a hex-encoded signed transaction and extracts the transfer it makes.
+// Native STX transfers and SIP-010 transfer calls are supported. The TxID is that of the
+// transaction as given, which a sponsor's counter-signature changes.
+func (d *TransactionDecoder) DecodeTransaction(signedTx string, tokenType valueobject.TokenType) (service.BlockchainTransaction, valueobject.Network, error) {
+	tx, err := transaction.DecodeHex(signedTx)
+	if err != nil {
+		return service.BlockchainTransaction{}, "", err
+	}
+
+	network, err := tx.Network()
+	if err != nil {
+		return service.BlockchainTransaction{}, "", err
+	}
+
+	txID, err := tx.TxID()
+	if err != nil {
+		return service.BlockchainTransaction{}, "", err
+	}
+
+	result := service.BlockchainTransaction{
+		TxID:      txID,
+		TokenType: tokenType,
+		Sender:    tx.OriginAddress(),
+		Fee:       valueobject.NewAmount(tx.Fee()),
+		Nonce:     tx.Auth.Origin.Nonce,
+		Status:    valueobject.TxStatusPending,
+		Sponsored: tx.IsSponsored(),
+	}
+
+	switch payload := tx.Payload.(type) {
+	case transaction.TokenTransferPayload:
+		recipient, err := principal(payload.Recipient)
+		if err != nil {
+			return service.BlockchainTransaction{}, "", err
+		}
+		result.Recipient = recipient
+		result.Amount = valueobject.NewAmount(payload.Amount)
+		result.Memo = payload.MemoString()
+	case transaction.ContractCallPayload:
+		if err := decodeSIP010Transfer(payload, &result); err != nil {
+			return service.BlockchainTransaction{}, "", err
+		}
+	default:
+		return service.BlockchainTransaction{}, "", fmt.Errorf("unsupported payload type 0x%02x", byte(tx.Payload.PayloadType()))
+	}
+
+	return result, network, nil
+}
+
+// RecoverSigner returns the origin address of a hex-encoded signed transaction, recovered
+// from its single-sig or multisig signatures. The recovered keys must hash to the origin's
+// signer hash; errors otherwise wrap transaction.ErrInvalidSignature.
+func (d *TransactionDecoder) RecoverSigner(signedTx string) (valueobject.StacksAddress, error) {
+	tx, err := transaction.DecodeHex(signedTx)
+	if err != nil {
+		return valueobject.StacksAddress{}, err
+	}
+	return tx.RecoverOrigin()
+}
+
+// decodeSIP010Transfer reads the positional arguments of
+// (transfer (amount uint) (sender principal) (recipient principal) (memo (optional (buff 34))))
+func decodeSIP010Transfer(call transaction.ContractCallPayload, result *service.BlockchainTransaction) error {
+	if call.FunctionName != "transfer" {
+		return fmt.Errorf("unsupported contract function: %s", call.FunctionName)
+	}
+	if len(call.Args) < 3 || len(call.Args) > 4 {
+		return fmt.Errorf("transfer expects 3 or 4 arguments, got %d", len(call.Args))
+	}
+
+	amountArg, ok := call.Args[0].(clarity.UInt)
+	if !ok {
+		return errors.New("transfer amount is not a uint")
+	}
+	amount, err := valueobject.NewAmountFromBigInt(amountArg.Value)
+	if err != nil {
+		return fmt.Errorf("invalid transfer amount: %w", err)
+	}
+
+	sender, err := principalAddress(call.Args[1])
+	if err != nil {
+		return fmt.Errorf("invalid transfer sender: %w", err)
+	}
+	if !sender.Equals(result.Sender) {
+		return fmt.Errorf("transfer sender %s does not match transaction origin %s", sender.String(), result.Sender.String())
+	}
+
+	recipient, err := principal(call.Args[2])
+	if err != nil {
+		return fmt.Errorf("invalid transfer recipient: %w", err)
+	}
+
+	if len(call.Args) == 4 {
+		switch memo := call.Args[3].(type) {
+		case clarity.None:
+		case clarity.Some:
+			buf, ok := memo.Value.(clarity.Buffer)
+			if !ok {
+				return errors.New("transfer memo is not a buffer")
+			}
+			result.Memo = string(bytes.TrimRight(buf, "\x00"))
+		default:
+			return errors.New("transfer memo is not an optional")
+		}
+	}
+
+	result.Recipient = recipient
+	result.Amount = amount
+	result.ContractID = call.ContractID()
+	return nil
+}
+
+// principalAddress converts a Clarity standard principal to a StacksAddress
+func principalAddress(v clarity.Value) (valueobject.StacksAddress, error) {
+	p, ok := v.(clarity.StandardPrincipal)
+	if !ok {
+		return valueobject.StacksAddress{}, errors.New("value is not a standard principal")
+	}
+	return valueobject.NewStacksAddress(p.String())
+}
+
+// principal converts a Clarity standard or contract principal to a Principal
+func principal(v clarity.Value) (valueobject.Principal, error) {
+	s, ok := clarity.PrincipalString(v)
+	if !ok {
+		return valueobject.Principal{}, errors.New("value is not a principal")
+	}
+	return valueobject.NewPrincipal(s)
+}
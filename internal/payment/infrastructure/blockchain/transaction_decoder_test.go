@@ -0,0 +1,192 @@
+package blockchain
+
+import (
+	"crypto/sha512"
+	"encoding/binary"
+	"encoding/hex"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/x402stacks/stacks-facilitator/internal/payment/domain/valueobject"
+	"github.com/x402stacks/stacks-facilitator/internal/stacks/secp256k1"
+	"github.com/x402stacks/stacks-facilitator/internal/stacks/transaction"
+)
+
+// Unsigned testnet transactions from ST2J6ZY48GV1EZ5V2V5RB9MP66SW86PYKKQYAC0RQ to ST1PQHQKV0RJXZFY1DGX8MNSNYVE3VGZJSRTPGZGM
+const (
+	// 1 STX token_transfer, nonce 7, fee 180, memo "invoice-42"
+	stxTransferHex = "80800000000400a46ff88886c2ef9762d970b4d2c63678835bd39d000000000000000700000000000000b4" +
+		"00" + zeroSignatureHex + "03020000000000051a6d78de7b0625dfbfc16c3a8a5735f6dc3dc3f2ce00000000000f4240" +
+		"696e766f6963652d3432000000000000000000000000000000000000000000000000"
+
+	// sbtc-token transfer of 2500 with memo (some 0x616263)
+	sbtcTransferHex = "80800000000400a46ff88886c2ef9762d970b4d2c63678835bd39d000000000000000300000000000001f4" +
+		"00" + zeroSignatureHex + "030200000000021a5e7ba8546bc27ca0077594336b3942a8e7f893520a736274632d746f6b656e" +
+		"087472616e736665720000000401000000000000000000000000000009c4051aa46ff88886c2ef9762d970b4d2c63678835bd39d" +
+		"051a6d78de7b0625dfbfc16c3a8a5735f6dc3dc3f2ce0a0200000003616263"
+
+	// sbtc-token transfer whose sender argument is not the origin
+	spoofedSenderHex = "80800000000400a46ff88886c2ef9762d970b4d2c63678835bd39d000000000000000300000000000001f4" +
+		"00" + zeroSignatureHex + "030200000000021a5e7ba8546bc27ca0077594336b3942a8e7f893520a736274632d746f6b656e" +
+		"087472616e736665720000000301000000000000000000000000000009c4051a6d78de7b0625dfbfc16c3a8a5735f6dc3dc3f2ce" +
+		"051a6d78de7b0625dfbfc16c3a8a5735f6dc3dc3f2ce"
+
+	// sbtc-token mint call
+	mintCallHex = "80800000000400a46ff88886c2ef9762d970b4d2c63678835bd39d000000000000000300000000000001f4" +
+		"00" + zeroSignatureHex + "030200000000021a5e7ba8546bc27ca0077594336b3942a8e7f893520a736274632d746f6b656e" +
+		"046d696e7400000000"
+
+	// 1 STX token_transfer to the contract principal ST1PQHQKV0RJXZFY1DGX8MNSNYVE3VGZJSRTPGZGM.my-vault
+	contractRecipientHex = "80800000000400a46ff88886c2ef9762d970b4d2c63678835bd39d000000000000000700000000000000b4" +
+		"00" + zeroSignatureHex + "03020000000000061a6d78de7b0625dfbfc16c3a8a5735f6dc3dc3f2ce086d792d7661756c7400000000000f4240" +
+		"696e766f6963652d3432000000000000000000000000000000000000000000000000"
+
+	// Sponsored 1 STX token_transfer, nonce 7, with a blank sponsor condition
+	sponsoredTransferHex = "80800000000500a46ff88886c2ef9762d970b4d2c63678835bd39d00000000000000070000000000000000" +
+		"00" + zeroSignatureHex + "00" + "0000000000000000000000000000000000000000" + "00000000000000000000000000000000" +
+		"00" + zeroSignatureHex + "03020000000000051a6d78de7b0625dfbfc16c3a8a5735f6dc3dc3f2ce00000000000f4240" +
+		"696e766f6963652d3432000000000000000000000000000000000000000000000000"
+
+	zeroSignatureHex = "0000000000000000000000000000000000000000000000000000000000000000" +
+		"0000000000000000000000000000000000000000000000000000000000000000" + "00"
+)
+
+func TestTransactionDecoder_STXTransfer(t *testing.T) {
+	decoder := NewTransactionDecoder()
+
+	tx, network, err := decoder.DecodeTransaction(stxTransferHex, valueobject.TokenSTX)
+
+	require.NoError(t, err)
+	assert.Equal(t, valueobject.NetworkTestnet, network)
+	raw, err := hex.DecodeString(stxTransferHex)
+	require.NoError(t, err)
+	assert.Equal(t, transaction.TxID(raw), tx.TxID)
+	assert.Equal(t, "ST2J6ZY48GV1EZ5V2V5RB9MP66SW86PYKKQYAC0RQ", tx.Sender.String())
+	assert.Equal(t, "ST1PQHQKV0RJXZFY1DGX8MNSNYVE3VGZJSRTPGZGM", tx.Recipient.String())
+	assert.Equal(t, "1000000", tx.Amount.String())
+	assert.Equal(t, "180", tx.Fee.String())
+	assert.Equal(t, uint64(7), tx.Nonce)
+	assert.Equal(t, "invoice-42", tx.Memo)
+	assert.Empty(t, tx.ContractID)
+	assert.Equal(t, valueobject.TxStatusPending, tx.Status)
+	assert.False(t, tx.IsConfirmed)
+	assert.False(t, tx.Sponsored)
+}
+
+func TestTransactionDecoder_ContractPrincipalRecipient(t *testing.T) {
+	decoder := NewTransactionDecoder()
+
+	tx, _, err := decoder.DecodeTransaction(contractRecipientHex, valueobject.TokenSTX)
+
+	require.NoError(t, err)
+	assert.True(t, tx.Recipient.IsContract())
+	assert.Equal(t, "ST1PQHQKV0RJXZFY1DGX8MNSNYVE3VGZJSRTPGZGM.my-vault", tx.Recipient.String())
+	assert.Equal(t, "1000000", tx.Amount.String())
+}
+
+func TestTransactionDecoder_Sponsored(t *testing.T) {
+	decoder := NewTransactionDecoder()
+
+	tx, network, err := decoder.DecodeTransaction(sponsoredTransferHex, valueobject.TokenSTX)
+
+	require.NoError(t, err)
+	assert.Equal(t, valueobject.NetworkTestnet, network)
+	assert.True(t, tx.Sponsored)
+	assert.Equal(t, "ST2J6ZY48GV1EZ5V2V5RB9MP66SW86PYKKQYAC0RQ", tx.Sender.String())
+	assert.Equal(t, "1000000", tx.Amount.String())
+	assert.Equal(t, "0", tx.Fee.String())
+}
+
+func TestTransactionDecoder_SIP010Transfer(t *testing.T) {
+	decoder := NewTransactionDecoder()
+
+	tx, network, err := decoder.DecodeTransaction("0x"+sbtcTransferHex, valueobject.TokenSBTC)
+
+	require.NoError(t, err)
+	assert.Equal(t, valueobject.NetworkTestnet, network)
+	assert.Equal(t, valueobject.TokenSBTC, tx.TokenType)
+	assert.Equal(t, "ST1F7QA2MDF17S807EPA36TSS8AMEFY4KA9TVGWXT.sbtc-token", tx.ContractID)
+	assert.Equal(t, "ST1PQHQKV0RJXZFY1DGX8MNSNYVE3VGZJSRTPGZGM", tx.Recipient.String())
+	assert.Equal(t, "2500", tx.Amount.String())
+	assert.Equal(t, "abc", tx.Memo)
+}
+
+func TestTransactionDecoder_SIP010AmountBeyondUint64(t *testing.T) {
+	decoder := NewTransactionDecoder()
+	// u18446744073709554116 (2^64 + 2500)
+	txHex := strings.Replace(sbtcTransferHex, "01000000000000000000000000000009c4", "01000000000000000100000000000009c4", 1)
+
+	tx, _, err := decoder.DecodeTransaction(txHex, valueobject.TokenSBTC)
+
+	require.NoError(t, err)
+	assert.Equal(t, "18446744073709554116", tx.Amount.String())
+}
+
+func TestTransactionDecoder_Rejects(t *testing.T) {
+	tests := []struct {
+		name      string
+		hex       string
+		wantError string
+	}{
+		{"malformed", "0x0080", "malformed transaction"},
+		{"spoofed sender", spoofedSenderHex, "does not match transaction origin"},
+		{"non-transfer call", mintCallHex, "unsupported contract function: mint"},
+	}
+
+	decoder := NewTransactionDecoder()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, _, err := decoder.DecodeTransaction(tt.hex, valueobject.TokenSBTC)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantError)
+		})
+	}
+}
+
+// signedTransferHex re-signs the STX transfer fixture with key as its P2PKH origin
+func signedTransferHex(t *testing.T, key *secp256k1.PrivateKey) string {
+	t.Helper()
+	tx, err := transaction.DecodeHex(stxTransferHex)
+	require.NoError(t, err)
+	tx.Auth.Origin.Signer = key.PublicKey().Hash160()
+
+	initial, err := tx.InitialSigHash()
+	require.NoError(t, err)
+	presign := append(initial[:], byte(transaction.AuthStandard))
+	presign = binary.BigEndian.AppendUint64(presign, tx.Auth.Origin.Fee)
+	presign = binary.BigEndian.AppendUint64(presign, tx.Auth.Origin.Nonce)
+	tx.Auth.Origin.Signature = key.Sign(sha512.Sum512_256(presign))
+
+	signed, err := tx.EncodeHex()
+	require.NoError(t, err)
+	return signed
+}
+
+func TestTransactionDecoder_RecoverSigner(t *testing.T) {
+	key, err := secp256k1.ParsePrivateKey(strings.Repeat("42", 32) + "01")
+	require.NoError(t, err)
+	other, err := secp256k1.ParsePrivateKey(strings.Repeat("24", 32) + "01")
+	require.NoError(t, err)
+	decoder := NewTransactionDecoder()
+
+	signed := signedTransferHex(t, key)
+	signer, err := decoder.RecoverSigner(signed)
+	require.NoError(t, err)
+	assert.Equal(t, valueobject.NewStacksAddressFromHash160(valueobject.AddressVersionTestnetSingleSig, key.PublicKey().Hash160()), signer)
+
+	// Signed by one key but claiming another as its signer
+	forged, err := transaction.DecodeHex(signed)
+	require.NoError(t, err)
+	forged.Auth.Origin.Signer = other.PublicKey().Hash160()
+	forgedHex, err := forged.EncodeHex()
+	require.NoError(t, err)
+	_, err = decoder.RecoverSigner(forgedHex)
+	assert.ErrorIs(t, err, transaction.ErrInvalidSignature)
+
+	_, err = decoder.RecoverSigner(stxTransferHex)
+	assert.ErrorIs(t, err, transaction.ErrInvalidSignature, "unsigned")
+	_, err = decoder.RecoverSigner("0x0080")
+	assert.ErrorIs(t, err, transaction.ErrMalformed)
+}
@@ -0,0 +1,278 @@
+package blockchain
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/x402stacks/stacks-facilitator/internal/payment/application/command"
+	"github.com/x402stacks/stacks-facilitator/internal/payment/domain/valueobject"
+	"github.com/x402stacks/stacks-facilitator/internal/stacks"
+	"github.com/x402stacks/stacks-facilitator/internal/stacks/secp256k1"
+	"github.com/x402stacks/stacks-facilitator/internal/stacks/transaction"
+)
+
+// Node rejection reasons that mean the sponsor nonce was wrong
+const (
+	reasonBadNonce                  = "BadNonce"
+	reasonConflictingNonceInMempool = "ConflictingNonceInMempool"
+)
+
+// sponsorKey is one fee-paying key with its address and nonce pool per network
+type sponsorKey struct {
+	key       *secp256k1.PrivateKey
+	addresses map[valueobject.Network]valueobject.StacksAddress
+	pools     map[valueobject.Network]*noncePool
+}
+
+// TransactionSponsor counter-signs sponsored transactions with the facilitator's keys.
+// Each key hands out nonces locally, so concurrent settlements use distinct nonces
+// and are spread over the key with the fewest transactions in flight.
+type TransactionSponsor struct {
+	keys          []*sponsorKey
+	mainnetClient *stacks.Client
+	testnetClient *stacks.Client
+}
+
+// NewTransactionSponsor creates a TransactionSponsor that pays fees from the given keys' accounts
+func NewTransactionSponsor(keys []*secp256k1.PrivateKey, mainnetClient, testnetClient *stacks.Client) *TransactionSponsor {
+	s := &TransactionSponsor{
+		mainnetClient: mainnetClient,
+		testnetClient: testnetClient,
+	}
+	for _, key := range keys {
+		k := &sponsorKey{
+			key:       key,
+			addresses: make(map[valueobject.Network]valueobject.StacksAddress),
+			pools:     make(map[valueobject.Network]*noncePool),
+		}
+		hash := key.PublicKey().Hash160()
+		for _, network := range valueobject.SupportedNetworks() {
+			version := byte(valueobject.AddressVersionTestnetSingleSig)
+			if network.IsMainnet() {
+				version = valueobject.AddressVersionMainnetSingleSig
+			}
+			k.addresses[network] = valueobject.NewStacksAddressFromHash160(version, hash)
+			k.pools[network] = newNoncePool()
+		}
+		s.keys = append(s.keys, k)
+	}
+	return s
+}
+
+// Addresses returns the sponsor addresses on a network, in key order
+func (s *TransactionSponsor) Addresses(network valueobject.Network) []valueobject.StacksAddress {
+	addresses := make([]valueobject.StacksAddress, len(s.keys))
+	for i, k := range s.keys {
+		addresses[i] = k.address(network)
+	}
+	return addresses
+}
+
+// SponsorTransaction fills in the sponsor spending condition of a payer-signed sponsored
+// transaction and signs it. The fee is the network fee rate times the transaction size;
+// when maxFee is non-zero and the fee is above it, a *command.SponsorFeeError is returned
+// rather than underpaying a fee the node would refuse.
+func (s *TransactionSponsor) SponsorTransaction(ctx context.Context, signedTx string, network valueobject.Network, maxFee uint64) (command.SponsoredTransaction, error) {
+	if len(s.keys) == 0 {
+		return command.SponsoredTransaction{}, errors.New("no sponsor keys configured")
+	}
+
+	tx, err := transaction.DecodeHex(signedTx)
+	if err != nil {
+		return command.SponsoredTransaction{}, err
+	}
+	if !tx.IsSponsored() {
+		return command.SponsoredTransaction{}, errors.New("transaction is not sponsored")
+	}
+	if txNetwork, err := tx.Network(); err != nil || txNetwork != network {
+		return command.SponsoredTransaction{}, fmt.Errorf("transaction is not for %s", network)
+	}
+
+	client := s.getClientForNetwork(network)
+
+	rate, err := client.GetFeeRate(ctx)
+	if err != nil {
+		return command.SponsoredTransaction{}, fmt.Errorf("failed to fetch fee rate: %w", err)
+	}
+
+	// Sign once to learn the final size, then again with the fee for that size
+	if err := tx.SignSponsor(s.keys[0].key, 0, 0); err != nil {
+		return command.SponsoredTransaction{}, err
+	}
+	encoded, err := tx.Encode()
+	if err != nil {
+		return command.SponsoredTransaction{}, err
+	}
+
+	fee := rate * uint64(len(encoded))
+	if maxFee > 0 && fee > maxFee {
+		return command.SponsoredTransaction{}, &command.SponsorFeeError{Fee: fee, MaxFee: maxFee}
+	}
+
+	var lastErr error
+	for _, k := range s.keysByLoad(network) {
+		address := k.address(network)
+		pool := k.pools[network]
+
+		account, err := client.GetAccount(ctx, address.String())
+		if err != nil {
+			lastErr = fmt.Errorf("failed to fetch sponsor account %s: %w", address, err)
+			continue
+		}
+		pool.observe(account.Nonce)
+		if account.Balance < fee {
+			lastErr = fmt.Errorf("sponsor %s balance %d is below fee %d", address, account.Balance, fee)
+			continue
+		}
+
+		nonce, err := pool.reserve(ctx, s.nonceFetcher(client, address))
+		if err != nil {
+			lastErr = fmt.Errorf("failed to fetch sponsor nonce for %s: %w", address, err)
+			continue
+		}
+
+		if err := tx.SignSponsor(k.key, nonce, fee); err != nil {
+			pool.unused(nonce)
+			return command.SponsoredTransaction{}, err
+		}
+		sponsoredHex, err := tx.EncodeHex()
+		if err != nil {
+			pool.unused(nonce)
+			return command.SponsoredTransaction{}, err
+		}
+
+		return command.SponsoredTransaction{
+			SignedTransaction: sponsoredHex,
+			Network:           network,
+			Sponsor:           address,
+			Nonce:             nonce,
+			Fee:               fee,
+		}, nil
+	}
+
+	return command.SponsoredTransaction{}, lastErr
+}
+
+// Release reports the broadcast outcome for a sponsored transaction's nonce. A rejected
+// sponsor nonce forces a resync and is worth retrying, and any other node rejection
+// returns the nonce for reuse. A failure without a rejection, such as a timeout, a 5xx or
+// a dropped connection, may come after the node accepted the transaction, so its nonce is
+// not reused; the pool resyncs from the node, which counts it if it reached the mempool.
+func (s *TransactionSponsor) Release(sponsored command.SponsoredTransaction, broadcastErr error) bool {
+	pool := s.pool(sponsored.Sponsor, sponsored.Network)
+	if pool == nil || broadcastErr == nil {
+		return false
+	}
+
+	if isSponsorNonceRejection(broadcastErr) {
+		pool.conflict(sponsored.Nonce)
+		return true
+	}
+
+	var rejected *command.BroadcastRejectedError
+	if errors.As(broadcastErr, &rejected) {
+		pool.unused(sponsored.Nonce)
+		return false
+	}
+
+	pool.conflict(sponsored.Nonce)
+	return false
+}
+
+// SponsorAccounts returns the balance, next nonce and pending count of every sponsor key on a network
+func (s *TransactionSponsor) SponsorAccounts(ctx context.Context, network valueobject.Network) ([]command.SponsorAccount, error) {
+	client := s.getClientForNetwork(network)
+
+	accounts := make([]command.SponsorAccount, 0, len(s.keys))
+	for _, k := range s.keys {
+		address := k.address(network)
+		pool := k.pools[network]
+
+		account, err := client.GetAccount(ctx, address.String())
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch sponsor account %s: %w", address, err)
+		}
+		pool.observe(account.Nonce)
+
+		next, pending, err := pool.snapshot(ctx, s.nonceFetcher(client, address))
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch sponsor nonce for %s: %w", address, err)
+		}
+
+		accounts = append(accounts, command.SponsorAccount{
+			Address:   address,
+			Balance:   account.Balance,
+			NextNonce: next,
+			Pending:   pending,
+		})
+	}
+	return accounts, nil
+}
+
+// keysByLoad returns the keys ordered by how many transactions they have in flight on a network
+func (s *TransactionSponsor) keysByLoad(network valueobject.Network) []*sponsorKey {
+	keys := append([]*sponsorKey(nil), s.keys...)
+	sort.SliceStable(keys, func(i, j int) bool {
+		return keys[i].pools[network].pendingCount() < keys[j].pools[network].pendingCount()
+	})
+	return keys
+}
+
+// pool returns the nonce pool of the key with the given address on a network
+func (s *TransactionSponsor) pool(address valueobject.StacksAddress, network valueobject.Network) *noncePool {
+	for _, k := range s.keys {
+		if k.address(network).Equals(address) {
+			return k.pools[network]
+		}
+	}
+	return nil
+}
+
+// nonceFetcher reads the next possible nonce and missing nonces of address from the API
+func (s *TransactionSponsor) nonceFetcher(client *stacks.Client, address valueobject.StacksAddress) nonceFetcher {
+	return func(ctx context.Context) (uint64, []uint64, error) {
+		info, err := client.GetNonces(ctx, address.String())
+		if err != nil {
+			return 0, nil, err
+		}
+		return info.PossibleNextNonce, info.DetectedMissingNonces, nil
+	}
+}
+
+// getClientForNetwork returns the appropriate client for the network
+func (s *TransactionSponsor) getClientForNetwork(network valueobject.Network) *stacks.Client {
+	if network.IsMainnet() {
+		return s.mainnetClient
+	}
+	return s.testnetClient
+}
+
+// address returns the key's single-sig address on a network
+func (k *sponsorKey) address(network valueobject.Network) valueobject.StacksAddress {
+	return k.addresses[network]
+}
+
+// isSponsorNonceRejection reports whether the node rejected the transaction because of the sponsor's nonce
+func isSponsorNonceRejection(err error) bool {
+	var broadcastErr *stacks.BroadcastError
+	if !errors.As(err, &broadcastErr) {
+		return false
+	}
+
+	switch broadcastErr.Reason {
+	case reasonConflictingNonceInMempool:
+		return true
+	case reasonBadNonce:
+		// The origin's nonce is the payer's problem; resyncing the sponsor will not help
+		var data struct {
+			IsOrigin bool `json:"is_origin"`
+		}
+		_ = json.Unmarshal(broadcastErr.ReasonData, &data)
+		return !data.IsOrigin
+	default:
+		return false
+	}
+}
@@ -0,0 +1,258 @@
+package blockchain
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/x402stacks/stacks-facilitator/internal/payment/application/command"
+	"github.com/x402stacks/stacks-facilitator/internal/payment/domain/valueobject"
+	"github.com/x402stacks/stacks-facilitator/internal/stacks"
+	"github.com/x402stacks/stacks-facilitator/internal/stacks/secp256k1"
+	"github.com/x402stacks/stacks-facilitator/internal/stacks/transaction"
+)
+
+const (
+	sponsorKeyHex       = "4242424242424242424242424242424242424242424242424242424242424242" + "01"
+	secondSponsorKeyHex = "4343434343434343434343434343434343434343434343434343434343434343" + "01"
+)
+
+// sponsorNode serves the account, nonce and fee endpoints used when sponsoring
+func sponsorNode(t *testing.T, balance, nonce, feeRate uint64) *stacks.Client {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case strings.HasPrefix(r.URL.Path, "/v2/accounts/"):
+			fmt.Fprintf(w, `{"balance":"0x%032x","nonce":%d}`, balance, nonce)
+		case strings.HasPrefix(r.URL.Path, "/extended/v1/address/") && strings.HasSuffix(r.URL.Path, "/nonces"):
+			fmt.Fprintf(w, `{"possible_next_nonce":%d,"detected_missing_nonces":[]}`, nonce)
+		case r.URL.Path == "/v2/fees/transfer":
+			fmt.Fprintf(w, "%d", feeRate)
+		default:
+			t.Errorf("unexpected request to %s", r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(server.Close)
+	return stacks.NewClient(server.URL)
+}
+
+func newTestSponsor(t *testing.T, client *stacks.Client) (*TransactionSponsor, *secp256k1.PrivateKey) {
+	key, err := secp256k1.ParsePrivateKey(sponsorKeyHex)
+	require.NoError(t, err)
+	return NewTransactionSponsor([]*secp256k1.PrivateKey{key}, client, client), key
+}
+
+// nonceRejection builds the error the node returns for a rejected nonce
+func nonceRejection(reason string, isOrigin bool) error {
+	data, _ := json.Marshal(map[string]bool{"is_origin": isOrigin})
+	return &stacks.BroadcastError{StatusCode: http.StatusBadRequest, Reason: reason, ReasonData: data}
+}
+
+func TestTransactionSponsor_SponsorTransaction(t *testing.T) {
+	sponsor, key := newTestSponsor(t, sponsorNode(t, 1000000, 42, 2))
+
+	sponsored, err := sponsor.SponsorTransaction(context.Background(), sponsoredTransferHex, valueobject.NetworkTestnet, 0)
+	require.NoError(t, err)
+	signed := sponsored.SignedTransaction
+
+	tx, err := transaction.DecodeHex(signed)
+	require.NoError(t, err)
+	require.NotNil(t, tx.Auth.Sponsor)
+	assert.Equal(t, uint64(42), sponsored.Nonce)
+	assert.Equal(t, tx.Fee(), sponsored.Fee)
+	assert.Equal(t, sponsor.Addresses(valueobject.NetworkTestnet)[0], sponsored.Sponsor)
+
+	assert.Equal(t, transaction.HashModeP2PKH, tx.Auth.Sponsor.HashMode)
+	assert.Equal(t, key.PublicKey().Hash160(), tx.Auth.Sponsor.Signer)
+	assert.Equal(t, uint64(42), tx.Auth.Sponsor.Nonce)
+	assert.Equal(t, uint64(2*(len(signed)/2)), tx.Fee()) // 2 microSTX per byte
+	assert.NotEqual(t, transaction.Signature{}, tx.Auth.Sponsor.Signature)
+
+	// The header and origin condition signed by the payer are untouched
+	originEnd := 2 * (5 + 1 + 1 + 20 + 16 + 1 + 65)
+	assert.Equal(t, sponsoredTransferHex[:originEnd], signed[:originEnd])
+	assert.Equal(t, "ST2J6ZY48GV1EZ5V2V5RB9MP66SW86PYKKQYAC0RQ", tx.OriginAddress().String())
+}
+
+func TestTransactionSponsor_RejectsFeeAboveMax(t *testing.T) {
+	sponsor, _ := newTestSponsor(t, sponsorNode(t, 1000000, 0, 100))
+
+	_, err := sponsor.SponsorTransaction(context.Background(), sponsoredTransferHex, valueobject.NetworkTestnet, 5000)
+
+	var feeErr *command.SponsorFeeError
+	require.ErrorAs(t, err, &feeErr)
+	assert.Equal(t, uint64(28300), feeErr.Fee, "100 microSTX per byte for 283 bytes")
+	assert.Equal(t, uint64(5000), feeErr.MaxFee)
+
+	// The rejected transaction reserved no nonce
+	accounts, err := sponsor.SponsorAccounts(context.Background(), valueobject.NetworkTestnet)
+	require.NoError(t, err)
+	assert.Zero(t, accounts[0].Pending)
+}
+
+func TestTransactionSponsor_Errors(t *testing.T) {
+	tests := []struct {
+		name     string
+		balance  uint64
+		signedTx string
+		network  valueobject.Network
+	}{
+		{"not sponsored", 1000000, stxTransferHex, valueobject.NetworkTestnet},
+		{"wrong network", 1000000, sponsoredTransferHex, valueobject.NetworkMainnet},
+		{"insufficient balance", 10, sponsoredTransferHex, valueobject.NetworkTestnet},
+		{"malformed", 1000000, "0xdead", valueobject.NetworkTestnet},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sponsor, _ := newTestSponsor(t, sponsorNode(t, tt.balance, 0, 1))
+
+			_, err := sponsor.SponsorTransaction(context.Background(), tt.signedTx, tt.network, 0)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestTransactionSponsor_ConcurrentNonces(t *testing.T) {
+	sponsor, _ := newTestSponsor(t, sponsorNode(t, 1000000, 5, 1))
+
+	first, err := sponsor.SponsorTransaction(context.Background(), sponsoredTransferHex, valueobject.NetworkTestnet, 0)
+	require.NoError(t, err)
+	second, err := sponsor.SponsorTransaction(context.Background(), sponsoredTransferHex, valueobject.NetworkTestnet, 0)
+	require.NoError(t, err)
+
+	assert.Equal(t, uint64(5), first.Nonce)
+	assert.Equal(t, uint64(6), second.Nonce)
+}
+
+func TestTransactionSponsor_SpreadsLoadAcrossKeys(t *testing.T) {
+	client := sponsorNode(t, 1000000, 0, 1)
+	first, err := secp256k1.ParsePrivateKey(sponsorKeyHex)
+	require.NoError(t, err)
+	second, err := secp256k1.ParsePrivateKey(secondSponsorKeyHex)
+	require.NoError(t, err)
+	sponsor := NewTransactionSponsor([]*secp256k1.PrivateKey{first, second}, client, client)
+	addresses := sponsor.Addresses(valueobject.NetworkTestnet)
+
+	a, err := sponsor.SponsorTransaction(context.Background(), sponsoredTransferHex, valueobject.NetworkTestnet, 0)
+	require.NoError(t, err)
+	b, err := sponsor.SponsorTransaction(context.Background(), sponsoredTransferHex, valueobject.NetworkTestnet, 0)
+	require.NoError(t, err)
+
+	assert.Equal(t, addresses[0], a.Sponsor)
+	assert.Equal(t, addresses[1], b.Sponsor)
+	assert.Equal(t, uint64(0), a.Nonce)
+	assert.Equal(t, uint64(0), b.Nonce)
+}
+
+func TestTransactionSponsor_Release(t *testing.T) {
+	tests := []struct {
+		name      string
+		err       error
+		wantRetry bool
+		wantNonce uint64
+	}{
+		{"broadcast succeeded", nil, false, 1},
+		{"sponsor bad nonce", nonceRejection(reasonBadNonce, false), true, 0},
+		{"origin bad nonce", nonceRejection(reasonBadNonce, true), false, 0},
+		{"conflicting nonce", nonceRejection(reasonConflictingNonceInMempool, false), true, 0},
+		{"other rejection", broadcastRejection(&stacks.BroadcastError{StatusCode: http.StatusBadRequest, Reason: "NotEnoughFunds"}), false, 0},
+		{"network error", fmt.Errorf("connection refused"), false, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sponsor, _ := newTestSponsor(t, sponsorNode(t, 1000000, 0, 1))
+
+			sponsored, err := sponsor.SponsorTransaction(context.Background(), sponsoredTransferHex, valueobject.NetworkTestnet, 0)
+			require.NoError(t, err)
+			require.Equal(t, uint64(0), sponsored.Nonce)
+
+			assert.Equal(t, tt.wantRetry, sponsor.Release(sponsored, tt.err))
+
+			// The node still reports 0 as the next nonce, so a released or resynced nonce is handed out again
+			next, err := sponsor.SponsorTransaction(context.Background(), sponsoredTransferHex, valueobject.NetworkTestnet, 0)
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantNonce, next.Nonce)
+		})
+	}
+}
+
+func TestTransactionSponsor_ReleaseAfterTransportError(t *testing.T) {
+	var accepted atomic.Bool
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/v2/transactions":
+			// The node takes the transaction, but the connection drops before it answers
+			accepted.Store(true)
+			conn, _, err := w.(http.Hijacker).Hijack()
+			require.NoError(t, err)
+			conn.Close()
+		case strings.HasPrefix(r.URL.Path, "/v2/accounts/"):
+			fmt.Fprintf(w, `{"balance":"0x%032x","nonce":0}`, 1000000)
+		case strings.HasSuffix(r.URL.Path, "/nonces"):
+			next := 0
+			if accepted.Load() {
+				next = 1
+			}
+			fmt.Fprintf(w, `{"possible_next_nonce":%d,"detected_missing_nonces":[]}`, next)
+		case r.URL.Path == "/v2/fees/transfer":
+			fmt.Fprint(w, "1")
+		default:
+			t.Errorf("unexpected request to %s", r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(server.Close)
+	client := stacks.NewClient(server.URL)
+	sponsor, _ := newTestSponsor(t, client)
+	adapter := NewStacksClientAdapterWithClients(client, client)
+
+	sponsored, err := sponsor.SponsorTransaction(context.Background(), sponsoredTransferHex, valueobject.NetworkTestnet, 0)
+	require.NoError(t, err)
+	require.Equal(t, uint64(0), sponsored.Nonce)
+
+	_, broadcastErr := adapter.BroadcastTransaction(context.Background(), sponsored.SignedTransaction, valueobject.NetworkTestnet)
+	require.Error(t, broadcastErr)
+	assert.NotErrorIs(t, broadcastErr, command.ErrBroadcastRejected)
+
+	assert.False(t, sponsor.Release(sponsored, broadcastErr))
+
+	next, err := sponsor.SponsorTransaction(context.Background(), sponsoredTransferHex, valueobject.NetworkTestnet, 0)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(1), next.Nonce, "a nonce that may be in the mempool is not handed out again")
+}
+
+func TestTransactionSponsor_SponsorAccounts(t *testing.T) {
+	sponsor, _ := newTestSponsor(t, sponsorNode(t, 250000, 9, 1))
+
+	_, err := sponsor.SponsorTransaction(context.Background(), sponsoredTransferHex, valueobject.NetworkTestnet, 0)
+	require.NoError(t, err)
+
+	accounts, err := sponsor.SponsorAccounts(context.Background(), valueobject.NetworkTestnet)
+	require.NoError(t, err)
+	require.Len(t, accounts, 1)
+
+	assert.Equal(t, sponsor.Addresses(valueobject.NetworkTestnet)[0], accounts[0].Address)
+	assert.Equal(t, uint64(250000), accounts[0].Balance)
+	assert.Equal(t, uint64(10), accounts[0].NextNonce)
+	assert.Equal(t, 1, accounts[0].Pending)
+}
+
+func TestTransactionSponsor_Addresses(t *testing.T) {
+	sponsor, key := newTestSponsor(t, nil)
+
+	testnet := sponsor.Addresses(valueobject.NetworkTestnet)[0]
+	mainnet := sponsor.Addresses(valueobject.NetworkMainnet)[0]
+
+	assert.True(t, strings.HasPrefix(testnet.String(), "ST"))
+	assert.True(t, mainnet.IsMainnet())
+	assert.Equal(t, valueobject.NewStacksAddressFromHash160(valueobject.AddressVersionTestnetSingleSig, key.PublicKey().Hash160()), testnet)
+}
@@ -0,0 +1,36 @@
+package http
+
+import (
+	"net/http"
+
+	"github.com/x402stacks/stacks-facilitator/internal/payment/application/command"
+)
+
+// rejectionResponse is how a node's broadcast rejection is reported on each API
+type rejectionResponse struct {
+	status     int    // HTTP status on /api/v1/settle
+	code       string // ErrorResponse error code on /api/v1/settle
+	x402Reason string // errorReason on the x402 /settle
+}
+
+// rejectionResponses maps the node's rejection reasons to stable response codes
+var rejectionResponses = map[command.BroadcastRejectionReason]rejectionResponse{
+	command.RejectionBadNonce:                  {http.StatusConflict, "bad_nonce", reasonNonceMismatch},
+	command.RejectionConflictingNonceInMempool: {http.StatusConflict, "conflicting_nonce_in_mempool", reasonNonceConflict},
+	command.RejectionNotEnoughFunds:            {http.StatusBadRequest, "not_enough_funds", reasonInsufficientFunds},
+	command.RejectionFeeTooLow:                 {http.StatusBadRequest, "fee_too_low", reasonFeeTooLow},
+	command.RejectionBadTransactionVersion:     {http.StatusBadRequest, "bad_transaction_version", reasonTransactionVersion},
+	command.RejectionContractAlreadyExists:     {http.StatusConflict, "contract_already_exists", reasonContractExists},
+	command.RejectionPostConditionFailed:       {http.StatusBadRequest, "post_condition_failed", reasonPostConditionFailed},
+}
+
+// otherRejection reports a rejection reason without its own code
+var otherRejection = rejectionResponse{http.StatusBadRequest, "transaction_rejected", reasonInvalidTransaction}
+
+// rejectionResponseFor returns how to report a node rejection
+func rejectionResponseFor(rejected *command.BroadcastRejectedError) rejectionResponse {
+	if response, ok := rejectionResponses[rejected.Reason]; ok {
+		return response
+	}
+	return otherRejection
+}
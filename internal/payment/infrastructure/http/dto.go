@@ -1,56 +1,123 @@
 package http
 
+import (
+	"encoding/json"
+	"time"
+)
+
 // VerifyRequest represents a verify payment request
 type VerifyRequest struct {
-	TxID              string  `json:"tx_id"`
-	TokenType         string  `json:"token_type,omitempty"`
-	ExpectedRecipient string  `json:"expected_recipient"`
-	MinAmount         uint64  `json:"min_amount"`
-	ExpectedSender    *string `json:"expected_sender,omitempty"`
-	ExpectedMemo      *string `json:"expected_memo,omitempty"`
-	Network           string  `json:"network"`
+	TxID              string      `json:"tx_id"`
+	SignedTransaction string      `json:"signed_transaction,omitempty"` // Hex signed transaction to verify before broadcast, instead of tx_id
+	TokenType         string      `json:"token_type,omitempty"`
+	ExpectedRecipient string      `json:"expected_recipient"`
+	MinAmount         json.Number `json:"min_amount"`            // Accepts a JSON number or a decimal string
+	AmountUnit        string      `json:"amount_unit,omitempty"` // "base" (default) or "token"
+	ExpectedSender    *string     `json:"expected_sender,omitempty"`
+	ExpectedMemo      *string     `json:"expected_memo,omitempty"`
+	Network           string      `json:"network"`
+	Resource          string      `json:"resource,omitempty"`
+	Nonce             string      `json:"nonce,omitempty"`
+
+	MinConfirmations     uint64 `json:"min_confirmations,omitempty"`      // Stacks blocks; cannot go below the server minimum
+	MinBurnConfirmations uint64 `json:"min_burn_confirmations,omitempty"` // Bitcoin burn blocks; cannot go below the server minimum
 }
 
 // VerifyResponse represents a verify payment response
 type VerifyResponse struct {
-	Valid            bool     `json:"valid"`
-	TxID             string   `json:"tx_id"`
-	SenderAddress    string   `json:"sender_address"`
-	RecipientAddress string   `json:"recipient_address"`
-	Amount           uint64   `json:"amount"`
-	Fee              uint64   `json:"fee"`
-	Nonce            uint64   `json:"nonce,omitempty"`
-	Status           string   `json:"status"`
-	BlockHeight      uint64   `json:"block_height"`
-	TokenType        string   `json:"token_type"`
-	Memo             string   `json:"memo,omitempty"`
-	Network          string   `json:"network"`
-	Errors           []string `json:"errors,omitempty"`
+	Valid             bool                          `json:"valid"`
+	TxID              string                        `json:"tx_id"`
+	SenderAddress     string                        `json:"sender_address"`
+	RecipientAddress  string                        `json:"recipient_address"`
+	Amount            string                        `json:"amount"`         // Decimal string, exact beyond 2^53
+	DisplayAmount     string                        `json:"display_amount"` // Whole tokens with symbol, e.g. "1.5 STX"
+	Fee               uint64                        `json:"fee"`
+	Nonce             uint64                        `json:"nonce,omitempty"`
+	Status            string                        `json:"status"`
+	ReplacedByTxID    string                        `json:"replaced_by_tx_id,omitempty"`
+	BlockHeight       uint64                        `json:"block_height"`
+	Confirmations     uint64                        `json:"confirmations"`
+	BurnConfirmations uint64                        `json:"burn_confirmations"`
+	TokenType         string                        `json:"token_type"`
+	Memo              string                        `json:"memo,omitempty"`
+	Network           string                        `json:"network"`
+	Errors            []string                      `json:"errors,omitempty"`   // Failure messages, for display
+	Failures          []VerificationFailureResponse `json:"failures,omitempty"` // Failures with machine-readable codes
 }
 
 // SettleRequest represents a settle payment request
 type SettleRequest struct {
-	SignedTransaction string  `json:"signed_transaction"`
-	TokenType         string  `json:"token_type,omitempty"`
-	ExpectedRecipient string  `json:"expected_recipient"`
-	MinAmount         uint64  `json:"min_amount"`
-	ExpectedSender    *string `json:"expected_sender,omitempty"`
-	Network           string  `json:"network"`
+	SignedTransaction string      `json:"signed_transaction"`
+	TokenType         string      `json:"token_type,omitempty"`
+	ExpectedRecipient string      `json:"expected_recipient"`
+	MinAmount         json.Number `json:"min_amount"`            // Accepts a JSON number or a decimal string
+	AmountUnit        string      `json:"amount_unit,omitempty"` // "base" (default) or "token"
+	ExpectedSender    *string     `json:"expected_sender,omitempty"`
+	Network           string      `json:"network"`
+	Resource          string      `json:"resource,omitempty"`
+	Nonce             string      `json:"nonce,omitempty"`
+
+	MinConfirmations     uint64 `json:"min_confirmations,omitempty"`      // Stacks blocks; cannot go below the server minimum
+	MinBurnConfirmations uint64 `json:"min_burn_confirmations,omitempty"` // Bitcoin burn blocks; cannot go below the server minimum
+
+	Async       bool   `json:"async,omitempty"`        // Answer 202 with a settlement to poll instead of waiting for confirmation
+	CallbackURL string `json:"callback_url,omitempty"` // POSTed a signed event when the payment is confirmed, fails or is dropped
 }
 
 // SettleResponse represents a settle payment response
 type SettleResponse struct {
-	Success          bool     `json:"success"`
-	TxID             string   `json:"tx_id"`
-	SenderAddress    string   `json:"sender_address"`
-	RecipientAddress string   `json:"recipient_address"`
-	Amount           uint64   `json:"amount"`
-	Fee              uint64   `json:"fee"`
-	Status           string   `json:"status"`
-	BlockHeight      uint64   `json:"block_height"`
-	TokenType        string   `json:"token_type"`
-	Network          string   `json:"network"`
-	Errors           []string `json:"errors,omitempty"`
+	Success           bool                          `json:"success"`
+	TxID              string                        `json:"tx_id"`
+	SenderAddress     string                        `json:"sender_address"`
+	RecipientAddress  string                        `json:"recipient_address"`
+	Amount            string                        `json:"amount"`         // Decimal string, exact beyond 2^53
+	DisplayAmount     string                        `json:"display_amount"` // Whole tokens with symbol, e.g. "1.5 STX"
+	Fee               uint64                        `json:"fee"`
+	Status            string                        `json:"status"`
+	ReplacedByTxID    string                        `json:"replaced_by_tx_id,omitempty"`
+	BlockHeight       uint64                        `json:"block_height"`
+	Confirmations     uint64                        `json:"confirmations"`
+	BurnConfirmations uint64                        `json:"burn_confirmations"`
+	TokenType         string                        `json:"token_type"`
+	Network           string                        `json:"network"`
+	Errors            []string                      `json:"errors,omitempty"`   // Failure messages, for display
+	Failures          []VerificationFailureResponse `json:"failures,omitempty"` // Failures with machine-readable codes
+}
+
+// SettlementResponse represents the progress of an asynchronous settlement
+type SettlementResponse struct {
+	ID                string          `json:"id"`
+	TxID              string          `json:"tx_id"`
+	State             string          `json:"state"` // broadcast, mempool, mined, confirmed, failed or dropped
+	Network           string          `json:"network"`
+	TokenType         string          `json:"token_type"`
+	Confirmations     uint64          `json:"confirmations"`
+	BurnConfirmations uint64          `json:"burn_confirmations"`
+	Result            *SettleResponse `json:"result,omitempty"` // Final settle response once confirmed, failed or dropped
+	Error             string          `json:"error,omitempty"`
+	Retryable         bool            `json:"retryable,omitempty"` // Settling the same transaction again may still succeed
+	CreatedAt         time.Time       `json:"created_at"`
+	UpdatedAt         time.Time       `json:"updated_at"`
+}
+
+// PaymentEventResponse is the data of a payment status event; the SSE event name gives its kind
+type PaymentEventResponse struct {
+	TxID              string `json:"tx_id"`
+	Status            string `json:"status"` // pending, confirmed, failed or dropped
+	ReplacedByTxID    string `json:"replaced_by_tx_id,omitempty"`
+	BlockHeight       uint64 `json:"block_height"`
+	Confirmations     uint64 `json:"confirmations"`
+	BurnConfirmations uint64 `json:"burn_confirmations"`
+	TokenType         string `json:"token_type"`
+	Network           string `json:"network"`
+}
+
+// VerificationFailureResponse is one reason a payment failed verification
+type VerificationFailureResponse struct {
+	Code     string `json:"code"` // e.g. recipient_mismatch, insufficient_amount, not_confirmed
+	Expected string `json:"expected,omitempty"`
+	Actual   string `json:"actual,omitempty"`
+	Message  string `json:"message"`
 }
 
 // ErrorResponse represents an error response
@@ -63,3 +130,17 @@ type ErrorResponse struct {
 type HealthResponse struct {
 	Status string `json:"status"`
 }
+
+// SponsorAccountResponse is the state of one sponsor key
+type SponsorAccountResponse struct {
+	Address   string `json:"address"`
+	Balance   uint64 `json:"balance"`
+	NextNonce uint64 `json:"next_nonce"`
+	Pending   int    `json:"pending"`
+}
+
+// SponsorAccountsResponse represents the response body for GET /api/v1/sponsor/accounts
+type SponsorAccountsResponse struct {
+	Network  string                   `json:"network"`
+	Accounts []SponsorAccountResponse `json:"accounts"`
+}
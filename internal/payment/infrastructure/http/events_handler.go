@@ -0,0 +1,143 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/x402stacks/stacks-facilitator/internal/payment/application/command"
+)
+
+// PaymentWatcher interface for following a payment's status changes
+type PaymentWatcher interface {
+	Subscribe(ctx context.Context, cmd command.WatchPaymentCommand) (<-chan command.PaymentUpdate, func(), error)
+}
+
+// EventsHandler streams payment status changes as Server-Sent Events
+type EventsHandler struct {
+	watcher   PaymentWatcher
+	heartbeat time.Duration // Interval of keep-alive comments, so idle proxies keep the stream open
+}
+
+// NewEventsHandler creates a new EventsHandler
+func NewEventsHandler(watcher PaymentWatcher) *EventsHandler {
+	return &EventsHandler{watcher: watcher, heartbeat: 15 * time.Second}
+}
+
+// PaymentEvents handles GET /api/v1/payments/:txid/events?network=&token_type=&min_confirmations=&min_burn_confirmations=
+func (h *EventsHandler) PaymentEvents(c echo.Context) error {
+	cmd := command.WatchPaymentCommand{
+		TxID:      c.Param("txid"),
+		TokenType: c.QueryParam("token_type"),
+		Network:   c.QueryParam("network"),
+	}
+	if cmd.Network == "" {
+		return c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "missing_required_fields",
+			Message: "network is required",
+		})
+	}
+	if cmd.TokenType == "" {
+		cmd.TokenType = "STX"
+	}
+	for _, q := range []struct {
+		param string
+		dst   *uint64
+	}{
+		{"min_confirmations", &cmd.MinConfirmations},
+		{"min_burn_confirmations", &cmd.MinBurnConfirmations},
+	} {
+		if v := c.QueryParam(q.param); v != "" {
+			n, err := strconv.ParseUint(v, 10, 64)
+			if err != nil {
+				return c.JSON(http.StatusBadRequest, ErrorResponse{
+					Error:   "invalid_request",
+					Message: fmt.Sprintf("invalid %s: %v", q.param, err),
+				})
+			}
+			*q.dst = n
+		}
+	}
+
+	ctx := c.Request().Context()
+	updates, unsubscribe, err := h.watcher.Subscribe(ctx, cmd)
+	if errors.Is(err, command.ErrInvalidWatchRequest) {
+		return c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "invalid_request",
+			Message: err.Error(),
+		})
+	}
+	if errors.Is(err, command.ErrWatcherClosed) {
+		return c.JSON(http.StatusServiceUnavailable, ErrorResponse{
+			Error:   "shutting_down",
+			Message: err.Error(),
+		})
+	}
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Error:   "watch_failed",
+			Message: err.Error(),
+		})
+	}
+	defer unsubscribe()
+
+	res := c.Response()
+	res.Header().Set(echo.HeaderContentType, "text/event-stream")
+	res.Header().Set(echo.HeaderCacheControl, "no-cache")
+	res.Header().Set(echo.HeaderConnection, "keep-alive")
+	res.Header().Set("X-Accel-Buffering", "no")
+	res.WriteHeader(http.StatusOK)
+	res.Flush()
+
+	heartbeat := time.NewTicker(h.heartbeat)
+	defer heartbeat.Stop()
+
+	for id := 1; ; {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-heartbeat.C:
+			if _, err := fmt.Fprint(res, ": keep-alive\n\n"); err != nil {
+				return nil
+			}
+			res.Flush()
+		case update, ok := <-updates:
+			if !ok {
+				return nil
+			}
+			data, err := json.Marshal(newPaymentEventResponse(update))
+			if err != nil {
+				return err
+			}
+			if _, err := fmt.Fprintf(res, "id: %d\nevent: %s\ndata: %s\n\n", id, update.Kind, data); err != nil {
+				return nil
+			}
+			res.Flush()
+			id++
+		}
+	}
+}
+
+// RegisterRoutes registers the payment event stream route
+func (h *EventsHandler) RegisterRoutes(e *echo.Echo) {
+	e.GET("/api/v1/payments/:txid/events", h.PaymentEvents)
+}
+
+// newPaymentEventResponse converts a payment update to its event data
+func newPaymentEventResponse(update command.PaymentUpdate) PaymentEventResponse {
+	return PaymentEventResponse{
+		TxID:              update.TxID,
+		Status:            update.Status.String(),
+		ReplacedByTxID:    update.ReplacedByTxID,
+		BlockHeight:       update.BlockHeight,
+		Confirmations:     update.Confirmations,
+		BurnConfirmations: update.BurnConfirmations,
+		TokenType:         update.TokenType,
+		Network:           update.Network,
+	}
+}
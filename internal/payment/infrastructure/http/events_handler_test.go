@@ -0,0 +1,147 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/x402stacks/stacks-facilitator/internal/payment/application/command"
+	"github.com/x402stacks/stacks-facilitator/internal/payment/domain/valueobject"
+)
+
+const eventsTxID = "0xabcdef1234567890abcdef1234567890abcdef1234567890abcdef1234567890"
+
+// MockPaymentWatcher for testing
+type MockPaymentWatcher struct {
+	SubscribeFn  func(ctx context.Context, cmd command.WatchPaymentCommand) (<-chan command.PaymentUpdate, error)
+	unsubscribed atomic.Int32
+}
+
+func (m *MockPaymentWatcher) Subscribe(ctx context.Context, cmd command.WatchPaymentCommand) (<-chan command.PaymentUpdate, func(), error) {
+	updates, err := m.SubscribeFn(ctx, cmd)
+	if err != nil {
+		return nil, nil, err
+	}
+	return updates, func() { m.unsubscribed.Add(1) }, nil
+}
+
+// watcherSending returns a watcher whose subscriptions receive updates and then end
+func watcherSending(updates ...command.PaymentUpdate) *MockPaymentWatcher {
+	return &MockPaymentWatcher{
+		SubscribeFn: func(ctx context.Context, cmd command.WatchPaymentCommand) (<-chan command.PaymentUpdate, error) {
+			ch := make(chan command.PaymentUpdate, len(updates))
+			for _, update := range updates {
+				ch <- update
+			}
+			close(ch)
+			return ch, nil
+		},
+	}
+}
+
+func getPaymentEvents(ctx context.Context, handler *EventsHandler, target string) *httptest.ResponseRecorder {
+	e := echo.New()
+	handler.RegisterRoutes(e)
+	req := httptest.NewRequest(http.MethodGet, target, nil).WithContext(ctx)
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestEventsHandler_StreamsUpdatesUntilFinal(t *testing.T) {
+	var received command.WatchPaymentCommand
+	watcher := watcherSending(
+		command.PaymentUpdate{Kind: command.PaymentUpdateMempool, TxID: eventsTxID, Status: valueobject.StatusPending, TokenType: "STX", Network: "testnet"},
+		command.PaymentUpdate{Kind: command.PaymentUpdateConfirmed, TxID: eventsTxID, Status: valueobject.StatusConfirmed, BlockHeight: 100, Confirmations: 1, TokenType: "STX", Network: "testnet"},
+	)
+	subscribe := watcher.SubscribeFn
+	watcher.SubscribeFn = func(ctx context.Context, cmd command.WatchPaymentCommand) (<-chan command.PaymentUpdate, error) {
+		received = cmd
+		return subscribe(ctx, cmd)
+	}
+
+	rec := getPaymentEvents(context.Background(), NewEventsHandler(watcher), "/api/v1/payments/"+eventsTxID+"/events?network=testnet&min_confirmations=2")
+
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "text/event-stream", rec.Header().Get("Content-Type"))
+	assert.Equal(t, "no-cache", rec.Header().Get("Cache-Control"))
+	assert.Equal(t, command.WatchPaymentCommand{TxID: eventsTxID, TokenType: "STX", Network: "testnet", MinConfirmations: 2}, received)
+	assert.Equal(t, int32(1), watcher.unsubscribed.Load())
+
+	events := strings.Split(strings.TrimSuffix(rec.Body.String(), "\n\n"), "\n\n")
+	require.Len(t, events, 2)
+	assert.True(t, strings.HasPrefix(events[0], "id: 1\nevent: mempool\ndata: "), events[0])
+	assert.True(t, strings.HasPrefix(events[1], "id: 2\nevent: confirmed\ndata: "), events[1])
+
+	var data PaymentEventResponse
+	require.NoError(t, json.Unmarshal([]byte(strings.TrimPrefix(events[1], "id: 2\nevent: confirmed\ndata: ")), &data))
+	assert.Equal(t, PaymentEventResponse{
+		TxID:          eventsTxID,
+		Status:        "confirmed",
+		BlockHeight:   100,
+		Confirmations: 1,
+		TokenType:     "STX",
+		Network:       "testnet",
+	}, data)
+}
+
+func TestEventsHandler_EndsWhenClientDisconnects(t *testing.T) {
+	watcher := &MockPaymentWatcher{
+		SubscribeFn: func(ctx context.Context, cmd command.WatchPaymentCommand) (<-chan command.PaymentUpdate, error) {
+			return make(chan command.PaymentUpdate), nil
+		},
+	}
+	handler := NewEventsHandler(watcher)
+	handler.heartbeat = time.Millisecond
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	rec := getPaymentEvents(ctx, handler, "/api/v1/payments/"+eventsTxID+"/events?network=testnet")
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), ": keep-alive\n\n")
+	assert.Equal(t, int32(1), watcher.unsubscribed.Load())
+}
+
+func TestEventsHandler_Errors(t *testing.T) {
+	tests := []struct {
+		name   string
+		query  string
+		err    error
+		status int
+		error  string
+	}{
+		{"missing network", "", nil, http.StatusBadRequest, "missing_required_fields"},
+		{"bad min confirmations", "?network=testnet&min_confirmations=-1", nil, http.StatusBadRequest, "invalid_request"},
+		{"invalid txid", "?network=testnet", command.ErrInvalidWatchRequest, http.StatusBadRequest, "invalid_request"},
+		{"shutting down", "?network=testnet", command.ErrWatcherClosed, http.StatusServiceUnavailable, "shutting_down"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			watcher := &MockPaymentWatcher{
+				SubscribeFn: func(ctx context.Context, cmd command.WatchPaymentCommand) (<-chan command.PaymentUpdate, error) {
+					if tt.err == nil {
+						t.Fatal("unexpected subscribe")
+					}
+					return nil, tt.err
+				},
+			}
+
+			rec := getPaymentEvents(context.Background(), NewEventsHandler(watcher), "/api/v1/payments/"+eventsTxID+"/events"+tt.query)
+
+			assert.Equal(t, tt.status, rec.Code)
+			var response ErrorResponse
+			require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+			assert.Equal(t, tt.error, response.Error)
+		})
+	}
+}
@@ -2,10 +2,12 @@ package http
 
 import (
 	"context"
+	"errors"
 	"net/http"
 
 	"github.com/labstack/echo/v4"
 	"github.com/x402stacks/stacks-facilitator/internal/payment/application/command"
+	"github.com/x402stacks/stacks-facilitator/internal/payment/domain/service"
 )
 
 // VerifyPaymentHandler interface for verify payment use case
@@ -18,10 +20,17 @@ type SettlePaymentHandler interface {
 	Handle(ctx context.Context, cmd command.SettlePaymentCommand) (command.SettlePaymentResult, error)
 }
 
+// AsyncSettlePaymentHandler interface for the asynchronous settle use case
+type AsyncSettlePaymentHandler interface {
+	Handle(ctx context.Context, cmd command.SettlePaymentCommand) (command.AsyncSettleResult, error)
+	Get(ctx context.Context, id string) (command.Settlement, bool, error)
+}
+
 // Handler handles HTTP requests for payments
 type Handler struct {
-	verifyHandler VerifyPaymentHandler
-	settleHandler SettlePaymentHandler
+	verifyHandler      VerifyPaymentHandler
+	settleHandler      SettlePaymentHandler
+	asyncSettleHandler AsyncSettlePaymentHandler
 }
 
 // NewHandler creates a new Handler
@@ -32,6 +41,12 @@ func NewHandler(verifyHandler VerifyPaymentHandler, settleHandler SettlePaymentH
 	}
 }
 
+// WithAsyncSettle enables "async": true on settle requests and the settlement status route
+func (h *Handler) WithAsyncSettle(asyncSettleHandler AsyncSettlePaymentHandler) *Handler {
+	h.asyncSettleHandler = asyncSettleHandler
+	return h
+}
+
 // Verify handles POST /api/v1/verify
 func (h *Handler) Verify(c echo.Context) error {
 	var req VerifyRequest
@@ -43,10 +58,16 @@ func (h *Handler) Verify(c echo.Context) error {
 	}
 
 	// Validate required fields
-	if req.TxID == "" || req.ExpectedRecipient == "" || req.Network == "" {
+	if (req.TxID == "" && req.SignedTransaction == "") || req.ExpectedRecipient == "" || req.Network == "" {
 		return c.JSON(http.StatusBadRequest, ErrorResponse{
 			Error:   "missing_required_fields",
-			Message: "tx_id, expected_recipient, and network are required",
+			Message: "tx_id or signed_transaction, expected_recipient, and network are required",
+		})
+	}
+	if req.TxID != "" && req.SignedTransaction != "" {
+		return c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "invalid_request",
+			Message: "tx_id and signed_transaction cannot both be given",
 		})
 	}
 
@@ -57,36 +78,45 @@ func (h *Handler) Verify(c echo.Context) error {
 
 	cmd := command.VerifyPaymentCommand{
 		TxID:              req.TxID,
+		SignedTransaction: req.SignedTransaction,
 		TokenType:         req.TokenType,
 		ExpectedRecipient: req.ExpectedRecipient,
-		MinAmount:         req.MinAmount,
+		MinAmount:         req.MinAmount.String(),
+		AmountUnit:        req.AmountUnit,
 		ExpectedSender:    req.ExpectedSender,
 		ExpectedMemo:      req.ExpectedMemo,
 		Network:           req.Network,
+		Resource:          req.Resource,
+		Nonce:             req.Nonce,
+
+		MinConfirmations:     req.MinConfirmations,
+		MinBurnConfirmations: req.MinBurnConfirmations,
 	}
 
 	result, err := h.verifyHandler.Handle(c.Request().Context(), cmd)
 	if err != nil {
-		return c.JSON(http.StatusInternalServerError, ErrorResponse{
-			Error:   "verification_failed",
-			Message: err.Error(),
-		})
+		return commandError(c, err, "verification_failed")
 	}
 
 	response := VerifyResponse{
-		Valid:            result.Valid,
-		TxID:             result.TxID,
-		SenderAddress:    result.SenderAddress,
-		RecipientAddress: result.RecipientAddress,
-		Amount:           result.Amount,
-		Fee:              result.Fee,
-		Nonce:            result.Nonce,
-		Status:           result.Status,
-		BlockHeight:      result.BlockHeight,
-		TokenType:        result.TokenType,
-		Memo:             result.Memo,
-		Network:          result.Network,
-		Errors:           result.Errors,
+		Valid:             result.Valid,
+		TxID:              result.TxID,
+		SenderAddress:     result.SenderAddress,
+		RecipientAddress:  result.RecipientAddress,
+		Amount:            result.Amount,
+		DisplayAmount:     result.DisplayAmount,
+		Fee:               result.Fee,
+		Nonce:             result.Nonce,
+		Status:            result.Status,
+		ReplacedByTxID:    result.ReplacedByTxID,
+		BlockHeight:       result.BlockHeight,
+		Confirmations:     result.Confirmations,
+		BurnConfirmations: result.BurnConfirmations,
+		TokenType:         result.TokenType,
+		Memo:              result.Memo,
+		Network:           result.Network,
+		Errors:            result.Errors,
+		Failures:          newFailureResponses(result.Failures),
 	}
 
 	return c.JSON(http.StatusOK, response)
@@ -119,38 +149,180 @@ func (h *Handler) Settle(c echo.Context) error {
 		SignedTransaction: req.SignedTransaction,
 		TokenType:         req.TokenType,
 		ExpectedRecipient: req.ExpectedRecipient,
-		MinAmount:         req.MinAmount,
+		MinAmount:         req.MinAmount.String(),
+		AmountUnit:        req.AmountUnit,
 		ExpectedSender:    req.ExpectedSender,
 		Network:           req.Network,
+		Resource:          req.Resource,
+		Nonce:             req.Nonce,
+
+		MinConfirmations:     req.MinConfirmations,
+		MinBurnConfirmations: req.MinBurnConfirmations,
+
+		CallbackURL: req.CallbackURL,
+	}
+
+	if req.Async {
+		return h.settleAsync(c, cmd)
 	}
 
 	result, err := h.settleHandler.Handle(c.Request().Context(), cmd)
+	if err != nil {
+		return commandError(c, err, "settlement_failed")
+	}
+
+	response := newSettleResponse(result)
+	if !result.Success {
+		return c.JSON(http.StatusBadRequest, response)
+	}
+
+	return c.JSON(http.StatusOK, response)
+}
+
+// settleAsync broadcasts the transaction and answers 202 with the settlement to poll
+func (h *Handler) settleAsync(c echo.Context, cmd command.SettlePaymentCommand) error {
+	if h.asyncSettleHandler == nil {
+		return c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "async_unavailable",
+			Message: "asynchronous settlement is not enabled",
+		})
+	}
+
+	result, err := h.asyncSettleHandler.Handle(c.Request().Context(), cmd)
+	if err != nil {
+		return commandError(c, err, "settlement_failed")
+	}
+
+	if !result.Accepted {
+		return c.JSON(http.StatusBadRequest, newSettleResponse(result.Rejected))
+	}
+
+	c.Response().Header().Set(echo.HeaderLocation, "/api/v1/settlements/"+result.Settlement.ID)
+	return c.JSON(http.StatusAccepted, newSettlementResponse(result.Settlement))
+}
+
+// Settlement handles GET /api/v1/settlements/:id
+func (h *Handler) Settlement(c echo.Context) error {
+	settlement, ok, err := h.asyncSettleHandler.Get(c.Request().Context(), c.Param("id"))
 	if err != nil {
 		return c.JSON(http.StatusInternalServerError, ErrorResponse{
-			Error:   "settlement_failed",
+			Error:   "settlement_lookup_failed",
 			Message: err.Error(),
 		})
 	}
+	if !ok {
+		return c.JSON(http.StatusNotFound, ErrorResponse{
+			Error:   "settlement_not_found",
+			Message: "no settlement with id " + c.Param("id"),
+		})
+	}
+
+	return c.JSON(http.StatusOK, newSettlementResponse(settlement))
+}
 
-	response := SettleResponse{
-		Success:          result.Success,
-		TxID:             result.TxID,
-		SenderAddress:    result.SenderAddress,
-		RecipientAddress: result.RecipientAddress,
-		Amount:           result.Amount,
-		Fee:              result.Fee,
-		Status:           result.Status,
-		BlockHeight:      result.BlockHeight,
-		TokenType:        result.TokenType,
-		Network:          result.Network,
-		Errors:           result.Errors,
+// commandErrors maps use case errors to their HTTP status and error code
+var commandErrors = []struct {
+	err    error
+	status int
+	code   string
+}{
+	{command.ErrInvalidAddress, http.StatusBadRequest, "invalid_address"},
+	{command.ErrInvalidAmount, http.StatusBadRequest, "invalid_amount"},
+	{command.ErrUnsupportedToken, http.StatusBadRequest, "unsupported_token"},
+	{command.ErrInvalidSignedTransaction, http.StatusBadRequest, "invalid_transaction"},
+	{command.ErrInvalidCallbackURL, http.StatusBadRequest, "invalid_callback_url"},
+	{command.ErrTransactionNotFound, http.StatusNotFound, "transaction_not_found"},
+	{command.ErrPaymentAlreadyUsed, http.StatusConflict, "already_used"},
+	{command.ErrTransactionUnavailable, http.StatusServiceUnavailable, "transaction_unavailable"},
+	{command.ErrSponsorFailed, http.StatusServiceUnavailable, "sponsor_unavailable"},
+	{command.ErrSettlementQueueFull, http.StatusServiceUnavailable, "settlement_queue_full"},
+	{command.ErrSettlementStopped, http.StatusServiceUnavailable, "settlement_stopped"},
+}
+
+// commandError maps a verify or settle use case error to its HTTP response, answering
+// any other error with 500 and the fallback code
+func commandError(c echo.Context, err error, fallback string) error {
+	for _, m := range commandErrors {
+		if errors.Is(err, m.err) {
+			return c.JSON(m.status, ErrorResponse{
+				Error:   m.code,
+				Message: err.Error(),
+			})
+		}
 	}
+	var rejected *command.BroadcastRejectedError
+	if errors.As(err, &rejected) {
+		response := rejectionResponseFor(rejected)
+		return c.JSON(response.status, ErrorResponse{
+			Error:   response.code,
+			Message: rejected.Error(),
+		})
+	}
+	return c.JSON(http.StatusInternalServerError, ErrorResponse{
+		Error:   fallback,
+		Message: err.Error(),
+	})
+}
 
-	if !result.Success {
-		return c.JSON(http.StatusBadRequest, response)
+// newSettleResponse converts a settle result to its response body
+func newSettleResponse(result command.SettlePaymentResult) SettleResponse {
+	return SettleResponse{
+		Success:           result.Success,
+		TxID:              result.TxID,
+		SenderAddress:     result.SenderAddress,
+		RecipientAddress:  result.RecipientAddress,
+		Amount:            result.Amount,
+		DisplayAmount:     result.DisplayAmount,
+		Fee:               result.Fee,
+		Status:            result.Status,
+		ReplacedByTxID:    result.ReplacedByTxID,
+		BlockHeight:       result.BlockHeight,
+		Confirmations:     result.Confirmations,
+		BurnConfirmations: result.BurnConfirmations,
+		TokenType:         result.TokenType,
+		Network:           result.Network,
+		Errors:            result.Errors,
+		Failures:          newFailureResponses(result.Failures),
 	}
+}
 
-	return c.JSON(http.StatusOK, response)
+// newFailureResponses converts verification failures to their response bodies
+func newFailureResponses(failures []service.VerificationFailure) []VerificationFailureResponse {
+	if len(failures) == 0 {
+		return nil
+	}
+	responses := make([]VerificationFailureResponse, len(failures))
+	for i, f := range failures {
+		responses[i] = VerificationFailureResponse{
+			Code:     f.Code.String(),
+			Expected: f.Expected,
+			Actual:   f.Actual,
+			Message:  f.Message,
+		}
+	}
+	return responses
+}
+
+// newSettlementResponse converts a settlement to its response body
+func newSettlementResponse(settlement command.Settlement) SettlementResponse {
+	response := SettlementResponse{
+		ID:                settlement.ID,
+		TxID:              settlement.TxID,
+		State:             string(settlement.State),
+		Network:           settlement.Network,
+		TokenType:         settlement.TokenType,
+		Confirmations:     settlement.Confirmations,
+		BurnConfirmations: settlement.BurnConfirmations,
+		Error:             settlement.Error,
+		Retryable:         settlement.Retryable,
+		CreatedAt:         settlement.CreatedAt,
+		UpdatedAt:         settlement.UpdatedAt,
+	}
+	if settlement.Result != nil {
+		result := newSettleResponse(*settlement.Result)
+		response.Result = &result
+	}
+	return response
 }
 
 // Health handles GET /health
@@ -165,6 +337,9 @@ func (h *Handler) RegisterRoutes(e *echo.Echo) {
 	api := e.Group("/api/v1")
 	api.POST("/verify", h.Verify)
 	api.POST("/settle", h.Settle)
+	if h.asyncSettleHandler != nil {
+		api.GET("/settlements/:id", h.Settlement)
+	}
 
 	e.GET("/health", h.Health)
 }
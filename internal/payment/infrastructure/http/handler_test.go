@@ -3,15 +3,20 @@ package http
 import (
 	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/labstack/echo/v4"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"github.com/x402stacks/stacks-facilitator/internal/payment/application/command"
+	"github.com/x402stacks/stacks-facilitator/internal/payment/domain/service"
+	"github.com/x402stacks/stacks-facilitator/internal/payment/domain/valueobject"
 )
 
 // MockVerifyHandler for testing
@@ -32,15 +37,30 @@ func (m *MockSettleHandler) Handle(ctx context.Context, cmd command.SettlePaymen
 	return m.HandleFn(ctx, cmd)
 }
 
+// MockAsyncSettleHandler for testing
+type MockAsyncSettleHandler struct {
+	HandleFn    func(ctx context.Context, cmd command.SettlePaymentCommand) (command.AsyncSettleResult, error)
+	Settlements map[string]command.Settlement
+}
+
+func (m *MockAsyncSettleHandler) Handle(ctx context.Context, cmd command.SettlePaymentCommand) (command.AsyncSettleResult, error) {
+	return m.HandleFn(ctx, cmd)
+}
+
+func (m *MockAsyncSettleHandler) Get(ctx context.Context, id string) (command.Settlement, bool, error) {
+	settlement, ok := m.Settlements[id]
+	return settlement, ok, nil
+}
+
 func TestHandler_Verify_Success(t *testing.T) {
 	mockVerify := &MockVerifyHandler{
 		HandleFn: func(ctx context.Context, cmd command.VerifyPaymentCommand) (command.VerifyPaymentResult, error) {
 			return command.VerifyPaymentResult{
 				Valid:            true,
 				TxID:             "0x1234567890abcdef1234567890abcdef1234567890abcdef1234567890abcdef",
-				SenderAddress:    "ST2J6ZY48GV1EZ5V2V5RB9MP66SW86PYKKNRV9EJ7",
+				SenderAddress:    "ST2J6ZY48GV1EZ5V2V5RB9MP66SW86PYKKQYAC0RQ",
 				RecipientAddress: "ST1PQHQKV0RJXZFY1DGX8MNSNYVE3VGZJSRTPGZGM",
-				Amount:           1000000,
+				Amount:           "1000000",
 				Fee:              180,
 				Status:           "confirmed",
 				BlockHeight:      12345,
@@ -76,6 +96,414 @@ func TestHandler_Verify_Success(t *testing.T) {
 	assert.Equal(t, "confirmed", response.Status)
 }
 
+func TestHandler_Verify_Failures(t *testing.T) {
+	mockVerify := &MockVerifyHandler{
+		HandleFn: func(ctx context.Context, cmd command.VerifyPaymentCommand) (command.VerifyPaymentResult, error) {
+			return command.VerifyPaymentResult{
+				Valid:   false,
+				Status:  "pending",
+				Network: "testnet",
+				Errors:  []string{"transaction not confirmed", "memo mismatch: expected order-42, got order-41"},
+				Failures: []service.VerificationFailure{
+					{Code: service.FailureNotConfirmed, Expected: "confirmed", Actual: "pending", Message: "transaction not confirmed"},
+					{Code: service.FailureMemoMismatch, Expected: "order-42", Actual: "order-41", Message: "memo mismatch: expected order-42, got order-41"},
+				},
+			}, nil
+		},
+	}
+
+	rec := serve(NewHandler(mockVerify, nil), http.MethodPost, "/api/v1/verify", `{
+		"tx_id": "0x1234567890abcdef1234567890abcdef1234567890abcdef1234567890abcdef",
+		"expected_recipient": "ST1PQHQKV0RJXZFY1DGX8MNSNYVE3VGZJSRTPGZGM",
+		"min_amount": 500000,
+		"network": "testnet"
+	}`)
+
+	require.Equal(t, http.StatusOK, rec.Code)
+	var body map[string]any
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body))
+	assert.Equal(t, []any{"transaction not confirmed", "memo mismatch: expected order-42, got order-41"}, body["errors"])
+	assert.Equal(t, []any{
+		map[string]any{"code": "not_confirmed", "expected": "confirmed", "actual": "pending", "message": "transaction not confirmed"},
+		map[string]any{"code": "memo_mismatch", "expected": "order-42", "actual": "order-41", "message": "memo mismatch: expected order-42, got order-41"},
+	}, body["failures"])
+}
+
+func TestHandler_Verify_AlreadyUsed(t *testing.T) {
+	mockVerify := &MockVerifyHandler{
+		HandleFn: func(ctx context.Context, cmd command.VerifyPaymentCommand) (command.VerifyPaymentResult, error) {
+			assert.Equal(t, "/premium", cmd.Resource)
+			return command.VerifyPaymentResult{}, fmt.Errorf("%w: transaction already accepted", command.ErrPaymentAlreadyUsed)
+		},
+	}
+
+	handler := NewHandler(mockVerify, nil)
+
+	e := echo.New()
+	reqBody := `{
+		"tx_id": "0x1234567890abcdef1234567890abcdef1234567890abcdef1234567890abcdef",
+		"expected_recipient": "ST1PQHQKV0RJXZFY1DGX8MNSNYVE3VGZJSRTPGZGM",
+		"min_amount": 500000,
+		"network": "testnet",
+		"resource": "/premium"
+	}`
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/verify", strings.NewReader(reqBody))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+
+	err := handler.Verify(c)
+
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusConflict, rec.Code)
+
+	var response ErrorResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+	assert.Equal(t, "already_used", response.Error)
+}
+
+func TestHandler_Verify_LookupErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		err    error
+		status int
+		error  string
+	}{
+		{"unknown txid", fmt.Errorf("%w: 0x1234", command.ErrTransactionNotFound), http.StatusNotFound, "transaction_not_found"},
+		{"api unreachable", fmt.Errorf("%w: connection refused", command.ErrTransactionUnavailable), http.StatusServiceUnavailable, "transaction_unavailable"},
+		{"unexpected", errors.New("boom"), http.StatusInternalServerError, "verification_failed"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockVerify := &MockVerifyHandler{
+				HandleFn: func(ctx context.Context, cmd command.VerifyPaymentCommand) (command.VerifyPaymentResult, error) {
+					return command.VerifyPaymentResult{}, tt.err
+				},
+			}
+			handler := NewHandler(mockVerify, nil)
+
+			e := echo.New()
+			reqBody := `{
+				"tx_id": "0x1234567890abcdef1234567890abcdef1234567890abcdef1234567890abcdef",
+				"expected_recipient": "ST1PQHQKV0RJXZFY1DGX8MNSNYVE3VGZJSRTPGZGM",
+				"min_amount": 500000,
+				"network": "testnet"
+			}`
+			req := httptest.NewRequest(http.MethodPost, "/api/v1/verify", strings.NewReader(reqBody))
+			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+			rec := httptest.NewRecorder()
+
+			require.NoError(t, handler.Verify(e.NewContext(req, rec)))
+
+			assert.Equal(t, tt.status, rec.Code)
+			var response ErrorResponse
+			require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+			assert.Equal(t, tt.error, response.Error)
+		})
+	}
+}
+
+func TestHandler_Verify_SignedTransaction(t *testing.T) {
+	mockVerify := &MockVerifyHandler{
+		HandleFn: func(ctx context.Context, cmd command.VerifyPaymentCommand) (command.VerifyPaymentResult, error) {
+			assert.Equal(t, "0x80800000000400", cmd.SignedTransaction)
+			assert.Empty(t, cmd.TxID)
+			return command.VerifyPaymentResult{
+				Valid:         true,
+				SenderAddress: "ST2J6ZY48GV1EZ5V2V5RB9MP66SW86PYKKQYAC0RQ",
+				Nonce:         5,
+				Status:        "pending",
+				Network:       "testnet",
+			}, nil
+		},
+	}
+	handler := NewHandler(mockVerify, nil)
+
+	e := echo.New()
+	reqBody := `{
+		"signed_transaction": "0x80800000000400",
+		"expected_recipient": "ST1PQHQKV0RJXZFY1DGX8MNSNYVE3VGZJSRTPGZGM",
+		"min_amount": 500000,
+		"network": "testnet"
+	}`
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/verify", strings.NewReader(reqBody))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+
+	err := handler.Verify(c)
+
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	var response VerifyResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+	assert.True(t, response.Valid)
+	assert.Empty(t, response.TxID)
+	assert.Equal(t, "pending", response.Status)
+	assert.Equal(t, uint64(5), response.Nonce)
+}
+
+func TestHandler_Verify_SignedTransactionErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		payload string
+		error   string
+	}{
+		{"both tx_id and signed_transaction", `"tx_id": "0x1234", "signed_transaction": "0x8080",`, "invalid_request"},
+		{"undecodable", `"signed_transaction": "0xdead",`, "invalid_transaction"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockVerify := &MockVerifyHandler{
+				HandleFn: func(ctx context.Context, cmd command.VerifyPaymentCommand) (command.VerifyPaymentResult, error) {
+					return command.VerifyPaymentResult{}, fmt.Errorf("%w: malformed transaction", command.ErrInvalidSignedTransaction)
+				},
+			}
+			handler := NewHandler(mockVerify, nil)
+
+			e := echo.New()
+			reqBody := `{` + tt.payload + `
+				"expected_recipient": "ST1PQHQKV0RJXZFY1DGX8MNSNYVE3VGZJSRTPGZGM",
+				"min_amount": 500000,
+				"network": "testnet"
+			}`
+			req := httptest.NewRequest(http.MethodPost, "/api/v1/verify", strings.NewReader(reqBody))
+			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+			rec := httptest.NewRecorder()
+
+			require.NoError(t, handler.Verify(e.NewContext(req, rec)))
+
+			assert.Equal(t, http.StatusBadRequest, rec.Code)
+			var response ErrorResponse
+			require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+			assert.Equal(t, tt.error, response.Error)
+		})
+	}
+}
+
+func TestHandler_Verify_DecimalStringAmounts(t *testing.T) {
+	tests := []struct {
+		name      string
+		minAmount string
+		want      string
+	}{
+		{"JSON number", `500000`, "500000"},
+		{"decimal string", `"500000"`, "500000"},
+		{"beyond 2^53", `"18446744073709551616"`, "18446744073709551616"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockVerify := &MockVerifyHandler{
+				HandleFn: func(ctx context.Context, cmd command.VerifyPaymentCommand) (command.VerifyPaymentResult, error) {
+					assert.Equal(t, tt.want, cmd.MinAmount)
+					return command.VerifyPaymentResult{Valid: true, Amount: "18446744073709551617"}, nil
+				},
+			}
+
+			handler := NewHandler(mockVerify, nil)
+
+			e := echo.New()
+			reqBody := `{
+				"tx_id": "0x1234567890abcdef1234567890abcdef1234567890abcdef1234567890abcdef",
+				"expected_recipient": "ST1PQHQKV0RJXZFY1DGX8MNSNYVE3VGZJSRTPGZGM",
+				"min_amount": ` + tt.minAmount + `,
+				"network": "testnet"
+			}`
+			req := httptest.NewRequest(http.MethodPost, "/api/v1/verify", strings.NewReader(reqBody))
+			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+
+			err := handler.Verify(c)
+
+			require.NoError(t, err)
+			assert.Equal(t, http.StatusOK, rec.Code)
+			assert.Contains(t, rec.Body.String(), `"amount":"18446744073709551617"`)
+		})
+	}
+}
+
+// stubBlockchainClient returns the same transaction for every lookup
+type stubBlockchainClient struct {
+	tx service.BlockchainTransaction
+}
+
+func (s stubBlockchainClient) GetTransactionWithRetry(ctx context.Context, txID valueobject.TransactionID, tokenType valueobject.TokenType, network valueobject.Network, maxRetries int, retryDelay time.Duration) (service.BlockchainTransaction, error) {
+	return s.tx, nil
+}
+
+func TestHandler_Verify_OmittedMinAmount(t *testing.T) {
+	txID, _ := valueobject.NewTransactionID("0x1234567890abcdef1234567890abcdef1234567890abcdef1234567890abcdef")
+	sender, _ := valueobject.NewStacksAddress("ST2J6ZY48GV1EZ5V2V5RB9MP66SW86PYKKQYAC0RQ")
+	recipient, _ := valueobject.NewPrincipal("ST1PQHQKV0RJXZFY1DGX8MNSNYVE3VGZJSRTPGZGM")
+	client := stubBlockchainClient{tx: service.BlockchainTransaction{
+		TxID:        txID,
+		TokenType:   valueobject.TokenSTX,
+		Sender:      sender,
+		Recipient:   recipient,
+		Amount:      valueobject.NewAmount(1000),
+		Status:      "success",
+		IsConfirmed: true,
+	}}
+	handler := NewHandler(command.NewVerifyPaymentHandler(client, service.NewVerificationService()), nil)
+
+	e := echo.New()
+	reqBody := `{
+		"tx_id": "0x1234567890abcdef1234567890abcdef1234567890abcdef1234567890abcdef",
+		"expected_recipient": "ST1PQHQKV0RJXZFY1DGX8MNSNYVE3VGZJSRTPGZGM",
+		"network": "testnet"
+	}`
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/verify", strings.NewReader(reqBody))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+
+	require.NoError(t, handler.Verify(e.NewContext(req, rec)))
+
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	var response VerifyResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+	assert.True(t, response.Valid, "an omitted min_amount is zero")
+}
+
+func TestHandler_Verify_AmountUnit(t *testing.T) {
+	mockVerify := &MockVerifyHandler{
+		HandleFn: func(ctx context.Context, cmd command.VerifyPaymentCommand) (command.VerifyPaymentResult, error) {
+			assert.Equal(t, "0.0001", cmd.MinAmount)
+			assert.Equal(t, command.AmountUnitToken, cmd.AmountUnit)
+			return command.VerifyPaymentResult{Valid: true, Amount: "10000", DisplayAmount: "0.0001 sBTC"}, nil
+		},
+	}
+
+	handler := NewHandler(mockVerify, nil)
+
+	e := echo.New()
+	reqBody := `{
+		"tx_id": "0x1234567890abcdef1234567890abcdef1234567890abcdef1234567890abcdef",
+		"expected_recipient": "ST1PQHQKV0RJXZFY1DGX8MNSNYVE3VGZJSRTPGZGM",
+		"min_amount": "0.0001",
+		"amount_unit": "token",
+		"token_type": "SBTC",
+		"network": "testnet"
+	}`
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/verify", strings.NewReader(reqBody))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+
+	err := handler.Verify(c)
+
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	var response VerifyResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+	assert.Equal(t, "10000", response.Amount)
+	assert.Equal(t, "0.0001 sBTC", response.DisplayAmount)
+}
+
+func TestHandler_Verify_Confirmations(t *testing.T) {
+	mockVerify := &MockVerifyHandler{
+		HandleFn: func(ctx context.Context, cmd command.VerifyPaymentCommand) (command.VerifyPaymentResult, error) {
+			assert.Equal(t, uint64(6), cmd.MinConfirmations)
+			assert.Equal(t, uint64(1), cmd.MinBurnConfirmations)
+			return command.VerifyPaymentResult{Valid: true, Status: "confirmed", Confirmations: 7, BurnConfirmations: 2}, nil
+		},
+	}
+
+	handler := NewHandler(mockVerify, nil)
+
+	e := echo.New()
+	reqBody := `{
+		"tx_id": "0x1234567890abcdef1234567890abcdef1234567890abcdef1234567890abcdef",
+		"expected_recipient": "ST1PQHQKV0RJXZFY1DGX8MNSNYVE3VGZJSRTPGZGM",
+		"min_amount": 1000,
+		"min_confirmations": 6,
+		"min_burn_confirmations": 1,
+		"network": "testnet"
+	}`
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/verify", strings.NewReader(reqBody))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+
+	err := handler.Verify(c)
+
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	var response VerifyResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+	assert.Equal(t, uint64(7), response.Confirmations)
+	assert.Equal(t, uint64(2), response.BurnConfirmations)
+}
+
+func TestHandler_Verify_InvalidAmount(t *testing.T) {
+	mockVerify := &MockVerifyHandler{
+		HandleFn: func(ctx context.Context, cmd command.VerifyPaymentCommand) (command.VerifyPaymentResult, error) {
+			return command.VerifyPaymentResult{}, fmt.Errorf("invalid min amount: %w: amount must be a non-negative integer: 1.5", command.ErrInvalidAmount)
+		},
+	}
+
+	handler := NewHandler(mockVerify, nil)
+
+	e := echo.New()
+	reqBody := `{
+		"tx_id": "0x1234567890abcdef1234567890abcdef1234567890abcdef1234567890abcdef",
+		"expected_recipient": "ST1PQHQKV0RJXZFY1DGX8MNSNYVE3VGZJSRTPGZGM",
+		"min_amount": 1.5,
+		"network": "testnet"
+	}`
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/verify", strings.NewReader(reqBody))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+
+	err := handler.Verify(c)
+
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+	var response ErrorResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+	assert.Equal(t, "invalid_amount", response.Error)
+}
+
+func TestHandler_Verify_UnsupportedToken(t *testing.T) {
+	mockVerify := &MockVerifyHandler{
+		HandleFn: func(ctx context.Context, cmd command.VerifyPaymentCommand) (command.VerifyPaymentResult, error) {
+			return command.VerifyPaymentResult{}, fmt.Errorf("%w: %s is not supported on testnet", command.ErrUnsupportedToken, cmd.TokenType)
+		},
+	}
+
+	handler := NewHandler(mockVerify, nil)
+
+	e := echo.New()
+	reqBody := `{
+		"tx_id": "0x1234567890abcdef1234567890abcdef1234567890abcdef1234567890abcdef",
+		"expected_recipient": "ST1PQHQKV0RJXZFY1DGX8MNSNYVE3VGZJSRTPGZGM",
+		"min_amount": 1000,
+		"token_type": "DOGE",
+		"network": "testnet"
+	}`
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/verify", strings.NewReader(reqBody))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+
+	err := handler.Verify(c)
+
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+	var response ErrorResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+	assert.Equal(t, "unsupported_token", response.Error)
+}
+
 func TestHandler_Verify_InvalidRequest(t *testing.T) {
 	handler := NewHandler(nil, nil)
 
@@ -114,9 +542,9 @@ func TestHandler_Settle_Success(t *testing.T) {
 			return command.SettlePaymentResult{
 				Success:          true,
 				TxID:             "0x1234567890abcdef1234567890abcdef1234567890abcdef1234567890abcdef",
-				SenderAddress:    "ST2J6ZY48GV1EZ5V2V5RB9MP66SW86PYKKNRV9EJ7",
+				SenderAddress:    "ST2J6ZY48GV1EZ5V2V5RB9MP66SW86PYKKQYAC0RQ",
 				RecipientAddress: "ST1PQHQKV0RJXZFY1DGX8MNSNYVE3VGZJSRTPGZGM",
-				Amount:           1000000,
+				Amount:           "1000000",
 				Fee:              180,
 				Status:           "confirmed",
 				BlockHeight:      12345,
@@ -152,6 +580,201 @@ func TestHandler_Settle_Success(t *testing.T) {
 	assert.Equal(t, "confirmed", response.Status)
 }
 
+func TestHandler_Settle_InvalidTransaction(t *testing.T) {
+	mockSettle := &MockSettleHandler{
+		HandleFn: func(ctx context.Context, cmd command.SettlePaymentCommand) (command.SettlePaymentResult, error) {
+			return command.SettlePaymentResult{}, fmt.Errorf("%w: malformed transaction: unexpected end of input", command.ErrInvalidSignedTransaction)
+		},
+	}
+
+	handler := NewHandler(nil, mockSettle)
+
+	e := echo.New()
+	reqBody := `{
+		"signed_transaction": "0x00000001deadbeef",
+		"expected_recipient": "ST1PQHQKV0RJXZFY1DGX8MNSNYVE3VGZJSRTPGZGM",
+		"min_amount": 500000,
+		"network": "testnet"
+	}`
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/settle", strings.NewReader(reqBody))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+
+	err := handler.Settle(c)
+
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+	var response ErrorResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+	assert.Equal(t, "invalid_transaction", response.Error)
+}
+
+func TestHandler_Settle_BroadcastRejected(t *testing.T) {
+	tests := []struct {
+		reason     command.BroadcastRejectionReason
+		wantStatus int
+		wantError  string
+	}{
+		{command.RejectionBadNonce, http.StatusConflict, "bad_nonce"},
+		{command.RejectionConflictingNonceInMempool, http.StatusConflict, "conflicting_nonce_in_mempool"},
+		{command.RejectionNotEnoughFunds, http.StatusBadRequest, "not_enough_funds"},
+		{command.RejectionFeeTooLow, http.StatusBadRequest, "fee_too_low"},
+		{command.RejectionBadTransactionVersion, http.StatusBadRequest, "bad_transaction_version"},
+		{command.RejectionContractAlreadyExists, http.StatusConflict, "contract_already_exists"},
+		{command.RejectionPostConditionFailed, http.StatusBadRequest, "post_condition_failed"},
+		{"Deserialization", http.StatusBadRequest, "transaction_rejected"},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.reason), func(t *testing.T) {
+			mockSettle := &MockSettleHandler{
+				HandleFn: func(ctx context.Context, cmd command.SettlePaymentCommand) (command.SettlePaymentResult, error) {
+					rejected := &command.BroadcastRejectedError{Reason: tt.reason, Message: "node says no"}
+					return command.SettlePaymentResult{}, fmt.Errorf("failed to broadcast transaction: %w", rejected)
+				},
+			}
+
+			handler := NewHandler(nil, mockSettle)
+
+			e := echo.New()
+			reqBody := `{
+				"signed_transaction": "0x00000001deadbeef",
+				"expected_recipient": "ST1PQHQKV0RJXZFY1DGX8MNSNYVE3VGZJSRTPGZGM",
+				"min_amount": 500000,
+				"network": "testnet"
+			}`
+			req := httptest.NewRequest(http.MethodPost, "/api/v1/settle", strings.NewReader(reqBody))
+			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+
+			err := handler.Settle(c)
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantStatus, rec.Code)
+
+			var response ErrorResponse
+			require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+			assert.Equal(t, tt.wantError, response.Error)
+			assert.Equal(t, "transaction rejected: "+string(tt.reason)+": node says no", response.Message)
+		})
+	}
+}
+
+func TestHandler_Settle_InvalidAddress(t *testing.T) {
+	mockSettle := &MockSettleHandler{
+		HandleFn: func(ctx context.Context, cmd command.SettlePaymentCommand) (command.SettlePaymentResult, error) {
+			return command.SettlePaymentResult{}, fmt.Errorf("invalid expected recipient: %w: %s is not a testnet address", command.ErrInvalidAddress, cmd.ExpectedRecipient)
+		},
+	}
+
+	handler := NewHandler(nil, mockSettle)
+
+	e := echo.New()
+	reqBody := `{
+		"signed_transaction": "0x00000001deadbeef",
+		"expected_recipient": "SP1PQHQKV0RJXZFY1DGX8MNSNYVE3VGZJSRCBGD7R",
+		"min_amount": 500000,
+		"network": "testnet"
+	}`
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/settle", strings.NewReader(reqBody))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+
+	err := handler.Settle(c)
+
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+	var response ErrorResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+	assert.Equal(t, "invalid_address", response.Error)
+}
+
+func TestHandler_Settle_AlreadyUsed(t *testing.T) {
+	var received command.SettlePaymentCommand
+	mockSettle := &MockSettleHandler{
+		HandleFn: func(ctx context.Context, cmd command.SettlePaymentCommand) (command.SettlePaymentResult, error) {
+			received = cmd
+			return command.SettlePaymentResult{}, fmt.Errorf("%w: transaction 0x12 was already accepted for /other", command.ErrPaymentAlreadyUsed)
+		},
+	}
+	handler := NewHandler(nil, mockSettle)
+
+	rec := serve(handler, http.MethodPost, "/api/v1/settle", `{
+		"signed_transaction": "0x00000001deadbeef",
+		"expected_recipient": "ST1PQHQKV0RJXZFY1DGX8MNSNYVE3VGZJSRTPGZGM",
+		"min_amount": 500000,
+		"network": "testnet",
+		"resource": "/premium",
+		"nonce": "abc"
+	}`)
+
+	assert.Equal(t, http.StatusConflict, rec.Code)
+	assert.Equal(t, "/premium", received.Resource)
+	assert.Equal(t, "abc", received.Nonce)
+	var response ErrorResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+	assert.Equal(t, "already_used", response.Error)
+}
+
+func TestHandler_Settle_SponsorFailed(t *testing.T) {
+	mockSettle := &MockSettleHandler{
+		HandleFn: func(ctx context.Context, cmd command.SettlePaymentCommand) (command.SettlePaymentResult, error) {
+			rejected := &command.BroadcastRejectedError{Reason: command.RejectionFeeTooLow, Message: "fee 180 is below the minimum of 200"}
+			return command.SettlePaymentResult{}, fmt.Errorf("%w: %w", command.ErrSponsorFailed, rejected)
+		},
+	}
+	handler := NewHandler(nil, mockSettle)
+
+	rec := serve(handler, http.MethodPost, "/api/v1/settle", `{
+		"signed_transaction": "0x00000001deadbeef",
+		"expected_recipient": "ST1PQHQKV0RJXZFY1DGX8MNSNYVE3VGZJSRTPGZGM",
+		"min_amount": 500000,
+		"network": "testnet"
+	}`)
+
+	assert.Equal(t, http.StatusServiceUnavailable, rec.Code)
+	var response ErrorResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+	assert.Equal(t, "sponsor_unavailable", response.Error, "the sponsor's fee is not the payer's problem")
+}
+
+func TestHandler_Settle_CallbackURL(t *testing.T) {
+	var received command.SettlePaymentCommand
+	mockSettle := &MockSettleHandler{
+		HandleFn: func(ctx context.Context, cmd command.SettlePaymentCommand) (command.SettlePaymentResult, error) {
+			received = cmd
+			if cmd.CallbackURL == "/hooks" {
+				return command.SettlePaymentResult{}, fmt.Errorf("%w: must be an absolute http or https url", command.ErrInvalidCallbackURL)
+			}
+			return command.SettlePaymentResult{Success: true, Status: "confirmed"}, nil
+		},
+	}
+	handler := NewHandler(nil, mockSettle)
+
+	body := `{
+		"signed_transaction": "0x00000001deadbeef",
+		"expected_recipient": "ST1PQHQKV0RJXZFY1DGX8MNSNYVE3VGZJSRTPGZGM",
+		"min_amount": 500000,
+		"network": "testnet",
+		"callback_url": "%s"
+	}`
+
+	rec := serve(handler, http.MethodPost, "/api/v1/settle", fmt.Sprintf(body, "https://merchant.example/hooks"))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "https://merchant.example/hooks", received.CallbackURL)
+
+	rec = serve(handler, http.MethodPost, "/api/v1/settle", fmt.Sprintf(body, "/hooks"))
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	var response ErrorResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+	assert.Equal(t, "invalid_callback_url", response.Error)
+}
+
 func TestHandler_Settle_InvalidRequest(t *testing.T) {
 	handler := NewHandler(nil, nil)
 
@@ -197,3 +820,167 @@ func TestHandler_Health(t *testing.T) {
 	require.NoError(t, err)
 	assert.Equal(t, http.StatusOK, rec.Code)
 }
+
+const asyncSettleBody = `{
+	"signed_transaction": "0x00000001deadbeef",
+	"expected_recipient": "ST1PQHQKV0RJXZFY1DGX8MNSNYVE3VGZJSRTPGZGM",
+	"min_amount": "1000",
+	"network": "testnet",
+	"async": true
+}`
+
+// serve sends a request through the handler's registered routes
+func serve(handler *Handler, method, target, body string) *httptest.ResponseRecorder {
+	e := echo.New()
+	handler.RegisterRoutes(e)
+	req := httptest.NewRequest(method, target, strings.NewReader(body))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestHandler_Settle_Async(t *testing.T) {
+	mockAsync := &MockAsyncSettleHandler{
+		HandleFn: func(ctx context.Context, cmd command.SettlePaymentCommand) (command.AsyncSettleResult, error) {
+			assert.Equal(t, "0x00000001deadbeef", cmd.SignedTransaction)
+			return command.AsyncSettleResult{
+				Accepted: true,
+				Settlement: command.Settlement{
+					ID:        "5f2b",
+					TxID:      "0xabcdef1234567890abcdef1234567890abcdef1234567890abcdef1234567890",
+					Network:   "testnet",
+					TokenType: "STX",
+					State:     command.SettlementBroadcast,
+				},
+			}, nil
+		},
+	}
+	handler := NewHandler(nil, &MockSettleHandler{}).WithAsyncSettle(mockAsync)
+
+	rec := serve(handler, http.MethodPost, "/api/v1/settle", asyncSettleBody)
+
+	assert.Equal(t, http.StatusAccepted, rec.Code)
+	assert.Equal(t, "/api/v1/settlements/5f2b", rec.Header().Get(echo.HeaderLocation))
+
+	var response SettlementResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+	assert.Equal(t, "5f2b", response.ID)
+	assert.Equal(t, "broadcast", response.State)
+	assert.Nil(t, response.Result)
+}
+
+func TestHandler_Settle_AsyncRejected(t *testing.T) {
+	mockAsync := &MockAsyncSettleHandler{
+		HandleFn: func(ctx context.Context, cmd command.SettlePaymentCommand) (command.AsyncSettleResult, error) {
+			return command.AsyncSettleResult{Rejected: command.SettlePaymentResult{
+				Status: "failed",
+				Errors: []string{"insufficient amount: expected at least 1000, got 10"},
+				Failures: []service.VerificationFailure{{
+					Code:     service.FailureInsufficientAmount,
+					Expected: "1000",
+					Actual:   "10",
+					Message:  "insufficient amount: expected at least 1000, got 10",
+				}},
+			}}, nil
+		},
+	}
+	handler := NewHandler(nil, &MockSettleHandler{}).WithAsyncSettle(mockAsync)
+
+	rec := serve(handler, http.MethodPost, "/api/v1/settle", asyncSettleBody)
+
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	var response SettleResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+	assert.False(t, response.Success)
+	assert.Equal(t, "failed", response.Status)
+	assert.Equal(t, []VerificationFailureResponse{{
+		Code:     "insufficient_amount",
+		Expected: "1000",
+		Actual:   "10",
+		Message:  "insufficient amount: expected at least 1000, got 10",
+	}}, response.Failures)
+}
+
+func TestHandler_Settle_AsyncErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		async  AsyncSettlePaymentHandler
+		status int
+		error  string
+	}{
+		{"not enabled", nil, http.StatusBadRequest, "async_unavailable"},
+		{"queue full", &MockAsyncSettleHandler{
+			HandleFn: func(ctx context.Context, cmd command.SettlePaymentCommand) (command.AsyncSettleResult, error) {
+				return command.AsyncSettleResult{}, command.ErrSettlementQueueFull
+			},
+		}, http.StatusServiceUnavailable, "settlement_queue_full"},
+		{"workers stopped", &MockAsyncSettleHandler{
+			HandleFn: func(ctx context.Context, cmd command.SettlePaymentCommand) (command.AsyncSettleResult, error) {
+				return command.AsyncSettleResult{}, command.ErrSettlementStopped
+			},
+		}, http.StatusServiceUnavailable, "settlement_stopped"},
+		{"invalid transaction", &MockAsyncSettleHandler{
+			HandleFn: func(ctx context.Context, cmd command.SettlePaymentCommand) (command.AsyncSettleResult, error) {
+				return command.AsyncSettleResult{}, fmt.Errorf("%w: bad", command.ErrInvalidSignedTransaction)
+			},
+		}, http.StatusBadRequest, "invalid_transaction"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := NewHandler(nil, &MockSettleHandler{})
+			if tt.async != nil {
+				handler.WithAsyncSettle(tt.async)
+			}
+
+			rec := serve(handler, http.MethodPost, "/api/v1/settle", asyncSettleBody)
+
+			assert.Equal(t, tt.status, rec.Code)
+			var response ErrorResponse
+			require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+			assert.Equal(t, tt.error, response.Error)
+		})
+	}
+}
+
+func TestHandler_Settlement(t *testing.T) {
+	mockAsync := &MockAsyncSettleHandler{
+		Settlements: map[string]command.Settlement{
+			"5f2b": {
+				ID:            "5f2b",
+				TxID:          "0xabcdef1234567890abcdef1234567890abcdef1234567890abcdef1234567890",
+				State:         command.SettlementConfirmed,
+				Confirmations: 1,
+				Result: &command.SettlePaymentResult{
+					Success: true,
+					Amount:  "1000",
+					Status:  "confirmed",
+				},
+			},
+		},
+	}
+	handler := NewHandler(nil, &MockSettleHandler{}).WithAsyncSettle(mockAsync)
+
+	rec := serve(handler, http.MethodGet, "/api/v1/settlements/5f2b", "")
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	var response SettlementResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+	assert.Equal(t, "confirmed", response.State)
+	assert.Equal(t, uint64(1), response.Confirmations)
+	require.NotNil(t, response.Result)
+	assert.True(t, response.Result.Success)
+	assert.Equal(t, "1000", response.Result.Amount)
+
+	rec = serve(handler, http.MethodGet, "/api/v1/settlements/missing", "")
+
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+	assert.Contains(t, rec.Body.String(), "settlement_not_found")
+}
+
+func TestHandler_SettlementRouteRequiresAsync(t *testing.T) {
+	rec := serve(NewHandler(nil, &MockSettleHandler{}), http.MethodGet, "/api/v1/settlements/5f2b", "")
+
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+}
@@ -0,0 +1,64 @@
+package http
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"github.com/x402stacks/stacks-facilitator/internal/payment/application/command"
+	"github.com/x402stacks/stacks-facilitator/internal/payment/domain/valueobject"
+)
+
+// SponsorAccountLister interface for reporting the state of sponsor keys
+type SponsorAccountLister interface {
+	SponsorAccounts(ctx context.Context, network valueobject.Network) ([]command.SponsorAccount, error)
+}
+
+// SponsorHandler handles HTTP requests about fee sponsorship
+type SponsorHandler struct {
+	lister SponsorAccountLister
+}
+
+// NewSponsorHandler creates a new SponsorHandler
+func NewSponsorHandler(lister SponsorAccountLister) *SponsorHandler {
+	return &SponsorHandler{lister: lister}
+}
+
+// Accounts handles GET /api/v1/sponsor/accounts?network=
+func (h *SponsorHandler) Accounts(c echo.Context) error {
+	network, err := valueobject.NewNetwork(c.QueryParam("network"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "invalid_network",
+			Message: err.Error(),
+		})
+	}
+
+	accounts, err := h.lister.SponsorAccounts(c.Request().Context(), network)
+	if err != nil {
+		return c.JSON(http.StatusBadGateway, ErrorResponse{
+			Error:   "sponsor_unavailable",
+			Message: err.Error(),
+		})
+	}
+
+	response := SponsorAccountsResponse{
+		Network:  network.String(),
+		Accounts: make([]SponsorAccountResponse, 0, len(accounts)),
+	}
+	for _, account := range accounts {
+		response.Accounts = append(response.Accounts, SponsorAccountResponse{
+			Address:   account.Address.String(),
+			Balance:   account.Balance,
+			NextNonce: account.NextNonce,
+			Pending:   account.Pending,
+		})
+	}
+
+	return c.JSON(http.StatusOK, response)
+}
+
+// RegisterRoutes registers the sponsor routes
+func (h *SponsorHandler) RegisterRoutes(e *echo.Echo) {
+	e.GET("/api/v1/sponsor/accounts", h.Accounts)
+}
@@ -0,0 +1,80 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/x402stacks/stacks-facilitator/internal/payment/application/command"
+	"github.com/x402stacks/stacks-facilitator/internal/payment/domain/valueobject"
+)
+
+// MockSponsorLister for testing
+type MockSponsorLister struct {
+	ListFn func(ctx context.Context, network valueobject.Network) ([]command.SponsorAccount, error)
+}
+
+func (m *MockSponsorLister) SponsorAccounts(ctx context.Context, network valueobject.Network) ([]command.SponsorAccount, error) {
+	return m.ListFn(ctx, network)
+}
+
+func getSponsorAccounts(t *testing.T, handler *SponsorHandler, query string) *httptest.ResponseRecorder {
+	t.Helper()
+
+	e := echo.New()
+	handler.RegisterRoutes(e)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/sponsor/accounts"+query, nil)
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestSponsorHandler_Accounts(t *testing.T) {
+	address, _ := valueobject.NewStacksAddress("ST1PQHQKV0RJXZFY1DGX8MNSNYVE3VGZJSRTPGZGM")
+	lister := &MockSponsorLister{
+		ListFn: func(ctx context.Context, network valueobject.Network) ([]command.SponsorAccount, error) {
+			assert.Equal(t, valueobject.NetworkTestnet, network)
+			return []command.SponsorAccount{{Address: address, Balance: 5000000, NextNonce: 12, Pending: 3}}, nil
+		},
+	}
+
+	rec := getSponsorAccounts(t, NewSponsorHandler(lister), "?network=testnet")
+
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	var response SponsorAccountsResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+	assert.Equal(t, "testnet", response.Network)
+	require.Len(t, response.Accounts, 1)
+	assert.Equal(t, SponsorAccountResponse{
+		Address:   "ST1PQHQKV0RJXZFY1DGX8MNSNYVE3VGZJSRTPGZGM",
+		Balance:   5000000,
+		NextNonce: 12,
+		Pending:   3,
+	}, response.Accounts[0])
+}
+
+func TestSponsorHandler_Accounts_InvalidNetwork(t *testing.T) {
+	rec := getSponsorAccounts(t, NewSponsorHandler(nil), "?network=devnet")
+
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+}
+
+func TestSponsorHandler_Accounts_Unavailable(t *testing.T) {
+	lister := &MockSponsorLister{
+		ListFn: func(ctx context.Context, network valueobject.Network) ([]command.SponsorAccount, error) {
+			return nil, errors.New("API error: upstream down")
+		},
+	}
+
+	rec := getSponsorAccounts(t, NewSponsorHandler(lister), "?network=mainnet")
+
+	assert.Equal(t, http.StatusBadGateway, rec.Code)
+}
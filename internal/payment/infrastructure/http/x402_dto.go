@@ -0,0 +1,89 @@
+package http
+
+// X402Version is the x402 protocol version accepted by the facilitator endpoints
+const X402Version = 1
+
+// X402SchemeExact is the only payment scheme supported on Stacks
+const X402SchemeExact = "exact"
+
+// X402PaymentRequirements describes what the resource server will accept
+type X402PaymentRequirements struct {
+	Scheme            string         `json:"scheme"`
+	Network           string         `json:"network"`
+	MaxAmountRequired string         `json:"maxAmountRequired"`
+	Resource          string         `json:"resource"`
+	Description       string         `json:"description,omitempty"`
+	MimeType          string         `json:"mimeType,omitempty"`
+	OutputSchema      map[string]any `json:"outputSchema,omitempty"`
+	PayTo             string         `json:"payTo"`
+	MaxTimeoutSeconds int            `json:"maxTimeoutSeconds,omitempty"`
+	Asset             string         `json:"asset"`
+	Extra             map[string]any `json:"extra,omitempty"`
+}
+
+// X402StacksPayload is the scheme-specific payload for exact payments on Stacks
+type X402StacksPayload struct {
+	Transaction string `json:"transaction,omitempty"` // Hex-encoded signed transaction (verify before broadcast, settle)
+	TxID        string `json:"txId,omitempty"`        // Transaction already on chain (verify only)
+}
+
+// X402PaymentPayload is the payment the client attached to its request
+type X402PaymentPayload struct {
+	X402Version int               `json:"x402Version"`
+	Scheme      string            `json:"scheme"`
+	Network     string            `json:"network"`
+	Payload     X402StacksPayload `json:"payload"`
+}
+
+// X402Request is the body of POST /verify and POST /settle
+type X402Request struct {
+	X402Version         int                     `json:"x402Version"`
+	PaymentPayload      X402PaymentPayload      `json:"paymentPayload"`
+	PaymentRequirements X402PaymentRequirements `json:"paymentRequirements"`
+}
+
+// X402VerifyResponse is the x402 verification result
+type X402VerifyResponse struct {
+	IsValid        bool   `json:"isValid"`
+	InvalidReason  string `json:"invalidReason,omitempty"`
+	InvalidMessage string `json:"invalidMessage,omitempty"` // Human-readable detail of InvalidReason
+	Payer          string `json:"payer,omitempty"`
+}
+
+// X402SettleResponse is the x402 settlement result
+type X402SettleResponse struct {
+	Success      bool   `json:"success"`
+	ErrorReason  string `json:"errorReason,omitempty"`
+	ErrorMessage string `json:"errorMessage,omitempty"` // Human-readable detail of ErrorReason
+	Transaction  string `json:"transaction"`
+	Network      string `json:"network"`
+	Payer        string `json:"payer,omitempty"`
+}
+
+// X402SupportedAsset is a token accepted on a network
+type X402SupportedAsset struct {
+	TokenType       string `json:"tokenType"`
+	Asset           string `json:"asset"`                     // Value to use as paymentRequirements.asset
+	AssetIdentifier string `json:"assetIdentifier,omitempty"` // contract::asset for SIP-010 tokens
+	Symbol          string `json:"symbol"`
+	Decimals        int    `json:"decimals"`
+}
+
+// X402SupportedExtra carries Stacks-specific details of a supported kind
+type X402SupportedExtra struct {
+	CAIP2  string               `json:"caip2"`
+	Assets []X402SupportedAsset `json:"assets"`
+}
+
+// X402SupportedKind is one (scheme, network) pair the facilitator can verify and settle
+type X402SupportedKind struct {
+	X402Version int                `json:"x402Version"`
+	Scheme      string             `json:"scheme"`
+	Network     string             `json:"network"`
+	Extra       X402SupportedExtra `json:"extra"`
+}
+
+// X402SupportedResponse is the body of GET /supported
+type X402SupportedResponse struct {
+	Kinds []X402SupportedKind `json:"kinds"`
+}
@@ -0,0 +1,369 @@
+package http
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+	"github.com/x402stacks/stacks-facilitator/internal/payment/application/command"
+	"github.com/x402stacks/stacks-facilitator/internal/payment/domain/service"
+	"github.com/x402stacks/stacks-facilitator/internal/payment/domain/valueobject"
+	"github.com/x402stacks/stacks-facilitator/internal/stacks/transaction"
+)
+
+// x402 invalidReason / errorReason codes
+const (
+	reasonInvalidX402Version         = "invalid_x402_version"
+	reasonUnsupportedScheme          = "unsupported_scheme"
+	reasonInvalidNetwork             = "invalid_network"
+	reasonInvalidPayload             = "invalid_payload"
+	reasonInvalidPaymentRequirements = "invalid_payment_requirements"
+	reasonUnsupportedAsset           = "unsupported_asset"
+	reasonAlreadyUsed                = "already_used"
+	reasonInvalidTransaction         = "invalid_exact_stacks_payload_transaction"
+	reasonInvalidTransactionState    = "invalid_transaction_state"
+	reasonAssetMismatch              = "invalid_exact_stacks_payload_asset_mismatch"
+	reasonRecipientMismatch          = "invalid_exact_stacks_payload_recipient_mismatch"
+	reasonAmountInsufficient         = "invalid_exact_stacks_payload_amount_insufficient"
+	reasonSenderMismatch             = "invalid_exact_stacks_payload_sender_mismatch"
+	reasonMemoMismatch               = "invalid_exact_stacks_payload_memo_mismatch"
+	reasonSponsorshipRejected        = "invalid_exact_stacks_payload_sponsorship"
+	reasonInvalidSignature           = "invalid_exact_stacks_payload_signature"
+	reasonNonceMismatch              = "invalid_exact_stacks_payload_nonce"
+	reasonInsufficientFunds          = "insufficient_funds"
+	reasonNonceConflict              = "invalid_exact_stacks_payload_nonce_conflict"
+	reasonFeeTooLow                  = "invalid_exact_stacks_payload_fee_too_low"
+	reasonTransactionVersion         = "invalid_exact_stacks_payload_transaction_version"
+	reasonContractExists             = "invalid_exact_stacks_payload_contract_exists"
+	reasonPostConditionFailed        = "invalid_exact_stacks_payload_post_condition"
+	reasonInvalidExactStacksPayload  = "invalid_exact_stacks_payload"
+	reasonTransactionNotFound        = "invalid_exact_stacks_payload_transaction_not_found"
+	reasonTransactionUnavailable     = "transaction_lookup_unavailable"
+	reasonSponsorUnavailable         = "sponsor_unavailable"
+	reasonUnexpectedVerifyError      = "unexpected_verify_error"
+	reasonUnexpectedSettleError      = "unexpected_settle_error"
+)
+
+// X402Handler exposes the x402 facilitator interface over the payment use cases
+type X402Handler struct {
+	verifyHandler VerifyPaymentHandler
+	settleHandler SettlePaymentHandler
+	tokenRegistry *service.TokenRegistry
+}
+
+// NewX402Handler creates a new X402Handler; a nil registry uses the default token contracts
+func NewX402Handler(verifyHandler VerifyPaymentHandler, settleHandler SettlePaymentHandler, tokenRegistry *service.TokenRegistry) *X402Handler {
+	if tokenRegistry == nil {
+		tokenRegistry = service.DefaultTokenRegistry()
+	}
+	return &X402Handler{
+		verifyHandler: verifyHandler,
+		settleHandler: settleHandler,
+		tokenRegistry: tokenRegistry,
+	}
+}
+
+// x402Payment is an x402 request resolved to domain terms
+type x402Payment struct {
+	network   valueobject.Network
+	tokenType valueobject.TokenType
+	payTo     string
+	minAmount valueobject.Amount
+	resource  string
+}
+
+// Verify handles POST /verify
+func (h *X402Handler) Verify(c echo.Context) error {
+	var req X402Request
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, X402VerifyResponse{InvalidReason: reasonInvalidPayload})
+	}
+
+	payment, reason := h.resolve(req)
+	if reason != "" {
+		return c.JSON(http.StatusOK, X402VerifyResponse{InvalidReason: reason})
+	}
+
+	// A signed transaction is checked before broadcast; a txId is looked up on chain
+	payload := req.PaymentPayload.Payload
+	if payload.Transaction == "" && payload.TxID == "" {
+		return c.JSON(http.StatusOK, X402VerifyResponse{InvalidReason: reasonInvalidPayload})
+	}
+	if payload.Transaction != "" {
+		payload.TxID = ""
+	}
+
+	cmd := command.VerifyPaymentCommand{
+		TxID:              payload.TxID,
+		SignedTransaction: payload.Transaction,
+		TokenType:         payment.tokenType.String(),
+		ExpectedRecipient: payment.payTo,
+		MinAmount:         payment.minAmount.String(),
+		Network:           payment.network.String(),
+		Resource:          payment.resource,
+	}
+
+	result, err := h.verifyHandler.Handle(c.Request().Context(), cmd)
+	if errors.Is(err, command.ErrPaymentAlreadyUsed) {
+		return c.JSON(http.StatusOK, X402VerifyResponse{InvalidReason: reasonAlreadyUsed})
+	}
+	if errors.Is(err, command.ErrInvalidSignedTransaction) {
+		return c.JSON(http.StatusOK, X402VerifyResponse{InvalidReason: reasonInvalidTransaction})
+	}
+	// A payment that cannot be found or looked up right now is invalid, not a facilitator fault
+	if errors.Is(err, command.ErrTransactionNotFound) {
+		return c.JSON(http.StatusOK, X402VerifyResponse{InvalidReason: reasonTransactionNotFound, InvalidMessage: err.Error()})
+	}
+	if errors.Is(err, command.ErrTransactionUnavailable) {
+		return c.JSON(http.StatusOK, X402VerifyResponse{InvalidReason: reasonTransactionUnavailable, InvalidMessage: err.Error()})
+	}
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, X402VerifyResponse{InvalidReason: reasonUnexpectedVerifyError})
+	}
+
+	response := X402VerifyResponse{
+		IsValid: result.Valid,
+		Payer:   result.SenderAddress,
+	}
+	if !result.Valid {
+		response.InvalidReason, response.InvalidMessage = reasonForFailures(result.Failures)
+	}
+
+	return c.JSON(http.StatusOK, response)
+}
+
+// Settle handles POST /settle
+func (h *X402Handler) Settle(c echo.Context) error {
+	var req X402Request
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, X402SettleResponse{ErrorReason: reasonInvalidPayload})
+	}
+
+	network := req.PaymentRequirements.Network
+
+	payment, reason := h.resolve(req)
+	if reason != "" {
+		return c.JSON(http.StatusOK, X402SettleResponse{ErrorReason: reason, Network: network})
+	}
+
+	if req.PaymentPayload.Payload.Transaction == "" {
+		return c.JSON(http.StatusOK, X402SettleResponse{ErrorReason: reasonInvalidPayload, Network: network})
+	}
+
+	cmd := command.SettlePaymentCommand{
+		SignedTransaction: req.PaymentPayload.Payload.Transaction,
+		TokenType:         payment.tokenType.String(),
+		ExpectedRecipient: payment.payTo,
+		MinAmount:         payment.minAmount.String(),
+		Network:           payment.network.String(),
+		Resource:          payment.resource,
+	}
+
+	result, err := h.settleHandler.Handle(c.Request().Context(), cmd)
+	if errors.Is(err, command.ErrInvalidSignedTransaction) {
+		return c.JSON(http.StatusOK, X402SettleResponse{ErrorReason: reasonInvalidTransaction, Network: network})
+	}
+	if errors.Is(err, command.ErrPaymentAlreadyUsed) {
+		return c.JSON(http.StatusOK, X402SettleResponse{ErrorReason: reasonAlreadyUsed, ErrorMessage: err.Error(), Network: network})
+	}
+	if errors.Is(err, command.ErrSponsorFailed) {
+		return c.JSON(http.StatusServiceUnavailable, X402SettleResponse{ErrorReason: reasonSponsorUnavailable, ErrorMessage: err.Error(), Network: network})
+	}
+	var rejected *command.BroadcastRejectedError
+	if errors.As(err, &rejected) {
+		return c.JSON(http.StatusOK, X402SettleResponse{
+			ErrorReason:  rejectionResponseFor(rejected).x402Reason,
+			ErrorMessage: rejected.Error(),
+			Network:      network,
+		})
+	}
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, X402SettleResponse{ErrorReason: reasonUnexpectedSettleError, Network: network})
+	}
+
+	response := X402SettleResponse{
+		Success:     result.Success,
+		Transaction: result.TxID,
+		Network:     network,
+		Payer:       result.SenderAddress,
+	}
+	if !result.Success {
+		response.ErrorReason, response.ErrorMessage = reasonForFailures(result.Failures)
+	}
+
+	return c.JSON(http.StatusOK, response)
+}
+
+// Supported handles GET /supported
+func (h *X402Handler) Supported(c echo.Context) error {
+	return c.JSON(http.StatusOK, X402SupportedResponse{Kinds: h.supportedKinds()})
+}
+
+// RegisterRoutes registers the x402 facilitator routes
+func (h *X402Handler) RegisterRoutes(e *echo.Echo) {
+	e.POST("/verify", h.Verify)
+	e.POST("/settle", h.Settle)
+	e.GET("/supported", h.Supported)
+}
+
+// supportedKinds lists every network with the tokens that resolve on it
+func (h *X402Handler) supportedKinds() []X402SupportedKind {
+	var kinds []X402SupportedKind
+	for _, network := range valueobject.SupportedNetworks() {
+		assets := []X402SupportedAsset{}
+		for _, tokenType := range h.tokenRegistry.TokenTypes() {
+			metadata, _ := h.tokenRegistry.Metadata(tokenType)
+			asset := X402SupportedAsset{
+				TokenType: tokenType.String(),
+				Asset:     tokenType.String(),
+				Symbol:    metadata.Symbol,
+				Decimals:  metadata.Decimals,
+			}
+			if !tokenType.IsNative() {
+				contract, ok := h.tokenRegistry.Contract(tokenType, network)
+				if !ok {
+					continue
+				}
+				asset.Asset = contract.ContractID
+				asset.AssetIdentifier = contract.AssetIdentifier()
+			}
+			assets = append(assets, asset)
+		}
+
+		kinds = append(kinds, X402SupportedKind{
+			X402Version: X402Version,
+			Scheme:      X402SchemeExact,
+			Network:     x402NetworkName(network),
+			Extra: X402SupportedExtra{
+				CAIP2:  x402CAIP2(network),
+				Assets: assets,
+			},
+		})
+	}
+	return kinds
+}
+
+// resolve validates an x402 request and maps it to domain terms, returning a reason code on failure
+func (h *X402Handler) resolve(req X402Request) (x402Payment, string) {
+	requirements := req.PaymentRequirements
+	payload := req.PaymentPayload
+
+	if req.X402Version != X402Version || payload.X402Version != X402Version {
+		return x402Payment{}, reasonInvalidX402Version
+	}
+
+	if payload.Scheme != X402SchemeExact || requirements.Scheme != X402SchemeExact {
+		return x402Payment{}, reasonUnsupportedScheme
+	}
+
+	network, err := parseX402Network(requirements.Network)
+	if err != nil {
+		return x402Payment{}, reasonInvalidNetwork
+	}
+	payloadNetwork, err := parseX402Network(payload.Network)
+	if err != nil || payloadNetwork != network {
+		return x402Payment{}, reasonInvalidNetwork
+	}
+
+	minAmount, err := valueobject.ParseAmount(requirements.MaxAmountRequired)
+	if err != nil {
+		return x402Payment{}, reasonInvalidPaymentRequirements
+	}
+
+	payTo, err := valueobject.NewPrincipal(requirements.PayTo)
+	if err != nil || payTo.Network() != network {
+		return x402Payment{}, reasonInvalidPaymentRequirements
+	}
+
+	tokenType, err := h.resolveAsset(requirements.Asset, network)
+	if err != nil {
+		return x402Payment{}, reasonUnsupportedAsset
+	}
+
+	return x402Payment{
+		network:   network,
+		tokenType: tokenType,
+		payTo:     requirements.PayTo,
+		minAmount: minAmount,
+		resource:  requirements.Resource,
+	}, ""
+}
+
+// resolveAsset maps an x402 asset (token symbol, contract ID or contract::asset) to a token type
+func (h *X402Handler) resolveAsset(asset string, network valueobject.Network) (valueobject.TokenType, error) {
+	if tokenType, err := valueobject.NewTokenType(asset); err == nil {
+		if _, ok := h.tokenRegistry.Contract(tokenType, network); !tokenType.IsNative() && !ok {
+			return "", fmt.Errorf("token %s is not supported on %s", tokenType, network)
+		}
+		return tokenType, nil
+	}
+
+	contractID, _, _ := strings.Cut(asset, "::")
+	if tokenType, ok := h.tokenRegistry.TokenTypeForContract(contractID, network); ok {
+		return tokenType, nil
+	}
+
+	return "", fmt.Errorf("unsupported asset %q on %s", asset, network)
+}
+
+// parseX402Network maps x402 network identifiers (including CAIP-2) to a Stacks network
+func parseX402Network(s string) (valueobject.Network, error) {
+	s = strings.ToLower(s)
+	for _, network := range valueobject.SupportedNetworks() {
+		if s == x402NetworkName(network) || s == x402CAIP2(network) || s == "stacks-"+network.String() {
+			return network, nil
+		}
+	}
+	return "", fmt.Errorf("unsupported x402 network: %s", s)
+}
+
+// x402NetworkName returns the x402 network name for a Stacks network
+func x402NetworkName(network valueobject.Network) string {
+	if network.IsMainnet() {
+		return "stacks"
+	}
+	return "stacks-" + network.String()
+}
+
+// x402CAIP2 returns the CAIP-2 chain identifier for a Stacks network
+func x402CAIP2(network valueobject.Network) string {
+	if network.IsMainnet() {
+		return fmt.Sprintf("stacks:%d", transaction.ChainIDMainnet)
+	}
+	return fmt.Sprintf("stacks:%d", transaction.ChainIDTestnet)
+}
+
+// failureReasons maps verification failure codes to x402 reason codes
+var failureReasons = map[service.FailureCode]string{
+	service.FailureNetworkMismatch:               reasonInvalidNetwork,
+	service.FailureTxFailed:                      reasonInvalidTransactionState,
+	service.FailureTxDropped:                     reasonInvalidTransactionState,
+	service.FailureNotConfirmed:                  reasonInvalidTransactionState,
+	service.FailureInsufficientConfirmations:     reasonInvalidTransactionState,
+	service.FailureInsufficientBurnConfirmations: reasonInvalidTransactionState,
+	service.FailureTokenMismatch:                 reasonAssetMismatch,
+	service.FailureTokenContractMismatch:         reasonAssetMismatch,
+	service.FailureRecipientMismatch:             reasonRecipientMismatch,
+	service.FailureInsufficientAmount:            reasonAmountInsufficient,
+	service.FailureSenderMismatch:                reasonSenderMismatch,
+	service.FailureMemoMismatch:                  reasonMemoMismatch,
+	service.FailureSponsorPolicy:                 reasonSponsorshipRejected,
+	service.FailureInvalidSignature:              reasonInvalidSignature,
+	service.FailureNonceMismatch:                 reasonNonceMismatch,
+	service.FailureInsufficientBalance:           reasonInsufficientFunds,
+}
+
+// reasonForFailures maps the first verification failure to an x402 reason code and message
+func reasonForFailures(failures []service.VerificationFailure) (string, string) {
+	if len(failures) == 0 {
+		return reasonInvalidExactStacksPayload, ""
+	}
+
+	reason, ok := failureReasons[failures[0].Code]
+	if !ok {
+		reason = reasonInvalidExactStacksPayload
+	}
+	return reason, failures[0].Message
+}
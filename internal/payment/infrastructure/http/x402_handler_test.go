@@ -0,0 +1,662 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/x402stacks/stacks-facilitator/internal/payment/application/command"
+	"github.com/x402stacks/stacks-facilitator/internal/payment/domain/service"
+	"github.com/x402stacks/stacks-facilitator/internal/payment/domain/valueobject"
+)
+
+// x402Body builds an x402 request body with the given payload and requirement overrides
+func x402Body(network, asset, payload string) string {
+	return fmt.Sprintf(`{
+		"x402Version": 1,
+		"paymentPayload": {
+			"x402Version": 1,
+			"scheme": "exact",
+			"network": %q,
+			"payload": %s
+		},
+		"paymentRequirements": {
+			"scheme": "exact",
+			"network": %q,
+			"maxAmountRequired": "500000",
+			"resource": "https://api.example.com/premium",
+			"payTo": "ST1PQHQKV0RJXZFY1DGX8MNSNYVE3VGZJSRTPGZGM",
+			"maxTimeoutSeconds": 60,
+			"asset": %q
+		}
+	}`, network, payload, network, asset)
+}
+
+func serveX402(t *testing.T, handler *X402Handler, path, body string) *httptest.ResponseRecorder {
+	t.Helper()
+
+	e := echo.New()
+	handler.RegisterRoutes(e)
+
+	req := httptest.NewRequest(http.MethodPost, path, strings.NewReader(body))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestX402Handler_Verify_Valid(t *testing.T) {
+	mockVerify := &MockVerifyHandler{
+		HandleFn: func(ctx context.Context, cmd command.VerifyPaymentCommand) (command.VerifyPaymentResult, error) {
+			assert.Equal(t, "0x1234567890abcdef1234567890abcdef1234567890abcdef1234567890abcdef", cmd.TxID)
+			assert.Equal(t, "SBTC", cmd.TokenType)
+			assert.Equal(t, "ST1PQHQKV0RJXZFY1DGX8MNSNYVE3VGZJSRTPGZGM", cmd.ExpectedRecipient)
+			assert.Equal(t, "500000", cmd.MinAmount)
+			assert.Equal(t, "testnet", cmd.Network)
+			assert.Equal(t, "https://api.example.com/premium", cmd.Resource)
+			return command.VerifyPaymentResult{
+				Valid:         true,
+				SenderAddress: "ST2J6ZY48GV1EZ5V2V5RB9MP66SW86PYKKQYAC0RQ",
+			}, nil
+		},
+	}
+	handler := NewX402Handler(mockVerify, nil, nil)
+
+	body := x402Body("stacks-testnet", "ST1F7QA2MDF17S807EPA36TSS8AMEFY4KA9TVGWXT.sbtc-token",
+		`{"txId": "0x1234567890abcdef1234567890abcdef1234567890abcdef1234567890abcdef"}`)
+	rec := serveX402(t, handler, "/verify", body)
+
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.JSONEq(t, `{"isValid": true, "payer": "ST2J6ZY48GV1EZ5V2V5RB9MP66SW86PYKKQYAC0RQ"}`, rec.Body.String())
+}
+
+func TestX402Handler_Verify_AmountBeyondUint64(t *testing.T) {
+	mockVerify := &MockVerifyHandler{
+		HandleFn: func(ctx context.Context, cmd command.VerifyPaymentCommand) (command.VerifyPaymentResult, error) {
+			assert.Equal(t, "18446744073709551616", cmd.MinAmount)
+			return command.VerifyPaymentResult{Valid: true}, nil
+		},
+	}
+	handler := NewX402Handler(mockVerify, nil, nil)
+
+	body := strings.Replace(x402Body("stacks-testnet", "STX",
+		`{"txId": "0x1234567890abcdef1234567890abcdef1234567890abcdef1234567890abcdef"}`), `"500000"`, `"18446744073709551616"`, 1)
+	rec := serveX402(t, handler, "/verify", body)
+
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"isValid":true`)
+}
+
+func TestX402Handler_Verify_Invalid(t *testing.T) {
+	mockVerify := &MockVerifyHandler{
+		HandleFn: func(ctx context.Context, cmd command.VerifyPaymentCommand) (command.VerifyPaymentResult, error) {
+			return command.VerifyPaymentResult{
+				Valid:         false,
+				SenderAddress: "ST2J6ZY48GV1EZ5V2V5RB9MP66SW86PYKKQYAC0RQ",
+				Errors:        []string{"insufficient amount: expected at least 500000, got 100"},
+				Failures: []service.VerificationFailure{{
+					Code:     service.FailureInsufficientAmount,
+					Expected: "500000",
+					Actual:   "100",
+					Message:  "insufficient amount: expected at least 500000, got 100",
+				}},
+			}, nil
+		},
+	}
+	handler := NewX402Handler(mockVerify, nil, nil)
+
+	body := x402Body("stacks:2147483648", "STX", `{"txId": "0x1234567890abcdef1234567890abcdef1234567890abcdef1234567890abcdef"}`)
+	rec := serveX402(t, handler, "/verify", body)
+
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	var response X402VerifyResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+	assert.False(t, response.IsValid)
+	assert.Equal(t, reasonAmountInsufficient, response.InvalidReason)
+	assert.Equal(t, "insufficient amount: expected at least 500000, got 100", response.InvalidMessage)
+	assert.Equal(t, "ST2J6ZY48GV1EZ5V2V5RB9MP66SW86PYKKQYAC0RQ", response.Payer)
+}
+
+func TestX402Handler_Verify_SignedTransaction(t *testing.T) {
+	mockVerify := &MockVerifyHandler{
+		HandleFn: func(ctx context.Context, cmd command.VerifyPaymentCommand) (command.VerifyPaymentResult, error) {
+			assert.Equal(t, "0x80800000000400", cmd.SignedTransaction)
+			assert.Empty(t, cmd.TxID, "the signed transaction is verified rather than looked up")
+			return command.VerifyPaymentResult{
+				Valid:         false,
+				SenderAddress: "ST2J6ZY48GV1EZ5V2V5RB9MP66SW86PYKKQYAC0RQ",
+				Errors:        []string{"nonce mismatch: account's next nonce is 6, got 5"},
+				Failures: []service.VerificationFailure{{
+					Code:     service.FailureNonceMismatch,
+					Expected: "6",
+					Actual:   "5",
+					Message:  "nonce mismatch: account's next nonce is 6, got 5",
+				}},
+			}, nil
+		},
+	}
+	handler := NewX402Handler(mockVerify, nil, nil)
+
+	body := x402Body("stacks-testnet", "STX", `{"transaction": "0x80800000000400", "txId": "0x1234567890abcdef1234567890abcdef1234567890abcdef1234567890abcdef"}`)
+	rec := serveX402(t, handler, "/verify", body)
+
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.JSONEq(t, `{
+		"isValid": false,
+		"invalidReason": "invalid_exact_stacks_payload_nonce",
+		"invalidMessage": "nonce mismatch: account's next nonce is 6, got 5",
+		"payer": "ST2J6ZY48GV1EZ5V2V5RB9MP66SW86PYKKQYAC0RQ"
+	}`, rec.Body.String())
+}
+
+func TestX402Handler_Verify_InvalidSignedTransaction(t *testing.T) {
+	mockVerify := &MockVerifyHandler{
+		HandleFn: func(ctx context.Context, cmd command.VerifyPaymentCommand) (command.VerifyPaymentResult, error) {
+			return command.VerifyPaymentResult{}, fmt.Errorf("%w: malformed transaction", command.ErrInvalidSignedTransaction)
+		},
+	}
+	handler := NewX402Handler(mockVerify, nil, nil)
+
+	rec := serveX402(t, handler, "/verify", x402Body("stacks-testnet", "STX", `{"transaction": "0xdead"}`))
+
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.JSONEq(t, `{"isValid": false, "invalidReason": "invalid_exact_stacks_payload_transaction"}`, rec.Body.String())
+}
+
+func TestX402Handler_Verify_AlreadyUsed(t *testing.T) {
+	mockVerify := &MockVerifyHandler{
+		HandleFn: func(ctx context.Context, cmd command.VerifyPaymentCommand) (command.VerifyPaymentResult, error) {
+			return command.VerifyPaymentResult{}, fmt.Errorf("%w: transaction already accepted", command.ErrPaymentAlreadyUsed)
+		},
+	}
+	handler := NewX402Handler(mockVerify, nil, nil)
+
+	body := x402Body("stacks-testnet", "STX", `{"txId": "0x1234567890abcdef1234567890abcdef1234567890abcdef1234567890abcdef"}`)
+	rec := serveX402(t, handler, "/verify", body)
+
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.JSONEq(t, `{"isValid": false, "invalidReason": "already_used"}`, rec.Body.String())
+}
+
+func TestX402Handler_Verify_LookupFailures(t *testing.T) {
+	tests := []struct {
+		name       string
+		err        error
+		wantStatus int
+		wantReason string
+	}{
+		{"not found", fmt.Errorf("failed to fetch transaction: %w: 0x12", command.ErrTransactionNotFound), http.StatusOK, "invalid_exact_stacks_payload_transaction_not_found"},
+		{"api unavailable", fmt.Errorf("failed to fetch transaction: %w: API error: bad gateway", command.ErrTransactionUnavailable), http.StatusOK, "transaction_lookup_unavailable"},
+		{"internal fault", fmt.Errorf("failed to fetch transaction: failed to decode response"), http.StatusInternalServerError, "unexpected_verify_error"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockVerify := &MockVerifyHandler{
+				HandleFn: func(ctx context.Context, cmd command.VerifyPaymentCommand) (command.VerifyPaymentResult, error) {
+					return command.VerifyPaymentResult{}, tt.err
+				},
+			}
+			handler := NewX402Handler(mockVerify, nil, nil)
+
+			body := x402Body("stacks-testnet", "STX", `{"txId": "0x1234567890abcdef1234567890abcdef1234567890abcdef1234567890abcdef"}`)
+			rec := serveX402(t, handler, "/verify", body)
+
+			require.Equal(t, tt.wantStatus, rec.Code)
+			var resp X402VerifyResponse
+			require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+			assert.False(t, resp.IsValid)
+			assert.Equal(t, tt.wantReason, resp.InvalidReason)
+			if tt.wantStatus == http.StatusOK {
+				assert.Equal(t, tt.err.Error(), resp.InvalidMessage)
+			}
+		})
+	}
+}
+
+func TestX402Handler_Verify_RejectsRequest(t *testing.T) {
+	txPayload := `{"txId": "0x1234567890abcdef1234567890abcdef1234567890abcdef1234567890abcdef"}`
+
+	tests := []struct {
+		name   string
+		body   string
+		reason string
+	}{
+		{
+			name:   "wrong version",
+			body:   strings.Replace(x402Body("stacks-testnet", "STX", txPayload), `"x402Version": 1,`, `"x402Version": 2,`, 1),
+			reason: reasonInvalidX402Version,
+		},
+		{
+			name:   "wrong scheme",
+			body:   strings.Replace(x402Body("stacks-testnet", "STX", txPayload), `"scheme": "exact"`, `"scheme": "upto"`, 1),
+			reason: reasonUnsupportedScheme,
+		},
+		{
+			name:   "unknown network",
+			body:   x402Body("base-sepolia", "STX", txPayload),
+			reason: reasonInvalidNetwork,
+		},
+		{
+			name:   "payload network differs",
+			body:   strings.Replace(x402Body("stacks-testnet", "STX", txPayload), `"network": "stacks-testnet"`, `"network": "stacks"`, 1),
+			reason: reasonInvalidNetwork,
+		},
+		{
+			name:   "non-numeric amount",
+			body:   strings.Replace(x402Body("stacks-testnet", "STX", txPayload), `"500000"`, `"0.5"`, 1),
+			reason: reasonInvalidPaymentRequirements,
+		},
+		{
+			name:   "amount beyond uint128",
+			body:   strings.Replace(x402Body("stacks-testnet", "STX", txPayload), `"500000"`, `"340282366920938463463374607431768211456"`, 1),
+			reason: reasonInvalidPaymentRequirements,
+		},
+		{
+			name:   "payTo bad checksum",
+			body:   strings.Replace(x402Body("stacks-testnet", "STX", txPayload), "ST1PQHQKV0RJXZFY1DGX8MNSNYVE3VGZJSRTPGZGM", "ST1PQHQKV0RJXZFY1DGX8MNSNYVE3VGZJSRTPGZGN", 1),
+			reason: reasonInvalidPaymentRequirements,
+		},
+		{
+			name:   "testnet payTo on mainnet",
+			body:   x402Body("stacks", "STX", txPayload),
+			reason: reasonInvalidPaymentRequirements,
+		},
+		{
+			name:   "unknown asset",
+			body:   x402Body("stacks-testnet", "ST1PQHQKV0RJXZFY1DGX8MNSNYVE3VGZJSRTPGZGM.fake-token", txPayload),
+			reason: reasonUnsupportedAsset,
+		},
+		{
+			name:   "mainnet contract on testnet",
+			body:   x402Body("stacks-testnet", "SM3VDXK3WZZSA84XXFKAFAF15NNZX32CTSG82JFQ4.sbtc-token", txPayload),
+			reason: reasonUnsupportedAsset,
+		},
+		{
+			name:   "missing transaction and txId",
+			body:   x402Body("stacks-testnet", "STX", `{}`),
+			reason: reasonInvalidPayload,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockVerify := &MockVerifyHandler{
+				HandleFn: func(ctx context.Context, cmd command.VerifyPaymentCommand) (command.VerifyPaymentResult, error) {
+					t.Fatal("verify handler should not be called")
+					return command.VerifyPaymentResult{}, nil
+				},
+			}
+			handler := NewX402Handler(mockVerify, nil, nil)
+
+			rec := serveX402(t, handler, "/verify", tt.body)
+
+			require.Equal(t, http.StatusOK, rec.Code)
+			var response X402VerifyResponse
+			require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+			assert.False(t, response.IsValid)
+			assert.Equal(t, tt.reason, response.InvalidReason)
+		})
+	}
+}
+
+func TestX402Handler_Verify_MalformedBody(t *testing.T) {
+	handler := NewX402Handler(nil, nil, nil)
+
+	rec := serveX402(t, handler, "/verify", `{"invalid json`)
+
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+}
+
+func TestX402Handler_Settle_Success(t *testing.T) {
+	mockSettle := &MockSettleHandler{
+		HandleFn: func(ctx context.Context, cmd command.SettlePaymentCommand) (command.SettlePaymentResult, error) {
+			assert.Equal(t, "0x808000000004", cmd.SignedTransaction)
+			assert.Equal(t, valueobject.TokenUSDCX.String(), cmd.TokenType)
+			assert.Equal(t, "mainnet", cmd.Network)
+			return command.SettlePaymentResult{
+				Success:       true,
+				TxID:          "0xabcdef1234567890abcdef1234567890abcdef1234567890abcdef1234567890",
+				SenderAddress: "SP2J6ZY48GV1EZ5V2V5RB9MP66SW86PYKKNRV9EJ7",
+				Status:        "confirmed",
+			}, nil
+		},
+	}
+	handler := NewX402Handler(nil, mockSettle, nil)
+
+	body := strings.Replace(x402Body("stacks", "SP120SBRBQJ00MCWS7TM5R8WJNTTKD5K0HFRC2CNE.usdcx::usdcx-token", `{"transaction": "0x808000000004"}`),
+		"ST1PQHQKV0RJXZFY1DGX8MNSNYVE3VGZJSRTPGZGM", "SP1PQHQKV0RJXZFY1DGX8MNSNYVE3VGZJSRCBGD7R", 1)
+	rec := serveX402(t, handler, "/settle", body)
+
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.JSONEq(t, `{
+		"success": true,
+		"transaction": "0xabcdef1234567890abcdef1234567890abcdef1234567890abcdef1234567890",
+		"network": "stacks",
+		"payer": "SP2J6ZY48GV1EZ5V2V5RB9MP66SW86PYKKNRV9EJ7"
+	}`, rec.Body.String())
+}
+
+func TestX402Handler_Settle_Rejected(t *testing.T) {
+	mockSettle := &MockSettleHandler{
+		HandleFn: func(ctx context.Context, cmd command.SettlePaymentCommand) (command.SettlePaymentResult, error) {
+			return command.SettlePaymentResult{
+				Success:       false,
+				SenderAddress: "ST2J6ZY48GV1EZ5V2V5RB9MP66SW86PYKKQYAC0RQ",
+				Status:        "failed",
+				Errors:        []string{"recipient mismatch: expected ST1..., got ST2..."},
+				Failures: []service.VerificationFailure{{
+					Code:    service.FailureRecipientMismatch,
+					Message: "recipient mismatch: expected ST1..., got ST2...",
+				}},
+			}, nil
+		},
+	}
+	handler := NewX402Handler(nil, mockSettle, nil)
+
+	rec := serveX402(t, handler, "/settle", x402Body("stacks-testnet", "STX", `{"transaction": "0x808000000004"}`))
+
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	var response X402SettleResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+	assert.False(t, response.Success)
+	assert.Equal(t, reasonRecipientMismatch, response.ErrorReason)
+	assert.Equal(t, "recipient mismatch: expected ST1..., got ST2...", response.ErrorMessage)
+	assert.Equal(t, "stacks-testnet", response.Network)
+	assert.Empty(t, response.Transaction)
+}
+
+func TestX402Handler_Settle_SponsorshipRejected(t *testing.T) {
+	mockSettle := &MockSettleHandler{
+		HandleFn: func(ctx context.Context, cmd command.SettlePaymentCommand) (command.SettlePaymentResult, error) {
+			return command.SettlePaymentResult{
+				Success: false,
+				Status:  "failed",
+				Errors:  []string{"sponsor policy: token STX is not sponsored"},
+				Failures: []service.VerificationFailure{{
+					Code:    service.FailureSponsorPolicy,
+					Actual:  "STX",
+					Message: "sponsor policy: token STX is not sponsored",
+				}},
+			}, nil
+		},
+	}
+	handler := NewX402Handler(nil, mockSettle, nil)
+
+	rec := serveX402(t, handler, "/settle", x402Body("stacks-testnet", "STX", `{"transaction": "0x808000000005"}`))
+
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	var response X402SettleResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+	assert.False(t, response.Success)
+	assert.Equal(t, reasonSponsorshipRejected, response.ErrorReason)
+}
+
+func TestX402Handler_Settle_InvalidTransaction(t *testing.T) {
+	mockSettle := &MockSettleHandler{
+		HandleFn: func(ctx context.Context, cmd command.SettlePaymentCommand) (command.SettlePaymentResult, error) {
+			return command.SettlePaymentResult{}, fmt.Errorf("%w: malformed transaction", command.ErrInvalidSignedTransaction)
+		},
+	}
+	handler := NewX402Handler(nil, mockSettle, nil)
+
+	rec := serveX402(t, handler, "/settle", x402Body("stacks-testnet", "STX", `{"transaction": "0xdead"}`))
+
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	var response X402SettleResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+	assert.False(t, response.Success)
+	assert.Equal(t, reasonInvalidTransaction, response.ErrorReason)
+}
+
+func TestX402Handler_Settle_AlreadyUsed(t *testing.T) {
+	mockSettle := &MockSettleHandler{
+		HandleFn: func(ctx context.Context, cmd command.SettlePaymentCommand) (command.SettlePaymentResult, error) {
+			assert.Equal(t, "https://api.example.com/premium", cmd.Resource)
+			return command.SettlePaymentResult{}, fmt.Errorf("%w: transaction 0x12 was already accepted for /other", command.ErrPaymentAlreadyUsed)
+		},
+	}
+	handler := NewX402Handler(nil, mockSettle, nil)
+
+	rec := serveX402(t, handler, "/settle", x402Body("stacks-testnet", "STX", `{"transaction": "0x808000000004"}`))
+
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.JSONEq(t, `{
+		"success": false,
+		"errorReason": "already_used",
+		"errorMessage": "already_used: transaction 0x12 was already accepted for /other",
+		"transaction": "",
+		"network": "stacks-testnet"
+	}`, rec.Body.String())
+}
+
+func TestX402Handler_Settle_SponsorFailed(t *testing.T) {
+	mockSettle := &MockSettleHandler{
+		HandleFn: func(ctx context.Context, cmd command.SettlePaymentCommand) (command.SettlePaymentResult, error) {
+			rejected := &command.BroadcastRejectedError{Reason: command.RejectionNotEnoughFunds, Message: "needs 180 microSTX, balance is 10"}
+			return command.SettlePaymentResult{}, fmt.Errorf("%w: %w", command.ErrSponsorFailed, rejected)
+		},
+	}
+	handler := NewX402Handler(nil, mockSettle, nil)
+
+	rec := serveX402(t, handler, "/settle", x402Body("stacks-testnet", "STX", `{"transaction": "0x808000000004"}`))
+
+	require.Equal(t, http.StatusServiceUnavailable, rec.Code)
+	assert.JSONEq(t, `{
+		"success": false,
+		"errorReason": "sponsor_unavailable",
+		"errorMessage": "failed to sponsor transaction: transaction rejected: NotEnoughFunds: needs 180 microSTX, balance is 10",
+		"transaction": "",
+		"network": "stacks-testnet"
+	}`, rec.Body.String())
+}
+
+func TestX402Handler_Settle_BroadcastRejected(t *testing.T) {
+	tests := []struct {
+		reason     command.BroadcastRejectionReason
+		wantReason string
+	}{
+		{command.RejectionBadNonce, "invalid_exact_stacks_payload_nonce"},
+		{command.RejectionConflictingNonceInMempool, "invalid_exact_stacks_payload_nonce_conflict"},
+		{command.RejectionNotEnoughFunds, "insufficient_funds"},
+		{command.RejectionFeeTooLow, "invalid_exact_stacks_payload_fee_too_low"},
+		{command.RejectionBadTransactionVersion, "invalid_exact_stacks_payload_transaction_version"},
+		{command.RejectionContractAlreadyExists, "invalid_exact_stacks_payload_contract_exists"},
+		{command.RejectionPostConditionFailed, "invalid_exact_stacks_payload_post_condition"},
+		{"Deserialization", "invalid_exact_stacks_payload_transaction"},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.reason), func(t *testing.T) {
+			mockSettle := &MockSettleHandler{
+				HandleFn: func(ctx context.Context, cmd command.SettlePaymentCommand) (command.SettlePaymentResult, error) {
+					return command.SettlePaymentResult{}, fmt.Errorf("failed to broadcast transaction: %w", &command.BroadcastRejectedError{Reason: tt.reason, Message: "refused"})
+				},
+			}
+			handler := NewX402Handler(nil, mockSettle, nil)
+
+			rec := serveX402(t, handler, "/settle", x402Body("stacks-testnet", "STX", `{"transaction": "0xdead"}`))
+
+			require.Equal(t, http.StatusOK, rec.Code)
+
+			var response X402SettleResponse
+			require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+			assert.False(t, response.Success)
+			assert.Equal(t, tt.wantReason, response.ErrorReason)
+			assert.Equal(t, "transaction rejected: "+string(tt.reason)+": refused", response.ErrorMessage)
+			assert.Equal(t, "stacks-testnet", response.Network)
+		})
+	}
+}
+
+func TestX402Handler_Settle_MissingTransaction(t *testing.T) {
+	handler := NewX402Handler(nil, nil, nil)
+
+	rec := serveX402(t, handler, "/settle", x402Body("stacks-testnet", "STX", `{"txId": "0x1234"}`))
+
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	var response X402SettleResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+	assert.False(t, response.Success)
+	assert.Equal(t, reasonInvalidPayload, response.ErrorReason)
+}
+
+func TestParseX402Network(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected valueobject.Network
+		wantErr  bool
+	}{
+		{"stacks", valueobject.NetworkMainnet, false},
+		{"stacks:1", valueobject.NetworkMainnet, false},
+		{"stacks-testnet", valueobject.NetworkTestnet, false},
+		{"stacks:2147483648", valueobject.NetworkTestnet, false},
+		{"mainnet", "", true},
+		{"base", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			network, err := parseX402Network(tt.input)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, network)
+		})
+	}
+}
+
+func getSupported(t *testing.T, handler *X402Handler) X402SupportedResponse {
+	t.Helper()
+
+	e := echo.New()
+	handler.RegisterRoutes(e)
+
+	req := httptest.NewRequest(http.MethodGet, "/supported", nil)
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	var response X402SupportedResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+	return response
+}
+
+func TestX402Handler_Supported_Default(t *testing.T) {
+	response := getSupported(t, NewX402Handler(nil, nil, nil))
+
+	require.Len(t, response.Kinds, 2)
+
+	mainnet := response.Kinds[0]
+	assert.Equal(t, 1, mainnet.X402Version)
+	assert.Equal(t, "exact", mainnet.Scheme)
+	assert.Equal(t, "stacks", mainnet.Network)
+	assert.Equal(t, "stacks:1", mainnet.Extra.CAIP2)
+	assert.Equal(t, []X402SupportedAsset{
+		{TokenType: "STX", Asset: "STX", Symbol: "STX", Decimals: 6},
+		{
+			TokenType:       "SBTC",
+			Asset:           "SM3VDXK3WZZSA84XXFKAFAF15NNZX32CTSG82JFQ4.sbtc-token",
+			AssetIdentifier: "SM3VDXK3WZZSA84XXFKAFAF15NNZX32CTSG82JFQ4.sbtc-token::sbtc-token",
+			Symbol:          "sBTC",
+			Decimals:        8,
+		},
+		{
+			TokenType:       "USDCX",
+			Asset:           "SP120SBRBQJ00MCWS7TM5R8WJNTTKD5K0HFRC2CNE.usdcx",
+			AssetIdentifier: "SP120SBRBQJ00MCWS7TM5R8WJNTTKD5K0HFRC2CNE.usdcx::usdcx-token",
+			Symbol:          "USDCx",
+			Decimals:        6,
+		},
+	}, mainnet.Extra.Assets)
+
+	testnet := response.Kinds[1]
+	assert.Equal(t, "stacks-testnet", testnet.Network)
+	assert.Equal(t, "stacks:2147483648", testnet.Extra.CAIP2)
+	assert.Len(t, testnet.Extra.Assets, 3)
+}
+
+func TestX402Handler_Supported_FollowsRegistry(t *testing.T) {
+	registry := service.NewTokenRegistry()
+	registry.Register(valueobject.NetworkTestnet, valueobject.TokenSBTC, service.TokenContract{
+		ContractID: "ST1F7QA2MDF17S807EPA36TSS8AMEFY4KA9TVGWXT.sbtc-token",
+		AssetName:  "sbtc-token",
+	})
+
+	response := getSupported(t, NewX402Handler(nil, nil, registry))
+
+	require.Len(t, response.Kinds, 2)
+	assert.Equal(t, []X402SupportedAsset{{TokenType: "STX", Asset: "STX", Symbol: "STX", Decimals: 6}}, response.Kinds[0].Extra.Assets)
+	require.Len(t, response.Kinds[1].Extra.Assets, 2)
+	assert.Equal(t, "SBTC", response.Kinds[1].Extra.Assets[1].TokenType)
+}
+
+func TestX402Handler_Supported_KindsAreAccepted(t *testing.T) {
+	handler := NewX402Handler(nil, nil, nil)
+
+	for _, kind := range getSupported(t, handler).Kinds {
+		network, err := parseX402Network(kind.Network)
+		require.NoError(t, err)
+
+		caip2Network, err := parseX402Network(kind.Extra.CAIP2)
+		require.NoError(t, err)
+		assert.Equal(t, network, caip2Network)
+
+		for _, asset := range kind.Extra.Assets {
+			for _, id := range []string{asset.TokenType, asset.Asset, asset.AssetIdentifier} {
+				if id == "" {
+					continue
+				}
+				tokenType, err := handler.resolveAsset(id, network)
+				require.NoError(t, err, "%s on %s", id, kind.Network)
+				assert.Equal(t, asset.TokenType, tokenType.String())
+			}
+		}
+	}
+}
+
+func TestReasonForFailures(t *testing.T) {
+	tests := []struct {
+		code   service.FailureCode
+		reason string
+	}{
+		{service.FailureNetworkMismatch, reasonInvalidNetwork},
+		{service.FailureTxFailed, reasonInvalidTransactionState},
+		{service.FailureTxDropped, reasonInvalidTransactionState},
+		{service.FailureInsufficientConfirmations, reasonInvalidTransactionState},
+		{service.FailureTokenContractMismatch, reasonAssetMismatch},
+		{service.FailureRecipientMismatch, reasonRecipientMismatch},
+		{service.FailureInvalidSignature, reasonInvalidSignature},
+		{service.FailureNonceMismatch, reasonNonceMismatch},
+		{service.FailureInsufficientBalance, reasonInsufficientFunds},
+		{"something_else", reasonInvalidExactStacksPayload},
+	}
+
+	for _, tt := range tests {
+		reason, message := reasonForFailures([]service.VerificationFailure{
+			{Code: tt.code, Message: "first"},
+			{Code: service.FailureMemoMismatch, Message: "second"},
+		})
+		assert.Equal(t, tt.reason, reason, tt.code)
+		assert.Equal(t, "first", message)
+	}
+
+	reason, message := reasonForFailures(nil)
+	assert.Equal(t, reasonInvalidExactStacksPayload, reason)
+	assert.Empty(t, message)
+}
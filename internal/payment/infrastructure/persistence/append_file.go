@@ -0,0 +1,85 @@
+package persistence
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+)
+
+// appendFile is the part of an *os.File the JSON lines stores use
+type appendFile interface {
+	io.ReadWriteCloser
+	Sync() error
+	Truncate(size int64) error
+	Stat() (os.FileInfo, error)
+}
+
+// replayLines opens (or creates) the JSON lines file at path for appending and passes
+// each complete line to apply, in order. A torn final line left by an interrupted write
+// is truncated away so later appends start on a clean line.
+func replayLines(path string, apply func(line []byte) error) (*os.File, error) {
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_RDWR|os.O_APPEND, 0o600)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open: %w", err)
+	}
+
+	if err := replay(file, apply); err != nil {
+		file.Close()
+		return nil, err
+	}
+	return file, nil
+}
+
+// replay reads an opened file for replayLines
+func replay(file *os.File, apply func(line []byte) error) error {
+	data, err := io.ReadAll(file)
+	if err != nil {
+		return fmt.Errorf("failed to read: %w", err)
+	}
+
+	complete := bytes.LastIndexByte(data, '\n') + 1
+	for i, line := range bytes.Split(data[:complete], []byte("\n")) {
+		if len(line) == 0 {
+			continue
+		}
+		if err := apply(line); err != nil {
+			return fmt.Errorf("corrupt at line %d: %w", i+1, err)
+		}
+	}
+
+	if complete < len(data) {
+		if err := file.Truncate(int64(complete)); err != nil {
+			return fmt.Errorf("failed to repair torn last line: %w", err)
+		}
+	}
+	return nil
+}
+
+// appendLine durably appends data as one line. A write or sync that fails partway is
+// rolled back by truncating the file to its size before the write, so a torn line is
+// never followed by later records.
+func appendLine(file appendFile, data []byte) error {
+	info, err := file.Stat()
+	if err != nil {
+		return fmt.Errorf("failed to stat file: %w", err)
+	}
+
+	if _, err := file.Write(append(data, '\n')); err != nil {
+		return rollback(file, info.Size(), fmt.Errorf("failed to write: %w", err))
+	}
+	if err := file.Sync(); err != nil {
+		return rollback(file, info.Size(), fmt.Errorf("failed to sync: %w", err))
+	}
+
+	return nil
+}
+
+// rollback truncates the file back to size after a failed append
+func rollback(file appendFile, size int64, err error) error {
+	if truncErr := file.Truncate(size); truncErr != nil {
+		return errors.Join(err, fmt.Errorf("failed to roll back: %w", truncErr))
+	}
+	return err
+}
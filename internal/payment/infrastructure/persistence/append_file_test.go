@@ -0,0 +1,77 @@
+package persistence
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// tornWriteFile writes only the first half of the next write and then fails, as a
+// full disk or an I/O error can
+type tornWriteFile struct {
+	appendFile
+	failNext bool
+}
+
+func (f *tornWriteFile) Write(p []byte) (int, error) {
+	if !f.failNext {
+		return f.appendFile.Write(p)
+	}
+	f.failNext = false
+	n, _ := f.appendFile.Write(p[:len(p)/2])
+	return n, errors.New("no space left on device")
+}
+
+func TestAppendLine_RollsBackTornWrite(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "records.jsonl")
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_RDWR|os.O_APPEND, 0o600)
+	require.NoError(t, err)
+	defer file.Close()
+	torn := &tornWriteFile{appendFile: file}
+
+	require.NoError(t, appendLine(torn, []byte(`{"n":1}`)))
+	torn.failNext = true
+	assert.ErrorContains(t, appendLine(torn, []byte(`{"n":2}`)), "no space left on device")
+	require.NoError(t, appendLine(torn, []byte(`{"n":3}`)))
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, "{\"n\":1}\n{\"n\":3}\n", string(data))
+}
+
+func TestReplayLines_TruncatesTornLastLine(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "records.jsonl")
+	require.NoError(t, os.WriteFile(path, []byte("{\"n\":1}\n\n{\"n\":2}\n{\"n\":"), 0o600))
+
+	var lines []string
+	file, err := replayLines(path, func(line []byte) error {
+		lines = append(lines, string(line))
+		return nil
+	})
+	require.NoError(t, err)
+	require.NoError(t, appendLine(file, []byte(`{"n":3}`)))
+	require.NoError(t, file.Close())
+
+	assert.Equal(t, []string{`{"n":1}`, `{"n":2}`}, lines)
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, "{\"n\":1}\n\n{\"n\":2}\n{\"n\":3}\n", string(data))
+}
+
+func TestReplayLines_CorruptLine(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "records.jsonl")
+	require.NoError(t, os.WriteFile(path, []byte("{\"n\":1}\nnot json\n"), 0o600))
+
+	_, err := replayLines(path, func(line []byte) error {
+		if line[0] != '{' {
+			return errors.New("bad record")
+		}
+		return nil
+	})
+
+	assert.ErrorContains(t, err, "corrupt at line 2: bad record")
+}
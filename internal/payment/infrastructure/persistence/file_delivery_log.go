@@ -0,0 +1,90 @@
+package persistence
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/x402stacks/stacks-facilitator/internal/payment/infrastructure/webhook"
+)
+
+// FileDeliveryLog is a durable webhook DeliveryLog backed by an append-only JSON lines file.
+// Every attempt appends the delivery's new state, fsynced before Save returns; on open the
+// file is replayed and the last line for each delivery wins.
+type FileDeliveryLog struct {
+	mu         sync.Mutex
+	file       appendFile
+	deliveries map[string]webhook.Delivery
+}
+
+// OpenFileDeliveryLog opens (or creates) the log at path and loads existing records
+func OpenFileDeliveryLog(path string) (*FileDeliveryLog, error) {
+	deliveries := make(map[string]webhook.Delivery)
+	file, err := replayLines(path, func(line []byte) error {
+		var delivery webhook.Delivery
+		if err := json.Unmarshal(line, &delivery); err != nil {
+			return err
+		}
+		deliveries[delivery.ID] = delivery
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("webhook delivery log: %w", err)
+	}
+
+	return &FileDeliveryLog{file: file, deliveries: deliveries}, nil
+}
+
+// Save durably appends the delivery's current state
+func (l *FileDeliveryLog) Save(ctx context.Context, delivery webhook.Delivery) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.file == nil {
+		return errors.New("webhook delivery log is closed")
+	}
+
+	data, err := json.Marshal(delivery)
+	if err != nil {
+		return fmt.Errorf("failed to encode webhook delivery: %w", err)
+	}
+
+	if err := appendLine(l.file, data); err != nil {
+		return fmt.Errorf("failed to record webhook delivery: %w", err)
+	}
+
+	l.deliveries[delivery.ID] = delivery
+	return nil
+}
+
+// Pending returns the deliveries waiting for an attempt, oldest first
+func (l *FileDeliveryLog) Pending(ctx context.Context) ([]webhook.Delivery, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return pendingDeliveries(l.deliveries), nil
+}
+
+// Get returns the latest state of a delivery
+func (l *FileDeliveryLog) Get(ctx context.Context, id string) (webhook.Delivery, bool, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	delivery, ok := l.deliveries[id]
+	return delivery, ok, nil
+}
+
+// Close closes the underlying file
+func (l *FileDeliveryLog) Close() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.file == nil {
+		return nil
+	}
+	err := l.file.Close()
+	l.file = nil
+	return err
+}
@@ -0,0 +1,91 @@
+package persistence
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/x402stacks/stacks-facilitator/internal/payment/infrastructure/webhook"
+)
+
+func TestFileDeliveryLog_LastStateWinsAcrossReopen(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "webhooks.jsonl")
+	ctx := context.Background()
+
+	deliveryLog, err := OpenFileDeliveryLog(path)
+	require.NoError(t, err)
+
+	first := testDelivery("a", 1)
+	require.NoError(t, deliveryLog.Save(ctx, first))
+	require.NoError(t, deliveryLog.Save(ctx, testDelivery("b", 2)))
+	first.Attempts = 1
+	first.LastStatusCode = 200
+	first.State = webhook.DeliveryDelivered
+	require.NoError(t, deliveryLog.Save(ctx, first))
+	require.NoError(t, deliveryLog.Close())
+
+	reopened, err := OpenFileDeliveryLog(path)
+	require.NoError(t, err)
+	defer reopened.Close()
+
+	pending, err := reopened.Pending(ctx)
+	require.NoError(t, err)
+	require.Len(t, pending, 1)
+	assert.Equal(t, "b", pending[0].ID)
+	assert.JSONEq(t, `{"id":"evt-b"}`, string(pending[0].Payload))
+
+	got, ok, err := reopened.Get(ctx, "a")
+	require.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, webhook.DeliveryDelivered, got.State)
+	assert.Equal(t, 1, got.Attempts)
+}
+
+func TestFileDeliveryLog_RecoversFromTornWrite(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "webhooks.jsonl")
+	ctx := context.Background()
+
+	deliveryLog, err := OpenFileDeliveryLog(path)
+	require.NoError(t, err)
+	require.NoError(t, deliveryLog.Save(ctx, testDelivery("a", 1)))
+	require.NoError(t, deliveryLog.Close())
+
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0o600)
+	require.NoError(t, err)
+	_, err = f.WriteString(`{"id":"a","state":"deliv`)
+	require.NoError(t, err)
+	require.NoError(t, f.Close())
+
+	reopened, err := OpenFileDeliveryLog(path)
+	require.NoError(t, err)
+	require.NoError(t, reopened.Save(ctx, testDelivery("b", 2)))
+	require.NoError(t, reopened.Close())
+
+	final, err := OpenFileDeliveryLog(path)
+	require.NoError(t, err)
+	defer final.Close()
+
+	pending, err := final.Pending(ctx)
+	require.NoError(t, err)
+	assert.Len(t, pending, 2)
+}
+
+func TestFileDeliveryLog_RejectsCorruptFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "webhooks.jsonl")
+	require.NoError(t, os.WriteFile(path, []byte("not json\n"), 0o600))
+
+	_, err := OpenFileDeliveryLog(path)
+
+	assert.Error(t, err)
+}
+
+func TestFileDeliveryLog_SaveAfterClose(t *testing.T) {
+	deliveryLog, err := OpenFileDeliveryLog(filepath.Join(t.TempDir(), "webhooks.jsonl"))
+	require.NoError(t, err)
+	require.NoError(t, deliveryLog.Close())
+
+	assert.Error(t, deliveryLog.Save(context.Background(), testDelivery("a", 1)))
+}
@@ -0,0 +1,125 @@
+package persistence
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/x402stacks/stacks-facilitator/internal/payment/application/command"
+)
+
+// FilePaymentStore is a durable PaymentStore backed by an append-only JSON lines file.
+// Every record is fsynced before MarkConsumed, Update or Release returns, a failed write
+// is truncated away, and the file is replayed on open with the last line for each
+// transaction winning.
+type FilePaymentStore struct {
+	mu       sync.Mutex
+	file     appendFile
+	payments map[string]command.ConsumedPayment
+}
+
+// paymentRecord is one line of the file; a released transaction is written with Released set
+type paymentRecord struct {
+	command.ConsumedPayment
+	Released bool `json:"released,omitempty"`
+}
+
+// OpenFilePaymentStore opens (or creates) the store at path and loads existing records
+func OpenFilePaymentStore(path string) (*FilePaymentStore, error) {
+	payments := make(map[string]command.ConsumedPayment)
+	file, err := replayLines(path, func(line []byte) error {
+		var record paymentRecord
+		if err := json.Unmarshal(line, &record); err != nil {
+			return err
+		}
+		if record.Released {
+			delete(payments, record.TxID)
+			return nil
+		}
+		payments[record.TxID] = record.ConsumedPayment
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("payment store: %w", err)
+	}
+
+	return &FilePaymentStore{file: file, payments: payments}, nil
+}
+
+// MarkConsumed durably records the payment unless the transaction was already consumed
+func (s *FilePaymentStore) MarkConsumed(ctx context.Context, payment command.ConsumedPayment) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if existing, ok := s.payments[payment.TxID]; ok {
+		return existing.AlreadyUsedError()
+	}
+
+	return s.write(paymentRecord{ConsumedPayment: payment})
+}
+
+// Update durably replaces the recorded payment for a transaction
+func (s *FilePaymentStore) Update(ctx context.Context, payment command.ConsumedPayment) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.write(paymentRecord{ConsumedPayment: payment})
+}
+
+// Release durably forgets a transaction
+func (s *FilePaymentStore) Release(ctx context.Context, txID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.payments[txID]; !ok {
+		return nil
+	}
+	return s.write(paymentRecord{ConsumedPayment: command.ConsumedPayment{TxID: txID}, Released: true})
+}
+
+// write appends a record and applies it in memory once it is durable; callers hold mu
+func (s *FilePaymentStore) write(record paymentRecord) error {
+	if s.file == nil {
+		return errors.New("payment store is closed")
+	}
+
+	data, err := json.Marshal(record)
+	if err != nil {
+		return fmt.Errorf("failed to encode payment: %w", err)
+	}
+
+	if err := appendLine(s.file, data); err != nil {
+		return fmt.Errorf("failed to record payment: %w", err)
+	}
+
+	if record.Released {
+		delete(s.payments, record.TxID)
+	} else {
+		s.payments[record.TxID] = record.ConsumedPayment
+	}
+	return nil
+}
+
+// Get returns the recorded payment for a transaction
+func (s *FilePaymentStore) Get(ctx context.Context, txID string) (command.ConsumedPayment, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	payment, ok := s.payments[txID]
+	return payment, ok, nil
+}
+
+// Close closes the underlying file
+func (s *FilePaymentStore) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.file == nil {
+		return nil
+	}
+	err := s.file.Close()
+	s.file = nil
+	return err
+}
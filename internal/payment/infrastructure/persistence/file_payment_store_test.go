@@ -0,0 +1,129 @@
+package persistence
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/x402stacks/stacks-facilitator/internal/payment/application/command"
+)
+
+func TestFilePaymentStore_PersistsAcrossReopen(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "payments.jsonl")
+	ctx := context.Background()
+
+	store, err := OpenFilePaymentStore(path)
+	require.NoError(t, err)
+	require.NoError(t, store.MarkConsumed(ctx, testPayment()))
+	require.NoError(t, store.Close())
+
+	reopened, err := OpenFilePaymentStore(path)
+	require.NoError(t, err)
+	defer reopened.Close()
+
+	got, ok, err := reopened.Get(ctx, testPayment().TxID)
+	require.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, "n-1", got.Nonce)
+	assert.ErrorIs(t, reopened.MarkConsumed(ctx, testPayment()), command.ErrPaymentAlreadyUsed)
+}
+
+func TestFilePaymentStore_UpdateAndReleaseAcrossReopen(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "payments.jsonl")
+	ctx := context.Background()
+
+	settled := testPayment()
+	released := testPayment()
+	released.TxID = "0xabcdef1234567890abcdef1234567890abcdef1234567890abcdef1234567890"
+
+	store, err := OpenFilePaymentStore(path)
+	require.NoError(t, err)
+	require.NoError(t, store.MarkConsumed(ctx, settled))
+	require.NoError(t, store.MarkConsumed(ctx, released))
+	settled.BroadcastTxID = "0xbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbb"
+	require.NoError(t, store.Update(ctx, settled))
+	require.NoError(t, store.Release(ctx, released.TxID))
+	require.NoError(t, store.Close())
+
+	reopened, err := OpenFilePaymentStore(path)
+	require.NoError(t, err)
+	defer reopened.Close()
+
+	got, ok, _ := reopened.Get(ctx, settled.TxID)
+	assert.True(t, ok)
+	assert.Equal(t, settled.BroadcastTxID, got.BroadcastTxID)
+	_, ok, _ = reopened.Get(ctx, released.TxID)
+	assert.False(t, ok)
+	assert.NoError(t, reopened.MarkConsumed(ctx, released), "a released transaction can be claimed again")
+}
+
+func TestFilePaymentStore_RecoversFromTornWrite(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "payments.jsonl")
+	ctx := context.Background()
+
+	store, err := OpenFilePaymentStore(path)
+	require.NoError(t, err)
+	require.NoError(t, store.MarkConsumed(ctx, testPayment()))
+	require.NoError(t, store.Close())
+
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0o600)
+	require.NoError(t, err)
+	_, err = f.WriteString(`{"tx_id":"0xdead`)
+	require.NoError(t, err)
+	require.NoError(t, f.Close())
+
+	reopened, err := OpenFilePaymentStore(path)
+	require.NoError(t, err)
+
+	second := testPayment()
+	second.TxID = "0xabcdef1234567890abcdef1234567890abcdef1234567890abcdef1234567890"
+	require.NoError(t, reopened.MarkConsumed(ctx, second))
+	require.NoError(t, reopened.Close())
+
+	final, err := OpenFilePaymentStore(path)
+	require.NoError(t, err)
+	defer final.Close()
+
+	_, ok, _ := final.Get(ctx, second.TxID)
+	assert.True(t, ok)
+}
+
+func TestFilePaymentStore_FailedWriteLeavesNoTornLine(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "payments.jsonl")
+	ctx := context.Background()
+
+	store, err := OpenFilePaymentStore(path)
+	require.NoError(t, err)
+	torn := &tornWriteFile{appendFile: store.file, failNext: true}
+	store.file = torn
+
+	assert.Error(t, store.MarkConsumed(ctx, testPayment()))
+	_, ok, _ := store.Get(ctx, testPayment().TxID)
+	assert.False(t, ok, "a payment that was not written is not recorded")
+
+	second := testPayment()
+	second.TxID = "0xabcdef1234567890abcdef1234567890abcdef1234567890abcdef1234567890"
+	require.NoError(t, store.MarkConsumed(ctx, second))
+	require.NoError(t, store.Close())
+
+	reopened, err := OpenFilePaymentStore(path)
+	require.NoError(t, err)
+	defer reopened.Close()
+
+	_, ok, _ = reopened.Get(ctx, second.TxID)
+	assert.True(t, ok)
+	_, ok, _ = reopened.Get(ctx, testPayment().TxID)
+	assert.False(t, ok)
+}
+
+func TestFilePaymentStore_RejectsCorruptFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "payments.jsonl")
+	require.NoError(t, os.WriteFile(path, []byte("not json\n{}\n"), 0o600))
+
+	_, err := OpenFilePaymentStore(path)
+
+	assert.Error(t, err)
+}
@@ -0,0 +1,63 @@
+package persistence
+
+import (
+	"context"
+	"sort"
+	"sync"
+
+	"github.com/x402stacks/stacks-facilitator/internal/payment/infrastructure/webhook"
+)
+
+// MemoryDeliveryLog is an in-memory webhook DeliveryLog; all deliveries are lost on restart
+type MemoryDeliveryLog struct {
+	mu         sync.Mutex
+	deliveries map[string]webhook.Delivery
+}
+
+// NewMemoryDeliveryLog creates an empty MemoryDeliveryLog
+func NewMemoryDeliveryLog() *MemoryDeliveryLog {
+	return &MemoryDeliveryLog{deliveries: make(map[string]webhook.Delivery)}
+}
+
+// Save creates or replaces a delivery
+func (l *MemoryDeliveryLog) Save(ctx context.Context, delivery webhook.Delivery) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.deliveries[delivery.ID] = delivery
+	return nil
+}
+
+// Pending returns the deliveries waiting for an attempt, oldest first
+func (l *MemoryDeliveryLog) Pending(ctx context.Context) ([]webhook.Delivery, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return pendingDeliveries(l.deliveries), nil
+}
+
+// Get returns a delivery by ID
+func (l *MemoryDeliveryLog) Get(ctx context.Context, id string) (webhook.Delivery, bool, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	delivery, ok := l.deliveries[id]
+	return delivery, ok, nil
+}
+
+// pendingDeliveries returns the pending deliveries in deliveries, oldest first
+func pendingDeliveries(deliveries map[string]webhook.Delivery) []webhook.Delivery {
+	var pending []webhook.Delivery
+	for _, delivery := range deliveries {
+		if delivery.State == webhook.DeliveryPending {
+			pending = append(pending, delivery)
+		}
+	}
+	sort.Slice(pending, func(i, j int) bool {
+		if pending[i].CreatedAt.Equal(pending[j].CreatedAt) {
+			return pending[i].ID < pending[j].ID
+		}
+		return pending[i].CreatedAt.Before(pending[j].CreatedAt)
+	})
+	return pending
+}
@@ -0,0 +1,50 @@
+package persistence
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/x402stacks/stacks-facilitator/internal/payment/infrastructure/webhook"
+)
+
+// testDelivery returns a pending delivery created at the given minute past noon
+func testDelivery(id string, minute int) webhook.Delivery {
+	created := time.Date(2025, 1, 7, 12, minute, 0, 0, time.UTC)
+	return webhook.Delivery{
+		ID:            id,
+		URL:           "https://merchant.example/hooks",
+		EventID:       "evt-" + id,
+		EventType:     "payment.confirmed",
+		TxID:          "0x1234567890abcdef1234567890abcdef1234567890abcdef1234567890abcdef",
+		Payload:       []byte(`{"id":"evt-` + id + `"}`),
+		State:         webhook.DeliveryPending,
+		NextAttemptAt: created,
+		CreatedAt:     created,
+		UpdatedAt:     created,
+	}
+}
+
+func TestMemoryDeliveryLog_PendingOldestFirst(t *testing.T) {
+	deliveryLog := NewMemoryDeliveryLog()
+	ctx := context.Background()
+
+	require.NoError(t, deliveryLog.Save(ctx, testDelivery("b", 2)))
+	require.NoError(t, deliveryLog.Save(ctx, testDelivery("a", 1)))
+	delivered := testDelivery("c", 0)
+	delivered.State = webhook.DeliveryDelivered
+	require.NoError(t, deliveryLog.Save(ctx, delivered))
+
+	pending, err := deliveryLog.Pending(ctx)
+	require.NoError(t, err)
+	require.Len(t, pending, 2)
+	assert.Equal(t, "a", pending[0].ID)
+	assert.Equal(t, "b", pending[1].ID)
+
+	got, ok, err := deliveryLog.Get(ctx, "c")
+	require.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, webhook.DeliveryDelivered, got.State)
+}
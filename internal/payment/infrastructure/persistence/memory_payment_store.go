@@ -0,0 +1,61 @@
+package persistence
+
+import (
+	"context"
+	"sync"
+
+	"github.com/x402stacks/stacks-facilitator/internal/payment/application/command"
+)
+
+// MemoryPaymentStore is an in-memory PaymentStore; records are lost on restart
+type MemoryPaymentStore struct {
+	mu       sync.Mutex
+	payments map[string]command.ConsumedPayment
+}
+
+// NewMemoryPaymentStore creates a new MemoryPaymentStore
+func NewMemoryPaymentStore() *MemoryPaymentStore {
+	return &MemoryPaymentStore{
+		payments: make(map[string]command.ConsumedPayment),
+	}
+}
+
+// MarkConsumed records the payment unless the transaction was already consumed
+func (s *MemoryPaymentStore) MarkConsumed(ctx context.Context, payment command.ConsumedPayment) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if existing, ok := s.payments[payment.TxID]; ok {
+		return existing.AlreadyUsedError()
+	}
+
+	s.payments[payment.TxID] = payment
+	return nil
+}
+
+// Get returns the recorded payment for a transaction
+func (s *MemoryPaymentStore) Get(ctx context.Context, txID string) (command.ConsumedPayment, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	payment, ok := s.payments[txID]
+	return payment, ok, nil
+}
+
+// Update replaces the recorded payment for a transaction
+func (s *MemoryPaymentStore) Update(ctx context.Context, payment command.ConsumedPayment) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.payments[payment.TxID] = payment
+	return nil
+}
+
+// Release forgets a transaction
+func (s *MemoryPaymentStore) Release(ctx context.Context, txID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.payments, txID)
+	return nil
+}
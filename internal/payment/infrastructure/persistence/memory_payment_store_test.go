@@ -0,0 +1,83 @@
+package persistence
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/x402stacks/stacks-facilitator/internal/payment/application/command"
+)
+
+func testPayment() command.ConsumedPayment {
+	return command.ConsumedPayment{
+		TxID:       "0x1234567890abcdef1234567890abcdef1234567890abcdef1234567890abcdef",
+		Network:    "testnet",
+		Resource:   "/premium/article/42",
+		Nonce:      "n-1",
+		ConsumedAt: time.Date(2025, 1, 7, 12, 0, 0, 0, time.UTC),
+	}
+}
+
+func TestMemoryPaymentStore_MarkConsumed(t *testing.T) {
+	store := NewMemoryPaymentStore()
+	ctx := context.Background()
+
+	require.NoError(t, store.MarkConsumed(ctx, testPayment()))
+
+	got, ok, err := store.Get(ctx, testPayment().TxID)
+	require.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, "/premium/article/42", got.Resource)
+}
+
+func TestMemoryPaymentStore_RejectsReuse(t *testing.T) {
+	store := NewMemoryPaymentStore()
+	ctx := context.Background()
+	require.NoError(t, store.MarkConsumed(ctx, testPayment()))
+
+	err := store.MarkConsumed(ctx, testPayment())
+
+	assert.ErrorIs(t, err, command.ErrPaymentAlreadyUsed)
+	assert.Contains(t, err.Error(), "/premium/article/42")
+}
+
+func TestMemoryPaymentStore_ConcurrentMarkAcceptsOnce(t *testing.T) {
+	store := NewMemoryPaymentStore()
+	ctx := context.Background()
+	var accepted int32
+	var wg sync.WaitGroup
+
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if store.MarkConsumed(ctx, testPayment()) == nil {
+				atomic.AddInt32(&accepted, 1)
+			}
+		}()
+	}
+	wg.Wait()
+
+	assert.Equal(t, int32(1), accepted)
+}
+
+func TestMemoryPaymentStore_UpdateAndRelease(t *testing.T) {
+	store := NewMemoryPaymentStore()
+	ctx := context.Background()
+	require.NoError(t, store.MarkConsumed(ctx, testPayment()))
+
+	settled := testPayment()
+	settled.BroadcastTxID = settled.TxID
+	require.NoError(t, store.Update(ctx, settled))
+	got, _, _ := store.Get(ctx, settled.TxID)
+	assert.Equal(t, settled.TxID, got.BroadcastTxID)
+
+	require.NoError(t, store.Release(ctx, settled.TxID))
+	_, ok, _ := store.Get(ctx, settled.TxID)
+	assert.False(t, ok)
+	assert.NoError(t, store.MarkConsumed(ctx, testPayment()), "a released transaction can be claimed again")
+}
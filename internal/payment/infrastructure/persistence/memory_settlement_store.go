@@ -0,0 +1,63 @@
+package persistence
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/x402stacks/stacks-facilitator/internal/payment/application/command"
+)
+
+// MemorySettlementStore is an in-memory SettlementStore. Settlements in a final state are
+// forgotten once they have not changed for the retention period; all are lost on restart.
+type MemorySettlementStore struct {
+	mu          sync.Mutex
+	retention   time.Duration
+	now         func() time.Time
+	settlements map[string]command.Settlement
+}
+
+// NewMemorySettlementStore creates a MemorySettlementStore keeping finished settlements for retention
+func NewMemorySettlementStore(retention time.Duration) *MemorySettlementStore {
+	return &MemorySettlementStore{
+		retention:   retention,
+		now:         time.Now,
+		settlements: make(map[string]command.Settlement),
+	}
+}
+
+// Save creates or replaces a settlement, pruning expired ones
+func (s *MemorySettlementStore) Save(ctx context.Context, settlement command.Settlement) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.prune()
+	s.settlements[settlement.ID] = settlement
+	return nil
+}
+
+// Get returns a settlement by ID
+func (s *MemorySettlementStore) Get(ctx context.Context, id string) (command.Settlement, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	settlement, ok := s.settlements[id]
+	if ok && s.expired(settlement) {
+		return command.Settlement{}, false, nil
+	}
+	return settlement, ok, nil
+}
+
+// prune drops expired settlements; the caller holds mu
+func (s *MemorySettlementStore) prune() {
+	for id, settlement := range s.settlements {
+		if s.expired(settlement) {
+			delete(s.settlements, id)
+		}
+	}
+}
+
+// expired reports whether a finished settlement is past the retention period
+func (s *MemorySettlementStore) expired(settlement command.Settlement) bool {
+	return settlement.State.IsFinal() && s.now().Sub(settlement.UpdatedAt) > s.retention
+}
@@ -0,0 +1,51 @@
+package persistence
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/x402stacks/stacks-facilitator/internal/payment/application/command"
+)
+
+func TestMemorySettlementStore_SaveAndGet(t *testing.T) {
+	store := NewMemorySettlementStore(time.Hour)
+	ctx := context.Background()
+
+	settlement := command.Settlement{ID: "s-1", TxID: "0x12", State: command.SettlementBroadcast, UpdatedAt: time.Now()}
+	require.NoError(t, store.Save(ctx, settlement))
+
+	settlement.State = command.SettlementMempool
+	require.NoError(t, store.Save(ctx, settlement))
+
+	got, ok, err := store.Get(ctx, "s-1")
+	require.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, command.SettlementMempool, got.State)
+
+	_, ok, err = store.Get(ctx, "s-2")
+	require.NoError(t, err)
+	assert.False(t, ok)
+}
+
+func TestMemorySettlementStore_ForgetsFinishedSettlementsAfterRetention(t *testing.T) {
+	store := NewMemorySettlementStore(time.Hour)
+	now := time.Date(2025, 1, 7, 12, 0, 0, 0, time.UTC)
+	store.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	require.NoError(t, store.Save(ctx, command.Settlement{ID: "done", State: command.SettlementConfirmed, UpdatedAt: now}))
+	require.NoError(t, store.Save(ctx, command.Settlement{ID: "waiting", State: command.SettlementMempool, UpdatedAt: now}))
+
+	now = now.Add(2 * time.Hour)
+
+	_, ok, _ := store.Get(ctx, "done")
+	assert.False(t, ok)
+	_, ok, _ = store.Get(ctx, "waiting")
+	assert.True(t, ok, "settlements still in progress are kept")
+
+	require.NoError(t, store.Save(ctx, command.Settlement{ID: "new", State: command.SettlementBroadcast, UpdatedAt: now}))
+	assert.Len(t, store.settlements, 2)
+}
@@ -0,0 +1,41 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+)
+
+// DeliveryState is the progress of one webhook delivery
+type DeliveryState string
+
+const (
+	DeliveryPending   DeliveryState = "pending"   // Waiting for its next attempt
+	DeliveryDelivered DeliveryState = "delivered" // Acknowledged with a 2xx response
+	DeliveryFailed    DeliveryState = "failed"    // Gave up after the last attempt
+)
+
+// Delivery is one event sent to one URL, with the outcome of its latest attempt
+type Delivery struct {
+	ID             string          `json:"id"`
+	URL            string          `json:"url"`
+	EventID        string          `json:"event_id"`
+	EventType      string          `json:"event_type"`
+	TxID           string          `json:"tx_id"`
+	Payload        json.RawMessage `json:"payload"` // Exact body that is signed and POSTed
+	State          DeliveryState   `json:"state"`
+	Attempts       int             `json:"attempts"`
+	LastStatusCode int             `json:"last_status_code,omitempty"`
+	LastError      string          `json:"last_error,omitempty"`
+	NextAttemptAt  time.Time       `json:"next_attempt_at"`
+	CreatedAt      time.Time       `json:"created_at"`
+	UpdatedAt      time.Time       `json:"updated_at"`
+}
+
+// DeliveryLog records every delivery and the outcome of its attempts
+type DeliveryLog interface {
+	// Save creates or replaces the delivery with the same ID
+	Save(ctx context.Context, delivery Delivery) error
+	// Pending returns the deliveries still waiting for an attempt, oldest first
+	Pending(ctx context.Context) ([]Delivery, error)
+}
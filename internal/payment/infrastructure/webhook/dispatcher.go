@@ -0,0 +1,356 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"net/http"
+	"net/netip"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/x402stacks/stacks-facilitator/internal/payment/application/command"
+	"github.com/x402stacks/stacks-facilitator/internal/payment/domain/service"
+)
+
+// ErrPrivateAddress is returned when a callback URL resolves to an address that is not public
+var ErrPrivateAddress = errors.New("webhook address is not public")
+
+// DispatcherConfig controls who is notified and how deliveries are retried
+type DispatcherConfig struct {
+	Secret         string        // HMAC key every delivery is signed with
+	URLs           []string      // Notified of every event, alongside any per-request callback. Trusted, so may be internal
+	MaxAttempts    int           // Attempts per delivery before it is marked failed
+	InitialBackoff time.Duration // Delay before the first retry, doubled after each failure
+	MaxBackoff     time.Duration // Longest delay between retries
+	Timeout        time.Duration // Time allowed for each attempt
+}
+
+// Dispatcher delivers payment events as signed JSON POSTs, retrying with exponential backoff.
+// Each delivery is recorded in the log before it is attempted and after every attempt, so
+// deliveries still pending when the process stops are resumed by the next Run.
+// Per-request callbacks are sent through a client that only connects to public addresses.
+type Dispatcher struct {
+	cfg            DispatcherConfig
+	log            DeliveryLog
+	client         *http.Client // Sends to the configured URLs
+	callbackClient *http.Client // Sends to per-request callback URLs
+	allowAddr      func(netip.Addr) bool
+	now            func() time.Time
+
+	mu  sync.Mutex
+	ctx context.Context // Set while Run is active
+	wg  sync.WaitGroup
+}
+
+// NewDispatcher creates a Dispatcher recording deliveries in log
+func NewDispatcher(cfg DispatcherConfig, log DeliveryLog) *Dispatcher {
+	cfg.MaxAttempts = max(cfg.MaxAttempts, 1)
+	if cfg.InitialBackoff <= 0 {
+		cfg.InitialBackoff = time.Second
+	}
+	cfg.MaxBackoff = max(cfg.MaxBackoff, cfg.InitialBackoff)
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = 10 * time.Second
+	}
+
+	d := &Dispatcher{
+		cfg:       cfg,
+		log:       log,
+		client:    &http.Client{},
+		allowAddr: command.PublicAddress,
+		now:       time.Now,
+	}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = nil // A proxy would connect on our behalf, past the address check
+	transport.DialContext = d.dialPublic
+	d.callbackClient = &http.Client{Transport: transport}
+	return d
+}
+
+// Run resumes the deliveries left pending in the log, then sends new ones until ctx is
+// cancelled. Deliveries in progress at that point stay pending for the next Run.
+func (d *Dispatcher) Run(ctx context.Context) error {
+	pending, err := d.log.Pending(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to load pending webhook deliveries: %w", err)
+	}
+
+	d.mu.Lock()
+	d.ctx = ctx
+	for _, delivery := range pending {
+		d.startLocked(delivery)
+	}
+	d.mu.Unlock()
+
+	<-ctx.Done()
+
+	d.mu.Lock()
+	d.ctx = nil
+	d.mu.Unlock()
+	d.wg.Wait()
+	return nil
+}
+
+// Notify records a delivery of event to every configured URL and to callbackURLs, and sends
+// them in the background. Deliveries recorded while Run is not active are sent by the next Run.
+func (d *Dispatcher) Notify(ctx context.Context, event command.PaymentEvent, callbackURLs []string) error {
+	payload, err := json.Marshal(newEventPayload(event))
+	if err != nil {
+		return fmt.Errorf("failed to encode webhook event: %w", err)
+	}
+
+	var errs []error
+	for _, url := range d.targets(callbackURLs) {
+		id, err := newDeliveryID()
+		if err != nil {
+			errs = append(errs, fmt.Errorf("failed to create webhook delivery to %s: %w", url, err))
+			continue
+		}
+
+		now := d.now().UTC()
+		delivery := Delivery{
+			ID:            id,
+			URL:           url,
+			EventID:       event.ID,
+			EventType:     event.Type,
+			TxID:          event.TxID,
+			Payload:       payload,
+			State:         DeliveryPending,
+			NextAttemptAt: now,
+			CreatedAt:     now,
+			UpdatedAt:     now,
+		}
+		if err := d.log.Save(ctx, delivery); err != nil {
+			errs = append(errs, fmt.Errorf("failed to record webhook delivery to %s: %w", url, err))
+			continue
+		}
+
+		d.mu.Lock()
+		d.startLocked(delivery)
+		d.mu.Unlock()
+	}
+
+	return errors.Join(errs...)
+}
+
+// targets returns the configured URLs followed by callbackURLs, without duplicates
+func (d *Dispatcher) targets(callbackURLs []string) []string {
+	urls := append([]string(nil), d.cfg.URLs...)
+	for _, callbackURL := range callbackURLs {
+		if callbackURL != "" && !slices.Contains(urls, callbackURL) {
+			urls = append(urls, callbackURL)
+		}
+	}
+	return urls
+}
+
+// startLocked sends a delivery in the background if Run is active; the caller holds mu
+func (d *Dispatcher) startLocked(delivery Delivery) {
+	if d.ctx == nil {
+		return
+	}
+
+	ctx := d.ctx
+	d.wg.Add(1)
+	go func() {
+		defer d.wg.Done()
+		d.deliver(ctx, delivery)
+	}()
+}
+
+// deliver attempts a delivery until it is acknowledged, runs out of attempts or ctx is cancelled
+func (d *Dispatcher) deliver(ctx context.Context, delivery Delivery) {
+	for delivery.State == DeliveryPending {
+		if wait := delivery.NextAttemptAt.Sub(d.now()); wait > 0 {
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(wait):
+			}
+		}
+
+		statusCode, err := d.send(ctx, delivery)
+		if ctx.Err() != nil {
+			return
+		}
+
+		now := d.now().UTC()
+		delivery.Attempts++
+		delivery.LastStatusCode = statusCode
+		delivery.LastError = ""
+		delivery.UpdatedAt = now
+		switch {
+		case err == nil:
+			delivery.State = DeliveryDelivered
+		case delivery.Attempts >= d.cfg.MaxAttempts:
+			delivery.State = DeliveryFailed
+			delivery.LastError = err.Error()
+		default:
+			delivery.LastError = err.Error()
+			delivery.NextAttemptAt = now.Add(d.backoff(delivery.Attempts))
+		}
+
+		if err := d.log.Save(ctx, delivery); err != nil {
+			log.Printf("webhook delivery %s: failed to record attempt %d: %v", delivery.ID, delivery.Attempts, err)
+		}
+	}
+}
+
+// send POSTs the signed payload once, returning the response status code
+func (d *Dispatcher) send(ctx context.Context, delivery Delivery) (int, error) {
+	ctx, cancel := context.WithTimeout(ctx, d.cfg.Timeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, delivery.URL, bytes.NewReader(delivery.Payload))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(HeaderSignature, signatureHeader([]byte(d.cfg.Secret), d.now().Unix(), delivery.Payload))
+	req.Header.Set(HeaderEventID, delivery.EventID)
+	req.Header.Set(HeaderEventType, delivery.EventType)
+	req.Header.Set(HeaderDelivery, delivery.ID)
+
+	client := d.callbackClient
+	if slices.Contains(d.cfg.URLs, delivery.URL) {
+		client = d.client
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return resp.StatusCode, fmt.Errorf("unexpected status %d", resp.StatusCode)
+	}
+	return resp.StatusCode, nil
+}
+
+// dialPublic resolves the host of a callback and connects only if every address it resolves
+// to is allowed. The checked address is dialled directly so the name is not resolved again.
+func (d *Dispatcher) dialPublic(ctx context.Context, network, address string) (net.Conn, error) {
+	host, port, err := net.SplitHostPort(address)
+	if err != nil {
+		return nil, err
+	}
+	addrs, err := net.DefaultResolver.LookupNetIP(ctx, "ip", host)
+	if err != nil {
+		return nil, err
+	}
+	for _, addr := range addrs {
+		if !d.allowAddr(addr) {
+			return nil, fmt.Errorf("%w: %s resolves to %s", ErrPrivateAddress, host, addr)
+		}
+	}
+
+	var dialer net.Dialer
+	var errs []error
+	for _, addr := range addrs {
+		conn, err := dialer.DialContext(ctx, network, net.JoinHostPort(addr.Unmap().String(), port))
+		if err == nil {
+			return conn, nil
+		}
+		errs = append(errs, err)
+	}
+	return nil, errors.Join(errs...)
+}
+
+// backoff is the delay after the given number of failed attempts
+func (d *Dispatcher) backoff(attempts int) time.Duration {
+	delay := d.cfg.InitialBackoff
+	for i := 1; i < attempts && delay < d.cfg.MaxBackoff; i++ {
+		delay *= 2
+	}
+	return min(delay, d.cfg.MaxBackoff)
+}
+
+// eventPayload is the JSON body of a delivery
+type eventPayload struct {
+	ID        string      `json:"id"`
+	Type      string      `json:"type"`
+	CreatedAt time.Time   `json:"created_at"`
+	Data      paymentData `json:"data"`
+}
+
+// paymentData describes the payment an event is about
+type paymentData struct {
+	TxID              string        `json:"tx_id"`
+	Network           string        `json:"network"`
+	TokenType         string        `json:"token_type"`
+	PreviousStatus    string        `json:"previous_status"`
+	Status            string        `json:"status"`
+	ReplacedByTxID    string        `json:"replaced_by_tx_id,omitempty"`
+	SenderAddress     string        `json:"sender_address"`
+	RecipientAddress  string        `json:"recipient_address"`
+	Amount            string        `json:"amount"` // Decimal string, exact beyond 2^53
+	BlockHeight       uint64        `json:"block_height"`
+	Confirmations     uint64        `json:"confirmations"`
+	BurnConfirmations uint64        `json:"burn_confirmations"`
+	Failures          []failureData `json:"failures,omitempty"` // Why a failed payment failed verification
+}
+
+// failureData is one reason a payment failed verification
+type failureData struct {
+	Code     string `json:"code"` // e.g. tx_failed, memo_mismatch, insufficient_amount
+	Expected string `json:"expected,omitempty"`
+	Actual   string `json:"actual,omitempty"`
+	Message  string `json:"message"`
+}
+
+// newEventPayload converts a payment event to its JSON body
+func newEventPayload(event command.PaymentEvent) eventPayload {
+	return eventPayload{
+		ID:        event.ID,
+		Type:      event.Type,
+		CreatedAt: event.OccurredAt,
+		Data: paymentData{
+			TxID:              event.TxID,
+			Network:           event.Network,
+			TokenType:         event.TokenType,
+			PreviousStatus:    event.PreviousStatus.String(),
+			Status:            event.Status.String(),
+			ReplacedByTxID:    event.ReplacedByTxID,
+			SenderAddress:     event.SenderAddress,
+			RecipientAddress:  event.RecipientAddress,
+			Amount:            event.Amount,
+			BlockHeight:       event.BlockHeight,
+			Confirmations:     event.Confirmations,
+			BurnConfirmations: event.BurnConfirmations,
+			Failures:          newFailureData(event.Failures),
+		},
+	}
+}
+
+// newFailureData converts verification failures to their JSON form
+func newFailureData(failures []service.VerificationFailure) []failureData {
+	var data []failureData
+	for _, failure := range failures {
+		data = append(data, failureData{
+			Code:     failure.Code.String(),
+			Expected: failure.Expected,
+			Actual:   failure.Actual,
+			Message:  failure.Message,
+		})
+	}
+	return data
+}
+
+// newDeliveryID returns a random 128-bit hex identifier
+func newDeliveryID() (string, error) {
+	var b [16]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b[:]), nil
+}
@@ -0,0 +1,368 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/x402stacks/stacks-facilitator/internal/payment/application/command"
+	"github.com/x402stacks/stacks-facilitator/internal/payment/domain/service"
+	"github.com/x402stacks/stacks-facilitator/internal/payment/domain/valueobject"
+)
+
+// MockDeliveryLog keeps deliveries in memory and remembers every saved version
+type MockDeliveryLog struct {
+	mu         sync.Mutex
+	deliveries map[string]Delivery
+	saves      []Delivery
+	SaveErr    error
+}
+
+func (m *MockDeliveryLog) Save(ctx context.Context, delivery Delivery) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.SaveErr != nil {
+		return m.SaveErr
+	}
+	if m.deliveries == nil {
+		m.deliveries = make(map[string]Delivery)
+	}
+	m.deliveries[delivery.ID] = delivery
+	m.saves = append(m.saves, delivery)
+	return nil
+}
+
+func (m *MockDeliveryLog) Pending(ctx context.Context) ([]Delivery, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var pending []Delivery
+	for _, delivery := range m.deliveries {
+		if delivery.State == DeliveryPending {
+			pending = append(pending, delivery)
+		}
+	}
+	sort.Slice(pending, func(i, j int) bool { return pending[i].CreatedAt.Before(pending[j].CreatedAt) })
+	return pending, nil
+}
+
+func (m *MockDeliveryLog) Deliveries() []Delivery {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var deliveries []Delivery
+	for _, delivery := range m.deliveries {
+		deliveries = append(deliveries, delivery)
+	}
+	return deliveries
+}
+
+var testEvent = command.PaymentEvent{
+	ID:               "evt_1",
+	Type:             command.EventPaymentConfirmed,
+	TxID:             "0x1234567890abcdef1234567890abcdef1234567890abcdef1234567890abcdef",
+	Network:          "testnet",
+	TokenType:        "STX",
+	PreviousStatus:   valueobject.StatusPending,
+	Status:           valueobject.StatusConfirmed,
+	SenderAddress:    "ST2J6ZY48GV1EZ5V2V5RB9MP66SW86PYKKQYAC0RQ",
+	RecipientAddress: "ST1PQHQKV0RJXZFY1DGX8MNSNYVE3VGZJSRTPGZGM",
+	Amount:           "1000000",
+	BlockHeight:      12345,
+	Confirmations:    1,
+	OccurredAt:       time.Date(2025, 1, 7, 12, 0, 0, 0, time.UTC),
+}
+
+// runDispatcher runs d until the test ends
+func runDispatcher(t *testing.T, d *Dispatcher) {
+	t.Helper()
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() { done <- d.Run(ctx) }()
+	t.Cleanup(func() {
+		cancel()
+		assert.NoError(t, <-done)
+	})
+	require.Eventually(t, func() bool {
+		d.mu.Lock()
+		defer d.mu.Unlock()
+		return d.ctx != nil
+	}, time.Second, time.Millisecond)
+}
+
+// allowLoopback lets d send callbacks to httptest servers, which listen on loopback
+func allowLoopback(d *Dispatcher) {
+	d.allowAddr = func(addr netip.Addr) bool { return addr.IsLoopback() || command.PublicAddress(addr) }
+}
+
+// waitForState polls the log until every delivery reaches state
+func waitForState(t *testing.T, deliveryLog *MockDeliveryLog, count int, state DeliveryState) []Delivery {
+	t.Helper()
+	var deliveries []Delivery
+	require.Eventually(t, func() bool {
+		deliveries = deliveryLog.Deliveries()
+		if len(deliveries) != count {
+			return false
+		}
+		for _, delivery := range deliveries {
+			if delivery.State != state {
+				return false
+			}
+		}
+		return true
+	}, 2*time.Second, time.Millisecond)
+	return deliveries
+}
+
+func TestDispatcher_DeliversSignedEvent(t *testing.T) {
+	type received struct {
+		header http.Header
+		body   []byte
+	}
+	requests := make(chan received, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		requests <- received{header: r.Header, body: body}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	deliveryLog := &MockDeliveryLog{}
+	d := NewDispatcher(DispatcherConfig{Secret: "whsec_test", MaxAttempts: 3}, deliveryLog)
+	allowLoopback(d)
+	runDispatcher(t, d)
+
+	require.NoError(t, d.Notify(context.Background(), testEvent, []string{server.URL}))
+
+	req := <-requests
+	assert.Equal(t, "application/json", req.header.Get("Content-Type"))
+	assert.Equal(t, "evt_1", req.header.Get(HeaderEventID))
+	assert.Equal(t, "payment.confirmed", req.header.Get(HeaderEventType))
+
+	signature := req.header.Get(HeaderSignature)
+	timestamp, mac, ok := strings.Cut(strings.TrimPrefix(signature, "t="), ",v1=")
+	require.True(t, ok, signature)
+	unix, err := strconv.ParseInt(timestamp, 10, 64)
+	require.NoError(t, err)
+	assert.Equal(t, Sign([]byte("whsec_test"), unix, req.body), mac)
+
+	var payload map[string]any
+	require.NoError(t, json.Unmarshal(req.body, &payload))
+	assert.Equal(t, "evt_1", payload["id"])
+	assert.Equal(t, "payment.confirmed", payload["type"])
+	data := payload["data"].(map[string]any)
+	assert.Equal(t, testEvent.TxID, data["tx_id"])
+	assert.Equal(t, "pending", data["previous_status"])
+	assert.Equal(t, "confirmed", data["status"])
+	assert.Equal(t, "1000000", data["amount"])
+	assert.NotContains(t, data, "failures")
+
+	deliveries := waitForState(t, deliveryLog, 1, DeliveryDelivered)
+	assert.Equal(t, req.header.Get(HeaderDelivery), deliveries[0].ID)
+	assert.Equal(t, 1, deliveries[0].Attempts)
+	assert.Equal(t, http.StatusNoContent, deliveries[0].LastStatusCode)
+}
+
+func TestDispatcher_NotifiesGlobalAndCallbackURLs(t *testing.T) {
+	var hits sync.Map
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		count, _ := hits.LoadOrStore(r.URL.Path, new(atomic.Int32))
+		count.(*atomic.Int32).Add(1)
+	}))
+	defer server.Close()
+
+	deliveryLog := &MockDeliveryLog{}
+	d := NewDispatcher(DispatcherConfig{Secret: "s", URLs: []string{server.URL + "/global", server.URL + "/shared"}}, deliveryLog)
+	allowLoopback(d)
+	runDispatcher(t, d)
+
+	require.NoError(t, d.Notify(context.Background(), testEvent, []string{server.URL + "/callback"}))
+	require.NoError(t, d.Notify(context.Background(), testEvent, []string{server.URL + "/shared"}))
+
+	waitForState(t, deliveryLog, 5, DeliveryDelivered)
+	for path, expected := range map[string]int32{"/global": 2, "/shared": 2, "/callback": 1} {
+		count, ok := hits.Load(path)
+		require.True(t, ok, path)
+		assert.Equal(t, expected, count.(*atomic.Int32).Load(), path)
+	}
+}
+
+func TestDispatcher_RetriesWithBackoff(t *testing.T) {
+	var attempts atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if attempts.Add(1) < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	}))
+	defer server.Close()
+
+	deliveryLog := &MockDeliveryLog{}
+	d := NewDispatcher(DispatcherConfig{Secret: "s", MaxAttempts: 5, InitialBackoff: time.Millisecond, MaxBackoff: 2 * time.Millisecond}, deliveryLog)
+	allowLoopback(d)
+	runDispatcher(t, d)
+
+	require.NoError(t, d.Notify(context.Background(), testEvent, []string{server.URL}))
+
+	deliveries := waitForState(t, deliveryLog, 1, DeliveryDelivered)
+	assert.Equal(t, 3, deliveries[0].Attempts)
+	assert.Empty(t, deliveries[0].LastError)
+
+	deliveryLog.mu.Lock()
+	defer deliveryLog.mu.Unlock()
+	require.Len(t, deliveryLog.saves, 4, "recorded before the first attempt and after each")
+	assert.Equal(t, "unexpected status 503", deliveryLog.saves[1].LastError)
+	assert.Equal(t, DeliveryPending, deliveryLog.saves[2].State)
+}
+
+func TestDispatcher_GivesUpAfterMaxAttempts(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	deliveryLog := &MockDeliveryLog{}
+	d := NewDispatcher(DispatcherConfig{Secret: "s", MaxAttempts: 2, InitialBackoff: time.Millisecond}, deliveryLog)
+	allowLoopback(d)
+	runDispatcher(t, d)
+
+	require.NoError(t, d.Notify(context.Background(), testEvent, []string{server.URL}))
+
+	deliveries := waitForState(t, deliveryLog, 1, DeliveryFailed)
+	assert.Equal(t, 2, deliveries[0].Attempts)
+	assert.Equal(t, http.StatusInternalServerError, deliveries[0].LastStatusCode)
+	assert.Equal(t, "unexpected status 500", deliveries[0].LastError)
+}
+
+func TestDispatcher_ResumesPendingDeliveries(t *testing.T) {
+	var attempts atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts.Add(1)
+	}))
+	defer server.Close()
+
+	deliveryLog := &MockDeliveryLog{}
+	d := NewDispatcher(DispatcherConfig{Secret: "s"}, deliveryLog)
+	allowLoopback(d)
+
+	// Recorded while Run is not active, as if the process stopped before sending
+	require.NoError(t, d.Notify(context.Background(), testEvent, []string{server.URL}))
+	waitForState(t, deliveryLog, 1, DeliveryPending)
+	assert.Zero(t, attempts.Load())
+
+	runDispatcher(t, d)
+
+	waitForState(t, deliveryLog, 1, DeliveryDelivered)
+	assert.Equal(t, int32(1), attempts.Load())
+}
+
+func TestDispatcher_NotifyReportsLogErrors(t *testing.T) {
+	deliveryLog := &MockDeliveryLog{SaveErr: errors.New("disk full")}
+	d := NewDispatcher(DispatcherConfig{Secret: "s"}, deliveryLog)
+
+	err := d.Notify(context.Background(), testEvent, []string{"https://merchant.example/hooks"})
+
+	assert.ErrorContains(t, err, "disk full")
+}
+
+func TestDispatcher_Backoff(t *testing.T) {
+	d := NewDispatcher(DispatcherConfig{InitialBackoff: time.Second, MaxBackoff: 5 * time.Second}, &MockDeliveryLog{})
+
+	assert.Equal(t, time.Second, d.backoff(1))
+	assert.Equal(t, 2*time.Second, d.backoff(2))
+	assert.Equal(t, 4*time.Second, d.backoff(3))
+	assert.Equal(t, 5*time.Second, d.backoff(4))
+	assert.Equal(t, 5*time.Second, d.backoff(30))
+}
+
+func TestDispatcher_RefusesCallbacksToPrivateAddresses(t *testing.T) {
+	var attempts atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts.Add(1)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	deliveryLog := &MockDeliveryLog{}
+	d := NewDispatcher(DispatcherConfig{Secret: "s", URLs: []string{server.URL + "/global"}, MaxAttempts: 1}, deliveryLog)
+	runDispatcher(t, d)
+
+	require.NoError(t, d.Notify(context.Background(), testEvent, []string{server.URL + "/callback"}))
+
+	require.Eventually(t, func() bool {
+		deliveries := deliveryLog.Deliveries()
+		return len(deliveries) == 2 && deliveries[0].State != DeliveryPending && deliveries[1].State != DeliveryPending
+	}, 2*time.Second, time.Millisecond)
+	for _, delivery := range deliveryLog.Deliveries() {
+		if strings.HasSuffix(delivery.URL, "/global") {
+			assert.Equal(t, DeliveryDelivered, delivery.State, "configured URLs are trusted")
+			continue
+		}
+		assert.Equal(t, DeliveryFailed, delivery.State)
+		assert.Contains(t, delivery.LastError, ErrPrivateAddress.Error())
+	}
+	assert.Equal(t, int32(1), attempts.Load())
+}
+
+func TestDispatcher_DialPublicRefusesPrivateAddresses(t *testing.T) {
+	d := NewDispatcher(DispatcherConfig{Secret: "s"}, &MockDeliveryLog{})
+
+	for _, address := range []string{
+		"127.0.0.1:80",
+		"[::1]:80",
+		"localhost:80",
+		"169.254.169.254:80",
+		"[fe80::1]:80",
+		"10.0.0.1:443",
+		"172.16.0.1:443",
+		"192.168.1.1:443",
+		"[fd00::1]:443",
+		"0.0.0.0:80",
+		"[::]:80",
+		"224.0.0.1:80",
+		"[ff02::1]:80",
+		"[::ffff:10.0.0.1]:80",
+	} {
+		t.Run(address, func(t *testing.T) {
+			conn, err := d.dialPublic(context.Background(), "tcp", address)
+
+			assert.ErrorIs(t, err, ErrPrivateAddress)
+			assert.Nil(t, conn)
+		})
+	}
+}
+
+func TestNewEventPayload_IncludesVerificationFailures(t *testing.T) {
+	event := testEvent
+	event.Type = command.EventPaymentFailed
+	event.Status = valueobject.StatusFailed
+	event.Failures = []service.VerificationFailure{
+		{Code: service.FailureMemoMismatch, Expected: "order-1", Actual: "order-2", Message: "memo mismatch"},
+	}
+
+	body, err := json.Marshal(newEventPayload(event))
+	require.NoError(t, err)
+
+	var payload struct {
+		Type string `json:"type"`
+		Data struct {
+			Status   string            `json:"status"`
+			Failures []json.RawMessage `json:"failures"`
+		} `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(body, &payload))
+	assert.Equal(t, "payment.failed", payload.Type)
+	assert.Equal(t, "failed", payload.Data.Status)
+	require.Len(t, payload.Data.Failures, 1)
+	assert.JSONEq(t, `{"code":"memo_mismatch","expected":"order-1","actual":"order-2","message":"memo mismatch"}`, string(payload.Data.Failures[0]))
+}
@@ -0,0 +1,26 @@
+package webhook
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSign(t *testing.T) {
+	body := []byte(`{"id":"evt_1"}`)
+
+	signature := Sign([]byte("whsec_test"), 1736251200, body)
+
+	// echo -n '1736251200.{"id":"evt_1"}' | openssl dgst -sha256 -hmac whsec_test
+	assert.Equal(t, "99603a95661af5a08a54b5b969f45a920cc6ed0fdf4d2772d26b2159471dd183", signature)
+	assert.NotEqual(t, signature, Sign([]byte("other"), 1736251200, body))
+	assert.NotEqual(t, signature, Sign([]byte("whsec_test"), 1736251201, body))
+}
+
+func TestSignatureHeader(t *testing.T) {
+	body := []byte(`{}`)
+
+	header := signatureHeader([]byte("s"), 42, body)
+
+	assert.Equal(t, "t=42,v1="+Sign([]byte("s"), 42, body), header)
+}
@@ -0,0 +1,236 @@
+package clarity
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"strings"
+	"unicode/utf8"
+)
+
+// maxDepth mirrors Clarity's maximum type nesting depth
+const maxDepth = 32
+
+// Decode deserializes a Clarity value that must span all of data
+func Decode(data []byte) (Value, error) {
+	r := bytes.NewReader(data)
+	v, err := DecodeFrom(r)
+	if err != nil {
+		return nil, err
+	}
+	if r.Len() != 0 {
+		return nil, fmt.Errorf("%d trailing bytes after clarity value", r.Len())
+	}
+	return v, nil
+}
+
+// DecodeHex decodes a hex-encoded Clarity value, with or without a 0x prefix
+func DecodeHex(s string) (Value, error) {
+	data, err := hex.DecodeString(strings.TrimPrefix(s, "0x"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid clarity hex: %w", err)
+	}
+	return Decode(data)
+}
+
+// DecodeFrom reads one serialized Clarity value from r
+func DecodeFrom(r *bytes.Reader) (Value, error) {
+	return decode(r, 0)
+}
+
+func decode(r *bytes.Reader, depth int) (Value, error) {
+	if depth > maxDepth {
+		return nil, errors.New("clarity value nested too deeply")
+	}
+
+	prefix, err := r.ReadByte()
+	if err != nil {
+		return nil, errors.New("unexpected end of clarity value")
+	}
+
+	switch Type(prefix) {
+	case TypeInt:
+		b, err := readN(r, 16)
+		if err != nil {
+			return nil, err
+		}
+		return Int{Value: fromTwosComplement(b)}, nil
+	case TypeUInt:
+		b, err := readN(r, 16)
+		if err != nil {
+			return nil, err
+		}
+		return UInt{Value: new(big.Int).SetBytes(b)}, nil
+	case TypeBuffer:
+		b, err := readLengthPrefixed(r)
+		if err != nil {
+			return nil, err
+		}
+		return Buffer(b), nil
+	case TypeTrue:
+		return Bool(true), nil
+	case TypeFalse:
+		return Bool(false), nil
+	case TypeStandardPrincipal:
+		return readStandardPrincipal(r)
+	case TypeContractPrincipal:
+		issuer, err := readStandardPrincipal(r)
+		if err != nil {
+			return nil, err
+		}
+		name, err := ReadName(r)
+		if err != nil {
+			return nil, err
+		}
+		return ContractPrincipal{Issuer: issuer, Name: name}, nil
+	case TypeResponseOk:
+		inner, err := decode(r, depth+1)
+		if err != nil {
+			return nil, err
+		}
+		return ResponseOk{Value: inner}, nil
+	case TypeResponseErr:
+		inner, err := decode(r, depth+1)
+		if err != nil {
+			return nil, err
+		}
+		return ResponseErr{Value: inner}, nil
+	case TypeNone:
+		return None{}, nil
+	case TypeSome:
+		inner, err := decode(r, depth+1)
+		if err != nil {
+			return nil, err
+		}
+		return Some{Value: inner}, nil
+	case TypeList:
+		n, err := readU32(r)
+		if err != nil {
+			return nil, err
+		}
+		if int64(n) > int64(r.Len()) {
+			return nil, errors.New("clarity list length exceeds input")
+		}
+		list := make(List, 0, n)
+		for i := uint32(0); i < n; i++ {
+			item, err := decode(r, depth+1)
+			if err != nil {
+				return nil, err
+			}
+			list = append(list, item)
+		}
+		return list, nil
+	case TypeTuple:
+		n, err := readU32(r)
+		if err != nil {
+			return nil, err
+		}
+		if int64(n) > int64(r.Len()) {
+			return nil, errors.New("clarity tuple length exceeds input")
+		}
+		tuple := make(Tuple, 0, n)
+		for i := uint32(0); i < n; i++ {
+			name, err := ReadName(r)
+			if err != nil {
+				return nil, err
+			}
+			item, err := decode(r, depth+1)
+			if err != nil {
+				return nil, err
+			}
+			tuple = append(tuple, TupleEntry{Name: name, Value: item})
+		}
+		return tuple, nil
+	case TypeStringASCII:
+		b, err := readLengthPrefixed(r)
+		if err != nil {
+			return nil, err
+		}
+		for _, c := range b {
+			if c > 0x7e || (c < 0x20 && c != '\t' && c != '\n' && c != '\f' && c != '\r') {
+				return nil, errors.New("invalid character in clarity ascii string")
+			}
+		}
+		return StringASCII(b), nil
+	case TypeStringUTF8:
+		b, err := readLengthPrefixed(r)
+		if err != nil {
+			return nil, err
+		}
+		if !utf8.Valid(b) {
+			return nil, errors.New("invalid clarity utf8 string")
+		}
+		return StringUTF8(b), nil
+	default:
+		return nil, fmt.Errorf("unknown clarity type prefix 0x%02x", prefix)
+	}
+}
+
+// ReadName reads a length-prefixed (one byte) contract or tuple field name
+func ReadName(r *bytes.Reader) (string, error) {
+	n, err := r.ReadByte()
+	if err != nil {
+		return "", errors.New("unexpected end of clarity name")
+	}
+	if n > 128 {
+		return "", fmt.Errorf("clarity name too long: %d", n)
+	}
+	b, err := readN(r, int(n))
+	if err != nil {
+		return "", err
+	}
+	return string(b), nil
+}
+
+// readStandardPrincipal reads a version byte and hash160
+func readStandardPrincipal(r *bytes.Reader) (StandardPrincipal, error) {
+	b, err := readN(r, 21)
+	if err != nil {
+		return StandardPrincipal{}, err
+	}
+	var p StandardPrincipal
+	p.Version = b[0]
+	copy(p.Hash160[:], b[1:])
+	return p, nil
+}
+
+// readLengthPrefixed reads a u32 length followed by that many bytes
+func readLengthPrefixed(r *bytes.Reader) ([]byte, error) {
+	n, err := readU32(r)
+	if err != nil {
+		return nil, err
+	}
+	if int64(n) > int64(r.Len()) {
+		return nil, errors.New("clarity length prefix exceeds input")
+	}
+	return readN(r, int(n))
+}
+
+func readU32(r *bytes.Reader) (uint32, error) {
+	b, err := readN(r, 4)
+	if err != nil {
+		return 0, err
+	}
+	return binary.BigEndian.Uint32(b), nil
+}
+
+func readN(r *bytes.Reader, n int) ([]byte, error) {
+	b := make([]byte, n)
+	if _, err := io.ReadFull(r, b); err != nil {
+		return nil, errors.New("unexpected end of clarity value")
+	}
+	return b, nil
+}
+
+// fromTwosComplement interprets b as a big-endian two's complement integer
+func fromTwosComplement(b []byte) *big.Int {
+	v := new(big.Int).SetBytes(b)
+	if len(b) > 0 && b[0]&0x80 != 0 {
+		v.Sub(v, new(big.Int).Lsh(big.NewInt(1), uint(len(b)*8)))
+	}
+	return v
+}
@@ -0,0 +1,111 @@
+package clarity
+
+import (
+	"encoding/hex"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func mustHex(t *testing.T, s string) []byte {
+	t.Helper()
+	b, err := hex.DecodeString(s)
+	require.NoError(t, err)
+	return b
+}
+
+func TestDecode_Integers(t *testing.T) {
+	v, err := Decode(mustHex(t, "01000000000000000000000000000f4240"))
+	require.NoError(t, err)
+	assert.Equal(t, UInt{Value: big.NewInt(1000000)}, v)
+
+	v, err = Decode(mustHex(t, "00ffffffffffffffffffffffffffffffff"))
+	require.NoError(t, err)
+	assert.Equal(t, Int{Value: big.NewInt(-1)}, v)
+}
+
+func TestDecode_Principals(t *testing.T) {
+	// ST1PQHQKV0RJXZFY1DGX8MNSNYVE3VGZJSRTPGZGM
+	standard := "051a6d78de7b0625dfbfc16c3a8a5735f6dc3dc3f2ce"
+
+	v, err := Decode(mustHex(t, standard))
+	require.NoError(t, err)
+	s, ok := PrincipalString(v)
+	require.True(t, ok)
+	assert.Equal(t, "ST1PQHQKV0RJXZFY1DGX8MNSNYVE3VGZJSRTPGZGM", s)
+
+	contract := "061a6d78de7b0625dfbfc16c3a8a5735f6dc3dc3f2ce" + "05" + hex.EncodeToString([]byte("usdcx"))
+	v, err = Decode(mustHex(t, contract))
+	require.NoError(t, err)
+	s, ok = PrincipalString(v)
+	require.True(t, ok)
+	assert.Equal(t, "ST1PQHQKV0RJXZFY1DGX8MNSNYVE3VGZJSRTPGZGM.usdcx", s)
+}
+
+func TestDecode_Compound(t *testing.T) {
+	// (some (tuple (a u1) (b 0x0102)))
+	data := "0a" + "0c00000002" +
+		"0161" + "0100000000000000000000000000000001" +
+		"0162" + "02000000020102"
+
+	v, err := Decode(mustHex(t, data))
+	require.NoError(t, err)
+
+	some, ok := v.(Some)
+	require.True(t, ok)
+	tuple, ok := some.Value.(Tuple)
+	require.True(t, ok)
+
+	b, ok := tuple.Get("b")
+	require.True(t, ok)
+	assert.Equal(t, Buffer{0x01, 0x02}, b)
+
+	_, ok = tuple.Get("c")
+	assert.False(t, ok)
+}
+
+func TestDecode_Strings(t *testing.T) {
+	v, err := Decode(mustHex(t, "0d00000002"+hex.EncodeToString([]byte("hi"))))
+	require.NoError(t, err)
+	assert.Equal(t, StringASCII("hi"), v)
+
+	_, err = Decode(mustHex(t, "0d0000000180"))
+	assert.Error(t, err)
+
+	_, err = Decode(mustHex(t, "0e00000001ff"))
+	assert.Error(t, err)
+}
+
+func TestDecode_Errors(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+	}{
+		{"empty", ""},
+		{"unknown prefix", "ff"},
+		{"truncated uint", "010000"},
+		{"trailing bytes", "0300"},
+		{"oversized buffer length", "02ffffffff00"},
+		{"oversized list length", "0bffffffff"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Decode(mustHex(t, tt.data))
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestDecode_TooDeep(t *testing.T) {
+	data := ""
+	for i := 0; i < maxDepth+2; i++ {
+		data += "0a"
+	}
+	data += "09"
+
+	_, err := Decode(mustHex(t, data))
+	assert.Error(t, err)
+}
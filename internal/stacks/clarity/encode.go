@@ -0,0 +1,154 @@
+package clarity
+
+import (
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math/big"
+	"sort"
+	"unicode/utf8"
+)
+
+// 128-bit integer bounds
+var (
+	maxUInt128 = new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 128), big.NewInt(1))
+	maxInt128  = new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 127), big.NewInt(1))
+	minInt128  = new(big.Int).Neg(new(big.Int).Lsh(big.NewInt(1), 127))
+)
+
+// NewUInt returns a UInt holding v
+func NewUInt(v uint64) UInt {
+	return UInt{Value: new(big.Int).SetUint64(v)}
+}
+
+// NewInt returns an Int holding v
+func NewInt(v int64) Int {
+	return Int{Value: big.NewInt(v)}
+}
+
+// Encode serializes a Clarity value. Tuple fields are written sorted by name,
+// which is the consensus order.
+func Encode(v Value) ([]byte, error) {
+	return appendValue(nil, v, 0)
+}
+
+// EncodeHex serializes a Clarity value as 0x-prefixed hex
+func EncodeHex(v Value) (string, error) {
+	b, err := Encode(v)
+	if err != nil {
+		return "", err
+	}
+	return "0x" + hex.EncodeToString(b), nil
+}
+
+func appendValue(buf []byte, v Value, depth int) ([]byte, error) {
+	if depth > maxDepth {
+		return nil, errors.New("clarity value nested too deeply")
+	}
+	if v == nil {
+		return nil, errors.New("nil clarity value")
+	}
+
+	buf = append(buf, byte(v.Type()))
+
+	switch v := v.(type) {
+	case Int:
+		if v.Value == nil || v.Value.Cmp(minInt128) < 0 || v.Value.Cmp(maxInt128) > 0 {
+			return nil, errors.New("clarity int out of 128-bit range")
+		}
+		return append(buf, toTwosComplement(v.Value, 16)...), nil
+	case UInt:
+		if v.Value == nil || v.Value.Sign() < 0 || v.Value.Cmp(maxUInt128) > 0 {
+			return nil, errors.New("clarity uint out of 128-bit range")
+		}
+		return append(buf, v.Value.FillBytes(make([]byte, 16))...), nil
+	case Buffer:
+		return appendLengthPrefixed(buf, v)
+	case Bool, None:
+		return buf, nil
+	case StandardPrincipal:
+		return appendStandardPrincipal(buf, v), nil
+	case ContractPrincipal:
+		return appendName(appendStandardPrincipal(buf, v.Issuer), v.Name)
+	case ResponseOk:
+		return appendValue(buf, v.Value, depth+1)
+	case ResponseErr:
+		return appendValue(buf, v.Value, depth+1)
+	case Some:
+		return appendValue(buf, v.Value, depth+1)
+	case List:
+		buf = binary.BigEndian.AppendUint32(buf, uint32(len(v)))
+		var err error
+		for _, item := range v {
+			if buf, err = appendValue(buf, item, depth+1); err != nil {
+				return nil, err
+			}
+		}
+		return buf, nil
+	case Tuple:
+		entries := append(Tuple(nil), v...)
+		sort.SliceStable(entries, func(i, j int) bool { return entries[i].Name < entries[j].Name })
+		buf = binary.BigEndian.AppendUint32(buf, uint32(len(entries)))
+		var err error
+		for i, entry := range entries {
+			if i > 0 && entry.Name == entries[i-1].Name {
+				return nil, fmt.Errorf("duplicate clarity tuple field %q", entry.Name)
+			}
+			if buf, err = appendName(buf, entry.Name); err != nil {
+				return nil, err
+			}
+			if buf, err = appendValue(buf, entry.Value, depth+1); err != nil {
+				return nil, err
+			}
+		}
+		return buf, nil
+	case StringASCII:
+		for i := 0; i < len(v); i++ {
+			if c := v[i]; c > 0x7e || (c < 0x20 && c != '\t' && c != '\n' && c != '\f' && c != '\r') {
+				return nil, errors.New("invalid character in clarity ascii string")
+			}
+		}
+		return appendLengthPrefixed(buf, []byte(v))
+	case StringUTF8:
+		if !utf8.ValidString(string(v)) {
+			return nil, errors.New("invalid clarity utf8 string")
+		}
+		return appendLengthPrefixed(buf, []byte(v))
+	default:
+		return nil, fmt.Errorf("unsupported clarity value %T", v)
+	}
+}
+
+// appendName appends a length-prefixed (one byte) contract or tuple field name
+func appendName(buf []byte, name string) ([]byte, error) {
+	if len(name) > 128 {
+		return nil, fmt.Errorf("clarity name too long: %d", len(name))
+	}
+	buf = append(buf, byte(len(name)))
+	return append(buf, name...), nil
+}
+
+// appendStandardPrincipal appends a version byte and hash160
+func appendStandardPrincipal(buf []byte, p StandardPrincipal) []byte {
+	buf = append(buf, p.Version)
+	return append(buf, p.Hash160[:]...)
+}
+
+// appendLengthPrefixed appends a u32 length followed by b
+func appendLengthPrefixed(buf []byte, b []byte) ([]byte, error) {
+	if uint64(len(b)) > 0xffffffff {
+		return nil, errors.New("clarity value too long")
+	}
+	buf = binary.BigEndian.AppendUint32(buf, uint32(len(b)))
+	return append(buf, b...), nil
+}
+
+// toTwosComplement returns v as a size-byte big-endian two's complement integer
+func toTwosComplement(v *big.Int, size int) []byte {
+	n := new(big.Int).Set(v)
+	if n.Sign() < 0 {
+		n.Add(n, new(big.Int).Lsh(big.NewInt(1), uint(size*8)))
+	}
+	return n.FillBytes(make([]byte, size))
+}
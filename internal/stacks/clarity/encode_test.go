@@ -0,0 +1,128 @@
+package clarity
+
+import (
+	"encoding/hex"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEncode_Integers(t *testing.T) {
+	b, err := Encode(NewUInt(1000000))
+	require.NoError(t, err)
+	assert.Equal(t, "01000000000000000000000000000f4240", hex.EncodeToString(b))
+
+	b, err = Encode(NewInt(-1))
+	require.NoError(t, err)
+	assert.Equal(t, "00ffffffffffffffffffffffffffffffff", hex.EncodeToString(b))
+
+	// u128 values beyond uint64 survive a round trip
+	v, err := Decode(mustHex(t, "01ffffffffffffffffffffffffffffffff"))
+	require.NoError(t, err)
+	assert.Equal(t, 0, v.(UInt).Value.Cmp(maxUInt128))
+	b, err = Encode(v)
+	require.NoError(t, err)
+	assert.Equal(t, "01ffffffffffffffffffffffffffffffff", hex.EncodeToString(b))
+
+	b, err = Encode(Int{Value: minInt128})
+	require.NoError(t, err)
+	assert.Equal(t, "0080000000000000000000000000000000", hex.EncodeToString(b))
+}
+
+func TestEncode_RoundTrip(t *testing.T) {
+	issuer := StandardPrincipal{Version: 26}
+	copy(issuer.Hash160[:], mustHex(t, "6d78de7b0625dfbfc16c3a8a5735f6dc3dc3f2ce"))
+
+	values := []Value{
+		NewInt(-42),
+		NewUInt(10),
+		Buffer{0x01, 0x02},
+		Bool(true),
+		Bool(false),
+		issuer,
+		ContractPrincipal{Issuer: issuer, Name: "usdcx"},
+		ResponseOk{Value: NewUInt(1)},
+		ResponseErr{Value: NewUInt(2)},
+		None{},
+		Some{Value: Buffer("memo")},
+		List{NewUInt(1), NewUInt(2)},
+		Tuple{{Name: "a", Value: NewUInt(1)}, {Name: "b", Value: StringASCII("hi")}},
+		StringASCII("hello\n"),
+		StringUTF8("héllo"),
+	}
+
+	for _, v := range values {
+		b, err := Encode(v)
+		require.NoError(t, err, "%#v", v)
+		decoded, err := Decode(b)
+		require.NoError(t, err, "%#v", v)
+		assert.Equal(t, v, decoded)
+	}
+}
+
+func TestEncode_TupleSortedByName(t *testing.T) {
+	tuple := Tuple{{Name: "b", Value: Buffer{0x01, 0x02}}, {Name: "a", Value: NewUInt(1)}}
+
+	b, err := Encode(tuple)
+	require.NoError(t, err)
+	assert.Equal(t, "0c00000002"+
+		"0161"+"0100000000000000000000000000000001"+
+		"0162"+"02000000020102", hex.EncodeToString(b))
+
+	// The caller's tuple is left in place
+	assert.Equal(t, "b", tuple[0].Name)
+}
+
+func TestEncode_Errors(t *testing.T) {
+	tests := []struct {
+		name  string
+		value Value
+	}{
+		{"nil", nil},
+		{"nil in list", List{nil}},
+		{"nil uint", UInt{}},
+		{"negative uint", UInt{Value: big.NewInt(-1)}},
+		{"uint above 128 bits", UInt{Value: new(big.Int).Lsh(big.NewInt(1), 128)}},
+		{"int above 127 bits", Int{Value: new(big.Int).Lsh(big.NewInt(1), 127)}},
+		{"long contract name", ContractPrincipal{Name: string(make([]byte, 129))}},
+		{"duplicate tuple field", Tuple{{Name: "a", Value: NewUInt(1)}, {Name: "a", Value: NewUInt(2)}}},
+		{"non-ascii string", StringASCII("\x80")},
+		{"invalid utf8 string", StringUTF8("\xff")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Encode(tt.value)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestEncode_TooDeep(t *testing.T) {
+	var v Value = None{}
+	for i := 0; i < maxDepth+2; i++ {
+		v = Some{Value: v}
+	}
+
+	_, err := Encode(v)
+	assert.Error(t, err)
+}
+
+func TestEncodeHex_DecodeHex(t *testing.T) {
+	s, err := EncodeHex(Some{Value: NewUInt(7)})
+	require.NoError(t, err)
+	assert.Equal(t, "0x0a0100000000000000000000000000000007", s)
+
+	v, err := DecodeHex(s)
+	require.NoError(t, err)
+	assert.Equal(t, Some{Value: NewUInt(7)}, v)
+
+	v, err = DecodeHex("09")
+	require.NoError(t, err)
+	assert.Equal(t, None{}, v)
+
+	_, err = DecodeHex("0xzz")
+	assert.Error(t, err)
+}
@@ -0,0 +1,152 @@
+package clarity
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/x402stacks/stacks-facilitator/internal/payment/domain/valueobject"
+)
+
+// Type is the type prefix byte of a serialized Clarity value
+type Type byte
+
+const (
+	TypeInt               Type = 0x00
+	TypeUInt              Type = 0x01
+	TypeBuffer            Type = 0x02
+	TypeTrue              Type = 0x03
+	TypeFalse             Type = 0x04
+	TypeStandardPrincipal Type = 0x05
+	TypeContractPrincipal Type = 0x06
+	TypeResponseOk        Type = 0x07
+	TypeResponseErr       Type = 0x08
+	TypeNone              Type = 0x09
+	TypeSome              Type = 0x0a
+	TypeList              Type = 0x0b
+	TypeTuple             Type = 0x0c
+	TypeStringASCII       Type = 0x0d
+	TypeStringUTF8        Type = 0x0e
+)
+
+// Value is a decoded Clarity value
+type Value interface {
+	Type() Type
+}
+
+// Int is a signed 128-bit integer
+type Int struct {
+	Value *big.Int
+}
+
+// UInt is an unsigned 128-bit integer
+type UInt struct {
+	Value *big.Int
+}
+
+// Buffer is a byte buffer
+type Buffer []byte
+
+// Bool is a boolean
+type Bool bool
+
+// StandardPrincipal is an account principal
+type StandardPrincipal struct {
+	Version byte
+	Hash160 [20]byte
+}
+
+// ContractPrincipal is a smart contract principal
+type ContractPrincipal struct {
+	Issuer StandardPrincipal
+	Name   string
+}
+
+// ResponseOk is an (ok ...) response
+type ResponseOk struct {
+	Value Value
+}
+
+// ResponseErr is an (err ...) response
+type ResponseErr struct {
+	Value Value
+}
+
+// None is the empty optional
+type None struct{}
+
+// Some is a present optional
+type Some struct {
+	Value Value
+}
+
+// List is a list of values
+type List []Value
+
+// TupleEntry is a named tuple field
+type TupleEntry struct {
+	Name  string
+	Value Value
+}
+
+// Tuple is a tuple with fields in serialized order
+type Tuple []TupleEntry
+
+// StringASCII is an ASCII string
+type StringASCII string
+
+// StringUTF8 is a UTF-8 string
+type StringUTF8 string
+
+func (Int) Type() Type               { return TypeInt }
+func (UInt) Type() Type              { return TypeUInt }
+func (Buffer) Type() Type            { return TypeBuffer }
+func (StandardPrincipal) Type() Type { return TypeStandardPrincipal }
+func (ContractPrincipal) Type() Type { return TypeContractPrincipal }
+func (ResponseOk) Type() Type        { return TypeResponseOk }
+func (ResponseErr) Type() Type       { return TypeResponseErr }
+func (None) Type() Type              { return TypeNone }
+func (Some) Type() Type              { return TypeSome }
+func (List) Type() Type              { return TypeList }
+func (Tuple) Type() Type             { return TypeTuple }
+func (StringASCII) Type() Type       { return TypeStringASCII }
+func (StringUTF8) Type() Type        { return TypeStringUTF8 }
+
+// Type returns TypeTrue or TypeFalse
+func (b Bool) Type() Type {
+	if b {
+		return TypeTrue
+	}
+	return TypeFalse
+}
+
+// String returns the c32check address of the principal
+func (p StandardPrincipal) String() string {
+	return valueobject.NewStacksAddressFromHash160(p.Version, p.Hash160).String()
+}
+
+// String returns the principal as address.contract-name
+func (p ContractPrincipal) String() string {
+	return fmt.Sprintf("%s.%s", p.Issuer.String(), p.Name)
+}
+
+// Get returns the value of a tuple field by name
+func (t Tuple) Get(name string) (Value, bool) {
+	for _, entry := range t {
+		if entry.Name == name {
+			return entry.Value, true
+		}
+	}
+	return nil, false
+}
+
+// PrincipalString returns the string form of a standard or contract principal
+func PrincipalString(v Value) (string, bool) {
+	switch p := v.(type) {
+	case StandardPrincipal:
+		return p.String(), true
+	case ContractPrincipal:
+		return p.String(), true
+	default:
+		return "", false
+	}
+}
@@ -8,6 +8,7 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"math/big"
 	"net/http"
 	"strconv"
 	"strings"
@@ -15,19 +16,41 @@ import (
 
 	"github.com/x402stacks/stacks-facilitator/internal/payment/domain/service"
 	"github.com/x402stacks/stacks-facilitator/internal/payment/domain/valueobject"
+	"github.com/x402stacks/stacks-facilitator/internal/stacks/clarity"
+	"github.com/x402stacks/stacks-facilitator/internal/stacks/transaction"
 )
 
 // TransactionResponse represents the API response for a transaction
 type TransactionResponse struct {
-	TxID          string             `json:"tx_id"`
-	TxStatus      string             `json:"tx_status"`
-	TxType        string             `json:"tx_type"`
-	BlockHeight   uint64             `json:"block_height"`
-	Fee           string             `json:"fee_rate"`
-	Nonce         uint64             `json:"nonce"`
-	SenderAddress string             `json:"sender_address"`
-	TokenTransfer *TokenTransferData `json:"token_transfer,omitempty"`
-	ContractCall  *ContractCallData  `json:"contract_call,omitempty"`
+	TxID            string             `json:"tx_id"`
+	TxStatus        string             `json:"tx_status"`
+	ReplacedByTxID  string             `json:"replaced_by_tx_id,omitempty"` // Set on a mempool tx dropped in favour of another
+	TxType          string             `json:"tx_type"`
+	BlockHeight     uint64             `json:"block_height"`
+	BurnBlockHeight uint64             `json:"burn_block_height"`
+	Fee             string             `json:"fee_rate"`
+	Nonce           uint64             `json:"nonce"`
+	SenderAddress   string             `json:"sender_address"`
+	TokenTransfer   *TokenTransferData `json:"token_transfer,omitempty"`
+	ContractCall    *ContractCallData  `json:"contract_call,omitempty"`
+	EventCount      int                `json:"event_count"`
+	Events          []TransactionEvent `json:"events"`
+}
+
+// TransactionEvent represents an event emitted by a transaction
+type TransactionEvent struct {
+	EventIndex int         `json:"event_index"`
+	EventType  string      `json:"event_type"` // stx_asset, fungible_token_asset, smart_contract_log, ...
+	Asset      *EventAsset `json:"asset,omitempty"`
+}
+
+// EventAsset represents the asset movement of an stx_asset or fungible_token_asset event
+type EventAsset struct {
+	AssetEventType string `json:"asset_event_type"` // transfer, mint or burn
+	AssetID        string `json:"asset_id"`         // contract::asset, fungible tokens only
+	Sender         string `json:"sender"`
+	Recipient      string `json:"recipient"`
+	Amount         string `json:"amount"`
 }
 
 // TokenTransferData represents STX transfer data
@@ -52,6 +75,78 @@ type ContractFunctionArgRaw struct {
 	Name string `json:"name"`
 }
 
+// AccountResponse represents the API response for an account
+type AccountResponse struct {
+	Balance string `json:"balance"` // Hex-encoded microSTX
+	Nonce   uint64 `json:"nonce"`
+}
+
+// Account is the spendable STX balance and next nonce of an address
+type Account struct {
+	Balance uint64
+	Nonce   uint64
+}
+
+// NonceInfo represents the API response for an address's nonces
+type NonceInfo struct {
+	LastExecutedTxNonce   *uint64  `json:"last_executed_tx_nonce"`
+	LastMempoolTxNonce    *uint64  `json:"last_mempool_tx_nonce"`
+	PossibleNextNonce     uint64   `json:"possible_next_nonce"`
+	DetectedMissingNonces []uint64 `json:"detected_missing_nonces"`
+}
+
+// ErrTransactionNotFound is returned when the API has no transaction with the requested ID
+var ErrTransactionNotFound = errors.New("transaction not found")
+
+// APIError is returned when the API answers a transaction lookup with an unexpected status
+type APIError struct {
+	StatusCode int
+	Body       string
+}
+
+// Error returns the raw response body
+func (e *APIError) Error() string {
+	return "API error: " + e.Body
+}
+
+// Transient reports whether the API was rate limiting or failing rather than refusing the
+// request, so the same lookup may succeed later
+func (e *APIError) Transient() bool {
+	return e.StatusCode == http.StatusTooManyRequests || e.StatusCode >= http.StatusInternalServerError
+}
+
+// BroadcastError is returned when the node does not accept a transaction
+type BroadcastError struct {
+	StatusCode int
+	Message    string          // Node's error message, such as "transaction rejected"
+	Reason     string          // Node rejection reason such as BadNonce; empty if the body was not a rejection
+	ReasonData json.RawMessage // Reason-specific details
+	TxID       string
+	Body       string
+}
+
+// Error returns the raw rejection body
+func (e *BroadcastError) Error() string {
+	return "broadcast failed: " + e.Body
+}
+
+// InfoResponse represents the node's /v2/info response
+type InfoResponse struct {
+	StacksTipHeight uint64 `json:"stacks_tip_height"`
+	BurnBlockHeight uint64 `json:"burn_block_height"`
+}
+
+// ReadOnlyResponse represents the API response for a read-only contract call
+type ReadOnlyResponse struct {
+	Okay   bool   `json:"okay"`
+	Result string `json:"result"` // Hex-encoded Clarity value when okay
+	Cause  string `json:"cause"`  // Failure reason when not okay
+}
+
+// eventPageSize is the number of events requested per page when a transaction has more
+// events than the API returned inline
+const eventPageSize = 50
+
 // Client is a Stacks blockchain API client
 type Client struct {
 	baseURL    string
@@ -60,10 +155,15 @@ type Client struct {
 
 // NewClient creates a new Stacks client
 func NewClient(baseURL string) *Client {
+	return NewClientWithTimeout(baseURL, 30*time.Second)
+}
+
+// NewClientWithTimeout creates a new Stacks client with a custom HTTP timeout
+func NewClientWithTimeout(baseURL string, timeout time.Duration) *Client {
 	return &Client{
-		baseURL: baseURL,
+		baseURL: strings.TrimSuffix(baseURL, "/"),
 		httpClient: &http.Client{
-			Timeout: 30 * time.Second,
+			Timeout: timeout,
 		},
 	}
 }
@@ -75,69 +175,108 @@ func NewClientForNetwork(network valueobject.Network) *Client {
 
 // GetTransaction fetches a transaction by ID
 func (c *Client) GetTransaction(ctx context.Context, txID valueobject.TransactionID) (service.BlockchainTransaction, error) {
+	txResp, err := c.fetchTransaction(ctx, txID)
+	if err != nil {
+		return service.BlockchainTransaction{}, err
+	}
+
+	return c.parseTransactionResponse(txResp, valueobject.TokenSTX)
+}
+
+// GetTransactionWithTokenType fetches a transaction and parses it for a specific token type
+func (c *Client) GetTransactionWithTokenType(ctx context.Context, txID valueobject.TransactionID, tokenType valueobject.TokenType, network valueobject.Network) (service.BlockchainTransaction, error) {
+	txResp, err := c.fetchTransaction(ctx, txID)
+	if err != nil {
+		return service.BlockchainTransaction{}, err
+	}
+
+	return c.parseTransactionResponse(txResp, tokenType)
+}
+
+// TransactionExists reports whether the API knows a transaction, without fetching or
+// parsing it: 200 is true, 404 is false, and any other status is an *APIError
+func (c *Client) TransactionExists(ctx context.Context, txID valueobject.TransactionID) (bool, error) {
 	url := fmt.Sprintf("%s/extended/v1/tx/%s", c.baseURL, txID.String())
 
 	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
 	if err != nil {
-		return service.BlockchainTransaction{}, fmt.Errorf("failed to create request: %w", err)
+		return false, fmt.Errorf("failed to create request: %w", err)
 	}
 
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
-		return service.BlockchainTransaction{}, fmt.Errorf("failed to fetch transaction: %w", err)
+		return false, fmt.Errorf("failed to fetch transaction: %w", err)
 	}
 	defer resp.Body.Close()
 
-	if resp.StatusCode == http.StatusNotFound {
-		return service.BlockchainTransaction{}, errors.New("transaction not found")
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return true, nil
+	case http.StatusNotFound:
+		return false, nil
+	default:
+		body, _ := io.ReadAll(resp.Body)
+		return false, &APIError{StatusCode: resp.StatusCode, Body: string(body)}
 	}
+}
 
-	if resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(resp.Body)
-		return service.BlockchainTransaction{}, fmt.Errorf("API error: %s", string(body))
+// fetchTransaction fetches a transaction with all of its events
+func (c *Client) fetchTransaction(ctx context.Context, txID valueobject.TransactionID) (TransactionResponse, error) {
+	txResp, err := c.fetchTransactionPage(ctx, txID, "")
+	if err != nil {
+		return TransactionResponse{}, err
 	}
 
-	var txResp TransactionResponse
-	if err := json.NewDecoder(resp.Body).Decode(&txResp); err != nil {
-		return service.BlockchainTransaction{}, fmt.Errorf("failed to decode response: %w", err)
+	// The API only returns the first events inline; page through the rest
+	for len(txResp.Events) < txResp.EventCount {
+		page, err := c.fetchTransactionPage(ctx, txID, fmt.Sprintf("?event_offset=%d&event_limit=%d", len(txResp.Events), eventPageSize))
+		if err != nil {
+			return TransactionResponse{}, err
+		}
+		if len(page.Events) == 0 {
+			return TransactionResponse{}, fmt.Errorf("expected %d events, got %d", txResp.EventCount, len(txResp.Events))
+		}
+		txResp.Events = append(txResp.Events, page.Events...)
 	}
 
-	return c.parseTransactionResponse(txResp, valueobject.TokenSTX)
+	return txResp, nil
 }
 
-// GetTransactionWithTokenType fetches a transaction and parses it for a specific token type
-func (c *Client) GetTransactionWithTokenType(ctx context.Context, txID valueobject.TransactionID, tokenType valueobject.TokenType, network valueobject.Network) (service.BlockchainTransaction, error) {
-	url := fmt.Sprintf("%s/extended/v1/tx/%s", c.baseURL, txID.String())
+// fetchTransactionPage fetches /extended/v1/tx/{txid} with an optional query string
+func (c *Client) fetchTransactionPage(ctx context.Context, txID valueobject.TransactionID, query string) (TransactionResponse, error) {
+	url := fmt.Sprintf("%s/extended/v1/tx/%s%s", c.baseURL, txID.String(), query)
 
 	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
 	if err != nil {
-		return service.BlockchainTransaction{}, fmt.Errorf("failed to create request: %w", err)
+		return TransactionResponse{}, fmt.Errorf("failed to create request: %w", err)
 	}
 
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
-		return service.BlockchainTransaction{}, fmt.Errorf("failed to fetch transaction: %w", err)
+		return TransactionResponse{}, fmt.Errorf("failed to fetch transaction: %w", err)
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode == http.StatusNotFound {
-		return service.BlockchainTransaction{}, errors.New("transaction not found")
+		return TransactionResponse{}, ErrTransactionNotFound
 	}
 
 	if resp.StatusCode != http.StatusOK {
 		body, _ := io.ReadAll(resp.Body)
-		return service.BlockchainTransaction{}, fmt.Errorf("API error: %s", string(body))
+		return TransactionResponse{}, &APIError{StatusCode: resp.StatusCode, Body: string(body)}
 	}
 
 	var txResp TransactionResponse
 	if err := json.NewDecoder(resp.Body).Decode(&txResp); err != nil {
-		return service.BlockchainTransaction{}, fmt.Errorf("failed to decode response: %w", err)
+		return TransactionResponse{}, fmt.Errorf("failed to decode response: %w", err)
 	}
 
-	return c.parseTransactionResponse(txResp, tokenType)
+	return txResp, nil
 }
 
-// BroadcastTransaction broadcasts a signed transaction to the network
+// BroadcastTransaction broadcasts a signed transaction to the network. The txid is the hash
+// of the transaction bytes, so it is computed locally; the node's answer is only checked
+// against it.
 func (c *Client) BroadcastTransaction(ctx context.Context, signedTx string) (valueobject.TransactionID, error) {
 	url := fmt.Sprintf("%s/v2/transactions", c.baseURL)
 
@@ -149,6 +288,7 @@ func (c *Client) BroadcastTransaction(ctx context.Context, signedTx string) (val
 	if err != nil {
 		return valueobject.TransactionID{}, fmt.Errorf("invalid transaction hex: %w", err)
 	}
+	txID := transaction.TxID(txBytes)
 
 	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(txBytes))
 	if err != nil {
@@ -168,17 +308,211 @@ func (c *Client) BroadcastTransaction(ctx context.Context, signedTx string) (val
 	}
 
 	if resp.StatusCode != http.StatusOK {
-		return valueobject.TransactionID{}, fmt.Errorf("broadcast failed: %s", string(body))
+		return valueobject.TransactionID{}, newBroadcastError(resp.StatusCode, body)
+	}
+
+	// The node answers with the txid as a JSON string. Any other txid means it accepted
+	// different bytes from the ones sent.
+	var nodeTxID string
+	if err := json.Unmarshal(body, &nodeTxID); err == nil && !sameTxID(nodeTxID, txID) {
+		return valueobject.TransactionID{}, fmt.Errorf("node returned txid %s for transaction %s", nodeTxID, txID)
+	}
+
+	return txID, nil
+}
+
+// sameTxID reports whether a txid from the node, with or without 0x, is txID
+func sameTxID(nodeTxID string, txID valueobject.TransactionID) bool {
+	return strings.EqualFold(strings.TrimPrefix(nodeTxID, "0x"), strings.TrimPrefix(txID.String(), "0x"))
+}
+
+// GetAccount fetches the STX balance and next nonce of an address
+func (c *Client) GetAccount(ctx context.Context, address string) (Account, error) {
+	url := fmt.Sprintf("%s/v2/accounts/%s?proof=0", c.baseURL, address)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return Account{}, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return Account{}, fmt.Errorf("failed to fetch account: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return Account{}, fmt.Errorf("API error: %s", string(body))
+	}
+
+	var accountResp AccountResponse
+	if err := json.NewDecoder(resp.Body).Decode(&accountResp); err != nil {
+		return Account{}, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	balance, ok := new(big.Int).SetString(strings.TrimPrefix(accountResp.Balance, "0x"), 16)
+	if !ok || !balance.IsUint64() {
+		return Account{}, fmt.Errorf("invalid balance: %s", accountResp.Balance)
+	}
+
+	return Account{Balance: balance.Uint64(), Nonce: accountResp.Nonce}, nil
+}
+
+// GetNonces fetches the nonce state of an address, including transactions in the mempool
+func (c *Client) GetNonces(ctx context.Context, address string) (NonceInfo, error) {
+	url := fmt.Sprintf("%s/extended/v1/address/%s/nonces", c.baseURL, address)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return NonceInfo{}, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return NonceInfo{}, fmt.Errorf("failed to fetch nonces: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return NonceInfo{}, fmt.Errorf("API error: %s", string(body))
+	}
+
+	var info NonceInfo
+	if err := json.NewDecoder(resp.Body).Decode(&info); err != nil {
+		return NonceInfo{}, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	return info, nil
+}
+
+// GetFeeRate fetches the current fee rate in microSTX per byte
+func (c *Client) GetFeeRate(ctx context.Context) (uint64, error) {
+	url := fmt.Sprintf("%s/v2/fees/transfer", c.baseURL)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return 0, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return 0, fmt.Errorf("failed to fetch fee rate: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return 0, fmt.Errorf("API error: %s", string(body))
+	}
+
+	var rate uint64
+	if err := json.NewDecoder(resp.Body).Decode(&rate); err != nil {
+		return 0, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	return rate, nil
+}
+
+// GetChainTip fetches the heights of the latest Stacks block and Bitcoin burn block
+func (c *Client) GetChainTip(ctx context.Context) (service.ChainTip, error) {
+	url := fmt.Sprintf("%s/v2/info", c.baseURL)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return service.ChainTip{}, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return service.ChainTip{}, fmt.Errorf("failed to fetch chain tip: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return service.ChainTip{}, fmt.Errorf("API error: %s", string(body))
+	}
+
+	var info InfoResponse
+	if err := json.NewDecoder(resp.Body).Decode(&info); err != nil {
+		return service.ChainTip{}, fmt.Errorf("failed to decode response: %w", err)
+	}
+	if info.StacksTipHeight == 0 {
+		return service.ChainTip{}, errors.New("chain tip height missing from response")
+	}
+
+	return service.ChainTip{StacksHeight: info.StacksTipHeight, BurnHeight: info.BurnBlockHeight}, nil
+}
+
+// CallReadOnly calls a read-only function of a contract as sender and decodes the result
+func (c *Client) CallReadOnly(ctx context.Context, contractID, function, sender string, args ...clarity.Value) (clarity.Value, error) {
+	address, name, ok := strings.Cut(contractID, ".")
+	if !ok {
+		return nil, fmt.Errorf("invalid contract ID: %s", contractID)
+	}
+	url := fmt.Sprintf("%s/v2/contracts/call-read/%s/%s/%s", c.baseURL, address, name, function)
+
+	arguments := make([]string, len(args))
+	for i, arg := range args {
+		encoded, err := clarity.EncodeHex(arg)
+		if err != nil {
+			return nil, fmt.Errorf("invalid argument %d: %w", i+1, err)
+		}
+		arguments[i] = encoded
+	}
+	payload, err := json.Marshal(map[string]any{"sender": sender, "arguments": arguments})
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(payload))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to call %s.%s: %w", contractID, function, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("API error: %s", string(body))
 	}
 
-	// Parse transaction ID from response (comes as JSON string with quotes)
-	var txIDStr string
-	if err := json.Unmarshal(body, &txIDStr); err != nil {
-		// Try without quotes
-		txIDStr = strings.Trim(string(body), "\"")
+	var result ReadOnlyResponse
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+	if !result.Okay {
+		return nil, fmt.Errorf("read-only call %s.%s failed: %s", contractID, function, result.Cause)
 	}
 
-	return valueobject.NewTransactionID(txIDStr)
+	return clarity.DecodeHex(result.Result)
+}
+
+// newBroadcastError parses a node rejection body, keeping the raw body when it is not JSON
+func newBroadcastError(statusCode int, body []byte) *BroadcastError {
+	broadcastErr := &BroadcastError{StatusCode: statusCode, Body: string(body)}
+
+	var rejection struct {
+		Error      string          `json:"error"`
+		Reason     string          `json:"reason"`
+		ReasonData json.RawMessage `json:"reason_data"`
+		TxID       string          `json:"txid"`
+	}
+	if err := json.Unmarshal(body, &rejection); err == nil {
+		broadcastErr.Message = rejection.Error
+		broadcastErr.Reason = rejection.Reason
+		broadcastErr.ReasonData = rejection.ReasonData
+		broadcastErr.TxID = rejection.TxID
+	}
+
+	return broadcastErr
 }
 
 // parseTransactionResponse converts API response to domain model
@@ -193,85 +527,194 @@ func (c *Client) parseTransactionResponse(resp TransactionResponse, tokenType va
 		return service.BlockchainTransaction{}, fmt.Errorf("invalid sender address: %w", err)
 	}
 
-	var recipient valueobject.StacksAddress
+	// A status the API added or renamed is treated as pending rather than failing the
+	// lookup: the transaction stays unconfirmed and settlement keeps polling it. The raw
+	// status is kept as UnknownStatus for the caller to report.
+	var unknownStatus string
+	status, err := valueobject.NewTransactionStatus(resp.TxStatus)
+	if err != nil {
+		unknownStatus = resp.TxStatus
+		status = valueobject.TxStatusPending
+	}
+
+	var replacedBy valueobject.TransactionID
+	if status.IsDropped() && resp.ReplacedByTxID != "" {
+		replacedBy, err = valueobject.NewTransactionID(resp.ReplacedByTxID)
+		if err != nil {
+			return service.BlockchainTransaction{}, fmt.Errorf("invalid replaced_by_tx_id: %w", err)
+		}
+	}
+
+	var recipient valueobject.Principal
 	var amount valueobject.Amount
 	var memo string
+	var contractID string
 
 	// Parse based on transaction type
 	if resp.TxType == "token_transfer" && resp.TokenTransfer != nil {
-		recipient, err = valueobject.NewStacksAddress(resp.TokenTransfer.RecipientAddress)
+		recipient, err = valueobject.NewPrincipal(resp.TokenTransfer.RecipientAddress)
 		if err != nil {
 			return service.BlockchainTransaction{}, fmt.Errorf("invalid recipient address: %w", err)
 		}
 
-		amountVal, err := strconv.ParseUint(resp.TokenTransfer.Amount, 10, 64)
+		amount, err = valueobject.ParseAmount(resp.TokenTransfer.Amount)
 		if err != nil {
 			return service.BlockchainTransaction{}, fmt.Errorf("invalid amount: %w", err)
 		}
-		amount = valueobject.NewAmount(amountVal)
 		memo = resp.TokenTransfer.Memo
 	} else if resp.TxType == "contract_call" && resp.ContractCall != nil {
-		// Parse SIP-010 transfer
-		parsedRecipient, parsedAmount, parsedMemo, err := parseSIP010Transfer(resp.ContractCall)
-		if err != nil {
-			return service.BlockchainTransaction{}, err
+		// Parse SIP-010 transfer. Other calls (routers, wrappers) leave the recipient empty
+		// and are verified from their transfer events once confirmed.
+		if resp.ContractCall.FunctionName == "transfer" {
+			parsedRecipient, parsedAmount, parsedMemo, err := parseSIP010Transfer(resp.ContractCall)
+			if err != nil {
+				return service.BlockchainTransaction{}, err
+			}
+			recipient = parsedRecipient
+			amount = parsedAmount
+			memo = parsedMemo
 		}
-		recipient = parsedRecipient
-		amount = parsedAmount
-		memo = parsedMemo
+		contractID = resp.ContractCall.ContractID
 	} else {
 		return service.BlockchainTransaction{}, errors.New("unsupported transaction type")
 	}
 
 	fee, _ := strconv.ParseUint(resp.Fee, 10, 64)
 
+	var transfers []service.AssetTransfer
+	if hasEvents(resp) {
+		transfers, err = parseTransferEvents(resp.Events)
+		if err != nil {
+			return service.BlockchainTransaction{}, err
+		}
+	}
+
 	return service.BlockchainTransaction{
-		TxID:        txID,
-		TokenType:   tokenType,
-		Sender:      sender,
-		Recipient:   recipient,
-		Amount:      amount,
-		Fee:         valueobject.NewAmount(fee),
-		Nonce:       resp.Nonce,
-		BlockHeight: resp.BlockHeight,
-		Memo:        memo,
-		Status:      resp.TxStatus,
-		IsConfirmed: IsTransactionConfirmed(resp.TxStatus, resp.BlockHeight),
+		TxID:            txID,
+		TokenType:       tokenType,
+		Sender:          sender,
+		Recipient:       recipient,
+		ContractID:      contractID,
+		Amount:          amount,
+		Fee:             valueobject.NewAmount(fee),
+		Nonce:           resp.Nonce,
+		BlockHeight:     resp.BlockHeight,
+		BurnBlockHeight: resp.BurnBlockHeight,
+		Memo:            memo,
+		Status:          status,
+		UnknownStatus:   unknownStatus,
+		ReplacedBy:      replacedBy,
+		IsConfirmed:     IsTransactionConfirmed(resp.TxStatus, resp.BlockHeight),
+		Transfers:       transfers,
 	}, nil
 }
 
-// parseSIP010Transfer parses a SIP-010 contract call (sBTC, USDCx)
-func parseSIP010Transfer(call *ContractCallData) (valueobject.StacksAddress, valueobject.Amount, string, error) {
+// hasEvents reports whether the transaction has been executed, so its event list is final,
+// and the API returned events. Without them the decoded transfer is checked instead.
+func hasEvents(resp TransactionResponse) bool {
+	return resp.BlockHeight > 0 && resp.TxStatus != "pending" && len(resp.Events) > 0
+}
+
+// parseTransferEvents extracts the STX and fungible token transfers from a transaction's events.
+// Mints, burns and other event types are ignored.
+func parseTransferEvents(events []TransactionEvent) ([]service.AssetTransfer, error) {
+	transfers := make([]service.AssetTransfer, 0, len(events))
+	for _, event := range events {
+		if event.Asset == nil || event.Asset.AssetEventType != "transfer" {
+			continue
+		}
+
+		var asset string
+		switch event.EventType {
+		case "stx_asset":
+			asset = valueobject.TokenSTX.String()
+		case "fungible_token_asset":
+			asset = event.Asset.AssetID
+		default:
+			continue
+		}
+
+		sender, err := valueobject.NewPrincipal(event.Asset.Sender)
+		if err != nil {
+			return nil, fmt.Errorf("invalid sender in event %d: %w", event.EventIndex, err)
+		}
+		recipient, err := valueobject.NewPrincipal(event.Asset.Recipient)
+		if err != nil {
+			return nil, fmt.Errorf("invalid recipient in event %d: %w", event.EventIndex, err)
+		}
+		amount, err := valueobject.ParseAmount(event.Asset.Amount)
+		if err != nil {
+			return nil, fmt.Errorf("invalid amount in event %d: %w", event.EventIndex, err)
+		}
+
+		transfers = append(transfers, service.AssetTransfer{
+			Asset:     asset,
+			Sender:    sender,
+			Recipient: recipient,
+			Amount:    amount,
+		})
+	}
+	return transfers, nil
+}
+
+// parseSIP010Transfer parses a SIP-010 contract call (sBTC, USDCx) from the
+// hex-encoded Clarity arguments. The called contract is not checked here;
+// verification binds it to the requested token.
+func parseSIP010Transfer(call *ContractCallData) (valueobject.Principal, valueobject.Amount, string, error) {
 	if call.FunctionName != "transfer" {
-		return valueobject.StacksAddress{}, valueobject.Amount{}, "", errors.New("not a transfer function")
+		return valueobject.Principal{}, valueobject.Amount{}, "", errors.New("not a transfer function")
 	}
 
-	var recipient valueobject.StacksAddress
+	var recipient valueobject.Principal
 	var amount valueobject.Amount
 	var memo string
 
 	for _, arg := range call.FunctionArgs {
+		if arg.Name != "amount" && arg.Name != "recipient" && arg.Name != "to" && arg.Name != "memo" {
+			continue
+		}
+
+		value, err := clarity.DecodeHex(arg.Hex)
+		if err != nil {
+			return valueobject.Principal{}, valueobject.Amount{}, "", fmt.Errorf("invalid %s argument: %w", arg.Name, err)
+		}
+
 		switch arg.Name {
 		case "amount":
-			amountVal, err := strconv.ParseUint(strings.TrimPrefix(arg.Repr, "u"), 10, 64)
+			amountVal, ok := value.(clarity.UInt)
+			if !ok {
+				return valueobject.Principal{}, valueobject.Amount{}, "", errors.New("invalid amount: expected a uint")
+			}
+			amount, err = valueobject.NewAmountFromBigInt(amountVal.Value)
 			if err != nil {
-				return valueobject.StacksAddress{}, valueobject.Amount{}, "", fmt.Errorf("invalid amount: %w", err)
+				return valueobject.Principal{}, valueobject.Amount{}, "", fmt.Errorf("invalid amount: %w", err)
 			}
-			amount = valueobject.NewAmount(amountVal)
 		case "recipient", "to":
-			addr := strings.TrimPrefix(arg.Repr, "'")
-			var err error
-			recipient, err = valueobject.NewStacksAddress(addr)
+			principal, ok := clarity.PrincipalString(value)
+			if !ok {
+				return valueobject.Principal{}, valueobject.Amount{}, "", errors.New("invalid recipient: expected a principal")
+			}
+			recipient, err = valueobject.NewPrincipal(principal)
 			if err != nil {
-				return valueobject.StacksAddress{}, valueobject.Amount{}, "", fmt.Errorf("invalid recipient: %w", err)
+				return valueobject.Principal{}, valueobject.Amount{}, "", fmt.Errorf("invalid recipient: %w", err)
 			}
 		case "memo":
-			memo = arg.Repr
+			switch m := value.(type) {
+			case clarity.None:
+			case clarity.Some:
+				buf, ok := m.Value.(clarity.Buffer)
+				if !ok {
+					return valueobject.Principal{}, valueobject.Amount{}, "", errors.New("invalid memo: expected a buffer")
+				}
+				memo = string(bytes.TrimRight(buf, "\x00"))
+			default:
+				return valueobject.Principal{}, valueobject.Amount{}, "", errors.New("invalid memo: expected an optional")
+			}
 		}
 	}
 
 	if recipient.IsZero() {
-		return valueobject.StacksAddress{}, valueobject.Amount{}, "", errors.New("recipient not found in contract call")
+		return valueobject.Principal{}, valueobject.Amount{}, "", errors.New("recipient not found in contract call")
 	}
 
 	return recipient, amount, memo, nil
@@ -296,3 +739,8 @@ func IsTransactionFailed(status string) bool {
 	}
 	return false
 }
+
+// IsTransactionDropped checks if a transaction was dropped from the mempool without being mined
+func IsTransactionDropped(status string) bool {
+	return valueobject.TransactionStatus(status).IsDropped()
+}
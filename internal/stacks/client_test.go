@@ -5,11 +5,13 @@ import (
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
+	"strconv"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"github.com/x402stacks/stacks-facilitator/internal/payment/domain/valueobject"
+	"github.com/x402stacks/stacks-facilitator/internal/stacks/clarity"
 )
 
 func TestClient_GetTransaction_STXTransfer(t *testing.T) {
@@ -18,13 +20,14 @@ func TestClient_GetTransaction_STXTransfer(t *testing.T) {
 		assert.Equal(t, "/extended/v1/tx/0x1234567890abcdef1234567890abcdef1234567890abcdef1234567890abcdef", r.URL.Path)
 
 		response := TransactionResponse{
-			TxID:        "0x1234567890abcdef1234567890abcdef1234567890abcdef1234567890abcdef",
-			TxStatus:    "success",
-			TxType:      "token_transfer",
-			BlockHeight: 12345,
-			Fee:         "180",
-			Nonce:       5,
-			SenderAddress: "ST2J6ZY48GV1EZ5V2V5RB9MP66SW86PYKKNRV9EJ7",
+			TxID:            "0x1234567890abcdef1234567890abcdef1234567890abcdef1234567890abcdef",
+			TxStatus:        "success",
+			TxType:          "token_transfer",
+			BlockHeight:     12345,
+			BurnBlockHeight: 880000,
+			Fee:             "180",
+			Nonce:           5,
+			SenderAddress:   "ST2J6ZY48GV1EZ5V2V5RB9MP66SW86PYKKQYAC0RQ",
 			TokenTransfer: &TokenTransferData{
 				RecipientAddress: "ST1PQHQKV0RJXZFY1DGX8MNSNYVE3VGZJSRTPGZGM",
 				Amount:           "1000000",
@@ -43,11 +46,12 @@ func TestClient_GetTransaction_STXTransfer(t *testing.T) {
 
 	require.NoError(t, err)
 	assert.Equal(t, txID.String(), tx.TxID.String())
-	assert.Equal(t, "ST2J6ZY48GV1EZ5V2V5RB9MP66SW86PYKKNRV9EJ7", tx.Sender.String())
+	assert.Equal(t, "ST2J6ZY48GV1EZ5V2V5RB9MP66SW86PYKKQYAC0RQ", tx.Sender.String())
 	assert.Equal(t, "ST1PQHQKV0RJXZFY1DGX8MNSNYVE3VGZJSRTPGZGM", tx.Recipient.String())
-	assert.Equal(t, uint64(1000000), tx.Amount.Value())
-	assert.Equal(t, uint64(180), tx.Fee.Value())
+	assert.Equal(t, "1000000", tx.Amount.String())
+	assert.Equal(t, "180", tx.Fee.String())
 	assert.Equal(t, uint64(12345), tx.BlockHeight)
+	assert.Equal(t, uint64(880000), tx.BurnBlockHeight)
 	assert.Equal(t, "test payment", tx.Memo)
 	assert.True(t, tx.IsConfirmed)
 	assert.Equal(t, valueobject.TokenSTX, tx.TokenType)
@@ -56,13 +60,13 @@ func TestClient_GetTransaction_STXTransfer(t *testing.T) {
 func TestClient_GetTransaction_PendingTransaction(t *testing.T) {
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		response := TransactionResponse{
-			TxID:        "0x1234567890abcdef1234567890abcdef1234567890abcdef1234567890abcdef",
-			TxStatus:    "pending",
-			TxType:      "token_transfer",
-			BlockHeight: 0,
-			Fee:         "180",
-			Nonce:       5,
-			SenderAddress: "ST2J6ZY48GV1EZ5V2V5RB9MP66SW86PYKKNRV9EJ7",
+			TxID:          "0x1234567890abcdef1234567890abcdef1234567890abcdef1234567890abcdef",
+			TxStatus:      "pending",
+			TxType:        "token_transfer",
+			BlockHeight:   0,
+			Fee:           "180",
+			Nonce:         5,
+			SenderAddress: "ST2J6ZY48GV1EZ5V2V5RB9MP66SW86PYKKQYAC0RQ",
 			TokenTransfer: &TokenTransferData{
 				RecipientAddress: "ST1PQHQKV0RJXZFY1DGX8MNSNYVE3VGZJSRTPGZGM",
 				Amount:           "1000000",
@@ -81,19 +85,19 @@ func TestClient_GetTransaction_PendingTransaction(t *testing.T) {
 
 	require.NoError(t, err)
 	assert.False(t, tx.IsConfirmed)
-	assert.Equal(t, "pending", tx.Status)
+	assert.Equal(t, valueobject.TxStatusPending, tx.Status)
 }
 
 func TestClient_GetTransaction_FailedTransaction(t *testing.T) {
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		response := TransactionResponse{
-			TxID:        "0x1234567890abcdef1234567890abcdef1234567890abcdef1234567890abcdef",
-			TxStatus:    "abort_by_response",
-			TxType:      "token_transfer",
-			BlockHeight: 12345,
-			Fee:         "180",
-			Nonce:       5,
-			SenderAddress: "ST2J6ZY48GV1EZ5V2V5RB9MP66SW86PYKKNRV9EJ7",
+			TxID:          "0x1234567890abcdef1234567890abcdef1234567890abcdef1234567890abcdef",
+			TxStatus:      "abort_by_response",
+			TxType:        "token_transfer",
+			BlockHeight:   12345,
+			Fee:           "180",
+			Nonce:         5,
+			SenderAddress: "ST2J6ZY48GV1EZ5V2V5RB9MP66SW86PYKKQYAC0RQ",
 			TokenTransfer: &TokenTransferData{
 				RecipientAddress: "ST1PQHQKV0RJXZFY1DGX8MNSNYVE3VGZJSRTPGZGM",
 				Amount:           "1000000",
@@ -111,7 +115,108 @@ func TestClient_GetTransaction_FailedTransaction(t *testing.T) {
 	tx, err := client.GetTransaction(ctx, txID)
 
 	require.NoError(t, err)
-	assert.Equal(t, "abort_by_response", tx.Status)
+	assert.Equal(t, valueobject.TxStatusAbortByResponse, tx.Status)
+}
+
+func TestClient_GetTransactionWithTokenType_SIP010Transfer(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		response := TransactionResponse{
+			TxID:          "0x1234567890abcdef1234567890abcdef1234567890abcdef1234567890abcdef",
+			TxStatus:      "success",
+			TxType:        "contract_call",
+			BlockHeight:   12345,
+			Fee:           "300",
+			Nonce:         7,
+			SenderAddress: "ST2J6ZY48GV1EZ5V2V5RB9MP66SW86PYKKQYAC0RQ",
+			ContractCall: &ContractCallData{
+				ContractID:   "ST1F7QA2MDF17S807EPA36TSS8AMEFY4KA9TVGWXT.sbtc-token",
+				FunctionName: "transfer",
+				FunctionArgs: []ContractFunctionArgRaw{
+					{Name: "amount", Type: "uint", Hex: "0x01000000000000000000000000000009c4", Repr: "u2500"},
+					{Name: "sender", Type: "principal", Hex: "0x051aa46ff88886c2ef9762d970b4d2c63678835bd39d", Repr: "'ST2J6ZY48GV1EZ5V2V5RB9MP66SW86PYKKQYAC0RQ"},
+					{Name: "recipient", Type: "principal", Hex: "0x051a6d78de7b0625dfbfc16c3a8a5735f6dc3dc3f2ce", Repr: "'ST1PQHQKV0RJXZFY1DGX8MNSNYVE3VGZJSRTPGZGM"},
+					{Name: "memo", Type: "(optional (buff 34))", Hex: "0x09", Repr: "none"},
+				},
+			},
+		}
+		json.NewEncoder(w).Encode(response)
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL)
+	txID, _ := valueobject.NewTransactionID("0x1234567890abcdef1234567890abcdef1234567890abcdef1234567890abcdef")
+
+	tx, err := client.GetTransactionWithTokenType(context.Background(), txID, valueobject.TokenSBTC, valueobject.NetworkTestnet)
+
+	require.NoError(t, err)
+	assert.Equal(t, "ST1F7QA2MDF17S807EPA36TSS8AMEFY4KA9TVGWXT.sbtc-token", tx.ContractID)
+	assert.Equal(t, "ST1PQHQKV0RJXZFY1DGX8MNSNYVE3VGZJSRTPGZGM", tx.Recipient.String())
+	assert.Equal(t, "2500", tx.Amount.String())
+	assert.Equal(t, valueobject.TokenSBTC, tx.TokenType)
+}
+
+func TestParseSIP010Transfer(t *testing.T) {
+	recipient := ContractFunctionArgRaw{Name: "recipient", Hex: "0x051a6d78de7b0625dfbfc16c3a8a5735f6dc3dc3f2ce"}
+	amount := ContractFunctionArgRaw{Name: "amount", Hex: "0x01000000000000000000000000000009c4"}
+
+	tests := []struct {
+		name       string
+		args       []ContractFunctionArgRaw
+		wantAmount string
+		wantMemo   string
+		wantErr    bool
+	}{
+		{
+			name:       "memo buffer",
+			args:       []ContractFunctionArgRaw{amount, recipient, {Name: "memo", Hex: "0x0a0200000005" + "6162630000"}},
+			wantAmount: "2500",
+			wantMemo:   "abc",
+		},
+		{
+			name:       "repr is ignored",
+			args:       []ContractFunctionArgRaw{{Name: "amount", Hex: amount.Hex, Repr: "u1"}, recipient},
+			wantAmount: "2500",
+		},
+		{
+			name:       "amount beyond uint64",
+			args:       []ContractFunctionArgRaw{{Name: "amount", Hex: "0x0100000000000000010000000000000000"}, recipient},
+			wantAmount: "18446744073709551616",
+		},
+		{
+			name:    "amount not a uint",
+			args:    []ContractFunctionArgRaw{{Name: "amount", Hex: "0x00000000000000000000000000000009c4"}, recipient},
+			wantErr: true,
+		},
+		{
+			name:    "missing hex",
+			args:    []ContractFunctionArgRaw{{Name: "amount", Repr: "u2500"}, recipient},
+			wantErr: true,
+		},
+		{
+			name:    "recipient not a principal",
+			args:    []ContractFunctionArgRaw{amount, {Name: "recipient", Hex: amount.Hex}},
+			wantErr: true,
+		},
+		{
+			name:    "memo not an optional",
+			args:    []ContractFunctionArgRaw{amount, recipient, {Name: "memo", Hex: "0x020000000161"}},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, gotAmount, gotMemo, err := parseSIP010Transfer(&ContractCallData{FunctionName: "transfer", FunctionArgs: tt.args})
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, "ST1PQHQKV0RJXZFY1DGX8MNSNYVE3VGZJSRTPGZGM", got.String())
+			assert.Equal(t, tt.wantAmount, gotAmount.String())
+			assert.Equal(t, tt.wantMemo, gotMemo)
+		})
+	}
 }
 
 func TestClient_GetTransaction_NotFound(t *testing.T) {
@@ -127,8 +232,71 @@ func TestClient_GetTransaction_NotFound(t *testing.T) {
 
 	_, err := client.GetTransaction(ctx, txID)
 
-	assert.Error(t, err)
-	assert.Contains(t, err.Error(), "not found")
+	assert.ErrorIs(t, err, ErrTransactionNotFound)
+}
+
+func TestClient_GetTransaction_APIError(t *testing.T) {
+	tests := []struct {
+		status        int
+		wantTransient bool
+	}{
+		{http.StatusBadRequest, false},
+		{http.StatusTooManyRequests, true},
+		{http.StatusBadGateway, true},
+	}
+
+	for _, tt := range tests {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(tt.status)
+			w.Write([]byte("upstream trouble"))
+		}))
+
+		client := NewClient(server.URL)
+		txID, _ := valueobject.NewTransactionID("0x1234567890abcdef1234567890abcdef1234567890abcdef1234567890abcdef")
+		_, err := client.GetTransaction(context.Background(), txID)
+		server.Close()
+
+		var apiErr *APIError
+		require.ErrorAs(t, err, &apiErr)
+		assert.Equal(t, tt.status, apiErr.StatusCode)
+		assert.Equal(t, "API error: upstream trouble", err.Error())
+		assert.Equal(t, tt.wantTransient, apiErr.Transient(), tt.status)
+	}
+}
+
+func TestClient_TransactionExists(t *testing.T) {
+	tests := []struct {
+		status     int
+		wantExists bool
+		wantErr    bool
+	}{
+		{http.StatusOK, true, false},
+		{http.StatusNotFound, false, false},
+		{http.StatusServiceUnavailable, false, true},
+	}
+
+	for _, tt := range tests {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, "/extended/v1/tx/0x1234567890abcdef1234567890abcdef1234567890abcdef1234567890abcdef", r.URL.Path)
+			w.WriteHeader(tt.status)
+			// Not a transaction: only the status is read
+			w.Write([]byte("{}"))
+		}))
+
+		client := NewClient(server.URL)
+		txID, _ := valueobject.NewTransactionID("0x1234567890abcdef1234567890abcdef1234567890abcdef1234567890abcdef")
+		exists, err := client.TransactionExists(context.Background(), txID)
+		server.Close()
+
+		assert.Equal(t, tt.wantExists, exists, tt.status)
+		if tt.wantErr {
+			var apiErr *APIError
+			require.ErrorAs(t, err, &apiErr)
+			assert.Equal(t, tt.status, apiErr.StatusCode)
+			continue
+		}
+		assert.NoError(t, err, tt.status)
+	}
 }
 
 func TestClient_BroadcastTransaction(t *testing.T) {
@@ -137,9 +305,9 @@ func TestClient_BroadcastTransaction(t *testing.T) {
 		assert.Equal(t, "POST", r.Method)
 		assert.Equal(t, "application/octet-stream", r.Header.Get("Content-Type"))
 
-		// Return transaction ID
+		// Return transaction ID, the SHA512/256 of the body, as the node does without 0x
 		w.WriteHeader(http.StatusOK)
-		w.Write([]byte(`"0xabcdef1234567890abcdef1234567890abcdef1234567890abcdef1234567890"`))
+		w.Write([]byte(`"f0c4b6db6c7ce3e523a945d2051f3e7697886e60ce9271848f3acd0673b1852e"`))
 	}))
 	defer server.Close()
 
@@ -149,7 +317,37 @@ func TestClient_BroadcastTransaction(t *testing.T) {
 	txID, err := client.BroadcastTransaction(ctx, "0x00000001deadbeef")
 
 	require.NoError(t, err)
-	assert.Equal(t, "0xabcdef1234567890abcdef1234567890abcdef1234567890abcdef1234567890", txID.String())
+	assert.Equal(t, "0xf0c4b6db6c7ce3e523a945d2051f3e7697886e60ce9271848f3acd0673b1852e", txID.String())
+}
+
+func TestClient_BroadcastTransaction_ChecksNodeTxID(t *testing.T) {
+	tests := []struct {
+		name    string
+		body    string
+		wantErr string
+	}{
+		{"other txid", `"0xabcdef1234567890abcdef1234567890abcdef1234567890abcdef1234567890"`, "node returned txid 0xabcdef"},
+		{"not a JSON string", `accepted`, ""},
+		{"uppercase", `"0xF0C4B6DB6C7CE3E523A945D2051F3E7697886E60CE9271848F3ACD0673B1852E"`, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Write([]byte(tt.body))
+			}))
+			defer server.Close()
+
+			txID, err := NewClient(server.URL).BroadcastTransaction(context.Background(), "0x00000001deadbeef")
+
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, "0xf0c4b6db6c7ce3e523a945d2051f3e7697886e60ce9271848f3acd0673b1852e", txID.String())
+		})
+	}
 }
 
 func TestClient_BroadcastTransaction_Error(t *testing.T) {
@@ -167,6 +365,213 @@ func TestClient_BroadcastTransaction_Error(t *testing.T) {
 	assert.Error(t, err)
 }
 
+func TestClient_BroadcastTransaction_Rejection(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(`{"error":"transaction rejected","reason":"BadNonce","reason_data":{"expected":4,"actual":2,"is_origin":false},"txid":"abcd"}`))
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL)
+
+	_, err := client.BroadcastTransaction(context.Background(), "0x00000001deadbeef")
+
+	var broadcastErr *BroadcastError
+	require.ErrorAs(t, err, &broadcastErr)
+	assert.Equal(t, http.StatusBadRequest, broadcastErr.StatusCode)
+	assert.Equal(t, "transaction rejected", broadcastErr.Message)
+	assert.Equal(t, "BadNonce", broadcastErr.Reason)
+	assert.JSONEq(t, `{"expected":4,"actual":2,"is_origin":false}`, string(broadcastErr.ReasonData))
+	assert.Equal(t, "abcd", broadcastErr.TxID)
+	assert.Contains(t, err.Error(), "broadcast failed")
+}
+
+func TestClient_GetNonces(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/extended/v1/address/ST1PQHQKV0RJXZFY1DGX8MNSNYVE3VGZJSRTPGZGM/nonces", r.URL.Path)
+		w.Write([]byte(`{"last_executed_tx_nonce":4,"last_mempool_tx_nonce":null,"possible_next_nonce":5,"detected_missing_nonces":[]}`))
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL)
+
+	info, err := client.GetNonces(context.Background(), "ST1PQHQKV0RJXZFY1DGX8MNSNYVE3VGZJSRTPGZGM")
+
+	require.NoError(t, err)
+	assert.Equal(t, uint64(5), info.PossibleNextNonce)
+	require.NotNil(t, info.LastExecutedTxNonce)
+	assert.Equal(t, uint64(4), *info.LastExecutedTxNonce)
+	assert.Nil(t, info.LastMempoolTxNonce)
+}
+
+func TestClient_GetAccount(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/v2/accounts/ST1PQHQKV0RJXZFY1DGX8MNSNYVE3VGZJSRTPGZGM", r.URL.Path)
+		assert.Equal(t, "0", r.URL.Query().Get("proof"))
+
+		w.Write([]byte(`{"balance":"0x00000000000000000000000000989680","locked":"0x0","nonce":12}`))
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL)
+
+	account, err := client.GetAccount(context.Background(), "ST1PQHQKV0RJXZFY1DGX8MNSNYVE3VGZJSRTPGZGM")
+
+	require.NoError(t, err)
+	assert.Equal(t, uint64(10000000), account.Balance)
+	assert.Equal(t, uint64(12), account.Nonce)
+}
+
+func TestClient_GetAccount_InvalidBalance(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"balance":"not-hex","nonce":0}`))
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL)
+
+	_, err := client.GetAccount(context.Background(), "ST1PQHQKV0RJXZFY1DGX8MNSNYVE3VGZJSRTPGZGM")
+
+	assert.Error(t, err)
+}
+
+func TestClient_GetFeeRate(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/v2/fees/transfer", r.URL.Path)
+		w.Write([]byte(`3`))
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL)
+
+	rate, err := client.GetFeeRate(context.Background())
+
+	require.NoError(t, err)
+	assert.Equal(t, uint64(3), rate)
+}
+
+func TestClient_GetChainTip(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/v2/info", r.URL.Path)
+		w.Write([]byte(`{"peer_version":4207599116,"burn_block_height":880002,"stacks_tip_height":1005,"stacks_tip":"0xabc"}`))
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL)
+
+	tip, err := client.GetChainTip(context.Background())
+
+	require.NoError(t, err)
+	assert.Equal(t, uint64(1005), tip.StacksHeight)
+	assert.Equal(t, uint64(880002), tip.BurnHeight)
+}
+
+func TestClient_GetChainTip_MissingHeight(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL)
+
+	_, err := client.GetChainTip(context.Background())
+
+	assert.Error(t, err)
+}
+
+func TestClient_CallReadOnly(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "/v2/contracts/call-read/SP3K8BC0PPEVCV7NZ6QSRWPQ2JE9E5B6N3PA0KBR9/token-alex/get-decimals", r.URL.Path)
+
+		var body struct {
+			Sender    string   `json:"sender"`
+			Arguments []string `json:"arguments"`
+		}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		assert.Equal(t, "SP3K8BC0PPEVCV7NZ6QSRWPQ2JE9E5B6N3PA0KBR9", body.Sender)
+		assert.Equal(t, []string{"0x0100000000000000000000000000000007"}, body.Arguments)
+
+		w.Write([]byte(`{"okay":true,"result":"0x070100000000000000000000000000000008"}`))
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL)
+
+	result, err := client.CallReadOnly(context.Background(), "SP3K8BC0PPEVCV7NZ6QSRWPQ2JE9E5B6N3PA0KBR9.token-alex", "get-decimals",
+		"SP3K8BC0PPEVCV7NZ6QSRWPQ2JE9E5B6N3PA0KBR9", clarity.NewUInt(7))
+
+	require.NoError(t, err)
+	assert.Equal(t, clarity.ResponseOk{Value: clarity.NewUInt(8)}, result)
+}
+
+func TestClient_CallReadOnly_Failed(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"okay":false,"cause":"Unchecked(NoSuchPublicFunction)"}`))
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL)
+
+	_, err := client.CallReadOnly(context.Background(), "SP3K8BC0PPEVCV7NZ6QSRWPQ2JE9E5B6N3PA0KBR9.token-alex", "get-symbol", "SP3K8BC0PPEVCV7NZ6QSRWPQ2JE9E5B6N3PA0KBR9")
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "NoSuchPublicFunction")
+
+	_, err = client.CallReadOnly(context.Background(), "not-a-contract", "get-symbol", "SP3K8BC0PPEVCV7NZ6QSRWPQ2JE9E5B6N3PA0KBR9")
+	assert.Error(t, err)
+}
+
+func TestClient_GetTransaction_DroppedTransaction(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{
+			"tx_id": "0x1234567890abcdef1234567890abcdef1234567890abcdef1234567890abcdef",
+			"tx_status": "dropped_replace_by_fee",
+			"replaced_by_tx_id": "0xabababababababababababababababababababababababababababababababab",
+			"tx_type": "token_transfer",
+			"fee_rate": "180",
+			"nonce": 5,
+			"sender_address": "ST2J6ZY48GV1EZ5V2V5RB9MP66SW86PYKKQYAC0RQ",
+			"token_transfer": {"recipient_address": "ST1PQHQKV0RJXZFY1DGX8MNSNYVE3VGZJSRTPGZGM", "amount": "1000000", "memo": ""}
+		}`))
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL)
+	txID, _ := valueobject.NewTransactionID("0x1234567890abcdef1234567890abcdef1234567890abcdef1234567890abcdef")
+
+	tx, err := client.GetTransaction(context.Background(), txID)
+
+	require.NoError(t, err)
+	assert.False(t, tx.IsConfirmed)
+	assert.Equal(t, valueobject.TxStatusDroppedReplaceByFee, tx.Status)
+	assert.Equal(t, "0xabababababababababababababababababababababababababababababababab", tx.ReplacedBy.String())
+}
+
+func TestClient_GetTransaction_UnknownStatus(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{
+			"tx_id": "0x1234567890abcdef1234567890abcdef1234567890abcdef1234567890abcdef",
+			"tx_status": "lost",
+			"block_height": 12345,
+			"tx_type": "token_transfer",
+			"sender_address": "ST2J6ZY48GV1EZ5V2V5RB9MP66SW86PYKKQYAC0RQ",
+			"token_transfer": {"recipient_address": "ST1PQHQKV0RJXZFY1DGX8MNSNYVE3VGZJSRTPGZGM", "amount": "1"}
+		}`))
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL)
+	txID, _ := valueobject.NewTransactionID("0x1234567890abcdef1234567890abcdef1234567890abcdef1234567890abcdef")
+
+	tx, err := client.GetTransaction(context.Background(), txID)
+
+	require.NoError(t, err)
+	assert.Equal(t, valueobject.TxStatusPending, tx.Status)
+	assert.Equal(t, "lost", tx.UnknownStatus)
+	assert.False(t, tx.IsConfirmed)
+}
+
 func TestClient_IsTransactionConfirmed(t *testing.T) {
 	assert.True(t, IsTransactionConfirmed("success", 12345))
 	assert.False(t, IsTransactionConfirmed("success", 0))
@@ -180,4 +585,182 @@ func TestClient_IsTransactionFailed(t *testing.T) {
 	assert.True(t, IsTransactionFailed("failed"))
 	assert.True(t, IsTransactionFailed("abort_by_response"))
 	assert.True(t, IsTransactionFailed("abort_by_post_condition"))
+	assert.False(t, IsTransactionFailed("dropped_replace_by_fee"))
+}
+
+func TestClient_IsTransactionDropped(t *testing.T) {
+	assert.False(t, IsTransactionDropped("pending"))
+	assert.False(t, IsTransactionDropped("abort_by_response"))
+	assert.True(t, IsTransactionDropped("dropped_replace_by_fee"))
+	assert.True(t, IsTransactionDropped("dropped_replace_across_fork"))
+	assert.True(t, IsTransactionDropped("dropped_too_expensive"))
+	assert.True(t, IsTransactionDropped("dropped_stale_garbage_collect"))
+}
+
+func TestClient_GetTransaction_ContractPrincipalRecipient(t *testing.T) {
+	tests := []struct {
+		name     string
+		response TransactionResponse
+	}{
+		{
+			name: "token_transfer",
+			response: TransactionResponse{
+				TxType:        "token_transfer",
+				TokenTransfer: &TokenTransferData{RecipientAddress: "ST1PQHQKV0RJXZFY1DGX8MNSNYVE3VGZJSRTPGZGM.my-vault", Amount: "1000000"},
+			},
+		},
+		{
+			name: "SIP-010 transfer",
+			response: TransactionResponse{
+				TxType: "contract_call",
+				ContractCall: &ContractCallData{
+					ContractID:   "ST1F7QA2MDF17S807EPA36TSS8AMEFY4KA9TVGWXT.sbtc-token",
+					FunctionName: "transfer",
+					FunctionArgs: []ContractFunctionArgRaw{
+						{Name: "amount", Type: "uint", Hex: "0x01000000000000000000000000000f4240", Repr: "u1000000"},
+						{Name: "sender", Type: "principal", Hex: "0x051aa46ff88886c2ef9762d970b4d2c63678835bd39d", Repr: "'ST2J6ZY48GV1EZ5V2V5RB9MP66SW86PYKKQYAC0RQ"},
+						{Name: "recipient", Type: "principal", Hex: "0x061a6d78de7b0625dfbfc16c3a8a5735f6dc3dc3f2ce086d792d7661756c74", Repr: "'ST1PQHQKV0RJXZFY1DGX8MNSNYVE3VGZJSRTPGZGM.my-vault"},
+					},
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				response := tt.response
+				response.TxID = "0x1234567890abcdef1234567890abcdef1234567890abcdef1234567890abcdef"
+				response.TxStatus = "success"
+				response.BlockHeight = 12345
+				response.SenderAddress = "ST2J6ZY48GV1EZ5V2V5RB9MP66SW86PYKKQYAC0RQ"
+				json.NewEncoder(w).Encode(response)
+			}))
+			defer server.Close()
+
+			client := NewClient(server.URL)
+			txID, _ := valueobject.NewTransactionID("0x1234567890abcdef1234567890abcdef1234567890abcdef1234567890abcdef")
+
+			tx, err := client.GetTransaction(context.Background(), txID)
+
+			require.NoError(t, err)
+			assert.True(t, tx.Recipient.IsContract())
+			assert.Equal(t, "ST1PQHQKV0RJXZFY1DGX8MNSNYVE3VGZJSRTPGZGM.my-vault", tx.Recipient.String())
+			assert.Equal(t, "1000000", tx.Amount.String())
+		})
+	}
+}
+
+func TestClient_GetTransaction_TransferEvents(t *testing.T) {
+	const sbtc = "ST1F7QA2MDF17S807EPA36TSS8AMEFY4KA9TVGWXT.sbtc-token::sbtc-token"
+	events := []TransactionEvent{
+		{EventIndex: 0, EventType: "fungible_token_asset", Asset: &EventAsset{AssetEventType: "transfer", AssetID: sbtc,
+			Sender: "ST2J6ZY48GV1EZ5V2V5RB9MP66SW86PYKKQYAC0RQ", Recipient: "ST2J6ZY48GV1EZ5V2V5RB9MP66SW86PYKKQYAC0RQ.swap-router", Amount: "2600"}},
+		{EventIndex: 1, EventType: "smart_contract_log"},
+		{EventIndex: 2, EventType: "fungible_token_asset", Asset: &EventAsset{AssetEventType: "mint", AssetID: sbtc,
+			Recipient: "ST2J6ZY48GV1EZ5V2V5RB9MP66SW86PYKKQYAC0RQ", Amount: "5"}},
+		{EventIndex: 3, EventType: "fungible_token_asset", Asset: &EventAsset{AssetEventType: "transfer", AssetID: sbtc,
+			Sender: "ST2J6ZY48GV1EZ5V2V5RB9MP66SW86PYKKQYAC0RQ.swap-router", Recipient: "ST1PQHQKV0RJXZFY1DGX8MNSNYVE3VGZJSRTPGZGM", Amount: "2500"}},
+		{EventIndex: 4, EventType: "stx_asset", Asset: &EventAsset{AssetEventType: "transfer",
+			Sender: "ST2J6ZY48GV1EZ5V2V5RB9MP66SW86PYKKQYAC0RQ", Recipient: "ST1PQHQKV0RJXZFY1DGX8MNSNYVE3VGZJSRTPGZGM", Amount: "100"}},
+	}
+
+	var queries []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		queries = append(queries, r.URL.RawQuery)
+
+		// Return two events inline and the rest one page at a time
+		offset, _ := strconv.Atoi(r.URL.Query().Get("event_offset"))
+		end := offset + 2
+		if end > len(events) {
+			end = len(events)
+		}
+
+		response := TransactionResponse{
+			TxID:          "0x1234567890abcdef1234567890abcdef1234567890abcdef1234567890abcdef",
+			TxStatus:      "success",
+			TxType:        "contract_call",
+			BlockHeight:   12345,
+			SenderAddress: "ST2J6ZY48GV1EZ5V2V5RB9MP66SW86PYKKQYAC0RQ",
+			ContractCall: &ContractCallData{
+				ContractID:   "ST2J6ZY48GV1EZ5V2V5RB9MP66SW86PYKKQYAC0RQ.swap-router",
+				FunctionName: "swap-and-pay",
+			},
+			EventCount: len(events),
+			Events:     events[offset:end],
+		}
+		json.NewEncoder(w).Encode(response)
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL)
+	txID, _ := valueobject.NewTransactionID("0x1234567890abcdef1234567890abcdef1234567890abcdef1234567890abcdef")
+
+	tx, err := client.GetTransactionWithTokenType(context.Background(), txID, valueobject.TokenSBTC, valueobject.NetworkTestnet)
+
+	require.NoError(t, err)
+	assert.Equal(t, []string{"", "event_offset=2&event_limit=50", "event_offset=4&event_limit=50"}, queries)
+	assert.True(t, tx.Recipient.IsZero())
+	require.Len(t, tx.Transfers, 3)
+	assert.Equal(t, sbtc, tx.Transfers[1].Asset)
+	assert.Equal(t, "ST1PQHQKV0RJXZFY1DGX8MNSNYVE3VGZJSRTPGZGM", tx.Transfers[1].Recipient.String())
+	assert.Equal(t, "ST2J6ZY48GV1EZ5V2V5RB9MP66SW86PYKKQYAC0RQ.swap-router", tx.Transfers[1].Sender.String())
+	assert.Equal(t, "2500", tx.Transfers[1].Amount.String())
+	assert.Equal(t, "STX", tx.Transfers[2].Asset)
+}
+
+func TestClient_GetTransaction_ConfirmedWithoutEvents(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		response := TransactionResponse{
+			TxID:          "0x1234567890abcdef1234567890abcdef1234567890abcdef1234567890abcdef",
+			TxStatus:      "success",
+			TxType:        "token_transfer",
+			BlockHeight:   12345,
+			SenderAddress: "ST2J6ZY48GV1EZ5V2V5RB9MP66SW86PYKKQYAC0RQ",
+			TokenTransfer: &TokenTransferData{
+				RecipientAddress: "ST1PQHQKV0RJXZFY1DGX8MNSNYVE3VGZJSRTPGZGM",
+				Amount:           "1000000",
+			},
+		}
+		json.NewEncoder(w).Encode(response)
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL)
+	txID, _ := valueobject.NewTransactionID("0x1234567890abcdef1234567890abcdef1234567890abcdef1234567890abcdef")
+
+	tx, err := client.GetTransaction(context.Background(), txID)
+
+	// The API returned no events, so the decoded transfer is verified instead
+	require.NoError(t, err)
+	assert.True(t, tx.IsConfirmed)
+	assert.Nil(t, tx.Transfers)
+	assert.Equal(t, "1000000", tx.Amount.String())
+}
+
+func TestClient_GetTransaction_PendingNonTransferCall(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		response := TransactionResponse{
+			TxID:          "0x1234567890abcdef1234567890abcdef1234567890abcdef1234567890abcdef",
+			TxStatus:      "pending",
+			TxType:        "contract_call",
+			SenderAddress: "ST2J6ZY48GV1EZ5V2V5RB9MP66SW86PYKKQYAC0RQ",
+			ContractCall: &ContractCallData{
+				ContractID:   "ST2J6ZY48GV1EZ5V2V5RB9MP66SW86PYKKQYAC0RQ.swap-router",
+				FunctionName: "swap-and-pay",
+			},
+		}
+		json.NewEncoder(w).Encode(response)
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL)
+	txID, _ := valueobject.NewTransactionID("0x1234567890abcdef1234567890abcdef1234567890abcdef1234567890abcdef")
+
+	tx, err := client.GetTransaction(context.Background(), txID)
+
+	require.NoError(t, err)
+	assert.True(t, tx.Recipient.IsZero())
+	assert.Nil(t, tx.Transfers)
+	assert.False(t, tx.IsConfirmed)
 }
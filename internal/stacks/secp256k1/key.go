@@ -0,0 +1,112 @@
+package secp256k1
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/decred/dcrd/crypto/ripemd160"
+	dcrsecp "github.com/decred/dcrd/dcrec/secp256k1/v4"
+)
+
+// PrivateKeySize is the length of a raw secp256k1 private key
+const PrivateKeySize = 32
+
+// PublicKeySize is the length of a compressed public key
+const PublicKeySize = 33
+
+// PrivateKey is a secp256k1 signing key
+type PrivateKey struct {
+	key        *dcrsecp.PrivateKey
+	compressed bool
+}
+
+// PublicKey is a point on secp256k1
+type PublicKey struct {
+	key        *dcrsecp.PublicKey
+	compressed bool
+}
+
+// ParsePrivateKey parses a hex private key in the Stacks format: 32 bytes,
+// optionally followed by 0x01 to mark a compressed public key
+func ParsePrivateKey(s string) (*PrivateKey, error) {
+	raw, err := hex.DecodeString(strings.TrimPrefix(s, "0x"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid private key hex: %w", err)
+	}
+
+	compressed := false
+	switch {
+	case len(raw) == PrivateKeySize+1 && raw[PrivateKeySize] == 0x01:
+		compressed = true
+		raw = raw[:PrivateKeySize]
+	case len(raw) != PrivateKeySize:
+		return nil, fmt.Errorf("invalid private key length: %d", len(raw))
+	}
+
+	var d dcrsecp.ModNScalar
+	if overflow := d.SetByteSlice(raw); overflow || d.IsZero() {
+		return nil, errors.New("private key out of range")
+	}
+	return &PrivateKey{key: dcrsecp.NewPrivateKey(&d), compressed: compressed}, nil
+}
+
+// PublicKey returns the public key for the private key
+func (k *PrivateKey) PublicKey() *PublicKey {
+	return &PublicKey{key: k.key.PubKey(), compressed: k.compressed}
+}
+
+// Compressed reports whether the key signs for a compressed public key
+func (k *PrivateKey) Compressed() bool {
+	return k.compressed
+}
+
+// ParsePublicKey parses a SEC1 compressed (33 bytes) or uncompressed (65 bytes) public key
+func ParsePublicKey(b []byte) (*PublicKey, error) {
+	key, err := dcrsecp.ParsePubKey(b)
+	if err != nil {
+		return nil, fmt.Errorf("invalid public key: %w", err)
+	}
+	return &PublicKey{key: key, compressed: len(b) == PublicKeySize}, nil
+}
+
+// Bytes returns the SEC1 encoding of the key, compressed or not as the key was created
+func (pk *PublicKey) Bytes() []byte {
+	if pk.compressed {
+		return pk.SerializeCompressed()
+	}
+	return pk.SerializeUncompressed()
+}
+
+// SerializeCompressed returns the 33-byte compressed encoding
+func (pk *PublicKey) SerializeCompressed() []byte {
+	return pk.key.SerializeCompressed()
+}
+
+// SerializeUncompressed returns the 65-byte uncompressed encoding
+func (pk *PublicKey) SerializeUncompressed() []byte {
+	return pk.key.SerializeUncompressed()
+}
+
+// Compressed reports whether the key is encoded compressed
+func (pk *PublicKey) Compressed() bool {
+	return pk.compressed
+}
+
+// Hash160 returns RIPEMD160(SHA256(Bytes())), the hash Stacks addresses commit to
+func (pk *PublicKey) Hash160() [20]byte {
+	return Hash160(pk.Bytes())
+}
+
+// Hash160 returns RIPEMD160(SHA256(data))
+func Hash160(data []byte) [20]byte {
+	sha := sha256.Sum256(data)
+	h := ripemd160.New()
+	h.Write(sha[:])
+
+	var out [20]byte
+	copy(out[:], h.Sum(nil))
+	return out
+}
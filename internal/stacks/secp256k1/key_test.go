@@ -0,0 +1,72 @@
+package secp256k1
+
+import (
+	"encoding/hex"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParsePrivateKey(t *testing.T) {
+	tests := []struct {
+		name           string
+		input          string
+		wantErr        bool
+		wantCompressed bool
+	}{
+		{name: "raw key", input: strings.Repeat("11", 32)},
+		{name: "compressed suffix", input: strings.Repeat("11", 32) + "01", wantCompressed: true},
+		{name: "0x prefix", input: "0x" + strings.Repeat("11", 32)},
+		{name: "bad suffix", input: strings.Repeat("11", 32) + "02", wantErr: true},
+		{name: "short", input: strings.Repeat("11", 31), wantErr: true},
+		{name: "not hex", input: strings.Repeat("zz", 32), wantErr: true},
+		{name: "zero", input: strings.Repeat("00", 32), wantErr: true},
+		{name: "curve order", input: "fffffffffffffffffffffffffffffffebaaedce6af48a03bbfd25e8cd0364141", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			key, err := ParsePrivateKey(tt.input)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantCompressed, key.Compressed())
+		})
+	}
+}
+
+func TestPrivateKey_PublicKey(t *testing.T) {
+	key, err := ParsePrivateKey(strings.Repeat("00", 31) + "0101")
+	require.NoError(t, err)
+
+	pub := key.PublicKey()
+	assert.Equal(t, "0279be667ef9dcbbac55a06295ce870b07029bfcdb2dce28d959f2815b16f81798", hex.EncodeToString(pub.Bytes()))
+	assert.Equal(t,
+		"0479be667ef9dcbbac55a06295ce870b07029bfcdb2dce28d959f2815b16f81798483ada7726a3c4655da4fbfc0e1108a8fd17b448a68554199c47d08ffb10d4b8",
+		hex.EncodeToString(pub.SerializeUncompressed()))
+
+	hash := pub.Hash160()
+	assert.Equal(t, "751e76e8199196d454941c45d1b3a323f1433bd6", hex.EncodeToString(hash[:]))
+}
+
+func TestParsePublicKey_RoundTrip(t *testing.T) {
+	key, err := ParsePrivateKey(strings.Repeat("42", 32) + "01")
+	require.NoError(t, err)
+	pub := key.PublicKey()
+
+	compressed, err := ParsePublicKey(pub.SerializeCompressed())
+	require.NoError(t, err)
+	assert.Equal(t, pub.SerializeUncompressed(), compressed.SerializeUncompressed())
+
+	uncompressed, err := ParsePublicKey(pub.SerializeUncompressed())
+	require.NoError(t, err)
+	assert.False(t, uncompressed.Compressed())
+	assert.Equal(t, pub.SerializeCompressed(), uncompressed.SerializeCompressed())
+
+	_, err = ParsePublicKey([]byte{0x02, 0x01})
+	assert.Error(t, err)
+}
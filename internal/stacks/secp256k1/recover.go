@@ -0,0 +1,36 @@
+package secp256k1
+
+import (
+	"errors"
+	"fmt"
+
+	dcrsecp "github.com/decred/dcrd/dcrec/secp256k1/v4"
+	"github.com/decred/dcrd/dcrec/secp256k1/v4/ecdsa"
+)
+
+// RecoverPublicKey returns the public key that produced a recoverable signature
+// over hash. The signature is in the Stacks layout [recovery id][r][s] and must
+// have a low s value; compressed selects how the returned key is encoded.
+func RecoverPublicKey(hash [32]byte, sig [SignatureSize]byte, compressed bool) (*PublicKey, error) {
+	recoveryID := sig[0]
+	if recoveryID > 3 {
+		return nil, errors.New("invalid recovery id")
+	}
+
+	var s dcrsecp.ModNScalar
+	if overflow := s.SetByteSlice(sig[33:]); !overflow && s.IsOverHalfOrder() {
+		return nil, errors.New("signature has a high s value")
+	}
+
+	compact := sig
+	compact[0] = compactRecoveryOffset + recoveryID
+	if compressed {
+		compact[0] += compactCompressedFlag
+	}
+	key, _, err := ecdsa.RecoverCompact(compact[:], hash[:])
+	if err != nil {
+		return nil, fmt.Errorf("invalid signature: %w", err)
+	}
+
+	return &PublicKey{key: key, compressed: compressed}, nil
+}
@@ -0,0 +1,71 @@
+package secp256k1
+
+import (
+	"crypto/sha256"
+	"math/big"
+
+	dcrsecp "github.com/decred/dcrd/dcrec/secp256k1/v4"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRecoverPublicKey(t *testing.T) {
+	for _, hexKey := range []string{
+		"0000000000000000000000000000000000000000000000000000000000000001",
+		"edf9aee84d9b7abc145504dde6726c64f369d37ee34ded868fabd876c26570bc01",
+	} {
+		key, err := ParsePrivateKey(hexKey)
+		require.NoError(t, err)
+
+		for _, message := range []string{"payment", "Satoshi Nakamoto", "other"} {
+			hash := sha256.Sum256([]byte(message))
+			recovered, err := RecoverPublicKey(hash, key.Sign(hash), key.Compressed())
+
+			require.NoError(t, err)
+			assert.Equal(t, key.PublicKey().Bytes(), recovered.Bytes())
+		}
+	}
+}
+
+func TestRecoverPublicKey_OtherHashRecoversOtherKey(t *testing.T) {
+	key, err := ParsePrivateKey("edf9aee84d9b7abc145504dde6726c64f369d37ee34ded868fabd876c26570bc01")
+	require.NoError(t, err)
+
+	sig := key.Sign(sha256.Sum256([]byte("payment")))
+	recovered, err := RecoverPublicKey(sha256.Sum256([]byte("tampered")), sig, true)
+
+	require.NoError(t, err)
+	assert.NotEqual(t, key.PublicKey().Bytes(), recovered.Bytes())
+}
+
+func TestRecoverPublicKey_Invalid(t *testing.T) {
+	key, err := ParsePrivateKey("edf9aee84d9b7abc145504dde6726c64f369d37ee34ded868fabd876c26570bc01")
+	require.NoError(t, err)
+	hash := sha256.Sum256([]byte("payment"))
+	valid := key.Sign(hash)
+
+	badRecoveryID := valid
+	badRecoveryID[0] = 4
+
+	zeroR := valid
+	copy(zeroR[1:33], make([]byte, 32))
+
+	// The high-s twin of a valid signature verifies in plain ECDSA but is not accepted
+	highS := valid
+	s := new(big.Int).SetBytes(valid[33:])
+	new(big.Int).Sub(dcrsecp.S256().N, s).FillBytes(highS[33:])
+	highS[0] ^= 1
+
+	for name, sig := range map[string][SignatureSize]byte{
+		"recovery id": badRecoveryID,
+		"zero r":      zeroR,
+		"high s":      highS,
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, err := RecoverPublicKey(hash, sig, true)
+			assert.Error(t, err)
+		})
+	}
+}
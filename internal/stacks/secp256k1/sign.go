@@ -0,0 +1,25 @@
+package secp256k1
+
+import "github.com/decred/dcrd/dcrec/secp256k1/v4/ecdsa"
+
+// SignatureSize is the length of a recoverable signature
+const SignatureSize = 65
+
+// compactRecoveryOffset is the offset ecdsa.SignCompact adds to the recovery id,
+// plus compactCompressedFlag when the key is compressed; Stacks stores the bare id
+const (
+	compactRecoveryOffset = 27
+	compactCompressedFlag = 4
+)
+
+// Sign produces a recoverable signature over a 32-byte hash in the Stacks
+// layout [recovery id][r][s], using RFC 6979 nonces and a low s value.
+// Signing is constant time.
+func (k *PrivateKey) Sign(hash [32]byte) [SignatureSize]byte {
+	compact := ecdsa.SignCompact(k.key, hash[:], k.compressed)
+
+	var sig [SignatureSize]byte
+	copy(sig[:], compact)
+	sig[0] = (compact[0] - compactRecoveryOffset) &^ compactCompressedFlag
+	return sig
+}
@@ -0,0 +1,52 @@
+package secp256k1
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSign_RFC6979Vectors(t *testing.T) {
+	tests := []struct {
+		name    string
+		key     string
+		message string
+		rs      string
+	}{
+		{
+			name:    "key one",
+			key:     "0000000000000000000000000000000000000000000000000000000000000001",
+			message: "Satoshi Nakamoto",
+			rs:      "934b1ea10a4b3c1757e2b0c017d0b6143ce3c9a7e6a4a49860d7a6ab210ee3d82442ce9d2b916064108014783e923ec36b49743e2ffa1c4496f01a512aafd9e5",
+		},
+		{
+			name:    "key one long message",
+			key:     "0000000000000000000000000000000000000000000000000000000000000001",
+			message: "All those moments will be lost in time, like tears in rain. Time to die...",
+			rs:      "8600dbd41e348fe5c9465ab92d23e3db8b98b873beecd930736488696438cb6b547fe64427496db33bf66019dacbf0039c04199abb0122918601db38a72cfc21",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			key, err := ParsePrivateKey(tt.key)
+			require.NoError(t, err)
+
+			sig := key.Sign(sha256.Sum256([]byte(tt.message)))
+			assert.Equal(t, tt.rs, hex.EncodeToString(sig[1:]))
+			assert.LessOrEqual(t, sig[0], byte(3))
+		})
+	}
+}
+
+func TestSign_Deterministic(t *testing.T) {
+	key, err := ParsePrivateKey("edf9aee84d9b7abc145504dde6726c64f369d37ee34ded868fabd876c26570bc01")
+	require.NoError(t, err)
+
+	hash := sha256.Sum256([]byte("payment"))
+	assert.Equal(t, key.Sign(hash), key.Sign(hash))
+	assert.NotEqual(t, key.Sign(hash), key.Sign(sha256.Sum256([]byte("other"))))
+}
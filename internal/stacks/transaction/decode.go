@@ -0,0 +1,356 @@
+package transaction
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/x402stacks/stacks-facilitator/internal/stacks/clarity"
+)
+
+// ErrMalformed is wrapped by every decoding error
+var ErrMalformed = errors.New("malformed transaction")
+
+// DecodeHex decodes a hex-encoded transaction, with or without a 0x prefix
+func DecodeHex(s string) (*Transaction, error) {
+	s = strings.TrimPrefix(strings.TrimPrefix(strings.TrimSpace(s), "0x"), "0X")
+	data, err := hex.DecodeString(s)
+	if err != nil {
+		return nil, fmt.Errorf("%w: invalid hex: %v", ErrMalformed, err)
+	}
+	return Decode(data)
+}
+
+// Decode deserializes a transaction that must span all of data
+func Decode(data []byte) (*Transaction, error) {
+	d := &decoder{data: data, r: bytes.NewReader(data)}
+	tx := d.transaction()
+	if d.err == nil && d.r.Len() != 0 {
+		d.fail("%d trailing bytes", d.r.Len())
+	}
+	if d.err != nil {
+		return nil, d.err
+	}
+	return tx, nil
+}
+
+// decoder reads transaction fields, remembering the first error
+type decoder struct {
+	data []byte
+	r    *bytes.Reader
+	err  error
+}
+
+// offset returns the position of the next unread byte
+func (d *decoder) offset() int {
+	return len(d.data) - d.r.Len()
+}
+
+func (d *decoder) fail(format string, args ...any) {
+	if d.err == nil {
+		d.err = fmt.Errorf("%w: %s", ErrMalformed, fmt.Sprintf(format, args...))
+	}
+}
+
+func (d *decoder) bytes(n int) []byte {
+	if d.err != nil {
+		return nil
+	}
+	if n > d.r.Len() {
+		d.fail("unexpected end of input")
+		return nil
+	}
+	b := make([]byte, n)
+	if _, err := io.ReadFull(d.r, b); err != nil {
+		d.fail("unexpected end of input")
+		return nil
+	}
+	return b
+}
+
+func (d *decoder) byte() byte {
+	b := d.bytes(1)
+	if b == nil {
+		return 0
+	}
+	return b[0]
+}
+
+func (d *decoder) u16() uint16 {
+	b := d.bytes(2)
+	if b == nil {
+		return 0
+	}
+	return binary.BigEndian.Uint16(b)
+}
+
+func (d *decoder) u32() uint32 {
+	b := d.bytes(4)
+	if b == nil {
+		return 0
+	}
+	return binary.BigEndian.Uint32(b)
+}
+
+func (d *decoder) u64() uint64 {
+	b := d.bytes(8)
+	if b == nil {
+		return 0
+	}
+	return binary.BigEndian.Uint64(b)
+}
+
+func (d *decoder) name() string {
+	if d.err != nil {
+		return ""
+	}
+	s, err := clarity.ReadName(d.r)
+	if err != nil {
+		d.fail("%v", err)
+	}
+	return s
+}
+
+func (d *decoder) value() clarity.Value {
+	if d.err != nil {
+		return nil
+	}
+	v, err := clarity.DecodeFrom(d.r)
+	if err != nil {
+		d.fail("%v", err)
+	}
+	return v
+}
+
+func (d *decoder) principal() clarity.StandardPrincipal {
+	var p clarity.StandardPrincipal
+	p.Version = d.byte()
+	copy(p.Hash160[:], d.bytes(20))
+	return p
+}
+
+func (d *decoder) transaction() *Transaction {
+	tx := &Transaction{}
+
+	tx.Version = Version(d.byte())
+	if d.err == nil && tx.Version != VersionMainnet && tx.Version != VersionTestnet {
+		d.fail("unknown version 0x%02x", byte(tx.Version))
+	}
+	tx.ChainID = d.u32()
+
+	tx.Auth = d.authorization()
+	bodyStart := d.offset()
+
+	tx.AnchorMode = AnchorMode(d.byte())
+	if d.err == nil && (tx.AnchorMode < AnchorModeOnChainOnly || tx.AnchorMode > AnchorModeAny) {
+		d.fail("unknown anchor mode 0x%02x", byte(tx.AnchorMode))
+	}
+
+	tx.PostConditionMode = PostConditionMode(d.byte())
+	if d.err == nil && tx.PostConditionMode != PostConditionModeAllow && tx.PostConditionMode != PostConditionModeDeny {
+		d.fail("unknown post-condition mode 0x%02x", byte(tx.PostConditionMode))
+	}
+
+	count := d.u32()
+	if d.err == nil && int64(count) > int64(d.r.Len()) {
+		d.fail("post-condition count exceeds input")
+	}
+	for i := uint32(0); i < count && d.err == nil; i++ {
+		tx.PostConditions = append(tx.PostConditions, d.postCondition())
+	}
+
+	tx.Payload = d.payload()
+	if d.err == nil {
+		tx.body = append([]byte(nil), d.data[bodyStart:d.offset()]...)
+	}
+	return tx
+}
+
+func (d *decoder) authorization() Authorization {
+	auth := Authorization{Type: AuthType(d.byte())}
+	switch auth.Type {
+	case AuthStandard:
+		auth.Origin = d.spendingCondition()
+	case AuthSponsored:
+		auth.Origin = d.spendingCondition()
+		sponsor := d.spendingCondition()
+		auth.Sponsor = &sponsor
+	default:
+		d.fail("unknown auth type 0x%02x", byte(auth.Type))
+	}
+	return auth
+}
+
+func (d *decoder) spendingCondition() SpendingCondition {
+	c := SpendingCondition{HashMode: HashMode(d.byte())}
+	if d.err == nil && !c.HashMode.IsValid() {
+		d.fail("unknown hash mode 0x%02x", byte(c.HashMode))
+		return c
+	}
+	copy(c.Signer[:], d.bytes(20))
+	c.Nonce = d.u64()
+	c.Fee = d.u64()
+
+	if c.HashMode.IsSingleSig() {
+		c.KeyEncoding = PubKeyEncoding(d.byte())
+		if d.err == nil && c.KeyEncoding != PubKeyEncodingCompressed && c.KeyEncoding != PubKeyEncodingUncompressed {
+			d.fail("unknown key encoding 0x%02x", byte(c.KeyEncoding))
+		}
+		copy(c.Signature[:], d.bytes(65))
+		return c
+	}
+
+	count := d.u32()
+	if d.err == nil && int64(count) > int64(d.r.Len()) {
+		d.fail("auth field count exceeds input")
+	}
+	for i := uint32(0); i < count && d.err == nil; i++ {
+		field := AuthField{Type: AuthFieldType(d.byte())}
+		switch field.Type {
+		case AuthFieldPublicKeyCompressed, AuthFieldPublicKeyUncompressed:
+			copy(field.PublicKey[:], d.bytes(33))
+		case AuthFieldSignatureCompressed, AuthFieldSignatureUncompressed:
+			copy(field.Signature[:], d.bytes(65))
+		default:
+			d.fail("unknown auth field type 0x%02x", byte(field.Type))
+		}
+		c.Fields = append(c.Fields, field)
+	}
+	c.SignaturesRequired = d.u16()
+	return c
+}
+
+func (d *decoder) postCondition() PostCondition {
+	pc := PostCondition{Type: PostConditionType(d.byte())}
+	if d.err == nil && pc.Type > PostConditionNonFungible {
+		d.fail("unknown post-condition type 0x%02x", byte(pc.Type))
+		return pc
+	}
+
+	pc.Principal.Type = PostConditionPrincipalType(d.byte())
+	switch pc.Principal.Type {
+	case PostConditionPrincipalOrigin:
+	case PostConditionPrincipalStandard:
+		pc.Principal.Address = d.principal()
+	case PostConditionPrincipalContract:
+		pc.Principal.Address = d.principal()
+		pc.Principal.ContractName = d.name()
+	default:
+		d.fail("unknown post-condition principal 0x%02x", byte(pc.Principal.Type))
+		return pc
+	}
+
+	if pc.Type != PostConditionSTX {
+		pc.Asset.Address = d.principal()
+		pc.Asset.ContractName = d.name()
+		pc.Asset.AssetName = d.name()
+	}
+	if pc.Type == PostConditionNonFungible {
+		pc.AssetID = d.value()
+	}
+
+	pc.Code = ConditionCode(d.byte())
+	if d.err != nil {
+		return pc
+	}
+	switch pc.Type {
+	case PostConditionNonFungible:
+		if pc.Code != ConditionNonFungibleSent && pc.Code != ConditionNonFungibleNotSent {
+			d.fail("invalid non-fungible condition code 0x%02x", byte(pc.Code))
+		}
+	default:
+		if pc.Code < ConditionEqual || pc.Code > ConditionLessEqual {
+			d.fail("invalid fungible condition code 0x%02x", byte(pc.Code))
+		}
+		pc.Amount = d.u64()
+	}
+	return pc
+}
+
+func (d *decoder) payload() Payload {
+	typ := PayloadType(d.byte())
+	if d.err != nil {
+		return nil
+	}
+
+	switch typ {
+	case PayloadTokenTransfer:
+		p := TokenTransferPayload{Recipient: d.value()}
+		if d.err == nil {
+			if _, ok := clarity.PrincipalString(p.Recipient); !ok {
+				d.fail("token transfer recipient is not a principal")
+			}
+		}
+		p.Amount = d.u64()
+		copy(p.Memo[:], d.bytes(34))
+		return p
+	case PayloadSmartContract:
+		return SmartContractPayload{Name: d.name(), Code: string(d.lengthPrefixed())}
+	case PayloadVersionedSmartContract:
+		version := d.byte()
+		return SmartContractPayload{ClarityVersion: &version, Name: d.name(), Code: string(d.lengthPrefixed())}
+	case PayloadContractCall:
+		p := ContractCallPayload{
+			Address:      d.principal(),
+			ContractName: d.name(),
+			FunctionName: d.name(),
+		}
+		count := d.u32()
+		if d.err == nil && int64(count) > int64(d.r.Len()) {
+			d.fail("argument count exceeds input")
+		}
+		for i := uint32(0); i < count && d.err == nil; i++ {
+			p.Args = append(p.Args, d.value())
+		}
+		return p
+	case PayloadPoisonMicroblock:
+		return PoisonMicroblockPayload{Header1: d.bytes(132), Header2: d.bytes(132)}
+	case PayloadCoinbase:
+		var p CoinbasePayload
+		copy(p.Buffer[:], d.bytes(32))
+		return p
+	case PayloadCoinbaseToAltRecipient:
+		var p CoinbasePayload
+		copy(p.Buffer[:], d.bytes(32))
+		p.Recipient = d.value()
+		return p
+	case PayloadTenureChange:
+		var p TenureChangePayload
+		copy(p.TenureConsensusHash[:], d.bytes(20))
+		copy(p.PrevTenureConsensusHash[:], d.bytes(20))
+		copy(p.BurnViewConsensusHash[:], d.bytes(20))
+		copy(p.PreviousTenureEnd[:], d.bytes(32))
+		p.PreviousTenureBlocks = d.u32()
+		p.Cause = d.byte()
+		copy(p.PubKeyHash[:], d.bytes(20))
+		return p
+	case PayloadNakamotoCoinbase:
+		p := CoinbasePayload{Nakamoto: true}
+		copy(p.Buffer[:], d.bytes(32))
+		switch v := d.value().(type) {
+		case nil, clarity.None:
+		case clarity.Some:
+			p.Recipient = v.Value
+		default:
+			d.fail("coinbase recipient is not an optional")
+		}
+		p.VRFProof = d.bytes(80)
+		return p
+	default:
+		d.fail("unknown payload type 0x%02x", byte(typ))
+		return nil
+	}
+}
+
+func (d *decoder) lengthPrefixed() []byte {
+	n := d.u32()
+	if d.err == nil && int64(n) > int64(d.r.Len()) {
+		d.fail("length prefix exceeds input")
+		return nil
+	}
+	return d.bytes(int(n))
+}
@@ -0,0 +1,246 @@
+package transaction
+
+import (
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/x402stacks/stacks-facilitator/internal/payment/domain/valueobject"
+	"github.com/x402stacks/stacks-facilitator/internal/stacks/clarity"
+)
+
+// hash160 of ST1PQHQKV0RJXZFY1DGX8MNSNYVE3VGZJSRTPGZGM
+var recipientHash = mustHash("6d78de7b0625dfbfc16c3a8a5735f6dc3dc3f2ce")
+
+// hash160 of SP2J6ZY48GV1EZ5V2V5RB9MP66SW86PYKKNRV9EJ7
+var signerHash = mustHash("a46ff88886c2ef9762d970b4d2c63678835bd39d")
+
+func mustHash(s string) [20]byte {
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		panic(err)
+	}
+	var h [20]byte
+	copy(h[:], b)
+	return h
+}
+
+// txBuilder assembles raw transaction bytes for tests
+type txBuilder struct {
+	buf []byte
+}
+
+func (b *txBuilder) u8(v byte) *txBuilder {
+	b.buf = append(b.buf, v)
+	return b
+}
+
+func (b *txBuilder) raw(v []byte) *txBuilder {
+	b.buf = append(b.buf, v...)
+	return b
+}
+
+func (b *txBuilder) u16(v uint16) *txBuilder {
+	b.buf = binary.BigEndian.AppendUint16(b.buf, v)
+	return b
+}
+
+func (b *txBuilder) u32(v uint32) *txBuilder {
+	b.buf = binary.BigEndian.AppendUint32(b.buf, v)
+	return b
+}
+
+func (b *txBuilder) u64(v uint64) *txBuilder {
+	b.buf = binary.BigEndian.AppendUint64(b.buf, v)
+	return b
+}
+
+func (b *txBuilder) name(s string) *txBuilder {
+	return b.u8(byte(len(s))).raw([]byte(s))
+}
+
+// header writes a testnet header with a standard single-sig P2PKH origin
+func (b *txBuilder) header(nonce, fee uint64) *txBuilder {
+	b.u8(byte(VersionTestnet)).u32(ChainIDTestnet)
+	b.u8(byte(AuthStandard)).u8(byte(HashModeP2PKH)).raw(signerHash[:]).u64(nonce).u64(fee)
+	return b.u8(byte(PubKeyEncodingCompressed)).raw(make([]byte, 65))
+}
+
+func (b *txBuilder) uintCV(v uint64) *txBuilder {
+	return b.u8(byte(clarity.TypeUInt)).u64(0).u64(v)
+}
+
+func (b *txBuilder) principalCV(version byte, hash [20]byte) *txBuilder {
+	return b.u8(byte(clarity.TypeStandardPrincipal)).u8(version).raw(hash[:])
+}
+
+func stxTransferBytes() []byte {
+	b := &txBuilder{}
+	b.header(7, 180)
+	b.u8(byte(AnchorModeAny)).u8(byte(PostConditionModeDeny)).u32(0)
+	b.u8(byte(PayloadTokenTransfer)).principalCV(valueobject.AddressVersionTestnetSingleSig, recipientHash).u64(1000000)
+	memo := make([]byte, 34)
+	copy(memo, "invoice-42")
+	b.raw(memo)
+	return b.buf
+}
+
+func contractCallBytes() []byte {
+	b := &txBuilder{}
+	b.header(3, 500)
+	b.u8(byte(AnchorModeAny)).u8(byte(PostConditionModeDeny)).u32(1)
+
+	// Fungible post-condition: origin sends exactly 2500 of the token
+	b.u8(byte(PostConditionFungible)).u8(byte(PostConditionPrincipalOrigin))
+	b.u8(valueobject.AddressVersionTestnetSingleSig).raw(recipientHash[:]).name("usdcx").name("usdcx-token")
+	b.u8(byte(ConditionEqual)).u64(2500)
+
+	b.u8(byte(PayloadContractCall)).u8(valueobject.AddressVersionTestnetSingleSig).raw(recipientHash[:])
+	b.name("usdcx").name("transfer").u32(4)
+	b.uintCV(2500)
+	b.principalCV(valueobject.AddressVersionTestnetSingleSig, signerHash)
+	b.principalCV(valueobject.AddressVersionTestnetSingleSig, recipientHash)
+	b.u8(byte(clarity.TypeNone))
+	return b.buf
+}
+
+func TestDecode_TokenTransfer(t *testing.T) {
+	tx, err := Decode(stxTransferBytes())
+	require.NoError(t, err)
+
+	network, err := tx.Network()
+	require.NoError(t, err)
+	assert.Equal(t, valueobject.NetworkTestnet, network)
+
+	assert.Equal(t, AuthStandard, tx.Auth.Type)
+	assert.False(t, tx.IsSponsored())
+	assert.Equal(t, uint64(7), tx.Auth.Origin.Nonce)
+	assert.Equal(t, uint64(180), tx.Fee())
+	assert.Equal(t, "ST2J6ZY48GV1EZ5V2V5RB9MP66SW86PYKKQYAC0RQ", tx.OriginAddress().String())
+	assert.Equal(t, AnchorModeAny, tx.AnchorMode)
+	assert.Equal(t, PostConditionModeDeny, tx.PostConditionMode)
+
+	payload, ok := tx.Payload.(TokenTransferPayload)
+	require.True(t, ok)
+	recipient, ok := clarity.PrincipalString(payload.Recipient)
+	require.True(t, ok)
+	assert.Equal(t, "ST1PQHQKV0RJXZFY1DGX8MNSNYVE3VGZJSRTPGZGM", recipient)
+	assert.Equal(t, uint64(1000000), payload.Amount)
+	assert.Equal(t, "invoice-42", payload.MemoString())
+}
+
+func TestDecode_ContractCallWithPostCondition(t *testing.T) {
+	tx, err := Decode(contractCallBytes())
+	require.NoError(t, err)
+
+	require.Len(t, tx.PostConditions, 1)
+	pc := tx.PostConditions[0]
+	assert.Equal(t, PostConditionFungible, pc.Type)
+	assert.Equal(t, PostConditionPrincipalOrigin, pc.Principal.Type)
+	assert.Equal(t, "ST1PQHQKV0RJXZFY1DGX8MNSNYVE3VGZJSRTPGZGM.usdcx", pc.Asset.ContractID())
+	assert.Equal(t, "usdcx-token", pc.Asset.AssetName)
+	assert.Equal(t, ConditionEqual, pc.Code)
+	assert.Equal(t, uint64(2500), pc.Amount)
+
+	call, ok := tx.Payload.(ContractCallPayload)
+	require.True(t, ok)
+	assert.Equal(t, "ST1PQHQKV0RJXZFY1DGX8MNSNYVE3VGZJSRTPGZGM.usdcx", call.ContractID())
+	assert.Equal(t, "transfer", call.FunctionName)
+	require.Len(t, call.Args, 4)
+	assert.Equal(t, clarity.UInt{Value: big.NewInt(2500)}, call.Args[0])
+	assert.Equal(t, clarity.None{}, call.Args[3])
+}
+
+func TestDecode_SponsoredMultisig(t *testing.T) {
+	b := &txBuilder{}
+	b.u8(byte(VersionMainnet)).u32(ChainIDMainnet).u8(byte(AuthSponsored))
+
+	// Origin: 2-of-2 P2SH with one signature and one public key
+	b.u8(byte(HashModeP2SH)).raw(signerHash[:]).u64(1).u64(0).u32(2)
+	b.u8(byte(AuthFieldSignatureCompressed)).raw(make([]byte, 65))
+	b.u8(byte(AuthFieldPublicKeyCompressed)).raw(make([]byte, 33))
+	b.u16(2)
+
+	// Sponsor: single-sig P2PKH paying the fee
+	b.u8(byte(HashModeP2PKH)).raw(recipientHash[:]).u64(9).u64(1000).u8(byte(PubKeyEncodingCompressed)).raw(make([]byte, 65))
+
+	b.u8(byte(AnchorModeOnChainOnly)).u8(byte(PostConditionModeAllow)).u32(0)
+	b.u8(byte(PayloadTokenTransfer)).principalCV(valueobject.AddressVersionMainnetSingleSig, recipientHash).u64(5).raw(make([]byte, 34))
+
+	tx, err := Decode(b.buf)
+	require.NoError(t, err)
+
+	network, err := tx.Network()
+	require.NoError(t, err)
+	assert.Equal(t, valueobject.NetworkMainnet, network)
+
+	assert.True(t, tx.IsSponsored())
+	require.NotNil(t, tx.Auth.Sponsor)
+	assert.Equal(t, uint64(1000), tx.Fee())
+	assert.Equal(t, uint16(2), tx.Auth.Origin.SignaturesRequired)
+	require.Len(t, tx.Auth.Origin.Fields, 2)
+	assert.True(t, tx.Auth.Origin.Fields[0].Type.IsSignature())
+	assert.False(t, tx.Auth.Origin.Fields[1].Type.IsSignature())
+	assert.True(t, tx.OriginAddress().IsMainnet())
+	assert.Equal(t, "SM2J6ZY48GV1EZ5V2V5RB9MP66SW86PYKKQVX8X0G", tx.OriginAddress().String())
+}
+
+func TestDecodeHex(t *testing.T) {
+	raw := hex.EncodeToString(stxTransferBytes())
+
+	_, err := DecodeHex(raw)
+	require.NoError(t, err)
+
+	_, err = DecodeHex("0x" + raw)
+	require.NoError(t, err)
+
+	_, err = DecodeHex("0xzz")
+	assert.ErrorIs(t, err, ErrMalformed)
+}
+
+func TestDecode_Errors(t *testing.T) {
+	valid := stxTransferBytes()
+
+	withByte := func(offset int, v byte) []byte {
+		b := append([]byte(nil), valid...)
+		b[offset] = v
+		return b
+	}
+
+	tests := []struct {
+		name string
+		data []byte
+	}{
+		{"empty", nil},
+		{"truncated", valid[:len(valid)-1]},
+		{"trailing bytes", append(append([]byte(nil), valid...), 0x00)},
+		{"unknown version", withByte(0, 0x01)},
+		{"unknown auth type", withByte(5, 0x07)},
+		{"unknown hash mode", withByte(6, 0x09)},
+		{"unknown key encoding", withByte(43, 0x02)},
+		{"unknown anchor mode", withByte(109, 0x04)},
+		{"unknown post-condition mode", withByte(110, 0x03)},
+		{"unknown payload", withByte(115, 0x09)},
+		{"non-principal recipient", withByte(116, byte(clarity.TypeUInt))},
+		{"oversized post-condition count", withByte(111, 0xff)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Decode(tt.data)
+			require.Error(t, err)
+			assert.True(t, errors.Is(err, ErrMalformed))
+		})
+	}
+}
+
+func TestTransaction_NetworkMismatch(t *testing.T) {
+	tx := &Transaction{Version: VersionTestnet, ChainID: ChainIDMainnet}
+
+	_, err := tx.Network()
+	assert.Error(t, err)
+}
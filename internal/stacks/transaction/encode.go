@@ -0,0 +1,83 @@
+package transaction
+
+import (
+	"crypto/sha512"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+
+	"github.com/x402stacks/stacks-facilitator/internal/payment/domain/valueobject"
+)
+
+// ErrNotDecoded is returned when encoding a transaction that was not produced by Decode
+var ErrNotDecoded = errors.New("transaction was not decoded from wire bytes")
+
+// Encode serializes the transaction. Only the header and authorization are
+// re-encoded; everything after them is written back exactly as decoded.
+func (t *Transaction) Encode() ([]byte, error) {
+	if t.body == nil {
+		return nil, ErrNotDecoded
+	}
+
+	buf := []byte{byte(t.Version)}
+	buf = binary.BigEndian.AppendUint32(buf, t.ChainID)
+	buf = appendAuthorization(buf, t.Auth)
+	return append(buf, t.body...), nil
+}
+
+// EncodeHex serializes the transaction as hex without a 0x prefix
+func (t *Transaction) EncodeHex() (string, error) {
+	data, err := t.Encode()
+	if err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(data), nil
+}
+
+// TxID returns the ID of the transaction: the SHA512/256 hash of its encoding
+func (t *Transaction) TxID() (valueobject.TransactionID, error) {
+	data, err := t.Encode()
+	if err != nil {
+		return valueobject.TransactionID{}, err
+	}
+	return TxID(data), nil
+}
+
+// TxID returns the ID of a serialized transaction: the SHA512/256 hash of its bytes
+func TxID(raw []byte) valueobject.TransactionID {
+	hash := sha512.Sum512_256(raw)
+	id, _ := valueobject.NewTransactionID(hex.EncodeToString(hash[:])) // 32 bytes of hex is always a valid ID
+	return id
+}
+
+func appendAuthorization(buf []byte, auth Authorization) []byte {
+	buf = append(buf, byte(auth.Type))
+	buf = appendSpendingCondition(buf, auth.Origin)
+	if auth.Type == AuthSponsored && auth.Sponsor != nil {
+		buf = appendSpendingCondition(buf, *auth.Sponsor)
+	}
+	return buf
+}
+
+func appendSpendingCondition(buf []byte, c SpendingCondition) []byte {
+	buf = append(buf, byte(c.HashMode))
+	buf = append(buf, c.Signer[:]...)
+	buf = binary.BigEndian.AppendUint64(buf, c.Nonce)
+	buf = binary.BigEndian.AppendUint64(buf, c.Fee)
+
+	if c.HashMode.IsSingleSig() {
+		buf = append(buf, byte(c.KeyEncoding))
+		return append(buf, c.Signature[:]...)
+	}
+
+	buf = binary.BigEndian.AppendUint32(buf, uint32(len(c.Fields)))
+	for _, field := range c.Fields {
+		buf = append(buf, byte(field.Type))
+		if field.Type.IsSignature() {
+			buf = append(buf, field.Signature[:]...)
+		} else {
+			buf = append(buf, field.PublicKey[:]...)
+		}
+	}
+	return binary.BigEndian.AppendUint16(buf, c.SignaturesRequired)
+}
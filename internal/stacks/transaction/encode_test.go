@@ -0,0 +1,157 @@
+package transaction
+
+import (
+	"encoding/hex"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/x402stacks/stacks-facilitator/internal/payment/domain/valueobject"
+)
+
+// sponsoredTransferBytes is a testnet STX transfer with a single-sig origin and a blank sponsor
+func sponsoredTransferBytes() []byte {
+	b := &txBuilder{}
+	b.u8(byte(VersionTestnet)).u32(ChainIDTestnet).u8(byte(AuthSponsored))
+	b.u8(byte(HashModeP2PKH)).raw(signerHash[:]).u64(4).u64(0).u8(byte(PubKeyEncodingCompressed)).raw(make([]byte, 65))
+	b.u8(byte(HashModeP2PKH)).raw(make([]byte, 20)).u64(0).u64(0).u8(byte(PubKeyEncodingCompressed)).raw(make([]byte, 65))
+	b.u8(byte(AnchorModeAny)).u8(byte(PostConditionModeDeny)).u32(0)
+	b.u8(byte(PayloadTokenTransfer)).principalCV(valueobject.AddressVersionTestnetSingleSig, recipientHash).u64(1000).raw(make([]byte, 34))
+	return b.buf
+}
+
+// Sponsored testnet transactions assembled field by field from the SIP-005 wire format and
+// signed with the Clarinet devnet keys: wallet_1 (ST1SJ3DTE5DN7X54YDH5D64R3BCB6A2AG2ZQ8YPD5)
+// is the origin and the deployer (ST1PQHQKV0RJXZFY1DGX8MNSNYVE3VGZJSRTPGZGM) the sponsor.
+// Their bytes and txids are pinned so the encoder and sighash chain are checked against
+// fixed known answers rather than against their own output.
+const (
+	// 2.5 STX to wallet_2 with memo "x402 payment"; origin nonce 12, sponsor nonce 3, fee 180
+	knownSponsoredTransferHex  = "808000000005007321b74e2b6a7e949e6c4ad313035b1665095017000000000000000c00000000000000000000e81b851d4e17a2e9ffb9121dbe22852eea2dfe1d6a44da0a3777304e436ff51b174d71a5c9c074378ced4c307d13d7920652e71293dcf5f5914d213ec34f7cc1006d78de7b0625dfbfc16c3a8a5735f6dc3dc3f2ce000000000000000300000000000000b40000fcd3bad08344a47a95ae2917dc7bc4ad2224c30f043c77f22a6d8260f458ff890856c344024e714aa35bb70ad3d5ad274468e00f935edbe325ea92535b61f82e03020000000000051a99e2ec69ac5b6e67b4e26edd0e2c1c1a6b9bbd2300000000002625a078343032207061796d656e7400000000000000000000000000000000000000000000"
+	knownSponsoredTransferTxID = "0xc5120983070aeefa3a619a843afaad157fb0c5908d5229599b788696ddb3e632"
+
+	// sbtc-token transfer of 2500 to wallet_2 with a sent-equal post-condition; origin nonce 13, sponsor nonce 4, fee 300
+	knownSponsoredContractCallHex  = "808000000005007321b74e2b6a7e949e6c4ad313035b1665095017000000000000000d000000000000000000004570f367299845d9d49042cb55692140e8e5b920660551a24fcbc4bccc2a4d5d7db618ca34e37f0e67f4b05f6fc67a70aed2d17ff0d13cdb4be1d1a627c4e153006d78de7b0625dfbfc16c3a8a5735f6dc3dc3f2ce0000000000000004000000000000012c0000466610ad26eab3fb2fbf7aa231dff0a426713d85a2595e63d237d116a8310b0e5d0296d77ba60f4e6a220c7d7e7e97109d8b064a468e0ae8c68ae1aade43657603020000000101011a5e7ba8546bc27ca0077594336b3942a8e7f893520a736274632d746f6b656e0a736274632d746f6b656e0100000000000009c4021a5e7ba8546bc27ca0077594336b3942a8e7f893520a736274632d746f6b656e087472616e736665720000000401000000000000000000000000000009c4051a7321b74e2b6a7e949e6c4ad313035b1665095017051a99e2ec69ac5b6e67b4e26edd0e2c1c1a6b9bbd2309"
+	knownSponsoredContractCallTxID = "0x2b0257a732971a1cdfbedf9bd82bfd1e0bc3cf56b772e6745efe9a80e0d14d5c"
+
+	devnetDeployerKey = "753b7cc01a1a2e86221266a154af739463fce51219d97e4f856cd7200c3bd2a601"
+	devnetWallet1Key  = "7287ba251d44a4d3fd9276c88ce34c5c52a038955511cccaf77e61068649c17801"
+)
+
+var knownSponsoredTransactions = []struct {
+	name   string
+	hex    string
+	txID   string
+	nonce  uint64 // Sponsor nonce
+	fee    uint64
+	origin uint64 // Origin nonce
+}{
+	{"stx transfer", knownSponsoredTransferHex, knownSponsoredTransferTxID, 3, 180, 12},
+	{"sip-010 contract call", knownSponsoredContractCallHex, knownSponsoredContractCallTxID, 4, 300, 13},
+}
+
+func TestKnownSponsoredTransactions_RoundTrip(t *testing.T) {
+	for _, known := range knownSponsoredTransactions {
+		t.Run(known.name, func(t *testing.T) {
+			raw, err := hex.DecodeString(known.hex)
+			require.NoError(t, err)
+			assert.Equal(t, known.txID, TxID(raw).String())
+
+			tx, err := Decode(raw)
+			require.NoError(t, err)
+			require.True(t, tx.IsSponsored())
+			assert.Equal(t, known.origin, tx.Auth.Origin.Nonce)
+			assert.Equal(t, known.nonce, tx.Auth.Sponsor.Nonce)
+			assert.Equal(t, known.fee, tx.Fee())
+
+			encoded, err := tx.Encode()
+			require.NoError(t, err)
+			assert.Equal(t, raw, encoded)
+
+			txID, err := tx.TxID()
+			require.NoError(t, err)
+			assert.Equal(t, known.txID, txID.String())
+
+			origin, err := tx.RecoverOrigin()
+			require.NoError(t, err)
+			assert.Equal(t, "ST1SJ3DTE5DN7X54YDH5D64R3BCB6A2AG2ZQ8YPD5", origin.String())
+			sponsor := valueobject.NewStacksAddressFromHash160(valueobject.AddressVersionTestnetSingleSig, tx.Auth.Sponsor.Signer)
+			assert.Equal(t, "ST1PQHQKV0RJXZFY1DGX8MNSNYVE3VGZJSRTPGZGM", sponsor.String())
+		})
+	}
+
+	tx, err := DecodeHex(knownSponsoredContractCallHex)
+	require.NoError(t, err)
+	call, ok := tx.Payload.(ContractCallPayload)
+	require.True(t, ok)
+	assert.Equal(t, "ST1F7QA2MDF17S807EPA36TSS8AMEFY4KA9TVGWXT.sbtc-token", call.ContractID())
+	assert.Equal(t, "transfer", call.FunctionName)
+	require.Len(t, tx.PostConditions, 1)
+	assert.Equal(t, "sbtc-token", tx.PostConditions[0].Asset.AssetName)
+}
+
+func TestEncode_RoundTrip(t *testing.T) {
+	tests := []struct {
+		name string
+		data []byte
+	}{
+		{"token transfer", stxTransferBytes()},
+		{"contract call", contractCallBytes()},
+		{"sponsored", sponsoredTransferBytes()},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tx, err := Decode(tt.data)
+			require.NoError(t, err)
+
+			encoded, err := tx.Encode()
+			require.NoError(t, err)
+			assert.Equal(t, tt.data, encoded)
+
+			encodedHex, err := tx.EncodeHex()
+			require.NoError(t, err)
+			assert.Equal(t, hex.EncodeToString(tt.data), encodedHex)
+		})
+	}
+}
+
+func TestEncode_ReflectsAuthChanges(t *testing.T) {
+	tx, err := Decode(sponsoredTransferBytes())
+	require.NoError(t, err)
+
+	tx.Auth.Sponsor.Fee = 2000
+	encoded, err := tx.Encode()
+	require.NoError(t, err)
+
+	decoded, err := Decode(encoded)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(2000), decoded.Fee())
+	assert.Equal(t, tx.Payload, decoded.Payload)
+}
+
+func TestEncode_NotDecoded(t *testing.T) {
+	tx := &Transaction{Version: VersionTestnet, ChainID: ChainIDTestnet}
+
+	_, err := tx.Encode()
+	assert.ErrorIs(t, err, ErrNotDecoded)
+}
+
+func TestTxID(t *testing.T) {
+	// SHA512/256 of the four bytes 00000001 and deadbeef
+	raw, err := hex.DecodeString("00000001deadbeef")
+	require.NoError(t, err)
+	assert.Equal(t, "0xf0c4b6db6c7ce3e523a945d2051f3e7697886e60ce9271848f3acd0673b1852e", TxID(raw).String())
+
+	tx, err := Decode(sponsoredTransferBytes())
+	require.NoError(t, err)
+	txID, err := tx.TxID()
+	require.NoError(t, err)
+	assert.Equal(t, TxID(sponsoredTransferBytes()), txID)
+
+	// Signing as sponsor changes the bytes, and so the txid
+	tx.Auth.Sponsor.Fee = 2000
+	sponsored, err := tx.TxID()
+	require.NoError(t, err)
+	assert.NotEqual(t, txID, sponsored)
+}
@@ -0,0 +1,109 @@
+package transaction
+
+import (
+	"bytes"
+
+	"github.com/x402stacks/stacks-facilitator/internal/stacks/clarity"
+)
+
+// PayloadType identifies the kind of transaction payload
+type PayloadType byte
+
+const (
+	PayloadTokenTransfer          PayloadType = 0x00
+	PayloadSmartContract          PayloadType = 0x01
+	PayloadContractCall           PayloadType = 0x02
+	PayloadPoisonMicroblock       PayloadType = 0x03
+	PayloadCoinbase               PayloadType = 0x04
+	PayloadCoinbaseToAltRecipient PayloadType = 0x05
+	PayloadVersionedSmartContract PayloadType = 0x06
+	PayloadTenureChange           PayloadType = 0x07
+	PayloadNakamotoCoinbase       PayloadType = 0x08
+)
+
+// Payload is a decoded transaction payload
+type Payload interface {
+	PayloadType() PayloadType
+}
+
+// TokenTransferPayload is a native STX transfer
+type TokenTransferPayload struct {
+	Recipient clarity.Value // StandardPrincipal or ContractPrincipal
+	Amount    uint64
+	Memo      [34]byte
+}
+
+// MemoString returns the memo with trailing zero padding removed
+func (p TokenTransferPayload) MemoString() string {
+	return string(bytes.TrimRight(p.Memo[:], "\x00"))
+}
+
+// ContractCallPayload is a public function call
+type ContractCallPayload struct {
+	Address      clarity.StandardPrincipal
+	ContractName string
+	FunctionName string
+	Args         []clarity.Value
+}
+
+// ContractID returns the called contract principal
+func (p ContractCallPayload) ContractID() string {
+	return clarity.ContractPrincipal{Issuer: p.Address, Name: p.ContractName}.String()
+}
+
+// SmartContractPayload deploys a contract; ClarityVersion is set for versioned deploys
+type SmartContractPayload struct {
+	ClarityVersion *byte
+	Name           string
+	Code           string
+}
+
+// PoisonMicroblockPayload reports two conflicting microblock headers
+type PoisonMicroblockPayload struct {
+	Header1 []byte
+	Header2 []byte
+}
+
+// CoinbasePayload is a miner coinbase, optionally paid to an alternate recipient
+type CoinbasePayload struct {
+	Buffer    [32]byte
+	Recipient clarity.Value // Nil when paid to the miner
+	VRFProof  []byte        // Nakamoto coinbase only (80 bytes)
+	Nakamoto  bool
+}
+
+// TenureChangePayload starts or extends a Nakamoto tenure
+type TenureChangePayload struct {
+	TenureConsensusHash     [20]byte
+	PrevTenureConsensusHash [20]byte
+	BurnViewConsensusHash   [20]byte
+	PreviousTenureEnd       [32]byte
+	PreviousTenureBlocks    uint32
+	Cause                   byte
+	PubKeyHash              [20]byte
+}
+
+func (TokenTransferPayload) PayloadType() PayloadType    { return PayloadTokenTransfer }
+func (ContractCallPayload) PayloadType() PayloadType     { return PayloadContractCall }
+func (PoisonMicroblockPayload) PayloadType() PayloadType { return PayloadPoisonMicroblock }
+func (TenureChangePayload) PayloadType() PayloadType     { return PayloadTenureChange }
+
+// PayloadType returns PayloadSmartContract or PayloadVersionedSmartContract
+func (p SmartContractPayload) PayloadType() PayloadType {
+	if p.ClarityVersion != nil {
+		return PayloadVersionedSmartContract
+	}
+	return PayloadSmartContract
+}
+
+// PayloadType returns the coinbase variant
+func (p CoinbasePayload) PayloadType() PayloadType {
+	switch {
+	case p.Nakamoto:
+		return PayloadNakamotoCoinbase
+	case p.Recipient != nil:
+		return PayloadCoinbaseToAltRecipient
+	default:
+		return PayloadCoinbase
+	}
+}
@@ -0,0 +1,119 @@
+package transaction
+
+import (
+	"crypto/sha512"
+	"encoding/binary"
+	"errors"
+
+	"github.com/x402stacks/stacks-facilitator/internal/stacks/secp256k1"
+)
+
+// InitialSigHash returns the hash every signature chain starts from: the
+// transaction ID with the origin cleared and the sponsor reset to a blank
+// P2PKH condition
+func (t *Transaction) InitialSigHash() ([32]byte, error) {
+	cleared := *t
+	cleared.Auth = Authorization{Type: t.Auth.Type, Origin: t.Auth.Origin.cleared()}
+	if t.Auth.Type == AuthSponsored {
+		cleared.Auth.Sponsor = &SpendingCondition{HashMode: HashModeP2PKH, KeyEncoding: PubKeyEncodingCompressed}
+	}
+
+	data, err := cleared.Encode()
+	if err != nil {
+		return [32]byte{}, err
+	}
+	return sha512.Sum512_256(data), nil
+}
+
+// OriginSigHash returns the sighash after the origin's signatures, which is
+// the starting point for the sponsor's signature
+func (t *Transaction) OriginSigHash() ([32]byte, error) {
+	initial, err := t.InitialSigHash()
+	if err != nil {
+		return [32]byte{}, err
+	}
+	return t.Auth.Origin.finalSigHash(initial, AuthStandard), nil
+}
+
+// SignSponsor sets the sponsor spending condition to a single-sig P2PKH
+// condition for key with the given nonce and fee, and signs it
+func (t *Transaction) SignSponsor(key *secp256k1.PrivateKey, nonce, fee uint64) error {
+	if !t.IsSponsored() {
+		return errors.New("transaction is not sponsored")
+	}
+
+	sponsor := SpendingCondition{
+		HashMode:    HashModeP2PKH,
+		Signer:      key.PublicKey().Hash160(),
+		Nonce:       nonce,
+		Fee:         fee,
+		KeyEncoding: PubKeyEncodingCompressed,
+	}
+	if !key.Compressed() {
+		sponsor.KeyEncoding = PubKeyEncodingUncompressed
+	}
+
+	originHash, err := t.OriginSigHash()
+	if err != nil {
+		return err
+	}
+	sponsor.Signature = key.Sign(presignSigHash(originHash, AuthSponsored, fee, nonce))
+
+	t.Auth.Sponsor = &sponsor
+	return nil
+}
+
+// cleared returns the condition with nonce, fee and signatures removed
+func (c SpendingCondition) cleared() SpendingCondition {
+	out := SpendingCondition{
+		HashMode:           c.HashMode,
+		Signer:             c.Signer,
+		KeyEncoding:        c.KeyEncoding,
+		SignaturesRequired: c.SignaturesRequired,
+	}
+	if !c.HashMode.IsSingleSig() {
+		out.Fields = []AuthField{}
+	}
+	return out
+}
+
+// finalSigHash walks the condition's signatures starting from cur and
+// returns the sighash after the last one
+func (c SpendingCondition) finalSigHash(cur [32]byte, authType AuthType) [32]byte {
+	if c.HashMode.IsSingleSig() {
+		presign := presignSigHash(cur, authType, c.Fee, c.Nonce)
+		return postsignSigHash(presign, c.KeyEncoding, c.Signature)
+	}
+	if c.HashMode == HashModeP2SHNonSequential || c.HashMode == HashModeP2WSHNonSequential {
+		// Non-sequential multisig signers all sign the presign hash, so the chain does not advance
+		return cur
+	}
+
+	for _, field := range c.Fields {
+		if !field.Type.IsSignature() {
+			continue
+		}
+		encoding := PubKeyEncodingCompressed
+		if field.Type == AuthFieldSignatureUncompressed {
+			encoding = PubKeyEncodingUncompressed
+		}
+		presign := presignSigHash(cur, authType, c.Fee, c.Nonce)
+		cur = postsignSigHash(presign, encoding, field.Signature)
+	}
+	return cur
+}
+
+// presignSigHash is the hash a signer signs: SHA512/256(cur || auth type || fee || nonce)
+func presignSigHash(cur [32]byte, authType AuthType, fee, nonce uint64) [32]byte {
+	buf := append(cur[:], byte(authType))
+	buf = binary.BigEndian.AppendUint64(buf, fee)
+	buf = binary.BigEndian.AppendUint64(buf, nonce)
+	return sha512.Sum512_256(buf)
+}
+
+// postsignSigHash chains a signature into the next sighash: SHA512/256(presign || key encoding || signature)
+func postsignSigHash(presign [32]byte, encoding PubKeyEncoding, sig Signature) [32]byte {
+	buf := append(presign[:], byte(encoding))
+	buf = append(buf, sig[:]...)
+	return sha512.Sum512_256(buf)
+}
@@ -0,0 +1,179 @@
+package transaction
+
+import (
+	"crypto/sha512"
+	"encoding/hex"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/x402stacks/stacks-facilitator/internal/stacks/secp256k1"
+)
+
+func TestInitialSigHash_IgnoresSignaturesNonceAndFee(t *testing.T) {
+	tx, err := Decode(stxTransferBytes())
+	require.NoError(t, err)
+	initial, err := tx.InitialSigHash()
+	require.NoError(t, err)
+
+	tx.Auth.Origin.Nonce = 99
+	tx.Auth.Origin.Fee = 12345
+	tx.Auth.Origin.Signature[1] = 0xaa
+	changed, err := tx.InitialSigHash()
+	require.NoError(t, err)
+	assert.Equal(t, initial, changed)
+
+	// The signer hash is covered
+	tx.Auth.Origin.Signer[0] ^= 0xff
+	changed, err = tx.InitialSigHash()
+	require.NoError(t, err)
+	assert.NotEqual(t, initial, changed)
+}
+
+func TestInitialSigHash_ClearsMultisigFields(t *testing.T) {
+	tx, err := Decode(stxTransferBytes())
+	require.NoError(t, err)
+	tx.Auth.Origin = SpendingCondition{
+		HashMode:           HashModeP2SH,
+		Signer:             signerHash,
+		Nonce:              3,
+		Fields:             []AuthField{{Type: AuthFieldPublicKeyCompressed}},
+		SignaturesRequired: 1,
+	}
+
+	withFields, err := tx.InitialSigHash()
+	require.NoError(t, err)
+
+	tx.Auth.Origin.Fields = nil
+	tx.Auth.Origin.Nonce = 0
+	withoutFields, err := tx.InitialSigHash()
+	require.NoError(t, err)
+	assert.Equal(t, withFields, withoutFields)
+}
+
+func TestOriginSigHash_SingleSig(t *testing.T) {
+	tx, err := Decode(stxTransferBytes())
+	require.NoError(t, err)
+
+	initial, err := tx.InitialSigHash()
+	require.NoError(t, err)
+	origin, err := tx.OriginSigHash()
+	require.NoError(t, err)
+
+	presign := presignSigHash(initial, AuthStandard, 180, 7)
+	expected := sha512.Sum512_256(append(append(presign[:], byte(PubKeyEncodingCompressed)), tx.Auth.Origin.Signature[:]...))
+	assert.Equal(t, expected, origin)
+}
+
+func TestSignSponsor(t *testing.T) {
+	key, err := secp256k1.ParsePrivateKey(strings.Repeat("42", 32) + "01")
+	require.NoError(t, err)
+
+	tx, err := Decode(sponsoredTransferBytes())
+	require.NoError(t, err)
+	initialBefore, err := tx.InitialSigHash()
+	require.NoError(t, err)
+
+	require.NoError(t, tx.SignSponsor(key, 11, 2500))
+
+	sponsor := tx.Auth.Sponsor
+	require.NotNil(t, sponsor)
+	assert.Equal(t, HashModeP2PKH, sponsor.HashMode)
+	assert.Equal(t, key.PublicKey().Hash160(), sponsor.Signer)
+	assert.Equal(t, uint64(11), sponsor.Nonce)
+	assert.Equal(t, uint64(2500), sponsor.Fee)
+	assert.Equal(t, PubKeyEncodingCompressed, sponsor.KeyEncoding)
+
+	// Sponsoring must not disturb the origin's signature chain
+	initialAfter, err := tx.InitialSigHash()
+	require.NoError(t, err)
+	assert.Equal(t, initialBefore, initialAfter)
+
+	originHash, err := tx.OriginSigHash()
+	require.NoError(t, err)
+	assert.Equal(t, Signature(key.Sign(presignSigHash(originHash, AuthSponsored, 2500, 11))), sponsor.Signature)
+
+	encoded, err := tx.Encode()
+	require.NoError(t, err)
+	decoded, err := Decode(encoded)
+	require.NoError(t, err)
+	assert.Equal(t, *sponsor, *decoded.Auth.Sponsor)
+	assert.Equal(t, uint64(2500), decoded.Fee())
+}
+
+// Offsets of the origin and sponsor spending conditions in a raw sponsored transaction whose
+// origin and sponsor are both single-sig: after version, chain ID and auth type, then after the origin
+const knownOriginAt, knownSponsorAt = 6, 109
+
+// specOriginSigHash computes the hash the origin of such a transaction signs, following SIP-005
+// directly on the wire bytes: clear the origin's nonce, fee and signature and blank the sponsor,
+// hash the result, then take the origin's presign hash over its own fee and nonce
+func specOriginSigHash(raw []byte) [32]byte {
+	cleared := append([]byte(nil), raw...)
+	clear(cleared[knownOriginAt+21 : knownOriginAt+37])  // Nonce and fee
+	clear(cleared[knownOriginAt+38 : knownOriginAt+103]) // Signature
+	clear(cleared[knownSponsorAt : knownSponsorAt+103])  // P2PKH, zero signer, nonce, fee, compressed, no signature
+	initial := sha512.Sum512_256(cleared)
+	return specPresign(initial[:], raw, AuthStandard, knownOriginAt)
+}
+
+// specSponsorSigHash chains the origin's postsign hash into the sponsor's presign hash
+func specSponsorSigHash(raw []byte) [32]byte {
+	originPresign := specOriginSigHash(raw)
+	originPostsign := sha512.Sum512_256(append(originPresign[:], raw[knownOriginAt+37:knownOriginAt+103]...))
+	return specPresign(originPostsign[:], raw, AuthSponsored, knownSponsorAt)
+}
+
+func specPresign(cur, raw []byte, authType AuthType, conditionAt int) [32]byte {
+	buf := append(append([]byte(nil), cur...), byte(authType))
+	buf = append(buf, raw[conditionAt+29:conditionAt+37]...) // Fee
+	buf = append(buf, raw[conditionAt+21:conditionAt+29]...) // Nonce
+	return sha512.Sum512_256(buf)
+}
+
+func TestSignSponsor_KnownTransactions(t *testing.T) {
+	deployer, err := secp256k1.ParsePrivateKey(devnetDeployerKey)
+	require.NoError(t, err)
+	wallet1, err := secp256k1.ParsePrivateKey(devnetWallet1Key)
+	require.NoError(t, err)
+
+	for _, known := range knownSponsoredTransactions {
+		t.Run(known.name, func(t *testing.T) {
+			raw, err := hex.DecodeString(known.hex)
+			require.NoError(t, err)
+			tx, err := Decode(raw)
+			require.NoError(t, err)
+
+			// Each signature was made over its SIP-005 sighash by the matching devnet key
+			origin, err := secp256k1.RecoverPublicKey(specOriginSigHash(raw), tx.Auth.Origin.Signature, true)
+			require.NoError(t, err)
+			assert.Equal(t, wallet1.PublicKey().Bytes(), origin.Bytes())
+
+			sigHash := specSponsorSigHash(raw)
+			originHash, err := tx.OriginSigHash()
+			require.NoError(t, err)
+			assert.Equal(t, sigHash, presignSigHash(originHash, AuthSponsored, known.fee, known.nonce))
+			signer, err := secp256k1.RecoverPublicKey(sigHash, tx.Auth.Sponsor.Signature, true)
+			require.NoError(t, err)
+			assert.Equal(t, deployer.PublicKey().Bytes(), signer.Bytes())
+
+			// Sponsoring the origin-signed transaction again reproduces it byte for byte
+			tx.Auth.Sponsor = &SpendingCondition{HashMode: HashModeP2PKH, KeyEncoding: PubKeyEncodingCompressed}
+			require.NoError(t, tx.SignSponsor(deployer, known.nonce, known.fee))
+			encoded, err := tx.Encode()
+			require.NoError(t, err)
+			assert.Equal(t, raw, encoded)
+			assert.Equal(t, known.txID, TxID(encoded).String())
+		})
+	}
+}
+
+func TestSignSponsor_NotSponsored(t *testing.T) {
+	key, err := secp256k1.ParsePrivateKey(strings.Repeat("42", 32))
+	require.NoError(t, err)
+
+	tx, err := Decode(stxTransferBytes())
+	require.NoError(t, err)
+	assert.Error(t, tx.SignSponsor(key, 0, 100))
+}
@@ -0,0 +1,178 @@
+package transaction
+
+import (
+	"crypto/sha256"
+	"errors"
+	"fmt"
+
+	"github.com/x402stacks/stacks-facilitator/internal/payment/domain/valueobject"
+	"github.com/x402stacks/stacks-facilitator/internal/stacks/secp256k1"
+)
+
+// ErrInvalidSignature is returned when a spending condition's signatures were not
+// made by the keys its signer hash commits to
+var ErrInvalidSignature = errors.New("invalid signature")
+
+// Script opcodes used in multisig redeem scripts
+const (
+	opPushNum1      = 0x51
+	opCheckMultisig = 0xae
+)
+
+// RecoverOrigin recovers the public keys behind the origin's signatures and returns the
+// address they hash to. The address must match the origin's signer hash; a sponsor's
+// signature is not part of the origin's and does not affect the result.
+func (t *Transaction) RecoverOrigin() (valueobject.StacksAddress, error) {
+	initial, err := t.InitialSigHash()
+	if err != nil {
+		return valueobject.StacksAddress{}, err
+	}
+
+	origin := t.Auth.Origin
+	signer, err := origin.recoverSigner(initial, AuthStandard)
+	if err != nil {
+		return valueobject.StacksAddress{}, err
+	}
+	if signer != origin.Signer {
+		return valueobject.StacksAddress{}, fmt.Errorf("%w: signature does not match signer %x", ErrInvalidSignature, origin.Signer)
+	}
+	return valueobject.NewStacksAddressFromHash160(origin.AddressVersion(t.Version), signer), nil
+}
+
+// recoverSigner recovers the keys that signed the condition, starting the sighash chain
+// from cur, and returns the signer hash they commit to
+func (c SpendingCondition) recoverSigner(cur [32]byte, authType AuthType) ([20]byte, error) {
+	switch c.HashMode {
+	case HashModeP2PKH, HashModeP2WPKH:
+		return c.recoverSingleSig(cur, authType)
+	case HashModeP2SH, HashModeP2WSH:
+		return c.recoverMultisig(cur, authType, true)
+	case HashModeP2SHNonSequential, HashModeP2WSHNonSequential:
+		return c.recoverMultisig(cur, authType, false)
+	default:
+		return [20]byte{}, fmt.Errorf("%w: unknown hash mode 0x%02x", ErrInvalidSignature, byte(c.HashMode))
+	}
+}
+
+func (c SpendingCondition) recoverSingleSig(cur [32]byte, authType AuthType) ([20]byte, error) {
+	compressed := c.KeyEncoding == PubKeyEncodingCompressed
+	if c.HashMode == HashModeP2WPKH && !compressed {
+		return [20]byte{}, fmt.Errorf("%w: P2WPKH requires a compressed public key", ErrInvalidSignature)
+	}
+
+	key, err := secp256k1.RecoverPublicKey(presignSigHash(cur, authType, c.Fee, c.Nonce), c.Signature, compressed)
+	if err != nil {
+		return [20]byte{}, fmt.Errorf("%w: %v", ErrInvalidSignature, err)
+	}
+	return singleSigSignerHash(c.HashMode, key), nil
+}
+
+// recoverMultisig collects the condition's keys in field order, recovering one from each
+// signature. Sequential signers each sign the presign hash following the previous
+// signature and must number exactly SignaturesRequired; non-sequential signers all sign
+// the presign hash of cur and must number at least SignaturesRequired.
+func (c SpendingCondition) recoverMultisig(cur [32]byte, authType AuthType, sequential bool) ([20]byte, error) {
+	keys := make([][]byte, 0, len(c.Fields))
+	signatures := 0
+	uncompressed := false
+	presign := presignSigHash(cur, authType, c.Fee, c.Nonce)
+
+	for i, field := range c.Fields {
+		compressed := field.Type == AuthFieldPublicKeyCompressed || field.Type == AuthFieldSignatureCompressed
+		uncompressed = uncompressed || !compressed
+
+		if !field.Type.IsSignature() {
+			key, err := secp256k1.ParsePublicKey(field.PublicKey[:])
+			if err != nil {
+				return [20]byte{}, fmt.Errorf("%w: field %d: %v", ErrInvalidSignature, i, err)
+			}
+			keys = append(keys, serializeKey(key, compressed))
+			continue
+		}
+
+		key, err := secp256k1.RecoverPublicKey(presign, field.Signature, compressed)
+		if err != nil {
+			return [20]byte{}, fmt.Errorf("%w: field %d: %v", ErrInvalidSignature, i, err)
+		}
+		keys = append(keys, key.Bytes())
+		signatures++
+
+		if sequential {
+			encoding := PubKeyEncodingCompressed
+			if !compressed {
+				encoding = PubKeyEncodingUncompressed
+			}
+			presign = presignSigHash(postsignSigHash(presign, encoding, field.Signature), authType, c.Fee, c.Nonce)
+		}
+	}
+
+	required := int(c.SignaturesRequired)
+	switch {
+	case sequential && signatures != required:
+		return [20]byte{}, fmt.Errorf("%w: %d signatures, %d required", ErrInvalidSignature, signatures, required)
+	case signatures < required:
+		return [20]byte{}, fmt.Errorf("%w: %d signatures, at least %d required", ErrInvalidSignature, signatures, required)
+	}
+
+	witness := c.HashMode == HashModeP2WSH || c.HashMode == HashModeP2WSHNonSequential
+	if witness && uncompressed {
+		return [20]byte{}, fmt.Errorf("%w: P2WSH requires compressed public keys", ErrInvalidSignature)
+	}
+	return multisigSignerHash(witness, required, keys), nil
+}
+
+// singleSigSignerHash returns the signer hash a single-sig condition commits to for key:
+// the key's hash160 for P2PKH, or the hash160 of its P2WPKH witness program
+func singleSigSignerHash(mode HashMode, key *secp256k1.PublicKey) [20]byte {
+	if mode == HashModeP2WPKH {
+		keyHash := key.Hash160()
+		return secp256k1.Hash160(append([]byte{0x00, 0x14}, keyHash[:]...))
+	}
+	return key.Hash160()
+}
+
+// multisigSignerHash returns the signer hash of an m-of-n multisig: the hash160 of its
+// redeem script `m <keys> n OP_CHECKMULTISIG`, or for a witness condition the hash160 of
+// the P2WSH program wrapping the script's SHA256
+func multisigSignerHash(witness bool, required int, keys [][]byte) [20]byte {
+	script := appendScriptInt(nil, int64(required))
+	for _, key := range keys {
+		script = append(append(script, byte(len(key))), key...)
+	}
+	script = appendScriptInt(script, int64(len(keys)))
+	script = append(script, opCheckMultisig)
+
+	if witness {
+		digest := sha256.Sum256(script)
+		return secp256k1.Hash160(append([]byte{0x00, 0x20}, digest[:]...))
+	}
+	return secp256k1.Hash160(script)
+}
+
+// appendScriptInt appends the minimal script push of a non-negative integer: OP_0, OP_1
+// to OP_16, or a little-endian number with a clear sign bit
+func appendScriptInt(script []byte, n int64) []byte {
+	switch {
+	case n == 0:
+		return append(script, 0x00)
+	case n <= 16:
+		return append(script, byte(opPushNum1+n-1))
+	}
+
+	var num []byte
+	for ; n > 0; n >>= 8 {
+		num = append(num, byte(n))
+	}
+	if num[len(num)-1]&0x80 != 0 {
+		num = append(num, 0x00)
+	}
+	return append(append(script, byte(len(num))), num...)
+}
+
+// serializeKey returns the SEC1 encoding of key in the requested form
+func serializeKey(key *secp256k1.PublicKey, compressed bool) []byte {
+	if compressed {
+		return key.SerializeCompressed()
+	}
+	return key.SerializeUncompressed()
+}
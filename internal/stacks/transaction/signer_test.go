@@ -0,0 +1,354 @@
+package transaction
+
+import (
+	"encoding/hex"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/x402stacks/stacks-facilitator/internal/stacks/secp256k1"
+)
+
+// signOrigin makes key the single-sig origin of tx and signs it
+func signOrigin(t *testing.T, tx *Transaction, key *secp256k1.PrivateKey, mode HashMode) {
+	t.Helper()
+	tx.Auth.Origin.HashMode = mode
+	tx.Auth.Origin.Signer = singleSigSignerHash(mode, key.PublicKey())
+	tx.Auth.Origin.KeyEncoding = PubKeyEncodingCompressed
+	if !key.Compressed() {
+		tx.Auth.Origin.KeyEncoding = PubKeyEncodingUncompressed
+	}
+
+	initial, err := tx.InitialSigHash()
+	require.NoError(t, err)
+	tx.Auth.Origin.Signature = key.Sign(presignSigHash(initial, AuthStandard, tx.Auth.Origin.Fee, tx.Auth.Origin.Nonce))
+}
+
+// signMultisigOrigin makes keys a required-of-len(keys) multisig origin of tx, signed by
+// the first signers keys in field order and listing the others as public keys
+func signMultisigOrigin(t *testing.T, tx *Transaction, mode HashMode, required uint16, keys []*secp256k1.PrivateKey, signers int) {
+	t.Helper()
+	origin := &tx.Auth.Origin
+	origin.HashMode = mode
+	origin.SignaturesRequired = required
+	origin.Fields = make([]AuthField, len(keys))
+
+	serialized := make([][]byte, len(keys))
+	for i, key := range keys {
+		serialized[i] = key.PublicKey().Bytes()
+	}
+	witness := mode == HashModeP2WSH || mode == HashModeP2WSHNonSequential
+	origin.Signer = multisigSignerHash(witness, int(required), serialized)
+
+	initial, err := tx.InitialSigHash()
+	require.NoError(t, err)
+	presign := presignSigHash(initial, AuthStandard, origin.Fee, origin.Nonce)
+	sequential := mode == HashModeP2SH || mode == HashModeP2WSH
+
+	for i, key := range keys {
+		if i >= signers {
+			origin.Fields[i] = AuthField{Type: AuthFieldPublicKeyCompressed}
+			if !key.Compressed() {
+				origin.Fields[i].Type = AuthFieldPublicKeyUncompressed
+			}
+			copy(origin.Fields[i].PublicKey[:], key.PublicKey().SerializeCompressed())
+			continue
+		}
+
+		field := AuthField{Type: AuthFieldSignatureCompressed, Signature: key.Sign(presign)}
+		encoding := PubKeyEncodingCompressed
+		if !key.Compressed() {
+			field.Type, encoding = AuthFieldSignatureUncompressed, PubKeyEncodingUncompressed
+		}
+		origin.Fields[i] = field
+		if sequential {
+			presign = presignSigHash(postsignSigHash(presign, encoding, field.Signature), AuthStandard, origin.Fee, origin.Nonce)
+		}
+	}
+}
+
+func testKeys(t *testing.T, hexKeys ...string) []*secp256k1.PrivateKey {
+	t.Helper()
+	keys := make([]*secp256k1.PrivateKey, len(hexKeys))
+	for i, hexKey := range hexKeys {
+		key, err := secp256k1.ParsePrivateKey(hexKey)
+		require.NoError(t, err)
+		keys[i] = key
+	}
+	return keys
+}
+
+// roundTrip encodes and decodes tx, so signatures are checked as they travel on the wire
+func roundTrip(t *testing.T, tx *Transaction) *Transaction {
+	t.Helper()
+	encoded, err := tx.Encode()
+	require.NoError(t, err)
+	decoded, err := Decode(encoded)
+	require.NoError(t, err)
+	return decoded
+}
+
+func TestRecoverOrigin(t *testing.T) {
+	keys := testKeys(t, strings.Repeat("42", 32)+"01", strings.Repeat("42", 32))
+	compressed, uncompressed := keys[0], keys[1]
+
+	tests := []struct {
+		name string
+		key  *secp256k1.PrivateKey
+		mode HashMode
+	}{
+		{"P2PKH compressed", compressed, HashModeP2PKH},
+		{"P2PKH uncompressed", uncompressed, HashModeP2PKH},
+		{"P2WPKH", compressed, HashModeP2WPKH},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tx, err := Decode(stxTransferBytes())
+			require.NoError(t, err)
+			signOrigin(t, tx, tt.key, tt.mode)
+
+			address, err := roundTrip(t, tx).RecoverOrigin()
+			require.NoError(t, err)
+			assert.Equal(t, tx.OriginAddress(), address)
+		})
+	}
+}
+
+func TestRecoverOrigin_Multisig(t *testing.T) {
+	compressed := testKeys(t, strings.Repeat("42", 32)+"01", strings.Repeat("24", 32)+"01", strings.Repeat("33", 32)+"01")
+	mixed := testKeys(t, strings.Repeat("42", 32)+"01", strings.Repeat("24", 32), strings.Repeat("33", 32)+"01")
+
+	tests := []struct {
+		name     string
+		mode     HashMode
+		required uint16
+		keys     []*secp256k1.PrivateKey
+		signers  int
+	}{
+		{"P2SH 2 of 3", HashModeP2SH, 2, compressed, 2},
+		{"P2SH with an uncompressed key", HashModeP2SH, 2, mixed, 2},
+		{"P2WSH 2 of 3", HashModeP2WSH, 2, compressed, 2},
+		{"P2SH 3 of 3", HashModeP2SH, 3, compressed, 3},
+		{"non-sequential P2SH 2 of 3", HashModeP2SHNonSequential, 2, compressed, 2},
+		{"non-sequential P2SH with an extra signature", HashModeP2SHNonSequential, 2, mixed, 3},
+		{"non-sequential P2WSH 2 of 3", HashModeP2WSHNonSequential, 2, compressed, 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tx, err := Decode(stxTransferBytes())
+			require.NoError(t, err)
+			signMultisigOrigin(t, tx, tt.mode, tt.required, tt.keys, tt.signers)
+
+			address, err := roundTrip(t, tx).RecoverOrigin()
+			require.NoError(t, err)
+			assert.Equal(t, tx.OriginAddress(), address)
+			assert.Equal(t, "SN", address.String()[:2], "multisig origins have testnet multisig addresses")
+		})
+	}
+}
+
+func TestRecoverOrigin_SponsoredOriginIsIndependentOfSponsor(t *testing.T) {
+	keys := testKeys(t, strings.Repeat("42", 32)+"01", strings.Repeat("24", 32)+"01")
+
+	tx, err := Decode(sponsoredTransferBytes())
+	require.NoError(t, err)
+	signOrigin(t, tx, keys[0], HashModeP2PKH)
+	before, err := tx.RecoverOrigin()
+	require.NoError(t, err)
+
+	require.NoError(t, tx.SignSponsor(keys[1], 4, 900))
+	after, err := tx.RecoverOrigin()
+	require.NoError(t, err)
+	assert.Equal(t, before, after)
+}
+
+func TestRecoverOrigin_Rejects(t *testing.T) {
+	key := testKeys(t, strings.Repeat("42", 32)+"01")[0]
+
+	tests := []struct {
+		name   string
+		tamper func(tx *Transaction)
+	}{
+		{"unsigned", func(tx *Transaction) { tx.Auth.Origin.Signature = Signature{} }},
+		{"other signer", func(tx *Transaction) { tx.Auth.Origin.Signer = signerHash }},
+		{"fee changed", func(tx *Transaction) { tx.Auth.Origin.Fee++ }},
+		{"nonce changed", func(tx *Transaction) { tx.Auth.Origin.Nonce++ }},
+		{"key encoding changed", func(tx *Transaction) { tx.Auth.Origin.KeyEncoding = PubKeyEncodingUncompressed }},
+		{"hash mode changed", func(tx *Transaction) { tx.Auth.Origin.HashMode = HashModeP2WPKH }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tx, err := Decode(stxTransferBytes())
+			require.NoError(t, err)
+			signOrigin(t, tx, key, HashModeP2PKH)
+			tt.tamper(tx)
+
+			_, err = tx.RecoverOrigin()
+			assert.ErrorIs(t, err, ErrInvalidSignature)
+		})
+	}
+}
+
+func TestRecoverOrigin_RejectsMultisig(t *testing.T) {
+	compressed := testKeys(t, strings.Repeat("42", 32)+"01", strings.Repeat("24", 32)+"01", strings.Repeat("33", 32)+"01")
+	mixed := testKeys(t, strings.Repeat("42", 32)+"01", strings.Repeat("24", 32), strings.Repeat("33", 32)+"01")
+
+	tests := []struct {
+		name     string
+		mode     HashMode
+		keys     []*secp256k1.PrivateKey
+		signers  int
+		tamper   func(origin *SpendingCondition)
+		wantText string
+	}{
+		{name: "too few signatures", mode: HashModeP2SH, keys: compressed, signers: 1, wantText: "1 signatures, 2 required"},
+		{name: "too many sequential signatures", mode: HashModeP2SH, keys: compressed, signers: 3, wantText: "3 signatures, 2 required"},
+		{name: "too few non-sequential signatures", mode: HashModeP2SHNonSequential, keys: compressed, signers: 1, wantText: "at least 2 required"},
+		{name: "P2WSH with an uncompressed key", mode: HashModeP2WSH, keys: mixed, signers: 2, wantText: "P2WSH requires compressed"},
+		{
+			name: "signatures reordered", mode: HashModeP2SH, keys: compressed, signers: 2,
+			tamper: func(origin *SpendingCondition) {
+				origin.Fields[0], origin.Fields[1] = origin.Fields[1], origin.Fields[0]
+			},
+		},
+		{
+			name: "sequential signatures read as non-sequential", mode: HashModeP2SH, keys: compressed, signers: 2,
+			tamper: func(origin *SpendingCondition) { origin.HashMode = HashModeP2SHNonSequential },
+		},
+		{
+			name: "public key replaced", mode: HashModeP2SH, keys: compressed, signers: 2,
+			tamper: func(origin *SpendingCondition) {
+				copy(origin.Fields[2].PublicKey[:], mixed[1].PublicKey().SerializeCompressed())
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tx, err := Decode(stxTransferBytes())
+			require.NoError(t, err)
+			signMultisigOrigin(t, tx, tt.mode, 2, tt.keys, tt.signers)
+			if tt.tamper != nil {
+				tt.tamper(&tx.Auth.Origin)
+			}
+
+			_, err = tx.RecoverOrigin()
+			assert.ErrorIs(t, err, ErrInvalidSignature)
+			if tt.wantText != "" {
+				assert.ErrorContains(t, err, tt.wantText)
+			}
+		})
+	}
+}
+
+func TestRecoverOrigin_RejectsChangedPayload(t *testing.T) {
+	key := testKeys(t, strings.Repeat("42", 32)+"01")[0]
+
+	tx, err := Decode(stxTransferBytes())
+	require.NoError(t, err)
+	signOrigin(t, tx, key, HashModeP2PKH)
+	encoded, err := tx.Encode()
+	require.NoError(t, err)
+
+	// Raise the amount, the u64 just before the 34-byte memo
+	encoded[len(encoded)-34-3]++
+	tampered, err := Decode(encoded)
+	require.NoError(t, err)
+	require.Equal(t, uint64(1000000+1<<16), tampered.Payload.(TokenTransferPayload).Amount)
+
+	_, err = tampered.RecoverOrigin()
+	assert.ErrorIs(t, err, ErrInvalidSignature)
+}
+
+func TestMultisigSignerHash(t *testing.T) {
+	keys := testKeys(t, strings.Repeat("42", 32)+"01", strings.Repeat("24", 32))
+	first, second := keys[0].PublicKey().Bytes(), keys[1].PublicKey().Bytes()
+
+	// OP_2 <33-byte key> <65-byte key> OP_2 OP_CHECKMULTISIG
+	script := append([]byte{0x52, 0x21}, first...)
+	script = append(append(script, 0x41), second...)
+	script = append(script, 0x52, 0xae)
+
+	assert.Equal(t, secp256k1.Hash160(script), multisigSignerHash(false, 2, [][]byte{first, second}))
+	assert.NotEqual(t, secp256k1.Hash160(script), multisigSignerHash(true, 2, [][]byte{first, second}))
+}
+
+func TestMultisigSignerHash_KnownVector(t *testing.T) {
+	// The 2-of-2 P2SH signer set from stacks-core's address hashing tests, both keys uncompressed
+	keys := make([][]byte, 2)
+	for i, key := range []string{
+		"040fadbbcea0ff3b05f03195b41cd991d7a0af8bd38559943aec99cbdaf0b22cc806b9a4f07579934774cc0c155e781d45c989f94336765e88a66d91cfb9f060b0",
+		"04c77f262dda02580d65c9069a8a34c56bd77325bba4110b693b90216f5a3edc0bebc8ce28d61aa86b414aa91ecb29823b11aeed06098fcd97fee4bc73d54b1e96",
+	} {
+		var err error
+		keys[i], err = hex.DecodeString(key)
+		require.NoError(t, err)
+	}
+
+	signer := multisigSignerHash(false, 2, keys)
+	assert.Equal(t, "fd3a5e9f5ba311ce6122765f0af8da7488e25d3a", hex.EncodeToString(signer[:]))
+
+	condition := SpendingCondition{HashMode: HashModeP2SH, Signer: signer}
+	assert.Equal(t, "SM3YKMQMZBEHH3KK149V5Y2QRV9T8HRJX79T5NKMP", condition.Address(VersionMainnet).String())
+	assert.Equal(t, "SN3YKMQMZBEHH3KK149V5Y2QRV9T8HRJX798AC621", condition.Address(VersionTestnet).String())
+}
+
+func TestRecoverOrigin_KnownKeys(t *testing.T) {
+	tests := []struct {
+		name       string
+		key        string
+		mode       HashMode
+		wantSigner string
+		wantAddr   string
+	}{
+		{
+			// Clarinet devnet deployer
+			name: "P2PKH", key: "753b7cc01a1a2e86221266a154af739463fce51219d97e4f856cd7200c3bd2a601", mode: HashModeP2PKH,
+			wantSigner: "6d78de7b0625dfbfc16c3a8a5735f6dc3dc3f2ce", wantAddr: "ST1PQHQKV0RJXZFY1DGX8MNSNYVE3VGZJSRTPGZGM",
+		},
+		{
+			// BIP49 test vector: the nested P2WPKH script hash behind 2Mww8dCYPUpKHofjgcXcBCEGmniw9CoaiD2
+			name: "P2WPKH", key: "c9bdb49cfbaedca21c4b1f3a7803c34636b1d7dc55a717132443fc3f4c5867e801", mode: HashModeP2WPKH,
+			wantSigner: "336caa13e08b96080a32b5d818d59b4ab3b36742",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tx, err := Decode(stxTransferBytes())
+			require.NoError(t, err)
+			signOrigin(t, tx, testKeys(t, tt.key)[0], tt.mode)
+			assert.Equal(t, tt.wantSigner, hex.EncodeToString(tx.Auth.Origin.Signer[:]))
+
+			address, err := roundTrip(t, tx).RecoverOrigin()
+			require.NoError(t, err)
+			assert.Equal(t, tx.OriginAddress(), address)
+			if tt.wantAddr != "" {
+				assert.Equal(t, tt.wantAddr, address.String())
+			}
+		})
+	}
+}
+
+func TestAppendScriptInt(t *testing.T) {
+	tests := []struct {
+		n    int64
+		want []byte
+	}{
+		{0, []byte{0x00}},
+		{1, []byte{0x51}},
+		{16, []byte{0x60}},
+		{17, []byte{0x01, 0x11}},
+		{127, []byte{0x01, 0x7f}},
+		{128, []byte{0x02, 0x80, 0x00}},
+		{256, []byte{0x02, 0x00, 0x01}},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, appendScriptInt(nil, tt.n), tt.n)
+	}
+}
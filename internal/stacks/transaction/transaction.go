@@ -0,0 +1,254 @@
+package transaction
+
+import (
+	"fmt"
+
+	"github.com/x402stacks/stacks-facilitator/internal/payment/domain/valueobject"
+	"github.com/x402stacks/stacks-facilitator/internal/stacks/clarity"
+)
+
+// Version is the transaction version byte
+type Version byte
+
+const (
+	VersionMainnet Version = 0x00
+	VersionTestnet Version = 0x80
+)
+
+// Chain IDs for the public networks
+const (
+	ChainIDMainnet uint32 = 0x00000001
+	ChainIDTestnet uint32 = 0x80000000
+)
+
+// AuthType distinguishes standard from sponsored authorization
+type AuthType byte
+
+const (
+	AuthStandard  AuthType = 0x04
+	AuthSponsored AuthType = 0x05
+)
+
+// HashMode is how a spending condition's signer hash is derived
+type HashMode byte
+
+const (
+	HashModeP2PKH              HashMode = 0x00
+	HashModeP2SH               HashMode = 0x01
+	HashModeP2WPKH             HashMode = 0x02
+	HashModeP2WSH              HashMode = 0x03
+	HashModeP2SHNonSequential  HashMode = 0x05
+	HashModeP2WSHNonSequential HashMode = 0x07
+)
+
+// IsSingleSig returns true for single-signature hash modes
+func (m HashMode) IsSingleSig() bool {
+	return m == HashModeP2PKH || m == HashModeP2WPKH
+}
+
+// IsValid returns true for hash modes defined by the protocol
+func (m HashMode) IsValid() bool {
+	switch m {
+	case HashModeP2PKH, HashModeP2SH, HashModeP2WPKH, HashModeP2WSH,
+		HashModeP2SHNonSequential, HashModeP2WSHNonSequential:
+		return true
+	default:
+		return false
+	}
+}
+
+// PubKeyEncoding is the public key encoding of a single-sig spending condition
+type PubKeyEncoding byte
+
+const (
+	PubKeyEncodingCompressed   PubKeyEncoding = 0x00
+	PubKeyEncodingUncompressed PubKeyEncoding = 0x01
+)
+
+// AuthFieldType identifies a multisig authorization field
+type AuthFieldType byte
+
+const (
+	AuthFieldPublicKeyCompressed   AuthFieldType = 0x00
+	AuthFieldPublicKeyUncompressed AuthFieldType = 0x01
+	AuthFieldSignatureCompressed   AuthFieldType = 0x02
+	AuthFieldSignatureUncompressed AuthFieldType = 0x03
+)
+
+// IsSignature returns true if the field carries a signature rather than a public key
+func (t AuthFieldType) IsSignature() bool {
+	return t == AuthFieldSignatureCompressed || t == AuthFieldSignatureUncompressed
+}
+
+// AnchorMode controls where a transaction may be mined
+type AnchorMode byte
+
+const (
+	AnchorModeOnChainOnly  AnchorMode = 0x01
+	AnchorModeOffChainOnly AnchorMode = 0x02
+	AnchorModeAny          AnchorMode = 0x03
+)
+
+// PostConditionMode controls whether unlisted asset transfers are allowed
+type PostConditionMode byte
+
+const (
+	PostConditionModeAllow PostConditionMode = 0x01
+	PostConditionModeDeny  PostConditionMode = 0x02
+)
+
+// Signature is a 65-byte recoverable signature (recovery id, r, s)
+type Signature [65]byte
+
+// AuthField is one public key or signature in a multisig spending condition
+type AuthField struct {
+	Type      AuthFieldType
+	PublicKey [33]byte  // Set for public key fields
+	Signature Signature // Set for signature fields
+}
+
+// SpendingCondition authorizes the origin or sponsor of a transaction
+type SpendingCondition struct {
+	HashMode HashMode
+	Signer   [20]byte
+	Nonce    uint64
+	Fee      uint64
+
+	// Single-sig fields
+	KeyEncoding PubKeyEncoding
+	Signature   Signature
+
+	// Multisig fields
+	Fields             []AuthField
+	SignaturesRequired uint16
+}
+
+// Authorization holds the origin and optional sponsor spending conditions
+type Authorization struct {
+	Type    AuthType
+	Origin  SpendingCondition
+	Sponsor *SpendingCondition
+}
+
+// Transaction is a decoded Stacks transaction
+type Transaction struct {
+	Version           Version
+	ChainID           uint32
+	Auth              Authorization
+	AnchorMode        AnchorMode
+	PostConditionMode PostConditionMode
+	PostConditions    []PostCondition
+	Payload           Payload
+
+	body []byte // Wire bytes from the anchor mode onward, kept for re-encoding
+}
+
+// Network returns the network the transaction is valid on, checking version and chain ID agree
+func (t *Transaction) Network() (valueobject.Network, error) {
+	switch {
+	case t.Version == VersionMainnet && t.ChainID == ChainIDMainnet:
+		return valueobject.NetworkMainnet, nil
+	case t.Version == VersionTestnet && t.ChainID == ChainIDTestnet:
+		return valueobject.NetworkTestnet, nil
+	default:
+		return "", fmt.Errorf("unknown network: version 0x%02x, chain ID 0x%08x", byte(t.Version), t.ChainID)
+	}
+}
+
+// IsSponsored returns true if the transaction uses sponsored authorization
+func (t *Transaction) IsSponsored() bool {
+	return t.Auth.Type == AuthSponsored
+}
+
+// OriginAddress returns the address of the account that signed as origin
+func (t *Transaction) OriginAddress() valueobject.StacksAddress {
+	return t.Auth.Origin.Address(t.Version)
+}
+
+// Fee returns the fee paid by the transaction (the sponsor's fee when sponsored)
+func (t *Transaction) Fee() uint64 {
+	if t.Auth.Sponsor != nil {
+		return t.Auth.Sponsor.Fee
+	}
+	return t.Auth.Origin.Fee
+}
+
+// Address returns the address derived from the spending condition's signer hash
+func (c SpendingCondition) Address(version Version) valueobject.StacksAddress {
+	return valueobject.NewStacksAddressFromHash160(c.AddressVersion(version), c.Signer)
+}
+
+// AddressVersion returns the c32 address version byte for the spending condition
+func (c SpendingCondition) AddressVersion(version Version) byte {
+	singleSig := c.HashMode == HashModeP2PKH
+	switch {
+	case version == VersionMainnet && singleSig:
+		return valueobject.AddressVersionMainnetSingleSig
+	case version == VersionMainnet:
+		return valueobject.AddressVersionMainnetMultiSig
+	case singleSig:
+		return valueobject.AddressVersionTestnetSingleSig
+	default:
+		return valueobject.AddressVersionTestnetMultiSig
+	}
+}
+
+// PostConditionType identifies the asset class a post-condition covers
+type PostConditionType byte
+
+const (
+	PostConditionSTX         PostConditionType = 0x00
+	PostConditionFungible    PostConditionType = 0x01
+	PostConditionNonFungible PostConditionType = 0x02
+)
+
+// PostConditionPrincipalType identifies whose assets a post-condition covers
+type PostConditionPrincipalType byte
+
+const (
+	PostConditionPrincipalOrigin   PostConditionPrincipalType = 0x01
+	PostConditionPrincipalStandard PostConditionPrincipalType = 0x02
+	PostConditionPrincipalContract PostConditionPrincipalType = 0x03
+)
+
+// ConditionCode is the comparison a post-condition applies
+type ConditionCode byte
+
+const (
+	ConditionEqual              ConditionCode = 0x01
+	ConditionGreater            ConditionCode = 0x02
+	ConditionGreaterEqual       ConditionCode = 0x03
+	ConditionLess               ConditionCode = 0x04
+	ConditionLessEqual          ConditionCode = 0x05
+	ConditionNonFungibleNotSent ConditionCode = 0x10
+	ConditionNonFungibleSent    ConditionCode = 0x11
+)
+
+// PostConditionPrincipal is the principal a post-condition applies to
+type PostConditionPrincipal struct {
+	Type         PostConditionPrincipalType
+	Address      clarity.StandardPrincipal // Unset for origin
+	ContractName string                    // Set for contract principals
+}
+
+// AssetInfo identifies a fungible or non-fungible asset
+type AssetInfo struct {
+	Address      clarity.StandardPrincipal
+	ContractName string
+	AssetName    string
+}
+
+// ContractID returns the contract principal that defines the asset
+func (a AssetInfo) ContractID() string {
+	return fmt.Sprintf("%s.%s", a.Address.String(), a.ContractName)
+}
+
+// PostCondition is a decoded post-condition
+type PostCondition struct {
+	Type      PostConditionType
+	Principal PostConditionPrincipal
+	Asset     AssetInfo     // Unset for STX
+	Code      ConditionCode //
+	Amount    uint64        // STX and fungible conditions
+	AssetID   clarity.Value // Non-fungible conditions
+}